@@ -9,6 +9,7 @@ require (
 	github.com/dikhan/http_goclient v0.0.0-20181010015730-b9de9b5ee7b6
 	github.com/dimfeld/httppath v0.0.0-20170720192232-ee938bf73598 // indirect
 	github.com/dimfeld/httptreemux v5.0.1+incompatible // indirect
+	github.com/getkin/kin-openapi v0.2.0
 	github.com/go-openapi/analysis v0.0.0-20171215055114-2bbaa248df98 // indirect
 	github.com/go-openapi/errors v0.0.0-20170426151106-03cfca65330d // indirect
 	github.com/go-openapi/jsonreference v0.17.0
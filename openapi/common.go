@@ -25,6 +25,8 @@ func checkHTTPStatusCode(openAPIResource SpecResource, res *http.Response, expec
 		switch res.StatusCode {
 		case http.StatusUnauthorized:
 			return fmt.Errorf("[resource='%s'] HTTP Response Status Code %d - Unauthorized: API access is denied due to invalid credentials (%s)", openAPIResource.getResourceName(), res.StatusCode, resBody)
+		case http.StatusPreconditionFailed:
+			return fmt.Errorf("[resource='%s'] HTTP Response Status Code %d - Precondition Failed: the resource has been modified outside terraform since it was last read (e,g: by another team or tool). Run 'terraform refresh' to get the latest changes, review them and try again (%s)", openAPIResource.getResourceName(), res.StatusCode, resBody)
 		case http.StatusNotFound:
 			return &openapierr.NotFoundError{OriginalError: fmt.Errorf("HTTP Response Status Code %d - Not Found. Could not find resource instance: %s", res.StatusCode, resBody)}
 		default:
@@ -48,6 +48,15 @@ func TestCheckHTTPStatusCode(t *testing.T) {
 			expectedStatusCodes: []int{http.StatusOK},
 			expectedError:       errors.New("[resource='resourceName'] HTTP Response Status Code 401 - Unauthorized: API access is denied due to invalid credentials (unauthorized)"),
 		},
+		{
+			name: "response known with code 412 Precondition Failed",
+			response: &http.Response{
+				Body:       ioutil.NopCloser(strings.NewReader("etag mismatch")),
+				StatusCode: http.StatusPreconditionFailed,
+			},
+			expectedStatusCodes: []int{http.StatusOK},
+			expectedError:       errors.New("[resource='resourceName'] HTTP Response Status Code 412 - Precondition Failed: the resource has been modified outside terraform since it was last read (e,g: by another team or tool). Run 'terraform refresh' to get the latest changes, review them and try again (etag mismatch)"),
+		},
 	}
 
 	for _, tc := range testCases {
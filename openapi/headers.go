@@ -4,4 +4,15 @@ package openapi
 const (
 	authorizationHeader = "Authorization"
 	userAgentHeader     = "User-Agent"
+	contentTypeHeader   = "Content-Type"
+	// locationHeader and operationLocationHeader contain the URL of the operation resource that reports the progress of
+	// asynchronous requests (202 Accepted)
+	locationHeader          = "Location"
+	operationLocationHeader = "Operation-Location"
+	// retryAfterHeader contains the time the client should wait before retrying the request
+	retryAfterHeader = "Retry-After"
+	// eTagHeader contains the version of the resource returned by the API and ifMatchHeader is used to send it back in
+	// the requests that modify the resource so the API rejects them if the resource changed in the meantime
+	eTagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
 )
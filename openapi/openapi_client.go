@@ -1,11 +1,15 @@
 package openapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"reflect"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/version"
 
@@ -18,6 +22,7 @@ const (
 	httpGet    httpMethodSupported = "GET"
 	httpPost   httpMethodSupported = "POST"
 	httpPut    httpMethodSupported = "PUT"
+	httpPatch  httpMethodSupported = "PATCH"
 	httpDelete httpMethodSupported = "DELETE"
 )
 
@@ -25,9 +30,15 @@ const (
 type ClientOpenAPI interface {
 	Post(resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
 	Put(resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	Patch(resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
 	Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
 	Delete(resource SpecResource, id string, parentIDs ...string) (*http.Response, error)
 	List(resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	GetOperation(resource SpecResource, operationURL string, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	GetLongRunningOperation(resource SpecResource, name string, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	PerformAction(resource SpecResource, action *specResourceAction, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error)
+	WithIfMatch(eTag string) ClientOpenAPI
+	WithIdempotencyKey(idempotencyKey string) ClientOpenAPI
 }
 
 // ProviderClient defines a client that is configured based on the OpenAPI server side documentation
@@ -38,64 +49,203 @@ type ProviderClient struct {
 	httpClient                  http_goclient.HttpClientIface
 	providerConfiguration       providerConfiguration
 	apiAuthenticator            specAuthenticator
+	// ifMatch contains the resource ETag sent in the If-Match header of the PUT, PATCH and DELETE requests (if any)
+	ifMatch string
+	// idempotencyKey contains the key sent in the idempotency key header of the POST requests (if the operation defines one)
+	idempotencyKey string
+	// headers contains the static headers configured for the service in the plugin configuration, which are sent along
+	// with all the requests unless the request already contains them (e,g: authentication or operation headers)
+	headers map[string]string
+}
+
+// WithIfMatch returns a copy of the client that sends the given ETag in the If-Match header of the PUT, PATCH and DELETE
+// requests, so the API rejects them with 412 Precondition Failed if the resource was modified since the ETag was obtained
+func (o *ProviderClient) WithIfMatch(eTag string) ClientOpenAPI {
+	client := *o
+	client.ifMatch = eTag
+	return &client
+}
+
+// WithIdempotencyKey returns a copy of the client that sends the given key in the idempotency key header of the POST
+// requests whose operation defines one. The same key is sent across all the retries of the requests made by the copy, so
+// a new copy (and key) is expected to be created for each resource instance creation
+func (o *ProviderClient) WithIdempotencyKey(idempotencyKey string) ClientOpenAPI {
+	client := *o
+	client.idempotencyKey = idempotencyKey
+	return &client
 }
 
 // Post performs a POST request to the server API based on the resource configuration and the payload passed in
 func (o *ProviderClient) Post(resource SpecResource, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
-	resourceURL, err := o.getResourceURL(resource, parentIDs)
+	operation := resource.getResourceOperations().Post
+	resourceURL, err := o.getResourceURL(resource, parentIDs, operation)
 	if err != nil {
 		return nil, err
 	}
-	operation := resource.getResourceOperations().Post
 	return o.performRequest(httpPost, resourceURL, operation, requestPayload, responsePayload)
 }
 
 // Put performs a PUT request to the server API based on the resource configuration and the payload passed in
 func (o *ProviderClient) Put(resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
-	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
+	operation := resource.getResourceOperations().Put
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id, operation)
 	if err != nil {
 		return nil, err
 	}
-	operation := resource.getResourceOperations().Put
 	return o.performRequest(httpPut, resourceURL, operation, requestPayload, responsePayload)
 }
 
+// Patch performs a PATCH request to the server API based on the resource configuration and the payload passed in. The
+// payload is expected to be already encoded as per the operation patch content type (e,g: JSON Merge Patch document)
+func (o *ProviderClient) Patch(resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	operation := resource.getResourceOperations().Patch
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id, operation)
+	if err != nil {
+		return nil, err
+	}
+	return o.performRequest(httpPatch, resourceURL, operation, requestPayload, responsePayload)
+}
+
 // Get performs a GET request to the server API based on the resource configuration and the resource instance id passed in
 func (o *ProviderClient) Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
-	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
+	operation := resource.getResourceOperations().Get
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id, operation)
 	if err != nil {
 		return nil, err
 	}
-	operation := resource.getResourceOperations().Get
 	return o.performRequest(httpGet, resourceURL, operation, nil, responsePayload)
 }
 
 // List performs a GET request to the root level endpoint of the resource (e,g: GET /v1/groups)
 func (o *ProviderClient) List(resource SpecResource, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
-	resourceURL, err := o.getResourceURL(resource, parentIDs)
+	operation := resource.getResourceOperations().List
+	resourceURL, err := o.getResourceURL(resource, parentIDs, operation)
 	if err != nil {
 		return nil, err
 	}
-	operation := resource.getResourceOperations().List
 	return o.performRequest(httpGet, resourceURL, operation, nil, responsePayload)
 }
 
+// GetOperation performs a GET request to the given operation URL, that is the URL returned by the API in the Location
+// or Operation-Location headers when handling requests asynchronously. Relative URLs are resolved against the resource
+// URL. The request is authenticated the same way as the resource GET operation
+func (o *ProviderClient) GetOperation(resource SpecResource, operationURL string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	operation := resource.getResourceOperations().Get
+	resourceURL, err := o.getResourceURL(resource, parentIDs, operation)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(resourceURL)
+	if err != nil {
+		return nil, err
+	}
+	operationRef, err := url.Parse(operationURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operation URL '%s': %s", operationURL, err)
+	}
+	return o.performRequest(httpGet, baseURL.ResolveReference(operationRef).String(), operation, nil, responsePayload)
+}
+
+// GetLongRunningOperation performs a GET request to retrieve the long-running operation with the given name, as defined
+// by the Google API design guidelines (AIP-151). The operation is requested against the same base URL as the resource
+// (e,g: https://api.server.com/v1/operations/{name}). The request is authenticated the same way as the resource GET operation
+func (o *ProviderClient) GetLongRunningOperation(resource SpecResource, name string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	operation := resource.getResourceOperations().Get
+	operationURL, err := o.getLongRunningOperationURL(resource, name, parentIDs, operation)
+	if err != nil {
+		return nil, err
+	}
+	return o.performRequest(httpGet, operationURL, operation, nil, responsePayload)
+}
+
+// PerformAction performs a POST request to the given resource custom method (e,g: /v1/certs/{id}:rotate) based on the
+// resource configuration and the resource instance id passed in
+func (o *ProviderClient) PerformAction(resource SpecResource, action *specResourceAction, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id, action.Operation)
+	if err != nil {
+		return nil, err
+	}
+	actionURL := fmt.Sprintf("%s:%s", resourceURL, action.Name)
+	return o.performRequest(httpPost, actionURL, action.Operation, requestPayload, responsePayload)
+}
+
 // Delete performs a DELETE request to the server API based on the resource configuration and the resource instance id passed in
 func (o *ProviderClient) Delete(resource SpecResource, id string, parentIDs ...string) (*http.Response, error) {
-	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id)
+	operation := resource.getResourceOperations().Delete
+	resourceURL, err := o.getResourceIDURL(resource, parentIDs, id, operation)
 	if err != nil {
 		return nil, err
 	}
-	operation := resource.getResourceOperations().Delete
 	return o.performRequest(httpDelete, resourceURL, operation, nil, nil)
 }
 
+// performRequest performs the request retrying it if it fails due to a transient error as defined by the retry policy
+// (see retryPolicy)
 func (o *ProviderClient) performRequest(method httpMethodSupported, resourceURL string, operation *specResourceOperation, requestPayload interface{}, responsePayload interface{}) (*http.Response, error) {
-	reqContext, err := o.apiAuthenticator.prepareAuth(resourceURL, operation.SecuritySchemes, o.providerConfiguration)
+	retryPolicy := newRetryPolicy(o.providerConfiguration, operation, o.idempotencyKey)
+	for retry := 0; ; retry++ {
+		res, err := o.performRequestAttempt(method, resourceURL, operation, requestPayload, responsePayload)
+		if retry >= retryPolicy.maxRetries || !retryPolicy.shouldRetry(method, res, err) {
+			return res, err
+		}
+		wait := retryPolicy.getWait(retry, res)
+		if res != nil {
+			log.Printf("[WARN] %s %s returned a retryable response '%s', retrying in %s (retry %d/%d)", method, resourceURL, res.Status, wait, retry+1, retryPolicy.maxRetries)
+			if res.Body != nil {
+				res.Body.Close()
+			}
+		} else {
+			log.Printf("[WARN] %s %s failed with a transient error '%s', retrying in %s (retry %d/%d)", method, resourceURL, err, wait, retry+1, retryPolicy.maxRetries)
+		}
+		resetResponsePayload(responsePayload)
+		time.Sleep(wait)
+	}
+}
+
+// resetResponsePayload sets the value the given response payload points at to its zero value so the payload returned by
+// a retried request does not contain values from the previous attempts
+func resetResponsePayload(responsePayload interface{}) {
+	value := reflect.ValueOf(responsePayload)
+	if value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().CanSet() {
+		value.Elem().Set(reflect.Zero(value.Elem().Type()))
+	}
+}
+
+// performRequestAttempt performs the request once. If the API rejects the request with 401 Unauthorized and the request
+// was authenticated with sessions (e,g: session cookies), the sessions are discarded and the request is performed again
+// so new sessions are created
+func (o *ProviderClient) performRequestAttempt(method httpMethodSupported, resourceURL string, operation *specResourceOperation, requestPayload interface{}, responsePayload interface{}) (*http.Response, error) {
+	res, reqContext, err := o.sendRequest(method, resourceURL, operation, requestPayload, responsePayload)
+	if res != nil && res.StatusCode == http.StatusUnauthorized && reqContext.invalidateSessions() {
+		log.Printf("[INFO] %s %s returned '%s', performing the request again with a new session", method, resourceURL, res.Status)
+		if res.Body != nil {
+			res.Body.Close()
+		}
+		resetResponsePayload(responsePayload)
+		res, _, err = o.sendRequest(method, resourceURL, operation, requestPayload, responsePayload)
+	}
+	return res, err
+}
+
+// sendRequest prepares the authentication and the headers of the request and performs it. The auth context used is
+// returned along with the response
+func (o *ProviderClient) sendRequest(method httpMethodSupported, resourceURL string, operation *specResourceOperation, requestPayload interface{}, responsePayload interface{}) (*http.Response, *authContext, error) {
+	requestBody, err := marshalRequestPayload(requestPayload)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	reqContext, err := o.apiAuthenticator.prepareAuth(method, resourceURL, requestBody, operation.SecuritySchemes, o.providerConfiguration)
+	if err != nil {
+		return nil, nil, err
 	}
 	o.appendOperationHeaders(operation.HeaderParameters, o.providerConfiguration, reqContext.headers)
+	o.appendServiceHeaders(reqContext.headers)
+	if method == httpPost && operation.IdempotencyKeyHeader != "" && o.idempotencyKey != "" {
+		reqContext.headers[operation.IdempotencyKeyHeader] = o.idempotencyKey
+	}
+	if o.ifMatch != "" && (method == httpPut || method == httpPatch || method == httpDelete) {
+		reqContext.headers[ifMatchHeader] = o.ifMatch
+	}
 	log.Printf("[DEBUG] Performing %s %s", method, reqContext.url)
 
 	userAgentHeader := version.BuildUserAgent(runtime.GOOS, runtime.GOARCH)
@@ -103,11 +253,28 @@ func (o *ProviderClient) performRequest(method httpMethodSupported, resourceURL
 
 	o.logHeadersSafely(reqContext.headers)
 
+	res, err := o.dispatchRequest(method, reqContext, operation, requestPayload, responsePayload)
+	return res, reqContext, err
+}
+
+// dispatchRequest performs the request using the http client method corresponding to the request method
+func (o *ProviderClient) dispatchRequest(method httpMethodSupported, reqContext *authContext, operation *specResourceOperation, requestPayload interface{}, responsePayload interface{}) (*http.Response, error) {
 	switch method {
 	case httpPost:
 		return o.httpClient.PostJson(reqContext.url, reqContext.headers, requestPayload, &responsePayload)
 	case httpPut:
 		return o.httpClient.PutJson(reqContext.url, reqContext.headers, requestPayload, &responsePayload)
+	case httpPatch:
+		patchClient, ok := o.httpClient.(httpPatchClientIface)
+		if !ok {
+			return nil, fmt.Errorf("http client does not support method '%s'", method)
+		}
+		contentType, err := operation.getPatchContentType()
+		if err != nil {
+			return nil, err
+		}
+		reqContext.headers[contentTypeHeader] = contentType
+		return patchClient.Patch(reqContext.url, reqContext.headers, requestPayload, &responsePayload)
 	case httpGet:
 		return o.httpClient.Get(reqContext.url, reqContext.headers, &responsePayload)
 	case httpDelete:
@@ -116,6 +283,19 @@ func (o *ProviderClient) performRequest(method httpMethodSupported, resourceURL
 	return nil, fmt.Errorf("method '%s' not supported", method)
 }
 
+// marshalRequestPayload returns the body of the request as it is sent by the http client (the payload is marshalled
+// the same way) so the authenticators can sign it. Nil is returned if the request does not have payload
+func marshalRequestPayload(requestPayload interface{}) ([]byte, error) {
+	if requestPayload == nil {
+		return nil, nil
+	}
+	body, err := json.Marshal(requestPayload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the request payload: %s", err)
+	}
+	return body, nil
+}
+
 func (o *ProviderClient) appendUserAgentHeader(headers map[string]string, value string) {
 	headers[userAgentHeader] = value
 }
@@ -143,36 +323,100 @@ func (o ProviderClient) appendOperationHeaders(operationHeaders []SpecHeaderPara
 	}
 }
 
-func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string) (string, error) {
-	var host string
-	var err error
+// appendServiceHeaders adds the static headers configured for the service to the given headers, keeping the values of the
+// headers already present
+func (o ProviderClient) appendServiceHeaders(headers map[string]string) {
+	for headerName, headerValue := range o.headers {
+		if _, exists := headers[headerName]; !exists {
+			headers[headerName] = headerValue
+		}
+	}
+}
 
+// getLongRunningOperationURL returns the URL of the long-running operation with the given name. The operation name is
+// expected to be relative to the API base URL (e,g: operations/1234); if the name does not contain the operations
+// collection it is added automatically
+func (o ProviderClient) getLongRunningOperationURL(resource SpecResource, name string, parentIDs []string, operation *specResourceOperation) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("could not build the long-running operation URL: required operation name value is missing")
+	}
+	resourceURL, err := o.getResourceURL(resource, parentIDs, operation)
+	if err != nil {
+		return "", err
+	}
+	resourcePath, err := resource.getResourcePath(parentIDs)
+	if err != nil {
+		return "", err
+	}
+	// the resource URL is made of the base URL (scheme, host and base path) followed by the resource path
+	baseURL := strings.TrimSuffix(resourceURL, "/"+strings.TrimPrefix(resourcePath, "/"))
+	operationPath := strings.TrimPrefix(name, "/")
+	if !strings.HasPrefix(operationPath, "operations/") && !strings.Contains(operationPath, "/operations/") {
+		operationPath = fmt.Sprintf("operations/%s", operationPath)
+	}
+	return fmt.Sprintf("%s/%s", baseURL, operationPath), nil
+}
+
+// getGlobalHost returns the host of the service API: the host of the region selected (if the service is multi-region) or
+// the global host otherwise
+func (o ProviderClient) getGlobalHost() (string, error) {
 	isMultiRegion, _, regions, err := o.openAPIBackendConfiguration.isMultiRegion()
 	if err != nil {
 		return "", err
 	}
-	if isMultiRegion {
-		// get region value provided by user in the terraform configuration file
-		region := o.providerConfiguration.getRegion()
-		// otherwise, if not provided falling back to the default value specified in the service provider swagger file
-		if region == "" {
-			region, err = o.openAPIBackendConfiguration.getDefaultRegion(regions)
-			if err != nil {
-				return "", err
-			}
-		}
-		host, err = o.openAPIBackendConfiguration.getHostByRegion(region)
+	if !isMultiRegion {
+		return o.openAPIBackendConfiguration.getHost()
+	}
+	// get region value provided by user in the terraform configuration file
+	region := o.providerConfiguration.getRegion()
+	// otherwise, if not provided falling back to the default value specified in the service provider swagger file
+	if region == "" {
+		region, err = o.openAPIBackendConfiguration.getDefaultRegion(regions)
 		if err != nil {
 			return "", err
 		}
-	} else {
-		host, err = o.openAPIBackendConfiguration.getHost()
-		if err != nil {
-			return "", err
+	}
+	return o.openAPIBackendConfiguration.getHostByRegion(region)
+}
+
+// getServiceHosts returns the hosts the service API is served from: the global host and the endpoint overrides configured
+// in the terraform configuration (if any), with the host variables resolved
+func (o ProviderClient) getServiceHosts() ([]string, error) {
+	host, err := o.getGlobalHost()
+	if err != nil {
+		return nil, err
+	}
+	hosts := []string{host}
+	for _, endPointHost := range o.providerConfiguration.Endpoints {
+		if endPointHost != "" {
+			hosts = append(hosts, endPointHost)
 		}
 	}
+	hostVariables, err := o.openAPIBackendConfiguration.getHostVariables()
+	if err != nil {
+		return nil, err
+	}
+	if len(hostVariables) > 0 {
+		hostVariableValues := hostVariables.getHostVariableValues(o.providerConfiguration.getHostVariables())
+		for i, host := range hosts {
+			hosts[i] = resolveHostVariables(host, hostVariableValues)
+		}
+	}
+	return hosts, nil
+}
+
+// getResourceURL returns the resource URL for the given operation. The scheme, host and base path specified at the
+// operation level (if any) take precedence over the global ones
+func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string, operation *specResourceOperation) (string, error) {
+	host, err := o.getGlobalHost()
+	if err != nil {
+		return "", err
+	}
 
 	basePath := o.openAPIBackendConfiguration.getBasePath()
+	if operation != nil && operation.BasePath != "" {
+		basePath = operation.BasePath
+	}
 	resourceRelativePath, err := resource.getResourcePath(parentIDs)
 	if err != nil {
 		return "", err
@@ -188,6 +432,11 @@ func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string
 		host = hostOverride
 	}
 
+	if operation != nil && operation.Host != "" {
+		log.Printf("[INFO] resource '%s' operation is configured with host override, API calls will be made against '%s' instead of '%s'", resourceRelativePath, operation.Host, host)
+		host = operation.Host
+	}
+
 	if endPointHost := o.providerConfiguration.getEndPoint(resource.getResourceName()); endPointHost != "" {
 		log.Printf("[INFO] resource '%s' is configured with endpoint override, API calls will be made against '%s' instead of '%s'", resourceRelativePath, endPointHost, host)
 		host = endPointHost
@@ -197,11 +446,26 @@ func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string
 		return "", fmt.Errorf("host and path are mandatory attributes to get the resource URL - host['%s'], path['%s']", host, resourceRelativePath)
 	}
 
-	// TODO: use resource operation schemes if specified
-	defaultScheme, err := o.openAPIBackendConfiguration.getHTTPScheme()
+	hostVariables, err := o.openAPIBackendConfiguration.getHostVariables()
+	if err != nil {
+		return "", err
+	}
+	if len(hostVariables) > 0 {
+		hostVariableValues := hostVariables.getHostVariableValues(o.providerConfiguration.getHostVariables())
+		host = resolveHostVariables(host, hostVariableValues)
+		basePath = resolveHostVariables(basePath, hostVariableValues)
+	}
+
+	defaultScheme, err := operation.getHTTPScheme()
 	if err != nil {
 		return "", err
 	}
+	if defaultScheme == "" {
+		defaultScheme, err = o.openAPIBackendConfiguration.getHTTPScheme()
+		if err != nil {
+			return "", err
+		}
+	}
 
 	path := resourceRelativePath
 	if strings.Index(resourceRelativePath, "/") != 0 {
@@ -217,11 +481,16 @@ func (o ProviderClient) getResourceURL(resource SpecResource, parentIDs []string
 	return fmt.Sprintf("%s://%s%s", defaultScheme, host, path), nil
 }
 
-func (o ProviderClient) getResourceIDURL(resource SpecResource, parentIDs []string, id string) (string, error) {
+// getResourceIDURL returns the resource instance URL for the given id. Singleton resources do not have instance paths,
+// hence the resource URL is returned instead and the id is ignored
+func (o ProviderClient) getResourceIDURL(resource SpecResource, parentIDs []string, id string, operation *specResourceOperation) (string, error) {
+	if resource.isSingleton() {
+		return o.getResourceURL(resource, parentIDs, operation)
+	}
 	if strings.Contains(id, "/") {
 		return "", fmt.Errorf("instance ID (%s) contains not supported characters (forward slashes)", id)
 	}
-	url, err := o.getResourceURL(resource, parentIDs)
+	url, err := o.getResourceURL(resource, parentIDs, operation)
 	if err != nil {
 		return "", err
 	}
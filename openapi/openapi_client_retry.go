@@ -0,0 +1,152 @@
+package openapi
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default values used when retrying failed requests (see retryPolicy). Retries are opt-in, hence disabled by default
+const defaultMaxRetries = 0
+
+var defaultRetryMaxWait = time.Duration(30 * time.Second)
+var defaultRetryableStatusCodes = []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// retryMinWait is the wait before the first retry, which is doubled on each subsequent retry (up to the retry max wait)
+var retryMinWait = time.Duration(1 * time.Second)
+
+// retryPolicy defines how requests that failed due to transient errors (e,g: 503 Service Unavailable or a connection
+// reset) are retried. Retries are performed with exponential backoff and jitter, honouring the Retry-After response
+// header if present
+type retryPolicy struct {
+	// maxRetries is the maximum number of times a request is retried. Zero disables the retries
+	maxRetries int
+	// maxWait is the maximum time to wait between retries
+	maxWait time.Duration
+	// retryableStatusCodes contains the response status codes that are considered transient
+	retryableStatusCodes []int
+	// idempotent defines whether the POST requests are idempotent, that is they include an idempotency key
+	idempotent bool
+}
+
+// newRetryPolicy returns the retryPolicy for the given operation. The operation retryable status codes (if any) take
+// precedence over the default ones. POST requests are considered idempotent if the operation defines the idempotency key
+// header and an idempotency key is provided
+func newRetryPolicy(providerConfiguration providerConfiguration, operation *specResourceOperation, idempotencyKey string) retryPolicy {
+	policy := retryPolicy{
+		maxRetries:           providerConfiguration.MaxRetries,
+		maxWait:              providerConfiguration.RetryMaxWait,
+		retryableStatusCodes: defaultRetryableStatusCodes,
+	}
+	if policy.maxWait <= 0 {
+		policy.maxWait = defaultRetryMaxWait
+	}
+	if operation != nil && len(operation.RetryableStatusCodes) > 0 {
+		policy.retryableStatusCodes = operation.RetryableStatusCodes
+	}
+	if operation != nil && operation.IdempotencyKeyHeader != "" && idempotencyKey != "" {
+		policy.idempotent = true
+	}
+	return policy
+}
+
+// shouldRetry returns true if the request that returned the given response and error should be retried. Requests that
+// are not idempotent (POST without idempotency key) are only retried when it is safe to do so, that is when the request
+// was not processed by the API: either the connection could not be established or the API rejected the request with 429
+// Too Many Requests
+func (p retryPolicy) shouldRetry(method httpMethodSupported, res *http.Response, err error) bool {
+	nonIdempotent := method == httpPost && !p.idempotent
+	if res != nil {
+		if nonIdempotent {
+			return res.StatusCode == http.StatusTooManyRequests && p.isRetryableStatusCode(res.StatusCode)
+		}
+		return p.isRetryableStatusCode(res.StatusCode)
+	}
+	if err == nil {
+		return false
+	}
+	transient, requestSent := isTransientRequestError(err)
+	if nonIdempotent {
+		return transient && !requestSent
+	}
+	return transient
+}
+
+func (p retryPolicy) isRetryableStatusCode(statusCode int) bool {
+	for _, retryableStatusCode := range p.retryableStatusCodes {
+		if retryableStatusCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// getWait returns the time to wait before performing the given retry (starting at 0). If the response contains the
+// Retry-After header its value is used; otherwise, the wait is calculated with exponential backoff and jitter. The wait
+// is never greater than the retry policy max wait
+func (p retryPolicy) getWait(retry int, res *http.Response) time.Duration {
+	if wait, ok := getRetryAfter(res); ok {
+		if wait > p.maxWait {
+			return p.maxWait
+		}
+		return wait
+	}
+	backoff := time.Duration(float64(retryMinWait) * math.Pow(2, float64(retry)))
+	if backoff <= 0 || backoff > p.maxWait {
+		backoff = p.maxWait
+	}
+	// half of the backoff is randomised so concurrent requests do not retry at the same time
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// getRetryAfter returns the wait specified in the Retry-After header of the given response (if any). The header value
+// can either be the number of seconds to wait or an HTTP date
+func getRetryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+	retryAfter := strings.TrimSpace(res.Header.Get(retryAfterHeader))
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isTransientRequestError returns whether the given error is a transient network error (e,g: connection refused,
+// connection reset or timeout) and whether the request might have been sent to the API before the error occurred
+func isTransientRequestError(err error) (transient bool, requestSent bool) {
+	requestErr, ok := err.(*httpRequestError)
+	if !ok {
+		return false, false
+	}
+	cause := requestErr.err
+	if urlErr, ok := cause.(*url.Error); ok {
+		cause = urlErr.Err
+	}
+	if opErr, ok := cause.(*net.OpError); ok && opErr.Op == "dial" {
+		return true, false
+	}
+	if netErr, ok := cause.(net.Error); ok && netErr.Timeout() {
+		return true, true
+	}
+	if cause == io.EOF || cause == io.ErrUnexpectedEOF || strings.Contains(cause.Error(), "connection reset by peer") {
+		return true, true
+	}
+	return false, true
+}
@@ -0,0 +1,272 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	Convey("Given a provider configuration with the retry properties configured", t, func() {
+		providerConfiguration := providerConfiguration{MaxRetries: 5, RetryMaxWait: time.Minute}
+		Convey("When newRetryPolicy is called with an operation that does not define retryable status codes", func() {
+			policy := newRetryPolicy(providerConfiguration, &specResourceOperation{}, "")
+			Convey("Then the retry policy should use the provider configuration and the default retryable status codes", func() {
+				So(policy.maxRetries, ShouldEqual, 5)
+				So(policy.maxWait, ShouldEqual, time.Minute)
+				So(policy.retryableStatusCodes, ShouldResemble, defaultRetryableStatusCodes)
+			})
+		})
+		Convey("When newRetryPolicy is called with an operation that defines retryable status codes", func() {
+			policy := newRetryPolicy(providerConfiguration, &specResourceOperation{RetryableStatusCodes: []int{http.StatusConflict}}, "")
+			Convey("Then the retry policy should use the operation retryable status codes", func() {
+				So(policy.retryableStatusCodes, ShouldResemble, []int{http.StatusConflict})
+			})
+		})
+	})
+	Convey("Given a provider configuration without the retry max wait", t, func() {
+		providerConfiguration := providerConfiguration{}
+		Convey("When newRetryPolicy is called", func() {
+			policy := newRetryPolicy(providerConfiguration, nil, "")
+			Convey("Then the retry policy should be disabled and use the default max wait", func() {
+				So(policy.maxRetries, ShouldEqual, 0)
+				So(policy.maxWait, ShouldEqual, defaultRetryMaxWait)
+			})
+		})
+	})
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	Convey("Given a retry policy with the default retryable status codes", t, func() {
+		policy := retryPolicy{maxRetries: 3, maxWait: time.Second, retryableStatusCodes: defaultRetryableStatusCodes}
+		Convey("When shouldRetry is called with a GET request that returned 503 Service Unavailable", func() {
+			shouldRetry := policy.shouldRetry(httpGet, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+			Convey("Then the request should be retried", func() {
+				So(shouldRetry, ShouldBeTrue)
+			})
+		})
+		Convey("When shouldRetry is called with a GET request that returned 404 Not Found", func() {
+			shouldRetry := policy.shouldRetry(httpGet, &http.Response{StatusCode: http.StatusNotFound}, nil)
+			Convey("Then the request should not be retried", func() {
+				So(shouldRetry, ShouldBeFalse)
+			})
+		})
+		Convey("When shouldRetry is called with a POST request that returned 503 Service Unavailable", func() {
+			shouldRetry := policy.shouldRetry(httpPost, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+			Convey("Then the request should not be retried as it might have been processed", func() {
+				So(shouldRetry, ShouldBeFalse)
+			})
+		})
+		Convey("When shouldRetry is called with a POST request that returned 429 Too Many Requests", func() {
+			shouldRetry := policy.shouldRetry(httpPost, &http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+			Convey("Then the request should be retried", func() {
+				So(shouldRetry, ShouldBeTrue)
+			})
+		})
+		Convey("When shouldRetry is called with a PUT request that failed with a connection reset", func() {
+			shouldRetry := policy.shouldRetry(httpPut, nil, &httpRequestError{message: "request failed", err: errors.New("read tcp: connection reset by peer")})
+			Convey("Then the request should be retried", func() {
+				So(shouldRetry, ShouldBeTrue)
+			})
+		})
+		Convey("When shouldRetry is called with a POST request that failed with a connection reset", func() {
+			shouldRetry := policy.shouldRetry(httpPost, nil, &httpRequestError{message: "request failed", err: errors.New("read tcp: connection reset by peer")})
+			Convey("Then the request should not be retried as it might have been processed", func() {
+				So(shouldRetry, ShouldBeFalse)
+			})
+		})
+		Convey("When shouldRetry is called with a POST request that includes an idempotency key and returned 503 Service Unavailable", func() {
+			idempotentPolicy := policy
+			idempotentPolicy.idempotent = true
+			shouldRetry := idempotentPolicy.shouldRetry(httpPost, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+			Convey("Then the request should be retried as it is safe to do so", func() {
+				So(shouldRetry, ShouldBeTrue)
+			})
+		})
+		Convey("When shouldRetry is called with a request that failed with a non transient error", func() {
+			shouldRetry := policy.shouldRetry(httpGet, nil, errors.New("some error"))
+			Convey("Then the request should not be retried", func() {
+				So(shouldRetry, ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given a retry policy and an API that is not reachable", t, func() {
+		policy := retryPolicy{maxRetries: 3, maxWait: time.Second, retryableStatusCodes: defaultRetryableStatusCodes}
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		api.Close()
+		Convey("When shouldRetry is called with a POST request that failed because the connection was refused", func() {
+			_, err := newHTTPClient(&http.Client{}).PostJson(api.URL, map[string]string{}, nil, nil)
+			shouldRetry := policy.shouldRetry(httpPost, nil, err)
+			Convey("Then the request should be retried as it never reached the API", func() {
+				So(err, ShouldNotBeNil)
+				So(shouldRetry, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestRetryPolicyGetWait(t *testing.T) {
+	Convey("Given a retry policy", t, func() {
+		policy := retryPolicy{maxRetries: 3, maxWait: 10 * time.Second}
+		Convey("When getWait is called with a response containing the Retry-After header in seconds", func() {
+			wait := policy.getWait(0, &http.Response{Header: http.Header{retryAfterHeader: []string{"5"}}})
+			Convey("Then the wait should be the one specified in the header", func() {
+				So(wait, ShouldEqual, 5*time.Second)
+			})
+		})
+		Convey("When getWait is called with a response containing a Retry-After header greater than the max wait", func() {
+			wait := policy.getWait(0, &http.Response{Header: http.Header{retryAfterHeader: []string{"120"}}})
+			Convey("Then the wait should be the max wait", func() {
+				So(wait, ShouldEqual, 10*time.Second)
+			})
+		})
+		Convey("When getWait is called without a response", func() {
+			wait := policy.getWait(2, nil)
+			Convey("Then the wait should be calculated with exponential backoff and jitter", func() {
+				So(wait, ShouldBeBetweenOrEqual, 2*retryMinWait, 4*retryMinWait)
+			})
+		})
+		Convey("When getWait is called for a retry which backoff is greater than the max wait", func() {
+			wait := policy.getWait(10, nil)
+			Convey("Then the wait should not be greater than the max wait", func() {
+				So(wait, ShouldBeBetweenOrEqual, 5*time.Second, 10*time.Second)
+			})
+		})
+	})
+}
+
+func TestPerformRequestWithRetries(t *testing.T) {
+	Convey("Given a providerClient configured with retries and an API that fails twice with 503 Service Unavailable before succeeding", t, func() {
+		retryMinWait = time.Millisecond
+		defer func() { retryMinWait = time.Second }()
+		var requests, idempotencyKeys []string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method)
+			idempotencyKeys = append(idempotencyKeys, r.Header.Get("Idempotency-Key"))
+			if len(requests) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`<html>Service Unavailable</html>`))
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":"someID"}`))
+		}))
+		defer api.Close()
+		providerClient := &ProviderClient{
+			httpClient:            newHTTPClient(&http.Client{}),
+			providerConfiguration: providerConfiguration{MaxRetries: 3},
+			apiAuthenticator:      &specStubAuthenticator{authContext: &authContext{url: api.URL, headers: map[string]string{}}},
+		}
+		Convey("When performRequest is called with a GET request", func() {
+			responsePayload := map[string]interface{}{}
+			res, err := providerClient.performRequest(httpGet, api.URL, &specResourceOperation{}, nil, &responsePayload)
+			Convey("Then the request should be retried until it succeeds", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(requests, ShouldHaveLength, 3)
+				So(responsePayload, ShouldResemble, map[string]interface{}{"id": "someID"})
+			})
+		})
+		Convey("When performRequest is called with a POST request", func() {
+			res, err := providerClient.performRequest(httpPost, api.URL, &specResourceOperation{}, map[string]interface{}{}, nil)
+			Convey("Then the request should not be retried", func() {
+				So(err, ShouldNotBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+				So(requests, ShouldHaveLength, 1)
+			})
+		})
+		Convey("When performRequest is called with a POST request that defines the idempotency key header but the client is not configured with a key", func() {
+			res, err := providerClient.performRequest(httpPost, api.URL, &specResourceOperation{IdempotencyKeyHeader: "Idempotency-Key"}, map[string]interface{}{}, nil)
+			Convey("Then the request should not be retried", func() {
+				So(err, ShouldNotBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+				So(requests, ShouldHaveLength, 1)
+			})
+		})
+		Convey("When performRequest is called with a POST request that includes an idempotency key", func() {
+			responsePayload := map[string]interface{}{}
+			res, err := providerClient.WithIdempotencyKey("some-idempotency-key").(*ProviderClient).performRequest(httpPost, api.URL, &specResourceOperation{IdempotencyKeyHeader: "Idempotency-Key"}, map[string]interface{}{}, &responsePayload)
+			Convey("Then the request should be retried until it succeeds", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(requests, ShouldHaveLength, 3)
+			})
+			Convey("And the same idempotency key should be sent in all the retries", func() {
+				So(idempotencyKeys, ShouldResemble, []string{"some-idempotency-key", "some-idempotency-key", "some-idempotency-key"})
+			})
+		})
+	})
+}
+
+func TestPerformRequestWithSessionLogin(t *testing.T) {
+	Convey("Given a providerClient configured with a session login security definition and an API that rejects the first session", t, func() {
+		var logins, requests int
+		loginServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logins++
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: fmt.Sprintf("session%d", logins)})
+		}))
+		defer loginServer.Close()
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if cookie, err := r.Cookie("session_id"); err != nil || cookie.Value != "session2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":"someID"}`))
+		}))
+		defer api.Close()
+		providerConfiguration := providerConfiguration{SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{}}
+		providerConfiguration.SecuritySchemaDefinitions["session_auth"] = newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, providerConfiguration.getCookieJar())
+		providerClient := &ProviderClient{
+			httpClient:            newHTTPClient(&http.Client{Jar: providerConfiguration.CookieJar}),
+			providerConfiguration: providerConfiguration,
+			apiAuthenticator:      newAPIAuthenticator(&SpecSecuritySchemes{SpecSecurityScheme{Name: "session_auth"}}),
+		}
+		Convey("When performRequest is called with a GET request", func() {
+			responsePayload := map[string]interface{}{}
+			res, err := providerClient.performRequest(httpGet, api.URL, &specResourceOperation{}, nil, &responsePayload)
+			Convey("Then the request should be performed again after logging in again", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(responsePayload, ShouldResemble, map[string]interface{}{"id": "someID"})
+				So(logins, ShouldEqual, 2)
+				So(requests, ShouldEqual, 2)
+			})
+		})
+	})
+	Convey("Given a providerClient configured with a session login security definition and an API that rejects every session", t, func() {
+		var logins, requests int
+		loginServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logins++
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "someSession"})
+		}))
+		defer loginServer.Close()
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer api.Close()
+		providerConfiguration := providerConfiguration{SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{}}
+		providerConfiguration.SecuritySchemaDefinitions["session_auth"] = newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, providerConfiguration.getCookieJar())
+		providerClient := &ProviderClient{
+			httpClient:            newHTTPClient(&http.Client{Jar: providerConfiguration.CookieJar}),
+			providerConfiguration: providerConfiguration,
+			apiAuthenticator:      newAPIAuthenticator(&SpecSecuritySchemes{SpecSecurityScheme{Name: "session_auth"}}),
+		}
+		Convey("When performRequest is called with a GET request", func() {
+			res, err := providerClient.performRequest(httpGet, api.URL, &specResourceOperation{}, nil, nil)
+			Convey("Then the 401 response should be returned after logging in again only once", func() {
+				So(err, ShouldNotBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusUnauthorized)
+				So(logins, ShouldEqual, 2)
+				So(requests, ShouldEqual, 2)
+			})
+		})
+	})
+}
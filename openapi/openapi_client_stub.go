@@ -15,6 +15,24 @@ type clientOpenAPIStub struct {
 	idReceived          string
 	parentIDsReceived   []string
 
+	requestPayloadReceived interface{}
+
+	// responseHeaders are the headers returned in the stub responses (e,g: Location)
+	responseHeaders http.Header
+	// operationPayload is the payload returned by GetOperation and GetLongRunningOperation. If operationPayloads is populated, each GetOperation call
+	// will return the next payload in the list instead
+	operationPayload     map[string]interface{}
+	operationPayloads    []map[string]interface{}
+	operationURLReceived string
+	// operationNameReceived is the name of the long-running operation received by GetLongRunningOperation
+	operationNameReceived string
+	// actionsReceived contains the names of the custom methods received by PerformAction in the order they were called
+	actionsReceived []string
+	// ifMatchReceived is the ETag received by WithIfMatch
+	ifMatchReceived string
+	// idempotencyKeyReceived is the key received by WithIdempotencyKey
+	idempotencyKeyReceived string
+
 	funcPut func() (*http.Response, error)
 }
 
@@ -50,6 +68,22 @@ func (c *clientOpenAPIStub) Put(resource SpecResource, id string, requestPayload
 	return c.generateStubResponse(http.StatusOK), nil
 }
 
+func (c *clientOpenAPIStub) Patch(resource SpecResource, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.idReceived = id
+	c.parentIDsReceived = parentIDs
+	c.requestPayloadReceived = requestPayload
+	switch p := responsePayload.(type) {
+	case *map[string]interface{}:
+		*p = c.responsePayload
+	default:
+		panic("unexpected type")
+	}
+	return c.generateStubResponse(http.StatusOK), nil
+}
+
 func (c *clientOpenAPIStub) Get(resource SpecResource, id string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
 	if c.error != nil {
 		return nil, c.error
@@ -81,6 +115,54 @@ func (c *clientOpenAPIStub) List(resource SpecResource, responsePayload interfac
 	return c.generateStubResponse(http.StatusOK), nil
 }
 
+func (c *clientOpenAPIStub) GetOperation(resource SpecResource, operationURL string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.operationURLReceived = operationURL
+	c.parentIDsReceived = parentIDs
+	operationPayload := c.operationPayload
+	if len(c.operationPayloads) > 0 {
+		operationPayload = c.operationPayloads[0]
+		c.operationPayloads = c.operationPayloads[1:]
+	}
+	switch p := responsePayload.(type) {
+	case *map[string]interface{}:
+		*p = operationPayload
+	default:
+		panic("unexpected type")
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func (c *clientOpenAPIStub) GetLongRunningOperation(resource SpecResource, name string, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.operationNameReceived = name
+	return c.GetOperation(resource, name, responsePayload, parentIDs...)
+}
+
+func (c *clientOpenAPIStub) PerformAction(resource SpecResource, action *specResourceAction, id string, requestPayload interface{}, responsePayload interface{}, parentIDs ...string) (*http.Response, error) {
+	if c.error != nil {
+		return nil, c.error
+	}
+	c.actionsReceived = append(c.actionsReceived, action.Name)
+	c.idReceived = id
+	c.parentIDsReceived = parentIDs
+	c.requestPayloadReceived = requestPayload
+	switch p := responsePayload.(type) {
+	case *map[string]interface{}:
+		*p = c.responsePayload
+	default:
+		panic("unexpected type")
+	}
+	return c.generateStubResponse(http.StatusOK), nil
+}
+
 func (c *clientOpenAPIStub) Delete(resource SpecResource, id string, parentIDs ...string) (*http.Response, error) {
 	if c.error != nil {
 		return nil, c.error
@@ -91,9 +173,20 @@ func (c *clientOpenAPIStub) Delete(resource SpecResource, id string, parentIDs .
 	return c.generateStubResponse(http.StatusNoContent), nil
 }
 
+func (c *clientOpenAPIStub) WithIfMatch(eTag string) ClientOpenAPI {
+	c.ifMatchReceived = eTag
+	return c
+}
+
+func (c *clientOpenAPIStub) WithIdempotencyKey(idempotencyKey string) ClientOpenAPI {
+	c.idempotencyKeyReceived = idempotencyKey
+	return c
+}
+
 func (c *clientOpenAPIStub) generateStubResponse(defaultHTTPCode int) *http.Response {
 	return &http.Response{
 		StatusCode: c.returnCode(defaultHTTPCode),
+		Header:     c.responseHeaders,
 		Body:       ioutil.NopCloser(strings.NewReader("")),
 	}
 }
@@ -131,7 +131,7 @@ func TestGetResourceIDURL(t *testing.T) {
 					},
 				},
 			}
-			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, expectedID)
+			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, expectedID, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -154,7 +154,7 @@ func TestGetResourceIDURL(t *testing.T) {
 					},
 				},
 			}
-			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, expectedID)
+			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, expectedID, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -177,7 +177,7 @@ func TestGetResourceIDURL(t *testing.T) {
 					},
 				},
 			}
-			resourceURL, err := providerClient.getResourceIDURL(r, parentIDs, expectedID)
+			resourceURL, err := providerClient.getResourceIDURL(r, parentIDs, expectedID, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -198,7 +198,7 @@ func TestGetResourceIDURL(t *testing.T) {
 					},
 				},
 			}
-			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, "5678")
+			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, "5678", nil)
 			Convey("Then an error should be returned", func() {
 				So(err.Error(), ShouldEqual, "could not resolve sub-resource path correctly '/v1/resource/{resource_id}/subresource' with the given ids - missing ids to resolve the path params properly: []")
 			})
@@ -216,11 +216,25 @@ func TestGetResourceIDURL(t *testing.T) {
 					},
 				},
 			}
-			_, err := providerClient.getResourceIDURL(r, []string{}, "")
+			_, err := providerClient.getResourceIDURL(r, []string{}, "", nil)
 			Convey("Then the error returned should match the expected one", func() {
 				So(err.Error(), ShouldEqual, "could not build the resourceIDURL: required instance id value is missing")
 			})
 		})
+
+		Convey("When getResourceIDURL is called with a singleton specResource", func() {
+			r := &specStubResource{
+				path:      "/v1/account/settings",
+				singleton: true,
+			}
+			resourceURL, err := providerClient.getResourceIDURL(r, []string{}, "/v1/account/settings", nil)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then the resourceURL returned should be the singleton path without the ID appended", func() {
+				So(resourceURL, ShouldEqual, "http://wwww.host.com/api/v1/account/settings")
+			})
+		})
 	})
 }
 
@@ -271,7 +285,7 @@ func TestGetResourceURL_edge_cases(t *testing.T) {
 						},
 					},
 				}
-				actualResourceURL, err := providerClient.getResourceIDURL(r, tc.parentIDs, tc.id)
+				actualResourceURL, err := providerClient.getResourceIDURL(r, tc.parentIDs, tc.id, nil)
 				if tc.expectedError != "" {
 					Convey("Then the error returned should be the expected one", func() {
 						So(err.Error(), ShouldEqual, tc.expectedError)
@@ -321,7 +335,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -337,7 +351,7 @@ func TestGetResourceURL(t *testing.T) {
 			specStubResource := &specStubResource{
 				funcGetResourcePath: func(parentIDs []string) (string, error) { return "", errors.New("getResourcePath blew up") },
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should not be nil", func() {
 				So(err.Error(), ShouldNotBeNil)
 			})
@@ -359,7 +373,7 @@ func TestGetResourceURL(t *testing.T) {
 					},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{expectedParentID})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{expectedParentID}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -383,7 +397,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -407,7 +421,7 @@ func TestGetResourceURL(t *testing.T) {
 			}
 
 			specStubResource := &specStubResource{}
-			_, err := providerClient.getResourceURL(specStubResource, []string{})
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -427,7 +441,7 @@ func TestGetResourceURL(t *testing.T) {
 					},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			So(resourceURL, ShouldEqual, "")
 			So(err.Error(), ShouldEqual, "getHTTPScheme blew up")
 		})
@@ -441,11 +455,60 @@ func TestGetResourceURL(t *testing.T) {
 				},
 			}
 			specStubResource := &specStubResource{path: "whatever"}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() { So(err, ShouldBeNil) })
 			Convey("And then resourceURL should use http scheme", func() { So(resourceURL, ShouldStartWith, "http://") })
 		})
 
+		Convey("When getResourceURL is called with an operation that specifies its own schemes, host and base path", func() {
+			providerClient := &ProviderClient{
+				openAPIBackendConfiguration: &specStubBackendConfiguration{
+					host:       "wwww.host.com",
+					basePath:   "/api",
+					httpScheme: "https",
+				},
+			}
+			specStubResource := &specStubResource{path: "/v1/resource"}
+			operation := &specResourceOperation{Schemes: []string{"http"}, Host: "www.operation-host.com", BasePath: "/operation-api"}
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, operation)
+			Convey("Then the error returned should be nil", func() { So(err, ShouldBeNil) })
+			Convey("And then resourceURL should use the operation scheme, host and base path", func() {
+				So(resourceURL, ShouldEqual, "http://www.operation-host.com/operation-api/v1/resource")
+			})
+		})
+
+		Convey("When getResourceURL is called with an operation that only overrides the base path", func() {
+			providerClient := &ProviderClient{
+				openAPIBackendConfiguration: &specStubBackendConfiguration{
+					host:       "wwww.host.com",
+					basePath:   "/api",
+					httpScheme: "https",
+				},
+			}
+			specStubResource := &specStubResource{path: "/v1/resource"}
+			operation := &specResourceOperation{BasePath: "/"}
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, operation)
+			Convey("Then the error returned should be nil", func() { So(err, ShouldBeNil) })
+			Convey("And then resourceURL should use the global scheme and host and the operation base path", func() {
+				So(resourceURL, ShouldEqual, "https://wwww.host.com/v1/resource")
+			})
+		})
+
+		Convey("When getResourceURL is called with an operation that specifies non supported schemes", func() {
+			providerClient := &ProviderClient{
+				openAPIBackendConfiguration: &specStubBackendConfiguration{
+					host:       "wwww.host.com",
+					httpScheme: "https",
+				},
+			}
+			specStubResource := &specStubResource{path: "/v1/resource"}
+			operation := &specResourceOperation{Schemes: []string{"ws"}}
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, operation)
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "specified operation schemes [ws] are not supported - must use http or https")
+			})
+		})
+
 		Convey("When getResourceURL with a specResource with a resource path that does not have leading /", func() {
 			expectedPath := "v1/resource"
 			specStubResource := &specStubResource{
@@ -456,7 +519,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -490,7 +553,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -524,7 +587,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -558,7 +621,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -592,7 +655,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -631,7 +694,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -670,7 +733,7 @@ func TestGetResourceURL(t *testing.T) {
 					SecuritySchemes:  SpecSecuritySchemes{},
 				},
 			}
-			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{})
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -699,7 +762,7 @@ func TestGetResourceURL(t *testing.T) {
 		}
 		Convey("When getResourceURL with a specResource with a resource path", func() {
 			specStubResource := &specStubResource{}
-			_, err := providerClient.getResourceURL(specStubResource, []string{})
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -726,7 +789,7 @@ func TestGetResourceURL(t *testing.T) {
 		}
 		Convey("When getResourceURL with a specResource with a resource path", func() {
 			specStubResource := &specStubResource{}
-			_, err := providerClient.getResourceURL(specStubResource, []string{})
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -753,7 +816,7 @@ func TestGetResourceURL(t *testing.T) {
 		}
 		Convey("When getResourceURL with a specResource with a resource path", func() {
 			specStubResource := &specStubResource{}
-			_, err := providerClient.getResourceURL(specStubResource, []string{})
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -779,7 +842,7 @@ func TestGetResourceURL(t *testing.T) {
 		}
 		Convey("When getResourceURL with a specResource with a resource path", func() {
 			specStubResource := &specStubResource{}
-			_, err := providerClient.getResourceURL(specStubResource, []string{})
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -788,6 +851,102 @@ func TestGetResourceURL(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a providerClient set up with a backend configuration that contains host variables", t, func() {
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: &specStubBackendConfiguration{
+				host:       "${cell}.api.${environment}.host.com",
+				basePath:   "/${environment}",
+				httpScheme: "http",
+				hostVariables: specHostVariables{
+					{name: "environment", defaultValue: "prod", allowedValues: []string{"prod", "dev"}},
+					{name: "cell", defaultValue: "cell1"},
+				},
+			},
+			httpClient: &http_goclient.HttpClientStub{},
+			providerConfiguration: providerConfiguration{
+				HostVariables: map[string]string{"environment": "dev"},
+			},
+			apiAuthenticator: &specStubAuthenticator{},
+		}
+		Convey("When getResourceURL is called with a specResource", func() {
+			specStubResource := &specStubResource{path: "/v1/resource"}
+			resourceURL, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the resourceURL should contain the values provided by the user or the default ones otherwise", func() {
+				So(resourceURL, ShouldEqual, "http://cell1.api.dev.host.com/dev/v1/resource")
+			})
+		})
+	})
+
+	Convey("Given a providerClient set up with a backend configuration that fails to return the host variables", t, func() {
+		expectedError := "some error thrown by getHostVariables method"
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: &specStubBackendConfiguration{
+				host:             "wwww.host.com",
+				httpScheme:       "http",
+				hostVariablesErr: fmt.Errorf(expectedError),
+			},
+			httpClient:            &http_goclient.HttpClientStub{},
+			providerConfiguration: providerConfiguration{},
+			apiAuthenticator:      &specStubAuthenticator{},
+		}
+		Convey("When getResourceURL is called with a specResource", func() {
+			specStubResource := &specStubResource{path: "/v1/resource"}
+			_, err := providerClient.getResourceURL(specStubResource, []string{}, nil)
+			Convey("Then the error returned should match the expected", func() {
+				So(err.Error(), ShouldEqual, expectedError)
+			})
+		})
+	})
+}
+
+func TestGetServiceHosts(t *testing.T) {
+	Convey("Given a providerClient set up with a backend configuration that contains host variables and a provider configuration with endpoint overrides", t, func() {
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: &specStubBackendConfiguration{
+				host:       "api.${environment}.host.com",
+				httpScheme: "http",
+				hostVariables: specHostVariables{
+					{name: "environment", defaultValue: "prod"},
+				},
+			},
+			providerConfiguration: providerConfiguration{
+				HostVariables: map[string]string{"environment": "dev"},
+				Endpoints:     map[string]string{"cdn_v1": "cdn.${environment}.host.com", "lb_v1": ""},
+			},
+		}
+		Convey("When getServiceHosts is called", func() {
+			serviceHosts, err := providerClient.getServiceHosts()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the service hosts should contain the global host and the endpoint overrides with the host variables resolved", func() {
+				So(serviceHosts, ShouldResemble, []string{"api.dev.host.com", "cdn.dev.host.com"})
+			})
+		})
+	})
+
+	Convey("Given a providerClient set up with a multi-region backend configuration", t, func() {
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: &specStubBackendConfiguration{
+				host:    "api.%s.host.com",
+				regions: []string{"rst1", "dub1"},
+			},
+			providerConfiguration: providerConfiguration{
+				Region: "dub1",
+			},
+		}
+		Convey("When getServiceHosts is called", func() {
+			serviceHosts, err := providerClient.getServiceHosts()
+			Convey("Then the service hosts should contain the host of the region selected", func() {
+				So(err, ShouldBeNil)
+				So(serviceHosts, ShouldResemble, []string{"api.dub1.host.com"})
+			})
+		})
+	})
 }
 
 func TestPerformRequest(t *testing.T) {
@@ -859,6 +1018,80 @@ func TestPerformRequest(t *testing.T) {
 				So(httpClient.In.(map[string]interface{}), ShouldContainKey, expectedReqPayloadProperty1)
 				So(httpClient.In.(map[string]interface{})[expectedReqPayloadProperty1], ShouldEqual, expectedReqPayloadProperty1Value)
 			})
+			Convey("And then the authenticator should have received the request method and the request body as sent to the API", func() {
+				So(apiAuthenticator.authContext.method, ShouldEqual, httpPost)
+				So(string(apiAuthenticator.authContext.body), ShouldEqual, `{"property1":"someValue"}`)
+			})
+		})
+		Convey("When performRequest POST method is called with a client configured with an idempotency key and an operation that has the idempotency key header configured", func() {
+			resourcePostOperation := &specResourceOperation{
+				HeaderParameters:     SpecHeaderParameters{},
+				SecuritySchemes:      SpecSecuritySchemes{},
+				IdempotencyKeyHeader: "Idempotency-Key",
+			}
+			idempotentClient := providerClient.WithIdempotencyKey("some-idempotency-key").(*ProviderClient)
+			_, err := idempotentClient.performRequest(httpPost, "http://wwww.host.com/api/v1/resource", resourcePostOperation, map[string]interface{}{"label": "some label"}, map[string]interface{}{})
+			Convey("Then the idempotency key should be sent in the configured header", func() {
+				So(err, ShouldBeNil)
+				So(httpClient.Headers["Idempotency-Key"], ShouldEqual, "some-idempotency-key")
+			})
+			Convey("And the original client should not be configured with the idempotency key", func() {
+				So(providerClient.idempotencyKey, ShouldBeEmpty)
+			})
+		})
+		Convey("When performRequest POST method is called with a client that is not configured with an idempotency key", func() {
+			resourcePostOperation := &specResourceOperation{
+				HeaderParameters:     SpecHeaderParameters{},
+				SecuritySchemes:      SpecSecuritySchemes{},
+				IdempotencyKeyHeader: "Idempotency-Key",
+			}
+			_, err := providerClient.performRequest(httpPost, "http://wwww.host.com/api/v1/resource", resourcePostOperation, map[string]interface{}{"label": "some label"}, map[string]interface{}{})
+			Convey("Then the idempotency key header should not be sent", func() {
+				So(err, ShouldBeNil)
+				So(httpClient.Headers, ShouldNotContainKey, "Idempotency-Key")
+			})
+		})
+		Convey("When performRequest method is called with a client configured with static service headers", func() {
+			resourceOperation := &specResourceOperation{
+				HeaderParameters: SpecHeaderParameters{headerParameter},
+				SecuritySchemes:  SpecSecuritySchemes{},
+			}
+			providerClient.headers = map[string]string{
+				"X-Team":             "infra",
+				expectedHeader:       "someOtherValue",
+				headerParameter.Name: "someOtherValue",
+			}
+			_, err := providerClient.performRequest(httpGet, "http://wwww.host.com/api/v1/resource/id", resourceOperation, nil, map[string]interface{}{})
+			Convey("Then the static service headers should be sent along with the request", func() {
+				So(err, ShouldBeNil)
+				So(httpClient.Headers["X-Team"], ShouldEqual, "infra")
+			})
+			Convey("And the static service headers should not override the authentication and operation headers", func() {
+				So(httpClient.Headers[expectedHeader], ShouldEqual, expectedHeaderValue)
+				So(httpClient.Headers[headerParameter.Name], ShouldEqual, providerConfiguration.Headers[headerParameter.TerraformName])
+			})
+		})
+		Convey("When performRequest PUT and GET methods are called with a client configured with an ETag", func() {
+			resourceOperation := &specResourceOperation{
+				HeaderParameters: SpecHeaderParameters{},
+				SecuritySchemes:  SpecSecuritySchemes{},
+				IfMatch:          true,
+			}
+			ifMatchClient := providerClient.WithIfMatch(`"v1"`).(*ProviderClient)
+			_, err := ifMatchClient.performRequest(httpPut, "http://wwww.host.com/api/v1/resource/id", resourceOperation, map[string]interface{}{}, map[string]interface{}{})
+			putHeaders := httpClient.Headers
+			// the stub authenticator returns the same headers map for every request, so a new one is used for the GET request
+			ifMatchClient.apiAuthenticator = newStubAuthenticator(expectedHeader, expectedHeaderValue, nil)
+			_, err2 := ifMatchClient.performRequest(httpGet, "http://wwww.host.com/api/v1/resource/id", resourceOperation, nil, map[string]interface{}{})
+			Convey("Then the If-Match header should only be sent in the PUT request", func() {
+				So(err, ShouldBeNil)
+				So(err2, ShouldBeNil)
+				So(putHeaders[ifMatchHeader], ShouldEqual, `"v1"`)
+				So(httpClient.Headers, ShouldNotContainKey, ifMatchHeader)
+			})
+			Convey("And the original client should not be configured with the ETag", func() {
+				So(providerClient.ifMatch, ShouldBeEmpty)
+			})
 		})
 		Convey("When performRequest with a method that is not supported", func() {
 			resourcePostOperation := &specResourceOperation{
@@ -1166,6 +1399,71 @@ func TestProviderClientPut(t *testing.T) {
 
 }
 
+func TestProviderClientPatch(t *testing.T) {
+	Convey("Given a providerClient set up with stub auth and an http client that supports PATCH requests", t, func() {
+		httpClient := &httpClientStub{}
+		expectedHeader := "Authentication"
+		expectedHeaderValue := "Bearer secret!"
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+			httpClient:                  httpClient,
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator(expectedHeader, expectedHeaderValue, nil),
+		}
+		requestPayload := map[string]interface{}{"property1": "someValue"}
+		Convey("When providerClient PATCH method is called with a specStubResource with a PATCH operation that does not specify the patch content type", func() {
+			specStubResource := &specStubResource{
+				path:                   "/v1/resource",
+				resourcePatchOperation: &specResourceOperation{},
+			}
+			_, err := providerClient.Patch(specStubResource, "1234", requestPayload, map[string]interface{}{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then client should have received the right URL", func() {
+				So(httpClient.URL, ShouldEqual, "http://wwww.host.com/api/v1/resource/1234")
+			})
+			Convey("And then client should have received the right Authentication header and the JSON Merge Patch content type", func() {
+				So(httpClient.Headers[expectedHeader], ShouldEqual, expectedHeaderValue)
+				So(httpClient.Headers[contentTypeHeader], ShouldEqual, mergePatchContentType)
+			})
+			Convey("And then client should have received the right request payload", func() {
+				So(httpClient.In, ShouldResemble, requestPayload)
+			})
+		})
+		Convey("When providerClient PATCH method is called with a specStubResource with a PATCH operation configured with the JSON Patch content type", func() {
+			specStubResource := &specStubResource{
+				path:                   "/v1/resource",
+				resourcePatchOperation: &specResourceOperation{PatchContentType: jsonPatchContentType},
+			}
+			_, err := providerClient.Patch(specStubResource, "1234", requestPayload, map[string]interface{}{})
+			Convey("Then the client should have received the JSON Patch content type", func() {
+				So(err, ShouldBeNil)
+				So(httpClient.Headers[contentTypeHeader], ShouldEqual, jsonPatchContentType)
+			})
+		})
+	})
+
+	Convey("Given a providerClient set up with an http client that does not support PATCH requests", t, func() {
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+			httpClient:                  &http_goclient.HttpClientStub{},
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator("Authentication", "Bearer secret!", nil),
+		}
+		Convey("When providerClient PATCH method is called", func() {
+			specStubResource := &specStubResource{
+				path:                   "/v1/resource",
+				resourcePatchOperation: &specResourceOperation{},
+			}
+			_, err := providerClient.Patch(specStubResource, "1234", map[string]interface{}{}, map[string]interface{}{})
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "http client does not support method 'PATCH'")
+			})
+		})
+	})
+}
+
 func TestProviderClientGet(t *testing.T) {
 
 	Convey("Given a providerClient set up with stub client that returns some response", t, func() {
@@ -1285,6 +1583,138 @@ func TestProviderClientGet(t *testing.T) {
 
 }
 
+func TestProviderClientGetOperation(t *testing.T) {
+	Convey("Given a providerClient set up with stub auth and a stub client that returns some response", t, func() {
+		httpClient := &http_goclient.HttpClientStub{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"status":"running"}`)),
+			},
+		}
+		expectedHeader := "Authentication"
+		expectedHeaderValue := "Bearer secret!"
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+			httpClient:                  httpClient,
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator(expectedHeader, expectedHeaderValue, nil),
+		}
+		specStubResource := &specStubResource{
+			path:                 "/v1/resource",
+			resourceGetOperation: &specResourceOperation{},
+		}
+		Convey("When providerClient GetOperation method is called with a relative operation URL", func() {
+			_, err := providerClient.GetOperation(specStubResource, "/api/v1/operations/op1", map[string]interface{}{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then client should have received the operation URL resolved against the resource URL", func() {
+				So(httpClient.URL, ShouldEqual, "http://wwww.host.com/api/v1/operations/op1")
+			})
+			Convey("And then client should have received the right Authentication header and expected value", func() {
+				So(httpClient.Headers[expectedHeader], ShouldEqual, expectedHeaderValue)
+			})
+		})
+		Convey("When providerClient GetOperation method is called with an absolute operation URL", func() {
+			_, err := providerClient.GetOperation(specStubResource, "https://operations.host.com/op1", map[string]interface{}{})
+			Convey("Then the client should have received the operation URL as is", func() {
+				So(err, ShouldBeNil)
+				So(httpClient.URL, ShouldEqual, "https://operations.host.com/op1")
+			})
+		})
+	})
+}
+
+func TestProviderClientGetLongRunningOperation(t *testing.T) {
+	Convey("Given a providerClient set up with stub auth and a stub client that returns some response", t, func() {
+		httpClient := &http_goclient.HttpClientStub{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name":"operations/op1","done":false}`)),
+			},
+		}
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+			httpClient:                  httpClient,
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator("Authentication", "Bearer secret!", nil),
+		}
+		specStubResource := &specStubResource{
+			path:                 "/v1/resource",
+			resourceGetOperation: &specResourceOperation{},
+		}
+		testCases := []struct {
+			name                 string
+			operationName        string
+			expectedOperationURL string
+		}{
+			{name: "operation name including the operations collection", operationName: "operations/op1", expectedOperationURL: "http://wwww.host.com/api/operations/op1"},
+			{name: "operation name without the operations collection", operationName: "op1", expectedOperationURL: "http://wwww.host.com/api/operations/op1"},
+			{name: "operation name nested in a parent resource", operationName: "projects/p1/operations/op1", expectedOperationURL: "http://wwww.host.com/api/projects/p1/operations/op1"},
+		}
+		for _, tc := range testCases {
+			Convey(fmt.Sprintf("When providerClient GetLongRunningOperation method is called with an %s", tc.name), func() {
+				responsePayload := map[string]interface{}{}
+				_, err := providerClient.GetLongRunningOperation(specStubResource, tc.operationName, &responsePayload)
+				Convey("Then the client should have received the expected operation URL", func() {
+					So(err, ShouldBeNil)
+					So(httpClient.URL, ShouldEqual, tc.expectedOperationURL)
+				})
+			})
+		}
+		Convey("When providerClient GetLongRunningOperation method is called with an empty operation name", func() {
+			_, err := providerClient.GetLongRunningOperation(specStubResource, "", map[string]interface{}{})
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "could not build the long-running operation URL: required operation name value is missing")
+			})
+		})
+	})
+}
+
+func TestProviderClientPerformAction(t *testing.T) {
+	Convey("Given a providerClient set up with stub auth and a stub client that returns some response", t, func() {
+		httpClient := &http_goclient.HttpClientStub{
+			Response: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"property1":"value1"}`)),
+			},
+		}
+		expectedHeader := "Authentication"
+		expectedHeaderValue := "Bearer secret!"
+		providerClient := &ProviderClient{
+			openAPIBackendConfiguration: newStubBackendConfiguration("wwww.host.com", "/api", "http"),
+			httpClient:                  httpClient,
+			providerConfiguration:       providerConfiguration{},
+			apiAuthenticator:            newStubAuthenticator(expectedHeader, expectedHeaderValue, nil),
+		}
+		Convey("When providerClient PerformAction method is called with a resource action", func() {
+			specStubResource := &specStubResource{path: "/v1/certs"}
+			action := &specResourceAction{Name: "rotate", TriggerAttribute: "rotation_trigger", Operation: &specResourceOperation{}}
+			requestPayload := map[string]interface{}{}
+			_, err := providerClient.PerformAction(specStubResource, action, "1234", requestPayload, &map[string]interface{}{})
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then client should have received the custom method URL", func() {
+				So(httpClient.URL, ShouldEqual, "http://wwww.host.com/api/v1/certs/1234:rotate")
+			})
+			Convey("And then client should have received the right Authentication header and the request payload", func() {
+				So(httpClient.Headers[expectedHeader], ShouldEqual, expectedHeaderValue)
+				So(httpClient.In, ShouldResemble, requestPayload)
+			})
+		})
+		Convey("When providerClient PerformAction method is called with a singleton resource action", func() {
+			specStubResource := &specStubResource{path: "/v1/account/settings", singleton: true}
+			action := &specResourceAction{Name: "reset", TriggerAttribute: "reset_trigger", Operation: &specResourceOperation{}}
+			_, err := providerClient.PerformAction(specStubResource, action, "/v1/account/settings", map[string]interface{}{}, &map[string]interface{}{})
+			Convey("Then the client should have received the custom method URL of the singleton path", func() {
+				So(err, ShouldBeNil)
+				So(httpClient.URL, ShouldEqual, "http://wwww.host.com/api/v1/account/settings:reset")
+			})
+		})
+	})
+}
+
 func TestProviderClientList(t *testing.T) {
 	Convey("Given a providerClient set up with stub client that returns some response", t, func() {
 		httpClient := &http_goclient.HttpClientStub{
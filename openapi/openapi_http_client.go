@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/dikhan/http_goclient"
+)
+
+// httpPatchClientIface defines the behaviour expected from http clients that support PATCH requests. The
+// http_goclient.HttpClientIface does not support PATCH requests, hence http clients used by the ProviderClient are
+// expected to implement this interface too in order to be able to update resources via PATCH
+type httpPatchClientIface interface {
+	Patch(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error)
+}
+
+// httpClient extends the http_goclient.HttpClient adding support for PATCH requests, for asynchronous responses
+// (202 Accepted) that do not contain a body and for transport errors that can be inspected (see httpRequestError)
+type httpClient struct {
+	*http_goclient.HttpClient
+}
+
+// newHTTPClient returns an httpClient that uses the given http.Client to perform the requests
+func newHTTPClient(client *http.Client) *httpClient {
+	return &httpClient{HttpClient: &http_goclient.HttpClient{HttpClient: client}}
+}
+
+// Get issues a GET HTTP request to the specified URL including the headers passed in. As opposed to the
+// http_goclient.HttpClient implementation, the response is returned along with the error if the response body can not
+// be un-marshalled so the caller is still able to check the response status code
+func (c *httpClient) Get(url string, headers map[string]string, out interface{}) (*http.Response, error) {
+	return c.performRequest(http.MethodGet, url, headers, nil, out, false)
+}
+
+// Delete issues a DELETE HTTP request to the specified URL including the headers passed in
+func (c *httpClient) Delete(url string, headers map[string]string) (*http.Response, error) {
+	return c.performRequest(http.MethodDelete, url, headers, nil, nil, false)
+}
+
+// PostJson issues a POST HTTP request to the specified URL including the headers passed in. The content type of the body
+// is set to application/json. As opposed to the http_goclient.HttpClient implementation, 202 Accepted responses are
+// allowed to have an empty body (e,g: when the API returns the Location of the operation resource instead)
+func (c *httpClient) PostJson(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error) {
+	return c.performJSONRequest(http.MethodPost, url, headers, in, out, false)
+}
+
+// PutJson issues a PUT HTTP request to the specified URL including the headers passed in. The content type of the body
+// is set to application/json. As opposed to the http_goclient.HttpClient implementation, 202 Accepted responses are
+// allowed to have an empty body (e,g: when the API returns the Location of the operation resource instead)
+func (c *httpClient) PutJson(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error) {
+	return c.performJSONRequest(http.MethodPut, url, headers, in, out, false)
+}
+
+// Patch issues a PATCH HTTP request to the specified URL including the headers passed in. The Content-Type header is
+// expected to be part of the headers passed in (e,g: application/merge-patch+json).
+//
+// The 'in' param interface is marshall and added to the http request body.
+// The 'out' param interface is the un-marshall representation of the http response returned. As opposed to the other
+// operations, PATCH responses are allowed to have an empty body (e,g: 204 No Content)
+func (c *httpClient) Patch(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error) {
+	return c.performRequest(http.MethodPatch, url, headers, in, out, true)
+}
+
+func (c *httpClient) performJSONRequest(method, url string, headers map[string]string, in interface{}, out interface{}, allowEmptyResponseBody bool) (*http.Response, error) {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headers[contentTypeHeader] = "application/json"
+	return c.performRequest(method, url, headers, in, out, allowEmptyResponseBody)
+}
+
+// httpRequestError is returned when the HTTP request could not be performed (e,g: the connection was refused or reset)
+// and keeps the underlying error so the caller can decide whether the request can be retried
+type httpRequestError struct {
+	message string
+	err     error
+}
+
+func (e *httpRequestError) Error() string {
+	return e.message
+}
+
+// performRequest issues the HTTP request and un-marshals the response body into 'out' (if not nil). An empty response
+// body is considered an error unless allowEmptyResponseBody is true or the response status code is 202 Accepted or 204
+// No Content. If the response body can not be un-marshalled, the response is returned along with the error so the caller
+// is still able to check the response status code
+func (c *httpClient) performRequest(method, url string, headers map[string]string, in interface{}, out interface{}, allowEmptyResponseBody bool) (*http.Response, error) {
+	var body []byte
+	var err error
+	if in != nil {
+		body, err = json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := c.HttpClient.HttpClient.Do(req)
+	if err != nil {
+		return nil, &httpRequestError{
+			message: fmt.Sprintf("request %s %s %s failed. Response Error: '%s'", req.Method, req.URL, req.Proto, err.Error()),
+			err:     err,
+		}
+	}
+	if out != nil {
+		responseBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		// a new reader is set so the caller is still able to read the response body afterwards
+		resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+		if len(responseBody) > 0 {
+			if err = json.Unmarshal(responseBody, &out); err != nil {
+				return resp, fmt.Errorf("unable to unmarshal response body ['%s'] for request = '%s %s %s'. Response = '%s'", err.Error(), req.Method, req.URL, req.Proto, resp.Status)
+			}
+		} else if !allowEmptyResponseBody && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+			return resp, fmt.Errorf("expected a response body but response body received was empty for request = '%s %s %s'. Response = '%s'", req.Method, req.URL, req.Proto, resp.Status)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,21 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/dikhan/http_goclient"
+)
+
+// httpClientStub is a stub implementation of the http_goclient.HttpClientIface that also supports PATCH requests
+// (httpPatchClientIface) and should be used for testing purposes
+type httpClientStub struct {
+	http_goclient.HttpClientStub
+}
+
+func (c *httpClientStub) Patch(url string, headers map[string]string, in interface{}, out interface{}) (*http.Response, error) {
+	c.URL = url
+	c.Headers = headers
+	c.In = in
+	c.Out = out
+	return c.Response, c.Error
+}
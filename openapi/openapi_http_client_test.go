@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHTTPClientPatch(t *testing.T) {
+	Convey("Given an httpClient and an API that supports PATCH requests", t, func() {
+		var methodReceived, contentTypeReceived, bodyReceived string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methodReceived = r.Method
+			contentTypeReceived = r.Header.Get(contentTypeHeader)
+			body, _ := ioutil.ReadAll(r.Body)
+			bodyReceived = string(body)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"name":"updatedName"}`))
+		}))
+		defer api.Close()
+		client := newHTTPClient(&http.Client{})
+		var _ httpPatchClientIface = client
+		Convey("When Patch method is called with a request payload and the merge patch content type", func() {
+			out := map[string]interface{}{}
+			headers := map[string]string{contentTypeHeader: mergePatchContentType}
+			res, err := client.Patch(api.URL, headers, map[string]interface{}{"name": "updatedName"}, &out)
+			Convey("Then the request sent should be a PATCH request containing the payload and content type", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(methodReceived, ShouldEqual, http.MethodPatch)
+				So(contentTypeReceived, ShouldEqual, mergePatchContentType)
+				So(bodyReceived, ShouldEqual, `{"name":"updatedName"}`)
+			})
+			Convey("And the response payload should be unmarshalled into out", func() {
+				So(out["name"], ShouldEqual, "updatedName")
+			})
+		})
+	})
+	Convey("Given an httpClient and an API that returns no content for PATCH requests", t, func() {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer api.Close()
+		client := newHTTPClient(&http.Client{})
+		Convey("When Patch method is called", func() {
+			out := map[string]interface{}{}
+			res, err := client.Patch(api.URL, map[string]string{}, map[string]interface{}{"name": "updatedName"}, &out)
+			Convey("Then the error returned should be nil and the out payload should be empty", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusNoContent)
+				So(out, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given an httpClient and an API that returns a non JSON response", t, func() {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`not json`))
+		}))
+		defer api.Close()
+		client := newHTTPClient(&http.Client{})
+		Convey("When Patch method is called", func() {
+			out := map[string]interface{}{}
+			_, err := client.Patch(api.URL, map[string]string{}, nil, &out)
+			Convey("Then the error returned should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestHTTPClientPostJson(t *testing.T) {
+	Convey("Given an httpClient and an API that handles POST requests asynchronously returning 202 Accepted with no body", t, func() {
+		var methodReceived, contentTypeReceived string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			methodReceived = r.Method
+			contentTypeReceived = r.Header.Get(contentTypeHeader)
+			w.Header().Set(operationLocationHeader, "/v1/operations/1234")
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer api.Close()
+		client := newHTTPClient(&http.Client{})
+		Convey("When PostJson method is called", func() {
+			out := map[string]interface{}{}
+			res, err := client.PostJson(api.URL, map[string]string{}, map[string]interface{}{"name": "someName"}, &out)
+			Convey("Then the error returned should be nil and the response should contain the operation location", func() {
+				So(err, ShouldBeNil)
+				So(methodReceived, ShouldEqual, http.MethodPost)
+				So(contentTypeReceived, ShouldEqual, "application/json")
+				So(res.StatusCode, ShouldEqual, http.StatusAccepted)
+				So(res.Header.Get(operationLocationHeader), ShouldEqual, "/v1/operations/1234")
+				So(out, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given an httpClient and an API that returns 201 Created with no body", t, func() {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer api.Close()
+		client := newHTTPClient(&http.Client{})
+		Convey("When PutJson method is called", func() {
+			out := map[string]interface{}{}
+			_, err := client.PutJson(api.URL, map[string]string{}, map[string]interface{}{"name": "someName"}, &out)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "expected a response body but response body received was empty")
+			})
+		})
+	})
+}
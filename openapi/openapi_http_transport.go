@@ -0,0 +1,273 @@
+package openapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// newServiceHTTPClient returns the http client used to call the service API configured as per the service configuration
+// (TLS, proxy and request timeout). The jar (if any) contains the cookies sent along with the API requests
+func newServiceHTTPClient(serviceConfiguration ServiceConfiguration, jar http.CookieJar) (*http.Client, error) {
+	client := &http.Client{Jar: jar}
+	if serviceConfiguration == nil {
+		return client, nil
+	}
+	transport, err := newServiceTransport(serviceConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	client.Transport = transport
+	if serviceConfigurationV2, ok := serviceConfiguration.(ServiceConfigurationV2); ok {
+		client.Timeout = serviceConfigurationV2.GetRequestTimeout()
+	}
+	return client, nil
+}
+
+// swaggerRequestTimeout defines the max time to wait for the swagger file to be retrieved if the service does not have
+// a request timeout configured
+const swaggerRequestTimeout = 30 * time.Second
+
+// newSwaggerHTTPClient returns the http client used to retrieve the swagger file. On top of the service http client
+// configuration, the swagger auth headers (if configured) are sent along with the requests made to the swagger file host.
+// If the service does not have a request timeout configured, the swagger file is given swaggerRequestTimeout to be retrieved
+func newSwaggerHTTPClient(serviceConfiguration ServiceConfiguration) (*http.Client, error) {
+	client, err := newServiceHTTPClient(serviceConfiguration, nil)
+	if err != nil {
+		return nil, err
+	}
+	if client.Timeout == 0 {
+		client.Timeout = swaggerRequestTimeout
+	}
+	swaggerAuthServiceConfiguration, ok := serviceConfiguration.(ServiceSwaggerAuthConfiguration)
+	if !ok {
+		return client, nil
+	}
+	swaggerAuthConfiguration := swaggerAuthServiceConfiguration.GetSwaggerAuthConfiguration()
+	if swaggerAuthConfiguration == nil || !isURL(serviceConfiguration.GetSwaggerURL()) {
+		return client, nil
+	}
+	headers, err := swaggerAuthConfiguration.getHeaders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the swagger auth credentials: %s", err)
+	}
+	swaggerURL, err := url.Parse(serviceConfiguration.GetSwaggerURL())
+	if err != nil {
+		return nil, fmt.Errorf("service swagger URL '%s' is not valid: %s", serviceConfiguration.GetSwaggerURL(), err)
+	}
+	client.Transport = swaggerAuthTransport{
+		host:      swaggerURL.Host,
+		headers:   headers,
+		transport: client.Transport,
+	}
+	return client, nil
+}
+
+// swaggerAuthTransport adds the swagger auth headers to the requests made to the swagger file host. Requests made to
+// other hosts (e,g: remote references in the swagger file) are sent as is, so the credentials are not leaked
+type swaggerAuthTransport struct {
+	host      string
+	headers   map[string]string
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request with the swagger auth headers if the request is made to the swagger file host. As per the
+// http.RoundTripper contract the given request is not modified, the headers are added to a copy of it
+func (t swaggerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if req.URL.Host != t.host || len(t.headers) == 0 {
+		return transport.RoundTrip(req)
+	}
+	authReq := new(http.Request)
+	*authReq = *req
+	authReq.Header = make(http.Header, len(req.Header)+len(t.headers))
+	for name, values := range req.Header {
+		authReq.Header[name] = append([]string(nil), values...)
+	}
+	for name, value := range t.headers {
+		authReq.Header.Set(name, value)
+	}
+	return transport.RoundTrip(authReq)
+}
+
+// newServiceTransport returns an http transport with the same settings as the http.DefaultTransport, configured with the
+// service TLS and proxy configuration. If the service does not have a proxy configured, the proxy is read from the
+// environment variables (HTTP_PROXY, HTTPS_PROXY and NO_PROXY)
+func newServiceTransport(serviceConfiguration ServiceConfiguration) (*http.Transport, error) {
+	tlsConfig, err := newServiceTLSConfig(serviceConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	proxy := http.ProxyFromEnvironment
+	if proxyURL := getServiceProxyURL(serviceConfiguration); proxyURL != "" {
+		parsedProxyURL, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("service proxy '%s' is not a valid URL: %s", proxyURL, err)
+		}
+		proxy = http.ProxyURL(parsedProxyURL)
+	}
+	return newTransport(proxy, tlsConfig), nil
+}
+
+// newTransport returns an http transport with the same settings as the http.DefaultTransport, using the given proxy and
+// TLS configuration
+func newTransport(proxy func(*http.Request) (*url.URL, error), tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+}
+
+// getServiceProxyURL returns the proxy URL configured in the service configuration; empty string is returned if not
+// configured or not supported by the service configuration
+func getServiceProxyURL(serviceConfiguration ServiceConfiguration) string {
+	if serviceConfigurationV2, ok := serviceConfiguration.(ServiceConfigurationV2); ok {
+		return serviceConfigurationV2.GetProxyURL()
+	}
+	return ""
+}
+
+// newServiceTLSConfig returns the TLS configuration used to connect to the service API:
+// - the CA bundle certificates (if configured) are trusted on top of the system ones
+// - the client certificate (if configured) is presented to the service API (mutual TLS)
+// - connections using a TLS version lower than the min version (if configured) are rejected
+// - connections to servers whose certificate chain does not contain any of the pinned public keys (if configured) are rejected
+func newServiceTLSConfig(serviceConfiguration ServiceConfiguration) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: serviceConfiguration.IsInsecureSkipVerifyEnabled(),
+	}
+	serviceConfigurationV2, ok := serviceConfiguration.(ServiceConfigurationV2)
+	if !ok {
+		return tlsConfig, nil
+	}
+	tlsConfiguration := serviceConfigurationV2.GetTLSConfiguration()
+	if tlsConfiguration == nil {
+		return tlsConfig, nil
+	}
+	if tlsConfiguration.CABundle != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		caBundle, err := ioutil.ReadFile(tlsConfiguration.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the service tls ca_bundle '%s': %s", tlsConfiguration.CABundle, err)
+		}
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("service tls ca_bundle '%s' does not contain any PEM encoded certificate", tlsConfiguration.CABundle)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if tlsConfiguration.ClientCert != "" {
+		clientCert, err := tls.LoadX509KeyPair(tlsConfiguration.ClientCert, tlsConfiguration.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the service tls client_cert '%s' and client_key '%s': %s", tlsConfiguration.ClientCert, tlsConfiguration.ClientKey, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	minVersion, err := tlsConfiguration.getMinVersion()
+	if err != nil {
+		return nil, fmt.Errorf("service tls configuration not valid: %s", err)
+	}
+	tlsConfig.MinVersion = minVersion
+	pins, err := tlsConfiguration.getPinnedPublicKeys()
+	if err != nil {
+		return nil, fmt.Errorf("service tls configuration not valid: %s", err)
+	}
+	if len(pins) > 0 {
+		tlsConfig.VerifyPeerCertificate = verifyPinnedPublicKeys(pins)
+	}
+	return tlsConfig, nil
+}
+
+// verifyPinnedPublicKeys returns a function that verifies that at least one of the certificates of the server chain has
+// one of the pinned public keys. The verified chains are checked if the server certificate has been verified; otherwise
+// (insecure skip verify enabled) the certificates presented by the server are checked
+func verifyPinnedPublicKeys(pins [][]byte) func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		var certificates []*x509.Certificate
+		for _, chain := range verifiedChains {
+			certificates = append(certificates, chain...)
+		}
+		if len(verifiedChains) == 0 {
+			for _, rawCert := range rawCerts {
+				certificate, err := x509.ParseCertificate(rawCert)
+				if err != nil {
+					return err
+				}
+				certificates = append(certificates, certificate)
+			}
+		}
+		for _, certificate := range certificates {
+			publicKeyHash := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+			for _, pin := range pins {
+				if bytes.Equal(publicKeyHash[:], pin) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("service API certificate chain does not contain any of the pinned public keys")
+	}
+}
+
+// transportConfigurableAuthenticator is implemented by the authenticators that send requests themselves (e,g: to login
+// or token URLs) so these requests are also sent using the service transport configuration
+type transportConfigurableAuthenticator interface {
+	setTransport(transport http.RoundTripper)
+}
+
+// newAuthenticatorsTransport returns the transport used by the authenticators that send requests themselves. The requests
+// made to the service hosts are sent using the service transport, whereas the requests made to any other host (e,g: an
+// identity provider token URL) are sent using the same proxy and TLS configuration except for the client certificate and
+// the pinned public keys, as these only apply to the service API
+func newAuthenticatorsTransport(serviceTransport *http.Transport, serviceHosts []string) http.RoundTripper {
+	var tlsConfig *tls.Config
+	if serviceTransport.TLSClientConfig != nil {
+		tlsConfig = serviceTransport.TLSClientConfig.Clone()
+		tlsConfig.Certificates = nil
+		tlsConfig.VerifyPeerCertificate = nil
+	}
+	return serviceHostTransport{
+		serviceHosts:     serviceHosts,
+		serviceTransport: serviceTransport,
+		transport:        newTransport(serviceTransport.Proxy, tlsConfig),
+	}
+}
+
+// serviceHostTransport sends the requests made to the service hosts using the service transport and the requests made to
+// other hosts using the given transport
+type serviceHostTransport struct {
+	serviceHosts     []string
+	serviceTransport http.RoundTripper
+	transport        http.RoundTripper
+}
+
+// RoundTrip sends the request using the service transport if the request is made to one of the service hosts; otherwise,
+// the request is sent using the transport for other hosts
+func (t serviceHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, serviceHost := range t.serviceHosts {
+		if strings.EqualFold(req.URL.Host, serviceHost) {
+			return t.serviceTransport.RoundTrip(req)
+		}
+	}
+	return t.transport.RoundTrip(req)
+}
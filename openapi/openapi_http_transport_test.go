@@ -0,0 +1,361 @@
+package openapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewServiceHTTPClient(t *testing.T) {
+	Convey("Given a nil service configuration and a cookie jar", t, func() {
+		jar, _ := cookiejar.New(nil)
+		Convey("When newServiceHTTPClient is called", func() {
+			client, err := newServiceHTTPClient(nil, jar)
+			Convey("Then the client returned should use the default transport and the cookie jar", func() {
+				So(err, ShouldBeNil)
+				So(client.Transport, ShouldBeNil)
+				So(client.Jar, ShouldEqual, jar)
+			})
+		})
+	})
+	Convey("Given a service configuration with a request timeout", t, func() {
+		serviceConfiguration := &ServiceConfigStub{RequestTimeout: 30 * time.Second}
+		Convey("When newServiceHTTPClient is called", func() {
+			client, err := newServiceHTTPClient(serviceConfiguration, nil)
+			Convey("Then the client returned should use the service transport and the request timeout", func() {
+				So(err, ShouldBeNil)
+				So(client.Transport, ShouldNotBeNil)
+				So(client.Timeout, ShouldEqual, 30*time.Second)
+			})
+		})
+	})
+	Convey("Given a service configuration with a CA bundle that does not exist", t, func() {
+		serviceConfiguration := &ServiceConfigStub{TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: "/non/existing/ca.pem"}}
+		Convey("When newServiceHTTPClient is called", func() {
+			_, err := newServiceHTTPClient(serviceConfiguration, nil)
+			Convey("Then the error returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to read the service tls ca_bundle '/non/existing/ca.pem'")
+			})
+		})
+	})
+}
+
+func TestNewSwaggerHTTPClient(t *testing.T) {
+	Convey("Given a service configuration without request timeout nor swagger auth configuration", t, func() {
+		serviceConfiguration := &ServiceConfigStub{SwaggerURL: "http://host.com/swagger.yaml"}
+		Convey("When newSwaggerHTTPClient is called", func() {
+			client, err := newSwaggerHTTPClient(serviceConfiguration)
+			Convey("Then the client returned should use the service transport and the default swagger request timeout", func() {
+				So(err, ShouldBeNil)
+				So(client.Transport, ShouldHaveSameTypeAs, &http.Transport{})
+				So(client.Timeout, ShouldEqual, swaggerRequestTimeout)
+			})
+		})
+	})
+	Convey("Given a swagger file served by an API that requires a bearer token and a service configured with the token", t, func() {
+		var receivedAuthorization string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedAuthorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer api.Close()
+		otherAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedAuthorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer otherAPI.Close()
+		serviceConfiguration := &ServiceConfigStub{
+			SwaggerURL:     api.URL + "/swagger.yaml",
+			RequestTimeout: 5 * time.Second,
+			SwaggerAuth:    &ServiceSwaggerAuthConfigurationV1{BearerToken: "some token"},
+		}
+		client, err := newSwaggerHTTPClient(serviceConfiguration)
+		So(err, ShouldBeNil)
+		Convey("When the swagger file is requested using the client returned by newSwaggerHTTPClient", func() {
+			req, _ := http.NewRequest(http.MethodGet, serviceConfiguration.SwaggerURL, nil)
+			_, err := client.Do(req)
+			Convey("Then the bearer token should be sent and the original request should not be modified", func() {
+				So(err, ShouldBeNil)
+				So(receivedAuthorization, ShouldEqual, "Bearer some token")
+				So(req.Header.Get("Authorization"), ShouldBeEmpty)
+			})
+			Convey("And the client should use the service request timeout", func() {
+				So(client.Timeout, ShouldEqual, 5*time.Second)
+			})
+		})
+		Convey("When a request to another host is sent using the client returned by newSwaggerHTTPClient", func() {
+			_, err := client.Get(otherAPI.URL)
+			Convey("Then the bearer token should not be sent", func() {
+				So(err, ShouldBeNil)
+				So(receivedAuthorization, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given a service configuration with a swagger auth token command that fails", t, func() {
+		serviceConfiguration := &ServiceConfigStub{
+			SwaggerURL:  "http://host.com/swagger.yaml",
+			SwaggerAuth: &ServiceSwaggerAuthConfigurationV1{TokenCommand: []string{"false"}},
+		}
+		Convey("When newSwaggerHTTPClient is called", func() {
+			_, err := newSwaggerHTTPClient(serviceConfiguration)
+			Convey("Then the error returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to get the swagger auth credentials: failed to execute 'swagger_auth' command '[false]'")
+			})
+		})
+	})
+}
+
+func TestNewServiceTransport(t *testing.T) {
+	Convey("Given an API served over TLS with a certificate that is not trusted by the system", t, func() {
+		api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer api.Close()
+		Convey("When a request is sent using the transport of a service configured with a CA bundle containing the API certificate", func() {
+			caBundle := writeTestPEMFile(t, "CERTIFICATE", api.Certificate().Raw)
+			defer os.Remove(caBundle)
+			transport, err := newServiceTransport(&ServiceConfigStub{TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: caBundle}})
+			So(err, ShouldBeNil)
+			res, err := (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should succeed", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+		Convey("When a request is sent using the transport of a service without CA bundle", func() {
+			transport, err := newServiceTransport(&ServiceConfigStub{})
+			So(err, ShouldBeNil)
+			_, err = (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should fail as the API certificate is not trusted", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+		Convey("When a request is sent using the transport of a service with insecure skip verify enabled", func() {
+			transport, err := newServiceTransport(&ServiceConfigStub{InsecureSkipVerify: true})
+			So(err, ShouldBeNil)
+			res, err := (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should succeed", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+		Convey("When a request is sent using the transport of a service with the API certificate public key pinned", func() {
+			caBundle := writeTestPEMFile(t, "CERTIFICATE", api.Certificate().Raw)
+			defer os.Remove(caBundle)
+			publicKeyHash := sha256.Sum256(api.Certificate().RawSubjectPublicKeyInfo)
+			transport, err := newServiceTransport(&ServiceConfigStub{TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: caBundle, PinnedPublicKeys: []string{base64.StdEncoding.EncodeToString(publicKeyHash[:])}}})
+			So(err, ShouldBeNil)
+			res, err := (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should succeed", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+		Convey("When a request is sent using the transport of a service with a different public key pinned", func() {
+			publicKeyHash := sha256.Sum256([]byte("some other public key"))
+			transport, err := newServiceTransport(&ServiceConfigStub{InsecureSkipVerify: true, TLSConfiguration: &ServiceTLSConfigurationV2{PinnedPublicKeys: []string{base64.StdEncoding.EncodeToString(publicKeyHash[:])}}})
+			So(err, ShouldBeNil)
+			_, err = (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should fail as the API certificate chain does not contain the pinned public key", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "service API certificate chain does not contain any of the pinned public keys")
+			})
+		})
+	})
+
+	Convey("Given an API served over TLS with a maximum TLS version 1.2", t, func() {
+		api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		api.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+		api.StartTLS()
+		defer api.Close()
+		Convey("When a request is sent using the transport of a service with TLS min version 1.2", func() {
+			transport, err := newServiceTransport(&ServiceConfigStub{InsecureSkipVerify: true, TLSConfiguration: &ServiceTLSConfigurationV2{MinVersion: "1.2"}})
+			So(err, ShouldBeNil)
+			res, err := (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should succeed", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+		Convey("When a request is sent using the transport of a service with TLS min version 1.3", func() {
+			transport, err := newServiceTransport(&ServiceConfigStub{InsecureSkipVerify: true, TLSConfiguration: &ServiceTLSConfigurationV2{MinVersion: "1.3"}})
+			So(err, ShouldBeNil)
+			_, err = (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should fail as the API does not support the min version", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given an API served over TLS that requires a client certificate", t, func() {
+		var clientCertificates []*x509.Certificate
+		api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientCertificates = r.TLS.PeerCertificates
+			w.WriteHeader(http.StatusOK)
+		}))
+		api.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		api.StartTLS()
+		defer api.Close()
+		Convey("When a request is sent using the transport of a service configured with a client certificate", func() {
+			clientCert, clientKey := writeTestClientCertificate(t)
+			defer os.Remove(clientCert)
+			defer os.Remove(clientKey)
+			transport, err := newServiceTransport(&ServiceConfigStub{InsecureSkipVerify: true, TLSConfiguration: &ServiceTLSConfigurationV2{ClientCert: clientCert, ClientKey: clientKey}})
+			So(err, ShouldBeNil)
+			res, err := (&http.Client{Transport: transport}).Get(api.URL)
+			Convey("Then the request should succeed and the API should receive the client certificate", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(clientCertificates, ShouldHaveLength, 1)
+				So(clientCertificates[0].Subject.CommonName, ShouldEqual, "openapi-client")
+			})
+		})
+	})
+
+	Convey("Given a proxy", t, func() {
+		var hostRequested string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hostRequested = r.URL.Host
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer proxy.Close()
+		Convey("When a request is sent using the transport of a service configured with the proxy", func() {
+			transport, err := newServiceTransport(&ServiceConfigStub{ProxyURL: proxy.URL})
+			So(err, ShouldBeNil)
+			res, err := (&http.Client{Transport: transport}).Get("http://api.server.com/v1/resource")
+			Convey("Then the request should be sent through the proxy", func() {
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(hostRequested, ShouldEqual, "api.server.com")
+			})
+		})
+	})
+
+	Convey("Given a service configured with a CA bundle that does not contain certificates", t, func() {
+		caBundle := writeTestPEMFile(t, "SOMETHING", []byte("not a certificate"))
+		defer os.Remove(caBundle)
+		Convey("When newServiceTransport is called", func() {
+			_, err := newServiceTransport(&ServiceConfigStub{TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: caBundle}})
+			Convey("Then the error returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "service tls ca_bundle '"+caBundle+"' does not contain any PEM encoded certificate")
+			})
+		})
+	})
+
+	Convey("Given a service configured with a client certificate whose key does not exist", t, func() {
+		clientCert, clientKey := writeTestClientCertificate(t)
+		defer os.Remove(clientCert)
+		os.Remove(clientKey)
+		Convey("When newServiceTransport is called", func() {
+			_, err := newServiceTransport(&ServiceConfigStub{TLSConfiguration: &ServiceTLSConfigurationV2{ClientCert: clientCert, ClientKey: clientKey}})
+			Convey("Then the error returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to load the service tls client_cert '"+clientCert+"' and client_key '"+clientKey+"'")
+			})
+		})
+	})
+}
+
+// writeTestClientCertificate writes a self-signed client certificate and its private key to temporary files and
+// returns the paths of the files
+func writeTestClientCertificate(t *testing.T) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "openapi-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeTestPEMFile(t, "CERTIFICATE", cert), writeTestPEMFile(t, "EC PRIVATE KEY", key)
+}
+
+// writeTestPEMFile writes the given bytes PEM encoded to a temporary file and returns the path of the file
+func writeTestPEMFile(t *testing.T, blockType string, bytes []byte) string {
+	file, err := ioutil.TempFile("", "*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := pem.Encode(file, &pem.Block{Type: blockType, Bytes: bytes}); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+func TestNewAuthenticatorsTransport(t *testing.T) {
+	Convey("Given a service API and an identity provider served over TLS that request a client certificate", t, func() {
+		newTLSServer := func(clientCertificates *[]*x509.Certificate) *httptest.Server {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				*clientCertificates = r.TLS.PeerCertificates
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+			server.StartTLS()
+			return server
+		}
+		var apiClientCertificates, identityProviderClientCertificates []*x509.Certificate
+		api := newTLSServer(&apiClientCertificates)
+		defer api.Close()
+		identityProvider := newTLSServer(&identityProviderClientCertificates)
+		defer identityProvider.Close()
+		clientCert, clientKey := writeTestClientCertificate(t)
+		defer os.Remove(clientCert)
+		defer os.Remove(clientKey)
+		serviceTransport, err := newServiceTransport(&ServiceConfigStub{InsecureSkipVerify: true, TLSConfiguration: &ServiceTLSConfigurationV2{ClientCert: clientCert, ClientKey: clientKey, PinnedPublicKeys: []string{base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))}}})
+		So(err, ShouldBeNil)
+		apiURL, _ := url.Parse(api.URL)
+		Convey("When newAuthenticatorsTransport is called with the service transport and the service API host", func() {
+			transport := newAuthenticatorsTransport(serviceTransport, []string{apiURL.Host})
+			client := &http.Client{Transport: transport}
+			Convey("Then the requests made to the service API host should be sent using the service transport", func() {
+				_, err := client.Get(api.URL)
+				So(err.Error(), ShouldContainSubstring, "service API certificate chain does not contain any of the pinned public keys")
+			})
+			Convey("And the requests made to other hosts should be sent without the client certificate and pinned public keys", func() {
+				res, err := client.Get(identityProvider.URL)
+				So(err, ShouldBeNil)
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(identityProviderClientCertificates, ShouldBeEmpty)
+			})
+			Convey("And the service transport TLS configuration should not be modified", func() {
+				So(serviceTransport.TLSClientConfig.Certificates, ShouldHaveLength, 1)
+				So(serviceTransport.TLSClientConfig.VerifyPeerCertificate, ShouldNotBeNil)
+			})
+		})
+	})
+}
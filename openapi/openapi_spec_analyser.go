@@ -2,13 +2,18 @@ package openapi
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v2"
 )
 
 // SpecAnalyser analyses the swagger doc and provides helper methods to retrieve all the end points that can
 // be used as terraform resources. These endpoints have to meet certain criteria to be considered eligible resources
 // as explained below:
 // A resource is considered any end point that meets the following:
-// 	- POST operation on the root path (e,g: api/users)
+// 	- POST operation on the root path (e,g: api/users) including the payload (body parameter in OpenAPI v2 and requestBody in OpenAPI v3)
 //	- GET operation on the instance path (e,g: api/users/{id}). Other operations like DELETE, PUT are optional
 // In the example above, the resource name would be 'users'.
 // Versioning is also supported, thus if the endpoint above had been api/v1/users the corresponding resouce name would
@@ -39,23 +44,83 @@ type SpecAnalyserVersion string
 const (
 	// specAnalyserV2 version that supports OpenAPI v2 (swagger)
 	specAnalyserV2 SpecAnalyserVersion = "v2"
+	// specAnalyserV3 version that supports OpenAPI v3
+	specAnalyserV3 SpecAnalyserVersion = "v3"
 )
 
 // CreateSpecAnalyser is a factory method that returns the appropriate implementation of SpecAnalyser
-// depending upon the openApiSpecAnalyserVersion passed in.
-// Currently only OpenAPI v2 version is supported but this constructor is ready to handle new implementations such as v3
-// when the time comes
-func CreateSpecAnalyser(specAnalyserVersion SpecAnalyserVersion, openAPIDocumentURL string) (SpecAnalyser, error) {
+// depending upon the openApiSpecAnalyserVersion passed in. Both OpenAPI v2 and OpenAPI v3 versions are supported; the
+// newSpecAnalyser function can be used instead to figure out the version from the document itself. The
+// httpClient is used to retrieve the document (and its external references) when served over http
+func CreateSpecAnalyser(specAnalyserVersion SpecAnalyserVersion, openAPIDocumentURL string, httpClient *http.Client) (SpecAnalyser, error) {
 	var err error
 	var specAnalyser SpecAnalyser
 	switch specAnalyserVersion {
 	case specAnalyserV2:
-		specAnalyser, err = newSpecAnalyserV2(openAPIDocumentURL)
+		specAnalyser, err = newSpecAnalyserV2(openAPIDocumentURL, httpClient)
+	case specAnalyserV3:
+		specAnalyser, err = newSpecAnalyserV3(openAPIDocumentURL, httpClient)
 	default:
-		return nil, fmt.Errorf("open api spec analyser version '%s' not supported, please choose a valid SpecAnalyser implementation [%s]", specAnalyserVersion, specAnalyserV2)
+		return nil, fmt.Errorf("open api spec analyser version '%s' not supported, please choose a valid SpecAnalyser implementation [%s, %s]", specAnalyserVersion, specAnalyserV2, specAnalyserV3)
 	}
 	if err != nil {
 		return nil, err
 	}
 	return specAnalyser, nil
 }
+
+// newSpecAnalyser retrieves the OpenAPI document and returns the SpecAnalyser implementation that supports the document
+// version. The document is retrieved only once and its content is used both to figure out the version and to analyse it
+func newSpecAnalyser(openAPIDocumentURL string, httpClient *http.Client) (SpecAnalyser, error) {
+	content, err := readOpenAPIDocument(openAPIDocumentURL, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentURL, err)
+	}
+	specAnalyserVersion, err := getSpecAnalyserVersion(openAPIDocumentURL, content)
+	if err != nil {
+		return nil, err
+	}
+	if specAnalyserVersion == specAnalyserV3 {
+		return loadSpecAnalyserV3(openAPIDocumentURL, content, httpClient)
+	}
+	return loadSpecAnalyserV2(openAPIDocumentURL, content)
+}
+
+// getSpecAnalyserVersion returns the SpecAnalyserVersion that should be used to analyse the given OpenAPI document content
+// (either JSON or YAML) based on the document's 'swagger' (2.0) or 'openapi' (3.x) fields
+func getSpecAnalyserVersion(openAPIDocumentURL string, content []byte) (SpecAnalyserVersion, error) {
+	document := struct {
+		Swagger string `yaml:"swagger"`
+		OpenAPI string `yaml:"openapi"`
+	}{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return "", fmt.Errorf("failed to read the version of the OpenAPI document from '%s' - error = %s", openAPIDocumentURL, err)
+	}
+	switch {
+	case document.Swagger == "2.0":
+		return specAnalyserV2, nil
+	case strings.HasPrefix(document.OpenAPI, "3."):
+		return specAnalyserV3, nil
+	}
+	return "", fmt.Errorf("OpenAPI document version not supported (swagger: '%s', openapi: '%s'), the document must be either a swagger 2.0 or an openapi 3.x document", document.Swagger, document.OpenAPI)
+}
+
+// readOpenAPIDocument returns the content of the OpenAPI document, which can either be a local file or a URL. URLs are
+// retrieved using the given httpClient
+func readOpenAPIDocument(openAPIDocumentURL string, httpClient *http.Client) ([]byte, error) {
+	if !isURL(openAPIDocumentURL) {
+		return ioutil.ReadFile(openAPIDocumentURL)
+	}
+	res, err := httpClient.Get(openAPIDocumentURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("could not access document at %q [%s], please make sure the swagger auth credentials (swagger_auth) are configured and valid", openAPIDocumentURL, res.Status)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not access document at %q [%s] ", openAPIDocumentURL, res.Status)
+	}
+	return ioutil.ReadAll(res.Body)
+}
@@ -1,9 +1,12 @@
 package openapi
 
 import (
-	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestCreateSpecAnalyser(t *testing.T) {
@@ -15,7 +18,7 @@ func TestCreateSpecAnalyser(t *testing.T) {
 
 		openAPIDocumentURL := file.Name()
 		Convey("When CreateSpecAnalyser method is called", func() {
-			specAnalyser, err := CreateSpecAnalyser(specAnalyserVersion, openAPIDocumentURL)
+			specAnalyser, err := CreateSpecAnalyser(specAnalyserVersion, openAPIDocumentURL, http.DefaultClient)
 			Convey("Then err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -25,7 +28,7 @@ func TestCreateSpecAnalyser(t *testing.T) {
 		})
 
 		Convey("When CreateSpecAnalyser method is called with a non valid openAPIDocumentURL", func() {
-			_, err := CreateSpecAnalyser(specAnalyserVersion, "some non valid spec file")
+			_, err := CreateSpecAnalyser(specAnalyserVersion, "some non valid spec file", http.DefaultClient)
 			Convey("Then err returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
@@ -35,12 +38,152 @@ func TestCreateSpecAnalyser(t *testing.T) {
 		})
 
 		Convey("When CreateSpecAnalyser method is called with a non supported version", func() {
-			_, err := CreateSpecAnalyser("nonSupportedVersion", openAPIDocumentURL)
+			_, err := CreateSpecAnalyser("nonSupportedVersion", openAPIDocumentURL, http.DefaultClient)
 			Convey("Then err returned should be nil", func() {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("Then the error message should equal", func() {
-				So(err.Error(), ShouldEqual, "open api spec analyser version 'nonSupportedVersion' not supported, please choose a valid SpecAnalyser implementation [v2]")
+				So(err.Error(), ShouldEqual, "open api spec analyser version 'nonSupportedVersion' not supported, please choose a valid SpecAnalyser implementation [v2, v3]")
+			})
+		})
+	})
+}
+
+func TestCreateSpecAnalyserV3(t *testing.T) {
+	Convey("Given the specAnalyserV3 version and a openAPIDocumentURL pointing at an OpenAPI v3 document", t, func() {
+		file := initAPISpecFile(`openapi: "3.0.0"`)
+		defer os.Remove(file.Name())
+		Convey("When CreateSpecAnalyser method is called", func() {
+			specAnalyser, err := CreateSpecAnalyser(specAnalyserV3, file.Name(), http.DefaultClient)
+			Convey("Then err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Then the specAnalyser is of type specV3Analyser", func() {
+				So(specAnalyser, ShouldHaveSameTypeAs, &specV3Analyser{})
+			})
+		})
+	})
+}
+
+func TestCreateSpecAnalyserWithHTTPClient(t *testing.T) {
+	documents := map[SpecAnalyserVersion]string{
+		specAnalyserV2: `swagger: "2.0"`,
+		specAnalyserV3: `openapi: "3.0.0"`,
+	}
+	for specAnalyserVersion, document := range documents {
+		Convey("Given an OpenAPI document "+string(specAnalyserVersion)+" served over TLS with a certificate that is not trusted by the system", t, func() {
+			api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(document))
+			}))
+			defer api.Close()
+			Convey("When CreateSpecAnalyser method is called with an http client that trusts the API certificate", func() {
+				specAnalyser, err := CreateSpecAnalyser(specAnalyserVersion, api.URL, api.Client())
+				Convey("Then the document should be retrieved using the http client", func() {
+					So(err, ShouldBeNil)
+					So(specAnalyser, ShouldNotBeNil)
+				})
+			})
+			Convey("When CreateSpecAnalyser method is called with an http client that does not trust the API certificate", func() {
+				_, err := CreateSpecAnalyser(specAnalyserVersion, api.URL, http.DefaultClient)
+				Convey("Then the error returned should be", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldStartWith, "failed to retrieve the OpenAPI document from '"+api.URL+"'")
+				})
+			})
+		})
+	}
+}
+
+func TestNewSpecAnalyser(t *testing.T) {
+	documents := map[SpecAnalyserVersion]string{
+		specAnalyserV2: `swagger: "2.0"`,
+		specAnalyserV3: `openapi: "3.0.0"`,
+	}
+	for specAnalyserVersion, document := range documents {
+		Convey("Given an OpenAPI document "+string(specAnalyserVersion)+" served by an API", t, func() {
+			requests := 0
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Write([]byte(document))
+			}))
+			defer api.Close()
+			Convey("When newSpecAnalyser method is called", func() {
+				specAnalyser, err := newSpecAnalyser(api.URL, http.DefaultClient)
+				Convey("Then the spec analyser returned should support the document version", func() {
+					So(err, ShouldBeNil)
+					if specAnalyserVersion == specAnalyserV2 {
+						So(specAnalyser, ShouldHaveSameTypeAs, &specV2Analyser{})
+					} else {
+						So(specAnalyser, ShouldHaveSameTypeAs, &specV3Analyser{})
+					}
+				})
+				Convey("And the document should have been retrieved only once", func() {
+					So(requests, ShouldEqual, 1)
+				})
+			})
+		})
+	}
+	Convey("Given an OpenAPI document that is not found", t, func() {
+		api := httptest.NewServer(http.NotFoundHandler())
+		defer api.Close()
+		Convey("When newSpecAnalyser method is called", func() {
+			_, err := newSpecAnalyser(api.URL, http.DefaultClient)
+			Convey("Then the error message should equal", func() {
+				So(err.Error(), ShouldEqual, "failed to retrieve the OpenAPI document from '"+api.URL+`' - error = could not access document at "`+api.URL+`" [404 Not Found] `)
+			})
+		})
+	})
+	Convey("Given a document served by an API that requires credentials", t, func() {
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer api.Close()
+		Convey("When newSpecAnalyser method is called without credentials", func() {
+			_, err := newSpecAnalyser(api.URL, http.DefaultClient)
+			Convey("Then the error message should point at the swagger auth configuration", func() {
+				So(err.Error(), ShouldEqual, "failed to retrieve the OpenAPI document from '"+api.URL+`' - error = could not access document at "`+api.URL+`" [401 Unauthorized], please make sure the swagger auth credentials (swagger_auth) are configured and valid`)
+			})
+		})
+	})
+	Convey("Given a document with a non supported version", t, func() {
+		file := initAPISpecFile(`swagger: "1.2"`)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyser method is called", func() {
+			_, err := newSpecAnalyser(file.Name(), http.DefaultClient)
+			Convey("Then the error message should equal", func() {
+				So(err.Error(), ShouldEqual, "OpenAPI document version not supported (swagger: '1.2', openapi: ''), the document must be either a swagger 2.0 or an openapi 3.x document")
+			})
+		})
+	})
+}
+
+func TestGetSpecAnalyserVersion(t *testing.T) {
+	Convey("Given a swagger 2.0 document", t, func() {
+		content := []byte(`{"swagger": "2.0"}`)
+		Convey("When getSpecAnalyserVersion method is called", func() {
+			version, err := getSpecAnalyserVersion("swagger.json", content)
+			Convey("Then the version returned should be v2", func() {
+				So(err, ShouldBeNil)
+				So(version, ShouldEqual, specAnalyserV2)
+			})
+		})
+	})
+	Convey("Given an openapi 3.x document", t, func() {
+		content := []byte(`openapi: 3.0.2`)
+		Convey("When getSpecAnalyserVersion method is called", func() {
+			version, err := getSpecAnalyserVersion("openapi.yaml", content)
+			Convey("Then the version returned should be v3", func() {
+				So(err, ShouldBeNil)
+				So(version, ShouldEqual, specAnalyserV3)
+			})
+		})
+	})
+	Convey("Given a document with a non supported version", t, func() {
+		content := []byte(`swagger: "1.2"`)
+		Convey("When getSpecAnalyserVersion method is called", func() {
+			_, err := getSpecAnalyserVersion("swagger.yaml", content)
+			Convey("Then the error message should equal", func() {
+				So(err.Error(), ShouldEqual, "OpenAPI document version not supported (swagger: '1.2', openapi: ''), the document must be either a swagger 2.0 or an openapi 3.x document")
 			})
 		})
 	})
@@ -6,19 +6,36 @@ type authType byte
 const ( // iota is reset to 0
 	authTypeAPIKeyHeader authType = iota
 	authTypeAPIQuery
+	authTypeAPIKeySignature
+	authTypeAPIKeyCookie
 )
 
 type specAuthenticator interface {
 	// prepareAuth generates an auth context with all the information regarding the authentication, including
 	// any metadata that should be passed in to the request when making the http call to get a resource (e,g: new headers
 	// with authentication details like access tokens, url with a query token, etc).
-	// The following parameters describe the operationId for which the authentication is being prepared, the url of
-	// the resource, the operation security schemes and the provider config containing the actual values like tokens,
-	// special headers, etc for each security schemes
-	prepareAuth(url string, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error)
+	// The following parameters describe the method, the url of the resource and the body (as sent to the API) of the
+	// request for which the authentication is being prepared, the operation security schemes and the provider config
+	// containing the actual values like tokens, special headers, etc for each security schemes
+	prepareAuth(method httpMethodSupported, url string, body []byte, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error)
 }
 
 type authContext struct {
 	headers map[string]string
 	url     string
+	// method and body of the request are exposed so authenticators can sign the request (they must not be modified)
+	method httpMethodSupported
+	body   []byte
+	// sessionInvalidators discard the sessions used to authenticate the request (e,g: session cookies) so new ones are
+	// created when the API rejects them
+	sessionInvalidators []func()
+}
+
+// invalidateSessions discards the sessions used to authenticate the request. It returns false if the request was not
+// authenticated with any session, in which case performing the request again would not make any difference
+func (a *authContext) invalidateSessions() bool {
+	for _, invalidateSession := range a.sessionInvalidators {
+		invalidateSession()
+	}
+	return len(a.sessionInvalidators) > 0
 }
@@ -3,6 +3,7 @@ package openapi
 import (
 	"fmt"
 	"log"
+	"strings"
 )
 
 // apiAuth is an implementation of specAuthenticator encapsulating the general settings to be applied in case
@@ -19,47 +20,78 @@ func newAPIAuthenticator(globalSecuritySchemes *SpecSecuritySchemes) specAuthent
 }
 
 // Check if the operation contains any security policy. In the case where the operation contains multiple security
-// policies, the first one found in the list will be the one returned.
+// requirements, all of them are returned so the first one configured by the user can be selected (see fetchRequiredAuthenticators).
 // For more information about multiple api keys refer to https://swagger.io/docs/specification/authentication/api-keys/#multiple
 func (oa apiAuth) authRequired(url string, operationSecuritySchemes SpecSecuritySchemes) (bool, SpecSecuritySchemes) {
 	// TODO: check in the OpenAPI spec whether operation overrides global schemes or can complement global configuration?
 	if len(operationSecuritySchemes) != 0 {
-		log.Printf("operation security policies found for '%s' (overriding global security config if applicable). Selected the following based on order of appearance in the list %+v", url, operationSecuritySchemes)
+		log.Printf("operation security policies found for '%s' (overriding global security config if applicable): %+v", url, operationSecuritySchemes.getRequirements())
 		return true, operationSecuritySchemes
 	}
 	log.Printf("operation security schemes missing, falling back to global security schemes (if there's any)")
 	if oa.globalSecuritySchemes != nil && len(*oa.globalSecuritySchemes) != 0 {
-		log.Printf("the global configuration contains security schemes, selected the following based on order of appearance in the list %+v", oa.globalSecuritySchemes)
+		log.Printf("the global configuration contains security schemes: %+v", oa.globalSecuritySchemes.getRequirements())
 		return true, *oa.globalSecuritySchemes
 	}
 	return false, nil
 }
 
-// Validate security policies. This function will perform the following checks:
-// 1. Verify that the operation security schemes are defined as security definitions in the provider config
+// fetchRequiredAuthenticators returns the authenticators of the first security requirement (in order of appearance) whose
+// security schemes are all defined as security definitions and configured in the provider config. If none of the security
+// requirements is satisfied, the error returned describes the security schemes missing for each of them. If an empty
+// security requirement ({}) is reached before any other requirement is satisfied, no authenticators are returned since
+// the security is optional
 func (oa apiAuth) fetchRequiredAuthenticators(operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) ([]specAPIKeyAuthenticator, error) {
-	var authenticators []specAPIKeyAuthenticator
-	for _, operationSecurityScheme := range operationSecuritySchemes {
-		authenticator := providerConfig.getAuthenticatorFor(operationSecurityScheme)
-		if authenticator == nil {
-			return nil, fmt.Errorf("operation's security policy '%s' is not defined, please make sure the swagger file contains a security definition named '%s' under the securityDefinitions section", operationSecurityScheme, operationSecurityScheme)
+	var unsatisfiedRequirements []string
+	for _, requirement := range operationSecuritySchemes.getRequirements() {
+		if requirement[0].isEmptyRequirement() {
+			log.Printf("[DEBUG] selected empty security requirement, the request will not be authenticated")
+			return []specAPIKeyAuthenticator{}, nil
 		}
-		authenticators = append(authenticators, authenticator)
+		var authenticators []specAPIKeyAuthenticator
+		var missingSecuritySchemes []string
+		for _, operationSecurityScheme := range requirement {
+			authenticator := providerConfig.getAuthenticatorFor(operationSecurityScheme)
+			if authenticator == nil {
+				missingSecuritySchemes = append(missingSecuritySchemes, operationSecurityScheme.Name)
+				continue
+			}
+			authenticators = append(authenticators, authenticator)
+		}
+		if len(missingSecuritySchemes) == 0 {
+			log.Printf("[DEBUG] selected security requirement %s", requirement)
+			return authenticators, nil
+		}
+		unsatisfiedRequirements = append(unsatisfiedRequirements, fmt.Sprintf("%s (missing: %s)", requirement, strings.Join(missingSecuritySchemes, ", ")))
 	}
-	return authenticators, nil
+	return nil, fmt.Errorf("operation's security requirements are not satisfied, please make sure the swagger file contains the security definitions and the provider configuration includes the credentials of one of the following security requirements: %s", strings.Join(unsatisfiedRequirements, " OR "))
 }
 
-func (oa apiAuth) prepareAuth(url string, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error) {
+// prepareAuth runs the authenticators of the security requirement selected. The authenticators that sign the request
+// run last so the signature is computed over the final url (e,g: including query api keys)
+func (oa apiAuth) prepareAuth(method httpMethodSupported, url string, body []byte, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error) {
 	authContext := &authContext{
 		headers: map[string]string{},
 		url:     url,
+		method:  method,
+		body:    body,
 	}
 	if required, requiredSecuritySchemes := oa.authRequired(url, operationSecuritySchemes); required {
 		authenticators, err := oa.fetchRequiredAuthenticators(requiredSecuritySchemes, providerConfig)
 		if err != nil {
 			return authContext, err
 		}
+		var signatureAuthenticators []specAPIKeyAuthenticator
 		for _, authenticator := range authenticators {
+			if authenticator.getType() == authTypeAPIKeySignature {
+				signatureAuthenticators = append(signatureAuthenticators, authenticator)
+				continue
+			}
+			if err := authenticator.prepareAuth(authContext); err != nil {
+				return authContext, err
+			}
+		}
+		for _, authenticator := range signatureAuthenticators {
 			if err := authenticator.prepareAuth(authContext); err != nil {
 				return authContext, err
 			}
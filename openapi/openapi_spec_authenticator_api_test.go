@@ -2,8 +2,10 @@ package openapi
 
 import (
 	"fmt"
-	. "github.com/smartystreets/goconvey/convey"
 	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestApiAuth(t *testing.T) {
@@ -113,6 +115,45 @@ func TestFetchRequiredAuthenticators(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given an operation whose security requirements are an 'apikey_auth' requirement OR an empty requirement ([{apikey_auth:[]},{}])", t, func() {
+		securityPolicyName := "apikey_auth"
+		operationSecuritySchemes := createSecuritySchemes([]map[string][]string{{securityPolicyName: {}}, {}})
+		oa := apiAuth{}
+		Convey("When fetchRequiredAuthenticators method is called with a provider configuration containing the 'apikey_auth' security definition", func() {
+			providerConfig := providerConfiguration{
+				SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+					securityPolicyName: apiKeyHeaderAuthenticator{
+						apiKey{
+							name:  authorizationHeader,
+							value: "superSecretKey",
+						},
+					},
+				},
+			}
+			authenticators, err := oa.fetchRequiredAuthenticators(operationSecuritySchemes, providerConfig)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the authenticators returned should be the ones of the first requirement", func() {
+				So(authenticators, ShouldHaveLength, 1)
+				So(authenticators[0].getContext().(apiKey).value, ShouldEqual, "superSecretKey")
+			})
+		})
+		Convey("When fetchRequiredAuthenticators method is called with a provider configuration that does not contain the 'apikey_auth' security definition", func() {
+			providerConfig := providerConfiguration{
+				SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{},
+			}
+			authenticators, err := oa.fetchRequiredAuthenticators(operationSecuritySchemes, providerConfig)
+			Convey("Then the err returned should be nil as the empty requirement is always satisfied", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the authenticators returned should be empty", func() {
+				So(authenticators, ShouldNotBeNil)
+				So(authenticators, ShouldBeEmpty)
+			})
+		})
+	})
 }
 
 func TestPrepareAuth(t *testing.T) {
@@ -132,7 +173,7 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(nil)
 		Convey("When prepareAuth method is called with a providerConfiguration", func() {
-			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -164,7 +205,7 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(nil)
 		Convey("When prepareAPIKeyAuthentication method is called with the operation, providerConfiguration and the service url", func() {
-			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -200,7 +241,7 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(nil)
 		Convey("When prepareAuth method is called with the providerConfiguration", func() {
-			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -235,7 +276,7 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(nil)
 		Convey("When prepareAuth method is called with the providerConfiguration", func() {
-			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -266,7 +307,7 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(globalSecuritySchemes)
 		Convey("When prepareAuth method is called with the providerConfiguration", func() {
-			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -299,7 +340,7 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(globalSecuritySchemes)
 		Convey("When prepareAuth method is called with the providerConfiguration", func() {
-			authContext, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -310,6 +351,65 @@ func TestPrepareAuth(t *testing.T) {
 		})
 	})
 
+	Convey("Given a provider configuration containing only the 'oauth2_auth' security definition and an operation that requires (api_key AND tenant_token) OR (oauth2_auth)", t, func() {
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"api_key": apiKeyHeaderAuthenticator{
+					apiKey{
+						name:  "X-API-KEY",
+						value: "superSecretKeyForApiKey",
+					},
+				},
+				"oauth2_auth": apiKeyHeaderAuthenticator{
+					apiKey{
+						name:  authorizationHeader,
+						value: "Bearer accessToken",
+					},
+				},
+			},
+		}
+		operationSecuritySchemes := createSecuritySchemes([]map[string][]string{
+			{
+				"api_key":      {},
+				"tenant_token": {},
+			},
+			{
+				"oauth2_auth": {},
+			},
+		})
+		url := "https://www.host.com/v1/resource"
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the providerConfiguration", func() {
+			authContext, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
+			Convey("Then the first security requirement fully configured (oauth2_auth) should be the one used for auth", func() {
+				So(err, ShouldBeNil)
+				So(authContext.headers, ShouldResemble, map[string]string{authorizationHeader: "Bearer accessToken"})
+			})
+		})
+	})
+
+	Convey("Given a provider configuration containing only the 'api_key' security definition and an operation that requires (api_key AND tenant_token) OR (oauth2_auth)", t, func() {
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"api_key": apiKeyHeaderAuthenticator{
+					apiKey{
+						name:  "X-API-KEY",
+						value: "superSecretKeyForApiKey",
+					},
+				},
+			},
+		}
+		operationSecuritySchemes := SpecSecuritySchemes{{Name: "api_key", requirement: 0}, {Name: "tenant_token", requirement: 0}, {Name: "oauth2_auth", requirement: 1}}
+		url := "https://www.host.com/v1/resource"
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the providerConfiguration", func() {
+			_, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
+			Convey("Then the error returned should describe the credentials missing for each security requirement", func() {
+				So(err.Error(), ShouldEqual, "operation's security requirements are not satisfied, please make sure the swagger file contains the security definitions and the provider configuration includes the credentials of one of the following security requirements: [api_key AND tenant_token] (missing: tenant_token) OR [oauth2_auth] (missing: oauth2_auth)")
+			})
+		})
+	})
+
 	Convey("Given a global security setting containing schemes which are not defined in the provider security definitions, and an operation with NO security schemes", t, func() {
 		providerConfig := providerConfiguration{
 			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
@@ -328,12 +428,12 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(globalSecuritySchemes)
 		Convey("When prepareAuth method is called with the providerConfiguration", func() {
-			_, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			_, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should NOT be nil as global schemes contain policies which are not defined", func() {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the err message should be", func() {
-				So(err.Error(), ShouldEqual, "operation's security policy '{not_defined_scheme}' is not defined, please make sure the swagger file contains a security definition named '{not_defined_scheme}' under the securityDefinitions section")
+				So(err.Error(), ShouldEqual, "operation's security requirements are not satisfied, please make sure the swagger file contains the security definitions and the provider configuration includes the credentials of one of the following security requirements: [not_defined_scheme] (missing: not_defined_scheme)")
 			})
 		})
 	})
@@ -353,12 +453,46 @@ func TestPrepareAuth(t *testing.T) {
 		url := "https://www.host.com/v1/resource"
 		oa := newAPIAuthenticator(nil)
 		Convey("When prepareAuth method is called with the providerConfiguration", func() {
-			_, err := oa.prepareAuth(url, operationSecuritySchemes, providerConfig)
+			_, err := oa.prepareAuth(httpGet, url, nil, operationSecuritySchemes, providerConfig)
 			Convey("Then err should NOT be nil as global schemes contain policies which are not defined", func() {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the err message should be", func() {
-				So(err.Error(), ShouldEqual, "operation's security policy '{not_defined_scheme}' is not defined, please make sure the swagger file contains a security definition named '{not_defined_scheme}' under the securityDefinitions section")
+				So(err.Error(), ShouldEqual, "operation's security requirements are not satisfied, please make sure the swagger file contains the security definitions and the provider configuration includes the credentials of one of the following security requirements: [not_defined_scheme] (missing: not_defined_scheme)")
+			})
+		})
+	})
+
+	Convey("Given a provider configuration containing a signature security definition and a query 'apiKey' security definition and an operation that requires both", t, func() {
+		configuration, _ := newSignatureConfiguration(map[string]interface{}{extTfAuthenticationSchemeSignature: true})
+		signatureAuthenticator := newAPIKeySignatureAuthenticator("X-Signature", "secret", configuration)
+		signatureAuthenticator.now = func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) }
+		providerConfig := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"signature_auth": signatureAuthenticator,
+				"apikey_query_auth": apiKeyQueryAuthenticator{
+					apiKey{
+						name:  "api_key",
+						value: "superSecretKey",
+					},
+				},
+			},
+		}
+		operationSecuritySchemes := createSecuritySchemes([]map[string][]string{{"signature_auth": []string{}, "apikey_query_auth": []string{}}})
+		url := "https://www.host.com/v1/resource"
+		body := []byte(`{"label":"some label"}`)
+		oa := newAPIAuthenticator(nil)
+		Convey("When prepareAuth method is called with the request method and body", func() {
+			expectedAuthContext := &authContext{method: httpPost, url: url + "?api_key=superSecretKey", body: body}
+			signatureAuthenticator.prepareAuth(expectedAuthContext)
+			authContext, err := oa.prepareAuth(httpPost, url, body, operationSecuritySchemes, providerConfig)
+			Convey("Then err should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the request should be signed once the query api key has been added to the url", func() {
+				So(authContext.url, ShouldEqual, expectedAuthContext.url)
+				So(authContext.headers["X-Signature"], ShouldEqual, expectedAuthContext.headers["X-Signature"])
+				So(authContext.headers[defaultSignatureTimestampHeader], ShouldEqual, "1577836800")
 			})
 		})
 	})
@@ -11,11 +11,18 @@ func createAPIKeyAuthenticator(secDef SpecSecurityDefinition, value string) spec
 	switch secDef.getAPIKey().In {
 	case inHeader:
 		if secDef.getType() == securityDefinitionAPIKeyRefreshToken {
-			return newAPIRefreshTokenAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value), secDef.getAPIKey().Metadata[refreshTokenURLKey].(string))
+			configuration, _ := secDef.getAPIKey().Metadata[refreshTokenConfigurationKey].(refreshTokenConfiguration)
+			return newAPIRefreshTokenAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value), secDef.getAPIKey().Metadata[refreshTokenURLKey].(string), configuration)
+		}
+		if secDef.getType() == securityDefinitionAPIKeySignature {
+			configuration, _ := secDef.getAPIKey().Metadata[signatureConfigurationKey].(signatureConfiguration)
+			return newAPIKeySignatureAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value), configuration)
 		}
 		return newAPIKeyHeaderAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value))
 	case inQuery:
 		return newAPIKeyQueryAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value))
+	case inCookie:
+		return newAPIKeyCookieAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value))
 	}
 	return nil
 }
@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const cookieHeader = "Cookie"
+
+// Api Key Cookie Auth
+type apiKeyCookieAuthenticator struct {
+	apiKey
+}
+
+func newAPIKeyCookieAuthenticator(name, value string) apiKeyCookieAuthenticator {
+	return apiKeyCookieAuthenticator{
+		apiKey: apiKey{
+			name:  name,
+			value: value,
+		},
+	}
+}
+
+func (a apiKeyCookieAuthenticator) getContext() interface{} {
+	return a.apiKey
+}
+
+func (a apiKeyCookieAuthenticator) getType() authType {
+	return authTypeAPIKeyCookie
+}
+
+// prepareAuth adds the api key cookie to the Cookie header, keeping any other cookie already added to the header (e,g:
+// by other security definitions). The url remains the same
+func (a apiKeyCookieAuthenticator) prepareAuth(authContext *authContext) error {
+	if authContext.headers == nil {
+		authContext.headers = map[string]string{}
+	}
+	cookie := (&http.Cookie{Name: a.name, Value: a.value}).String()
+	if cookies := authContext.headers[cookieHeader]; cookies != "" {
+		cookie = fmt.Sprintf("%s; %s", cookies, cookie)
+	}
+	authContext.headers[cookieHeader] = cookie
+	return nil
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApiKeyCookieAuthenticator(t *testing.T) {
+	Convey("Given a name and a value", t, func() {
+		name := "session_id"
+		value := "value"
+		Convey("When newAPIKeyCookieAuthenticator method is called", func() {
+			apiKeyCookieAuthenticator := newAPIKeyCookieAuthenticator(name, value)
+			Convey("Then the apiKeyCookieAuthenticator should comply with specAPIKeyAuthenticator interface", func() {
+				var _ specAPIKeyAuthenticator = apiKeyCookieAuthenticator
+			})
+			Convey("And the type should be authTypeAPIKeyCookie", func() {
+				So(apiKeyCookieAuthenticator.getType(), ShouldEqual, authTypeAPIKeyCookie)
+			})
+		})
+	})
+}
+
+func TestApiKeyCookieAuthenticatorPrepareAuth(t *testing.T) {
+	Convey("Given an apiKeyCookieAuthenticator", t, func() {
+		apiKeyCookieAuthenticator := newAPIKeyCookieAuthenticator("session_id", "value")
+		Convey("When prepareAuth method is called with a authContext", func() {
+			expectedURL := "http://www.backend.com"
+			ctx := &authContext{
+				headers: map[string]string{},
+				url:     expectedURL,
+			}
+			err := apiKeyCookieAuthenticator.prepareAuth(ctx)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And then the context url should remain the same", func() {
+				So(ctx.url, ShouldEqual, expectedURL)
+			})
+			Convey("And then the context Cookie header should contain the apiKey cookie", func() {
+				So(ctx.headers[cookieHeader], ShouldEqual, "session_id=value")
+			})
+		})
+		Convey("When prepareAuth method is called with a authContext that already contains cookies", func() {
+			ctx := &authContext{
+				headers: map[string]string{cookieHeader: "tenant=some_tenant"},
+				url:     "http://www.backend.com",
+			}
+			err := apiKeyCookieAuthenticator.prepareAuth(ctx)
+			Convey("Then the apiKey cookie should be appended to the existing cookies", func() {
+				So(err, ShouldBeNil)
+				So(ctx.headers[cookieHeader], ShouldEqual, "tenant=some_tenant; session_id=value")
+			})
+		})
+	})
+}
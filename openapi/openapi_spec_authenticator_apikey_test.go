@@ -48,4 +48,33 @@ func TestCreateAPIKeyAuthenticator(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a secDef of cookie type and a auth value", t, func() {
+		secDef := newAPIKeyCookieSecurityDefinition("cookie_auth", "session_id")
+		value := "value"
+		Convey("When createAPIKeyAuthenticator method is constructed", func() {
+			apiKeyAuthenticator := createAPIKeyAuthenticator(secDef, value)
+			Convey("And the the specAPIKeyAuthenticator returned Should Have Same Type As apiKeyCookieAuthenticator", func() {
+				So(apiKeyAuthenticator, ShouldHaveSameTypeAs, apiKeyCookieAuthenticator{})
+			})
+			Convey("And the the specAPIKeyAuthenticator returned should be of type authTypeAPIKeyCookie", func() {
+				So(apiKeyAuthenticator.getType(), ShouldEqual, authTypeAPIKeyCookie)
+			})
+		})
+	})
+
+	Convey("Given a Signature secDef of header type and a secret", t, func() {
+		configuration, _ := newSignatureConfiguration(map[string]interface{}{extTfAuthenticationSchemeSignature: true})
+		secDef := newAPIKeyHeaderSignatureSecurityDefinition("signature_auth", "X-Signature", configuration)
+		value := "secret"
+		Convey("When createAPIKeyAuthenticator method is constructed", func() {
+			apiKeyAuthenticator := createAPIKeyAuthenticator(secDef, value)
+			Convey("And the the specAPIKeyAuthenticator returned Should Have Same Type As apiKeySignatureAuthenticator", func() {
+				So(apiKeyAuthenticator, ShouldHaveSameTypeAs, apiKeySignatureAuthenticator{})
+			})
+			Convey("And the the specAPIKeyAuthenticator returned should be of type authTypeAPIKeySignature", func() {
+				So(apiKeyAuthenticator.getType(), ShouldEqual, authTypeAPIKeySignature)
+			})
+		})
+	})
 }
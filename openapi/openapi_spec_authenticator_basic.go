@@ -0,0 +1,43 @@
+package openapi
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// basicAuthenticator is a specAPIKeyAuthenticator that authenticates the requests using HTTP Basic authentication
+type basicAuthenticator struct {
+	name     string
+	username string
+	password string
+}
+
+func newBasicAuthenticator(name, username, password string) basicAuthenticator {
+	return basicAuthenticator{
+		name:     name,
+		username: username,
+		password: password,
+	}
+}
+
+func (a basicAuthenticator) getContext() interface{} {
+	return a.username
+}
+
+func (a basicAuthenticator) getType() authType {
+	return authTypeAPIKeyHeader
+}
+
+// prepareAuth adds the Authorization header containing the base64 encoded username and password using the basic scheme.
+// The url remains the same
+func (a basicAuthenticator) prepareAuth(authContext *authContext) error {
+	if a.username == "" {
+		return fmt.Errorf("basic security definition '%s' is missing the username, please make sure this value is provided in the terraform configuration", a.name)
+	}
+	if authContext.headers == nil {
+		authContext.headers = map[string]string{}
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(a.username + ":" + a.password))
+	authContext.headers[authorizationHeader] = fmt.Sprintf("%s %s", basicScheme, credentials)
+	return nil
+}
@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BasicAuthenticator_Successfully_Prepares_Authorization(t *testing.T) {
+	t.Run("happy path -- AuthContext is populated with the base64 encoded credentials using the basic scheme", func(t *testing.T) {
+		authenticator := newBasicAuthenticator("basic_auth", "Aladdin", "open sesame")
+		ctx := &authContext{url: "https://api.server.com/v1/resource"}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Basic QWxhZGRpbjpvcGVuIHNlc2FtZQ==", ctx.headers[authorizationHeader])
+		assert.Equal(t, "https://api.server.com/v1/resource", ctx.url)
+	})
+
+	t.Run("crappy path -- the username is not configured", func(t *testing.T) {
+		authenticator := newBasicAuthenticator("basic_auth", "", "")
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, "basic security definition 'basic_auth' is missing the username, please make sure this value is provided in the terraform configuration")
+	})
+}
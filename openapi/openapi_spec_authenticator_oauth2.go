@@ -0,0 +1,173 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// formURLEncodedContentType is the content type of the OAuth2 token requests
+const formURLEncodedContentType = "application/x-www-form-urlencoded"
+
+// Grant types supported when requesting OAuth2 access tokens
+const (
+	oauth2ClientCredentialsGrantType = "client_credentials"
+	oauth2PasswordGrantType          = "password"
+	oauth2RefreshTokenGrantType      = "refresh_token"
+)
+
+// oauth2Token is the access token returned by the OAuth2 token endpoint
+type oauth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	// expiry is the time at which the token expires. Zero means the token does not expire
+	expiry time.Time
+}
+
+// oauth2Authenticator is a specAPIKeyAuthenticator that authenticates the requests with an access token obtained from the
+// OAuth2 token URL. The token is cached and reused until it expires or the API rejects it, at which point a new one is
+// requested
+type oauth2Authenticator struct {
+	name         string
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	// grant contains the grant specific parameters sent to the token URL (e,g: grant_type=client_credentials)
+	grant      url.Values
+	httpClient *http.Client
+	cache      *accessTokenCache
+}
+
+// newOAuth2ClientCredentialsAuthenticator returns an authenticator that obtains the access tokens using the OAuth2 client
+// credentials flow
+func newOAuth2ClientCredentialsAuthenticator(name, tokenURL, clientID, clientSecret string, scopes []string) *oauth2Authenticator {
+	return &oauth2Authenticator{
+		name:         name,
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		grant:        url.Values{"grant_type": []string{oauth2ClientCredentialsGrantType}},
+		httpClient:   &http.Client{},
+		cache:        newAccessTokenCache(defaultTokenExpiryMargin),
+	}
+}
+
+// newOAuth2PasswordAuthenticator returns an authenticator that obtains the access tokens using the OAuth2 resource owner
+// password flow. The client id and client secret are optional in this flow
+func newOAuth2PasswordAuthenticator(name, tokenURL, username, password, clientID, clientSecret string, scopes []string) *oauth2Authenticator {
+	return &oauth2Authenticator{
+		name:         name,
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		grant: url.Values{
+			"grant_type": []string{oauth2PasswordGrantType},
+			"username":   []string{username},
+			"password":   []string{password},
+		},
+		httpClient: &http.Client{},
+		cache:      newAccessTokenCache(defaultTokenExpiryMargin),
+	}
+}
+
+func (a *oauth2Authenticator) getContext() interface{} {
+	return a.tokenURL
+}
+
+func (a *oauth2Authenticator) getType() authType {
+	return authTypeAPIKeyHeader
+}
+
+// setTransport sets the transport used to send the token requests
+func (a *oauth2Authenticator) setTransport(transport http.RoundTripper) {
+	a.httpClient.Transport = transport
+}
+
+// prepareAuth adds the Authorization header with the access token using the bearer scheme. The url remains the same
+func (a *oauth2Authenticator) prepareAuth(authContext *authContext) error {
+	token, err := a.cache.getToken(a.requestToken)
+	if err != nil {
+		return err
+	}
+	if authContext.headers == nil {
+		authContext.headers = map[string]string{}
+	}
+	authContext.headers[authorizationHeader] = fmt.Sprintf("%s %s", bearerScheme, token.AccessToken)
+	authContext.sessionInvalidators = append(authContext.sessionInvalidators, a.cache.sessionInvalidator(token))
+	return nil
+}
+
+// requestToken requests a new access token to the token URL using the authenticator grant
+func (a *oauth2Authenticator) requestToken() (*oauth2Token, error) {
+	if err := a.validateCredentials(); err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	for key, values := range a.grant {
+		form[key] = values
+	}
+	if len(a.scopes) > 0 {
+		form.Set("scope", strings.Join(a.scopes, " "))
+	}
+	return requestOAuth2Token(a.httpClient, a.tokenURL, a.clientID, a.clientSecret, form)
+}
+
+// validateCredentials checks that the credentials required by the authenticator grant have been configured
+func (a *oauth2Authenticator) validateCredentials() error {
+	switch a.grant.Get("grant_type") {
+	case oauth2PasswordGrantType:
+		if a.grant.Get("username") == "" || a.grant.Get("password") == "" {
+			return fmt.Errorf("OAuth2 security definition '%s' is missing the username or password, please make sure these values are provided in the terraform configuration", a.name)
+		}
+	default:
+		if a.clientID == "" || a.clientSecret == "" {
+			return fmt.Errorf("OAuth2 security definition '%s' is missing the client id or client secret, please make sure these values are provided in the terraform configuration", a.name)
+		}
+	}
+	return nil
+}
+
+// requestOAuth2Token posts the given form to the token URL and returns the access token received. The client credentials
+// (if any) are sent using HTTP Basic authentication as recommended by RFC 6749
+func requestOAuth2Token(httpClient *http.Client, tokenURL, clientID, clientSecret string, form url.Values) (*oauth2Token, error) {
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(contentTypeHeader, formURLEncodedContentType)
+	if clientID != "" {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OAuth2 token request to '%s' failed: %s", tokenURL, err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OAuth2 token response from '%s': %s", tokenURL, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OAuth2 token response '%s' status code '%d' not matching expected response status code [%d] (%s)", tokenURL, res.StatusCode, http.StatusOK, string(body))
+	}
+	token := &oauth2Token{}
+	if err := json.Unmarshal(body, token); err != nil {
+		return nil, fmt.Errorf("failed to parse the OAuth2 token response from '%s': %s", tokenURL, err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("OAuth2 token response from '%s' is missing the access token", tokenURL)
+	}
+	if token.ExpiresIn > 0 {
+		token.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
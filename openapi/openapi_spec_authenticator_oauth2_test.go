@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OAuth2ClientCredentialsAuthenticator_Successfully_Prepares_Authorization(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "clientID", clientID)
+		assert.Equal(t, "clientSecret", clientSecret)
+		assert.Equal(t, formURLEncodedContentType, r.Header.Get(contentTypeHeader))
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "read write", r.PostForm.Get("scope"))
+		w.Header().Set(contentTypeHeader, "application/json")
+		fmt.Fprintf(w, `{"access_token":"accessToken%d","token_type":"bearer","expires_in":3600}`, tokenRequests)
+	}))
+	defer tokenServer.Close()
+
+	authenticator := newOAuth2ClientCredentialsAuthenticator("oauth2_auth", tokenServer.URL, "clientID", "clientSecret", []string{"read", "write"})
+
+	t.Run("happy path -- AuthContext is populated with the access token using the bearer scheme", func(t *testing.T) {
+		ctx := &authContext{}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer accessToken1", ctx.headers[authorizationHeader])
+	})
+
+	t.Run("happy path -- the access token is cached until it expires", func(t *testing.T) {
+		ctx := &authContext{headers: map[string]string{}}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer accessToken1", ctx.headers[authorizationHeader])
+		assert.Equal(t, 1, tokenRequests)
+	})
+
+	t.Run("happy path -- a new access token is requested once the cached one is about to expire", func(t *testing.T) {
+		authenticator.cache.token.expiry = time.Now().Add(defaultTokenExpiryMargin / 2)
+		ctx := &authContext{headers: map[string]string{}}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer accessToken2", ctx.headers[authorizationHeader])
+		assert.Equal(t, 2, tokenRequests)
+	})
+}
+
+func Test_OAuth2ClientCredentialsAuthenticator_Fails_To_Prepare_Authorization(t *testing.T) {
+	t.Run("crappy path -- the token server returns a non expected response status code", func(t *testing.T) {
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+		}))
+		defer tokenServer.Close()
+		authenticator := newOAuth2ClientCredentialsAuthenticator("oauth2_auth", tokenServer.URL, "clientID", "wrongSecret", nil)
+		ctx := &authContext{}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.EqualError(t, err, fmt.Sprintf(`OAuth2 token response '%s' status code '401' not matching expected response status code [200] ({"error":"invalid_client"})`, tokenServer.URL))
+		assert.Empty(t, ctx.headers[authorizationHeader])
+	})
+
+	t.Run("crappy path -- the token server response does not contain the access token", func(t *testing.T) {
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"token_type":"bearer"}`))
+		}))
+		defer tokenServer.Close()
+		authenticator := newOAuth2ClientCredentialsAuthenticator("oauth2_auth", tokenServer.URL, "clientID", "clientSecret", nil)
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, fmt.Sprintf("OAuth2 token response from '%s' is missing the access token", tokenServer.URL))
+	})
+
+	t.Run("crappy path -- the client credentials are not configured", func(t *testing.T) {
+		authenticator := newOAuth2ClientCredentialsAuthenticator("oauth2_auth", "https://api.iam.com/oauth2/token", "", "", nil)
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, "OAuth2 security definition 'oauth2_auth' is missing the client id or client secret, please make sure these values are provided in the terraform configuration")
+	})
+}
+
+func Test_OAuth2PasswordAuthenticator_Successfully_Prepares_Authorization(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, ok := r.BasicAuth()
+		assert.False(t, ok)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "password", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "user", r.PostForm.Get("username"))
+		assert.Equal(t, "secret", r.PostForm.Get("password"))
+		w.Write([]byte(`{"access_token":"accessToken","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	t.Run("happy path -- AuthContext is populated with the access token obtained with the username and password", func(t *testing.T) {
+		authenticator := newOAuth2PasswordAuthenticator("oauth2_auth", tokenServer.URL, "user", "secret", "", "", nil)
+		ctx := &authContext{}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer accessToken", ctx.headers[authorizationHeader])
+	})
+
+	t.Run("crappy path -- the username and password are not configured", func(t *testing.T) {
+		authenticator := newOAuth2PasswordAuthenticator("oauth2_auth", tokenServer.URL, "", "", "clientID", "clientSecret", nil)
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, "OAuth2 security definition 'oauth2_auth' is missing the username or password, please make sure these values are provided in the terraform configuration")
+	})
+}
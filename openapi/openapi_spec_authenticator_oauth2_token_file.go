@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Fields of the OAuth2 token file
+const (
+	oauth2TokenFileAccessToken  = "access_token"
+	oauth2TokenFileRefreshToken = "refresh_token"
+	oauth2TokenFileExpiresAt    = "expires_at"
+)
+
+// oauth2TokenFileAuthenticator is a specAPIKeyAuthenticator that authenticates the requests with the access token stored
+// in a local token file (e,g: the token cache written by a CLI after an authorization code login). When the access token
+// expires, a new one is requested to the token URL using the refresh token and the token file is updated accordingly
+type oauth2TokenFileAuthenticator struct {
+	name         string
+	tokenURL     string
+	tokenFile    string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+	cache        *accessTokenCache
+}
+
+// newOAuth2TokenFileAuthenticator returns an authenticator that reads the access token from the given token file. The
+// client id and client secret (optional) are used to authenticate the refresh token requests
+func newOAuth2TokenFileAuthenticator(name, tokenURL, tokenFile, clientID, clientSecret string) *oauth2TokenFileAuthenticator {
+	return &oauth2TokenFileAuthenticator{
+		name:         name,
+		tokenURL:     tokenURL,
+		tokenFile:    tokenFile,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   &http.Client{},
+		cache:        newAccessTokenCache(defaultTokenExpiryMargin),
+	}
+}
+
+func (a *oauth2TokenFileAuthenticator) getContext() interface{} {
+	return a.tokenFile
+}
+
+func (a *oauth2TokenFileAuthenticator) getType() authType {
+	return authTypeAPIKeyHeader
+}
+
+// setTransport sets the transport used to send the refresh token requests
+func (a *oauth2TokenFileAuthenticator) setTransport(transport http.RoundTripper) {
+	a.httpClient.Transport = transport
+}
+
+// prepareAuth adds the Authorization header with the access token using the bearer scheme. The url remains the same
+func (a *oauth2TokenFileAuthenticator) prepareAuth(authContext *authContext) error {
+	token, err := a.cache.getToken(a.getToken)
+	if err != nil {
+		return err
+	}
+	if authContext.headers == nil {
+		authContext.headers = map[string]string{}
+	}
+	authContext.headers[authorizationHeader] = fmt.Sprintf("%s %s", bearerScheme, token.AccessToken)
+	authContext.sessionInvalidators = append(authContext.sessionInvalidators, a.cache.sessionInvalidator(token))
+	return nil
+}
+
+// getToken is called when the cached access token is no longer valid. The token file is read again (it might have been
+// updated by a new login) and, if the access token stored has expired, it gets refreshed
+func (a *oauth2TokenFileAuthenticator) getToken() (*oauth2Token, error) {
+	if a.tokenFile == "" {
+		return nil, fmt.Errorf("OAuth2 security definition '%s' is missing the token file, please make sure this value is provided in the terraform configuration", a.name)
+	}
+	tokenFileContent, err := a.readTokenFile()
+	if err != nil {
+		return nil, err
+	}
+	token, err := newOAuth2TokenFromFile(tokenFileContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth2 token file '%s': %s", a.tokenFile, err)
+	}
+	if !a.cache.valid(token) {
+		if token.RefreshToken == "" {
+			return nil, fmt.Errorf("the access token stored in the OAuth2 token file '%s' has expired and there is no refresh token available, please log in again", a.tokenFile)
+		}
+		if token, err = a.refreshToken(token); err != nil {
+			return nil, err
+		}
+		if err := a.writeTokenFile(tokenFileContent, token); err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+// refreshToken requests a new access token to the token URL using the refresh token. If the response does not contain
+// a new refresh token, the current one is kept
+func (a *oauth2TokenFileAuthenticator) refreshToken(token *oauth2Token) (*oauth2Token, error) {
+	form := url.Values{
+		"grant_type":    []string{oauth2RefreshTokenGrantType},
+		"refresh_token": []string{token.RefreshToken},
+	}
+	refreshedToken, err := requestOAuth2Token(a.httpClient, a.tokenURL, a.clientID, a.clientSecret, form)
+	if err != nil {
+		return nil, err
+	}
+	if refreshedToken.RefreshToken == "" {
+		refreshedToken.RefreshToken = token.RefreshToken
+	}
+	return refreshedToken, nil
+}
+
+func (a *oauth2TokenFileAuthenticator) readTokenFile() (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(a.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth2 token file '%s': %s", a.tokenFile, err)
+	}
+	tokenFileContent := map[string]interface{}{}
+	if err := json.Unmarshal(data, &tokenFileContent); err != nil {
+		return nil, fmt.Errorf("failed to read OAuth2 token file '%s': %s", a.tokenFile, err)
+	}
+	return tokenFileContent, nil
+}
+
+// writeTokenFile stores the refreshed token in the token file so it can be reused by subsequent executions. Any other
+// field present in the file is kept as is, and the expiry is written in the same format it was read (RFC3339 date or
+// unix timestamp)
+func (a *oauth2TokenFileAuthenticator) writeTokenFile(tokenFileContent map[string]interface{}, token *oauth2Token) error {
+	tokenFileContent[oauth2TokenFileAccessToken] = token.AccessToken
+	tokenFileContent[oauth2TokenFileRefreshToken] = token.RefreshToken
+	_, unixTimestamp := tokenFileContent[oauth2TokenFileExpiresAt].(float64)
+	switch {
+	case token.expiry.IsZero():
+		delete(tokenFileContent, oauth2TokenFileExpiresAt)
+	case unixTimestamp:
+		tokenFileContent[oauth2TokenFileExpiresAt] = token.expiry.Unix()
+	default:
+		tokenFileContent[oauth2TokenFileExpiresAt] = token.expiry.UTC().Format(time.RFC3339)
+	}
+	data, err := json.MarshalIndent(tokenFileContent, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(a.tokenFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to update OAuth2 token file '%s': %s", a.tokenFile, err)
+	}
+	return nil
+}
+
+// newOAuth2TokenFromFile returns the oauth2Token stored in the given token file content. The expires_at field can either
+// be an RFC3339 date or a unix timestamp
+func newOAuth2TokenFromFile(tokenFileContent map[string]interface{}) (*oauth2Token, error) {
+	token := &oauth2Token{}
+	token.AccessToken, _ = tokenFileContent[oauth2TokenFileAccessToken].(string)
+	token.RefreshToken, _ = tokenFileContent[oauth2TokenFileRefreshToken].(string)
+	switch expiresAt := tokenFileContent[oauth2TokenFileExpiresAt].(type) {
+	case nil:
+	case float64:
+		token.expiry = time.Unix(int64(expiresAt), 0)
+	case string:
+		expiry, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s value '%s' is not a valid RFC3339 date", oauth2TokenFileExpiresAt, expiresAt)
+		}
+		token.expiry = expiry
+	default:
+		return nil, fmt.Errorf("%s value '%v' must be an RFC3339 date or a unix timestamp", oauth2TokenFileExpiresAt, expiresAt)
+	}
+	return token, nil
+}
@@ -0,0 +1,96 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createOAuth2TokenFile(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "token.json")
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	return file.Name()
+}
+
+func Test_OAuth2TokenFileAuthenticator_Successfully_Prepares_Authorization(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "refreshToken", r.PostForm.Get("refresh_token"))
+		w.Write([]byte(`{"access_token":"refreshedAccessToken","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	t.Run("happy path -- AuthContext is populated with the access token stored in the token file if not expired", func(t *testing.T) {
+		tokenFile := createOAuth2TokenFile(t, fmt.Sprintf(`{"access_token":"accessToken","refresh_token":"refreshToken","expires_at":"%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339)))
+		defer os.Remove(tokenFile)
+		authenticator := newOAuth2TokenFileAuthenticator("oauth2_auth", tokenServer.URL, tokenFile, "", "")
+		ctx := &authContext{}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer accessToken", ctx.headers[authorizationHeader])
+		assert.Equal(t, 0, tokenRequests)
+	})
+
+	t.Run("happy path -- the expired access token is refreshed and the token file updated", func(t *testing.T) {
+		tokenFile := createOAuth2TokenFile(t, fmt.Sprintf(`{"access_token":"accessToken","refresh_token":"refreshToken","expires_at":%d,"user":"someone"}`, time.Now().Add(-time.Hour).Unix()))
+		defer os.Remove(tokenFile)
+		authenticator := newOAuth2TokenFileAuthenticator("oauth2_auth", tokenServer.URL, tokenFile, "", "")
+		ctx := &authContext{}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer refreshedAccessToken", ctx.headers[authorizationHeader])
+		assert.Equal(t, 1, tokenRequests)
+
+		data, err := ioutil.ReadFile(tokenFile)
+		require.NoError(t, err)
+		tokenFileContent := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(data, &tokenFileContent))
+		assert.Equal(t, "refreshedAccessToken", tokenFileContent["access_token"])
+		assert.Equal(t, "refreshToken", tokenFileContent["refresh_token"])
+		assert.Equal(t, "someone", tokenFileContent["user"])
+		assert.True(t, tokenFileContent["expires_at"].(float64) > float64(time.Now().Unix()))
+	})
+}
+
+func Test_OAuth2TokenFileAuthenticator_Fails_To_Prepare_Authorization(t *testing.T) {
+	t.Run("crappy path -- the access token has expired and there is no refresh token", func(t *testing.T) {
+		tokenFile := createOAuth2TokenFile(t, `{"access_token":"accessToken","expires_at":"2020-01-01T00:00:00Z"}`)
+		defer os.Remove(tokenFile)
+		authenticator := newOAuth2TokenFileAuthenticator("oauth2_auth", "https://api.iam.com/oauth2/token", tokenFile, "", "")
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, fmt.Sprintf("the access token stored in the OAuth2 token file '%s' has expired and there is no refresh token available, please log in again", tokenFile))
+	})
+
+	t.Run("crappy path -- the token file expires_at is not a valid date", func(t *testing.T) {
+		tokenFile := createOAuth2TokenFile(t, `{"access_token":"accessToken","expires_at":"tomorrow"}`)
+		defer os.Remove(tokenFile)
+		authenticator := newOAuth2TokenFileAuthenticator("oauth2_auth", "https://api.iam.com/oauth2/token", tokenFile, "", "")
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, fmt.Sprintf("failed to read OAuth2 token file '%s': expires_at value 'tomorrow' is not a valid RFC3339 date", tokenFile))
+	})
+
+	t.Run("crappy path -- the token file does not exist", func(t *testing.T) {
+		authenticator := newOAuth2TokenFileAuthenticator("oauth2_auth", "https://api.iam.com/oauth2/token", "/non/existing/token.json", "", "")
+		err := authenticator.prepareAuth(&authContext{})
+
+		assert.EqualError(t, err, "failed to read OAuth2 token file '/non/existing/token.json': open /non/existing/token.json: no such file or directory")
+	})
+}
@@ -1,8 +1,14 @@
 package openapi
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dikhan/http_goclient"
 )
@@ -11,17 +17,22 @@ import (
 type apiRefreshTokenAuthenticator struct {
 	apiKey
 	refreshTokenURL string
+	configuration   refreshTokenConfiguration
 	httpClient      http_goclient.HttpClientIface
+	// cache is shared by all the copies of the authenticator. If nil, the access token is not cached
+	cache *accessTokenCache
 }
 
-func newAPIRefreshTokenAuthenticator(name, refreshToken, refreshTokenURL string) apiRefreshTokenAuthenticator {
+func newAPIRefreshTokenAuthenticator(name, refreshToken, refreshTokenURL string, configuration refreshTokenConfiguration) apiRefreshTokenAuthenticator {
 	return apiRefreshTokenAuthenticator{
 		apiKey: apiKey{
 			name:  name,
 			value: refreshToken,
 		},
 		refreshTokenURL: refreshTokenURL,
+		configuration:   configuration,
 		httpClient:      &http_goclient.HttpClient{HttpClient: &http.Client{}},
+		cache:           newAccessTokenCache(configuration.expiryMargin),
 	}
 }
 
@@ -33,25 +44,112 @@ func (a apiRefreshTokenAuthenticator) getType() authType {
 	return authTypeAPIKeyHeader
 }
 
-// prepareAuth will send a post request to the refreshTokenURL and get the access token from the response Authorization
-// header. Otherwise, it will fail.
+// setTransport sets the transport used to send the refresh token requests
+func (a apiRefreshTokenAuthenticator) setTransport(transport http.RoundTripper) {
+	if httpClient, ok := a.httpClient.(*http_goclient.HttpClient); ok {
+		httpClient.HttpClient.Transport = transport
+	}
+}
+
+// prepareAuth adds the Authorization header containing the access token obtained with the refresh token. The access
+// token is cached until it is about to expire or the API rejects it, at which point a new one is requested
 func (a apiRefreshTokenAuthenticator) prepareAuth(authContext *authContext) error {
+	if a.cache == nil {
+		token, err := a.requestAccessToken()
+		if err != nil {
+			return err
+		}
+		a.setAuthorizationHeader(authContext, token)
+		return nil
+	}
+	token, err := a.cache.getToken(a.requestAccessToken)
+	if err != nil {
+		return err
+	}
+	if token.expiry.IsZero() {
+		log.Printf("[DEBUG] the expiry of the access token returned by '%s' could not be determined, the access token will be cached until the API rejects it", a.refreshTokenURL)
+	}
+	authContext.sessionInvalidators = append(authContext.sessionInvalidators, a.cache.sessionInvalidator(token))
+	a.setAuthorizationHeader(authContext, token)
+	return nil
+}
+
+func (a apiRefreshTokenAuthenticator) setAuthorizationHeader(authContext *authContext, token *oauth2Token) {
+	if authContext.headers == nil {
+		authContext.headers = map[string]string{}
+	}
+	authContext.headers[authorizationHeader] = token.AccessToken
+}
+
+// requestAccessToken sends a post request to the refreshTokenURL and gets the access token from the response Authorization
+// header (or the configured response body property) along with its expiry. Zero expiry means it could not be determined
+func (a apiRefreshTokenAuthenticator) requestAccessToken() (*oauth2Token, error) {
 	apiKey := a.getContext().(apiKey)
 	headers := map[string]string{apiKey.name: apiKey.value}
-	r, err := a.httpClient.PostJson(a.refreshTokenURL, headers, nil, nil)
+	var responsePayload map[string]interface{}
+	var out interface{}
+	if a.configuration.responseProperty != "" {
+		out = &responsePayload
+	}
+	r, err := a.httpClient.PostJson(a.refreshTokenURL, headers, nil, out)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if r.StatusCode != http.StatusOK && r.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("refresh token POST response '%s' status code '%d' not matching expected response status code [%d, %d]", a.refreshTokenURL, r.StatusCode, http.StatusOK, http.StatusNoContent)
+		return nil, fmt.Errorf("refresh token POST response '%s' status code '%d' not matching expected response status code [%d, %d]", a.refreshTokenURL, r.StatusCode, http.StatusOK, http.StatusNoContent)
 	}
 	accessToken := r.Header.Get(authorizationHeader)
+	if a.configuration.responseProperty != "" {
+		accessToken, _ = responsePayload[a.configuration.responseProperty].(string)
+		if accessToken != "" && !strings.HasPrefix(accessToken, bearerScheme) {
+			accessToken = fmt.Sprintf("%s %s", bearerScheme, accessToken)
+		}
+	}
 	if accessToken == "" {
-		return fmt.Errorf("refresh token POST response '%s' is missing the access token", a.refreshTokenURL)
+		return nil, fmt.Errorf("refresh token POST response '%s' is missing the access token", a.refreshTokenURL)
 	}
-	if authContext.headers == nil {
-		authContext.headers = map[string]string{}
+	return &oauth2Token{AccessToken: accessToken, expiry: a.getAccessTokenExpiry(r, accessToken)}, nil
+}
+
+// getAccessTokenExpiry returns the access token expiry read from the configured response header, which can either
+// contain the number of seconds until the access token expires or a date. If the header is not configured or not
+// present, the expiry is read from the 'exp' claim if the access token is a JWT
+func (a apiRefreshTokenAuthenticator) getAccessTokenExpiry(r *http.Response, accessToken string) time.Time {
+	if a.configuration.expiryHeader != "" {
+		if expiryValue := strings.TrimSpace(r.Header.Get(a.configuration.expiryHeader)); expiryValue != "" {
+			if seconds, err := strconv.ParseInt(expiryValue, 10, 64); err == nil {
+				return time.Now().Add(time.Duration(seconds) * time.Second)
+			}
+			if expiry, err := http.ParseTime(expiryValue); err == nil {
+				return expiry
+			}
+			if expiry, err := time.Parse(time.RFC3339, expiryValue); err == nil {
+				return expiry
+			}
+			log.Printf("[WARN] ignoring refresh token response header '%s' value '%s' as it is neither a number of seconds nor a date", a.configuration.expiryHeader, expiryValue)
+		}
 	}
-	authContext.headers[authorizationHeader] = accessToken
-	return nil
+	return getJWTExpiry(accessToken)
+}
+
+// getJWTExpiry returns the expiry contained in the 'exp' claim of the given JWT (optionally prefixed with the bearer
+// scheme). The JWT signature is not verified as the token is only inspected to know when it expires. Zero is returned
+// if the token is not a JWT or does not contain the 'exp' claim
+func getJWTExpiry(token string) time.Time {
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), bearerScheme))
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	claims := struct {
+		Exp float64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
 }
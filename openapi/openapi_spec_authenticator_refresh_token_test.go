@@ -1,11 +1,15 @@
 package openapi
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dikhan/http_goclient"
 
@@ -20,7 +24,7 @@ func Test_ApiKeyRefreshTokenAuthenticator_Successfully_Prepares_Authorization(t
 		w.Header().Add(authorizationHeader, accessTokenExpectedReturn)
 	}))
 
-	refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", fakeRefreshToken, accessTokenFakeServer.URL)
+	refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", fakeRefreshToken, accessTokenFakeServer.URL, refreshTokenConfiguration{})
 
 	t.Run("happy path -- Successful AuthContext is populated with an Access Token when the authContext have no headers map", func(t *testing.T) {
 		ctx := &authContext{}
@@ -48,7 +52,7 @@ func Test_ApiKeyRefreshTokenAuthenticator_Fails_To_Prepare_Authorization(t *test
 		accessTokenBrokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNoContent)
 		}))
-		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", fakeRefreshToken, accessTokenBrokenServer.URL)
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", fakeRefreshToken, accessTokenBrokenServer.URL, refreshTokenConfiguration{})
 		ctx := &authContext{}
 		err := refreshTokenAuthenticator.prepareAuth(ctx)
 
@@ -61,7 +65,7 @@ func Test_ApiKeyRefreshTokenAuthenticator_Fails_To_Prepare_Authorization(t *test
 		accessTokenBrokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
 		}))
-		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", fakeRefreshToken, accessTokenBrokenServer.URL)
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("my_fancy_name", fakeRefreshToken, accessTokenBrokenServer.URL, refreshTokenConfiguration{})
 		ctx := &authContext{}
 		err := refreshTokenAuthenticator.prepareAuth(ctx)
 
@@ -82,3 +86,141 @@ func Test_ApiKeyRefreshTokenAuthenticator_Fails_To_Prepare_Authorization(t *test
 		assert.EqualError(t, err, "postJSON failed")
 	})
 }
+
+func createJWT(claims string) string {
+	return fmt.Sprintf("eyJhbGciOiJIUzI1NiJ9.%s.c2lnbmF0dXJl", base64.RawURLEncoding.EncodeToString([]byte(claims)))
+}
+
+func Test_ApiKeyRefreshTokenAuthenticator_Caches_Access_Token(t *testing.T) {
+	t.Run("happy path -- the access token is cached until the JWT exp claim is about to be reached", func(t *testing.T) {
+		tokenRequests := 0
+		accessToken := fmt.Sprintf("Bearer %s", createJWT(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix())))
+		accessTokenFakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			w.Header().Add(authorizationHeader, accessToken)
+		}))
+		defer accessTokenFakeServer.Close()
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("Authorization", "Bearer refreshToken", accessTokenFakeServer.URL, refreshTokenConfiguration{})
+
+		for i := 0; i < 3; i++ {
+			ctx := &authContext{}
+			assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+			assert.Equal(t, accessToken, ctx.headers[authorizationHeader])
+		}
+		assert.Equal(t, 1, tokenRequests)
+
+		refreshTokenAuthenticator.cache.token.expiry = time.Now().Add(defaultTokenExpiryMargin / 2)
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.Equal(t, 2, tokenRequests)
+	})
+
+	t.Run("happy path -- a new access token is requested once the API rejects the cached one", func(t *testing.T) {
+		tokenRequests := 0
+		accessTokenFakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			w.Header().Add(authorizationHeader, createJWT(fmt.Sprintf(`{"exp":%d,"jti":"%d"}`, time.Now().Add(time.Hour).Unix(), tokenRequests)))
+		}))
+		defer accessTokenFakeServer.Close()
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("Authorization", "Bearer refreshToken", accessTokenFakeServer.URL, refreshTokenConfiguration{})
+
+		ctx := &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		rejectedAccessToken := ctx.headers[authorizationHeader]
+		assert.True(t, ctx.invalidateSessions())
+
+		ctx = &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.NotEqual(t, rejectedAccessToken, ctx.headers[authorizationHeader])
+		assert.Equal(t, 2, tokenRequests)
+	})
+
+	t.Run("happy path -- the access token is read from the response body and its expiry from the configured response header", func(t *testing.T) {
+		tokenRequests := 0
+		accessTokenFakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			w.Header().Add("X-Expires-In", "3600")
+			w.Write([]byte(`{"access_token":"opaqueAccessToken"}`))
+		}))
+		defer accessTokenFakeServer.Close()
+		configuration := refreshTokenConfiguration{expiryHeader: "X-Expires-In", responseProperty: "access_token"}
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("Authorization", "Bearer refreshToken", accessTokenFakeServer.URL, configuration)
+
+		for i := 0; i < 2; i++ {
+			ctx := &authContext{}
+			assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+			assert.Equal(t, "Bearer opaqueAccessToken", ctx.headers[authorizationHeader])
+		}
+		assert.Equal(t, 1, tokenRequests)
+	})
+
+	t.Run("happy path -- the bearer scheme is only added to the access token read from the response body if missing", func(t *testing.T) {
+		accessTokenFakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"access_token":"opaqueBearerAccessToken"}`))
+		}))
+		defer accessTokenFakeServer.Close()
+		configuration := refreshTokenConfiguration{responseProperty: "access_token"}
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("Authorization", "Bearer refreshToken", accessTokenFakeServer.URL, configuration)
+
+		ctx := &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.Equal(t, "Bearer opaqueBearerAccessToken", ctx.headers[authorizationHeader])
+	})
+
+	t.Run("happy path -- parallel API calls share the same access token refresh", func(t *testing.T) {
+		var tokenRequests int32
+		accessToken := createJWT(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix()))
+		accessTokenFakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&tokenRequests, 1)
+			time.Sleep(10 * time.Millisecond)
+			w.Header().Add(authorizationHeader, accessToken)
+		}))
+		defer accessTokenFakeServer.Close()
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("Authorization", "Bearer refreshToken", accessTokenFakeServer.URL, refreshTokenConfiguration{})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+	})
+
+	t.Run("happy path -- the access token is cached until the API rejects it when its expiry cannot be determined", func(t *testing.T) {
+		tokenRequests := 0
+		accessTokenFakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokenRequests++
+			w.Header().Add(authorizationHeader, "Bearer opaqueAccessToken")
+		}))
+		defer accessTokenFakeServer.Close()
+		refreshTokenAuthenticator := newAPIRefreshTokenAuthenticator("Authorization", "Bearer refreshToken", accessTokenFakeServer.URL, refreshTokenConfiguration{})
+
+		ctx := &authContext{}
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(ctx))
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.Equal(t, 1, tokenRequests)
+
+		// the API rejected the access token with 401 Unauthorized
+		assert.Len(t, ctx.sessionInvalidators, 1)
+		ctx.sessionInvalidators[0]()
+		assert.NoError(t, refreshTokenAuthenticator.prepareAuth(&authContext{}))
+		assert.Equal(t, 2, tokenRequests)
+	})
+}
+
+func Test_GetJWTExpiry(t *testing.T) {
+	t.Run("happy path -- the expiry is read from the exp claim of a JWT prefixed with the bearer scheme", func(t *testing.T) {
+		assert.Equal(t, time.Unix(1893456000, 0), getJWTExpiry("Bearer "+createJWT(`{"sub":"user","exp":1893456000}`)))
+	})
+
+	t.Run("crappy path -- the token is not a JWT", func(t *testing.T) {
+		assert.True(t, getJWTExpiry("Bearer opaqueAccessToken").IsZero())
+	})
+
+	t.Run("crappy path -- the JWT does not contain the exp claim", func(t *testing.T) {
+		assert.True(t, getJWTExpiry(createJWT(`{"sub":"user"}`)).IsZero())
+	})
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// loginSession holds the session cookie obtained by logging in so it can be reused by all the API calls (also the ones
+// performed in parallel) until the API rejects it
+type loginSession struct {
+	mutex  sync.Mutex
+	cookie *http.Cookie
+}
+
+// apiSessionLoginAuthenticator is a specAPIKeyAuthenticator that logs in to the login URL with the credentials provided by
+// the user and stores the session cookie returned in the cookie jar used by the ProviderClient http client, so the session
+// cookie is sent along with the API requests
+type apiSessionLoginAuthenticator struct {
+	apiKey
+	loginURL   string
+	jar        http.CookieJar
+	httpClient *http.Client
+	// session is shared by all the copies of the authenticator so the login is performed once per provider configuration
+	session *loginSession
+}
+
+// newAPISessionLoginAuthenticator returns an authenticator that obtains the session cookie with the given name by posting
+// the credentials in the Authorization header to the login URL. The jar must be the cookie jar used by the http client
+// performing the API requests
+func newAPISessionLoginAuthenticator(cookieName, credentials, loginURL string, jar http.CookieJar) apiSessionLoginAuthenticator {
+	return apiSessionLoginAuthenticator{
+		apiKey: apiKey{
+			name:  cookieName,
+			value: credentials,
+		},
+		loginURL:   loginURL,
+		jar:        jar,
+		httpClient: &http.Client{Jar: jar},
+		session:    &loginSession{},
+	}
+}
+
+func (a apiSessionLoginAuthenticator) getContext() interface{} {
+	return a.apiKey
+}
+
+func (a apiSessionLoginAuthenticator) getType() authType {
+	return authTypeAPIKeyCookie
+}
+
+// setTransport sets the transport used to send the login requests
+func (a apiSessionLoginAuthenticator) setTransport(transport http.RoundTripper) {
+	a.httpClient.Transport = transport
+}
+
+// prepareAuth logs in if there is no session yet and stores the session cookie in the cookie jar for the request url, so
+// it is sent even if the API is not served from the same host as the login URL. The url remains the same
+func (a apiSessionLoginAuthenticator) prepareAuth(authContext *authContext) error {
+	cookie, err := a.getSessionCookie()
+	if err != nil {
+		return err
+	}
+	requestURL, err := url.Parse(authContext.url)
+	if err != nil {
+		return err
+	}
+	a.jar.SetCookies(requestURL, []*http.Cookie{{Name: cookie.Name, Value: cookie.Value, Path: "/", Expires: cookie.Expires}})
+	authContext.sessionInvalidators = append(authContext.sessionInvalidators, func() {
+		a.invalidateSession(cookie)
+	})
+	return nil
+}
+
+// getSessionCookie returns the session cookie, logging in if there is no session yet. The session is locked while logging
+// in so parallel API calls wait for the same login instead of logging in each
+func (a apiSessionLoginAuthenticator) getSessionCookie() (*http.Cookie, error) {
+	a.session.mutex.Lock()
+	defer a.session.mutex.Unlock()
+	if a.session.cookie != nil {
+		return a.session.cookie, nil
+	}
+	cookie, err := a.login()
+	if err != nil {
+		return nil, err
+	}
+	a.session.cookie = cookie
+	return cookie, nil
+}
+
+// invalidateSession discards the given session cookie so the next API call logs in again. If the session has already been
+// renewed (e,g: by a parallel API call that was also rejected), the current session is kept
+func (a apiSessionLoginAuthenticator) invalidateSession(cookie *http.Cookie) {
+	a.session.mutex.Lock()
+	defer a.session.mutex.Unlock()
+	if a.session.cookie == cookie {
+		log.Printf("[INFO] session cookie '%s' obtained from '%s' has been rejected, logging in again", a.name, a.loginURL)
+		a.session.cookie = nil
+	}
+}
+
+// login sends a post request to the login URL with the credentials in the Authorization header and returns the session
+// cookie set in the response. Cookies set in redirect responses are also considered as they are stored in the cookie jar
+func (a apiSessionLoginAuthenticator) login() (*http.Cookie, error) {
+	req, err := http.NewRequest(http.MethodPost, a.loginURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(authorizationHeader, a.value)
+	res, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("session login POST request '%s' failed: %s", a.loginURL, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("session login POST response '%s' status code '%d' not matching expected 2xx response status code", a.loginURL, res.StatusCode)
+	}
+	cookies := res.Cookies()
+	if loginURL, err := url.Parse(a.loginURL); err == nil {
+		cookies = append(cookies, a.jar.Cookies(loginURL)...)
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == a.name && cookie.Value != "" {
+			return cookie, nil
+		}
+	}
+	return nil, fmt.Errorf("session login POST response '%s' is missing the session cookie '%s'", a.loginURL, a.name)
+}
@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_APISessionLoginAuthenticator_Successfully_Prepares_Authorization(t *testing.T) {
+	t.Run("happy path -- the session cookie is stored in the cookie jar for the request url and the login is performed once", func(t *testing.T) {
+		logins := 0
+		loginServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logins++
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "Basic credentials", r.Header.Get(authorizationHeader))
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "someSession"})
+		}))
+		defer loginServer.Close()
+		jar, _ := cookiejar.New(nil)
+		authenticator := newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, jar)
+
+		for i := 0; i < 3; i++ {
+			ctx := &authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"}
+			err := authenticator.prepareAuth(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, "https://api.server.com/v1/resource", ctx.url)
+		}
+		requestURL, _ := url.Parse("https://api.server.com/v1/resource")
+		assert.Equal(t, []*http.Cookie{{Name: "session_id", Value: "someSession"}}, jar.Cookies(requestURL))
+		assert.Equal(t, 1, logins)
+	})
+
+	t.Run("happy path -- a new login is performed once the session is invalidated", func(t *testing.T) {
+		logins := 0
+		loginServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logins++
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "someSession"})
+		}))
+		defer loginServer.Close()
+		jar, _ := cookiejar.New(nil)
+		authenticator := newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, jar)
+
+		ctx := &authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"}
+		assert.NoError(t, authenticator.prepareAuth(ctx))
+		assert.True(t, ctx.invalidateSessions())
+		assert.NoError(t, authenticator.prepareAuth(&authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"}))
+		// invalidating a session that has already been renewed should not discard the new session
+		ctx.invalidateSessions()
+		assert.NoError(t, authenticator.prepareAuth(&authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"}))
+		assert.Equal(t, 2, logins)
+	})
+
+	t.Run("happy path -- the login request is sent using the transport set", func(t *testing.T) {
+		loginServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "someSession"})
+		}))
+		defer loginServer.Close()
+		jar, _ := cookiejar.New(nil)
+		authenticator := newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, jar)
+		// the login server certificate is only trusted by the login server client transport
+		authenticator.setTransport(loginServer.Client().Transport)
+		err := authenticator.prepareAuth(&authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("crappy path -- the login response status code is not successful", func(t *testing.T) {
+		loginServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer loginServer.Close()
+		jar, _ := cookiejar.New(nil)
+		authenticator := newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, jar)
+		err := authenticator.prepareAuth(&authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"})
+
+		assert.EqualError(t, err, "session login POST response '"+loginServer.URL+"' status code '401' not matching expected 2xx response status code")
+	})
+
+	t.Run("crappy path -- the login response does not set the session cookie", func(t *testing.T) {
+		loginServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "other_cookie", Value: "someValue"})
+		}))
+		defer loginServer.Close()
+		jar, _ := cookiejar.New(nil)
+		authenticator := newAPISessionLoginAuthenticator("session_id", "Basic credentials", loginServer.URL, jar)
+		err := authenticator.prepareAuth(&authContext{headers: map[string]string{}, url: "https://api.server.com/v1/resource"})
+
+		assert.EqualError(t, err, "session login POST response '"+loginServer.URL+"' is missing the session cookie 'session_id'")
+	})
+}
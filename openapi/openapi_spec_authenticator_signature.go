@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiKeySignatureAuthenticator is a specAPIKeyAuthenticator that signs the requests with the secret configured by the
+// user. The signature is an HMAC-SHA256 over the canonical form of the request (see getStringToSign)
+type apiKeySignatureAuthenticator struct {
+	apiKey
+	configuration signatureConfiguration
+	// now returns the time used to build the request timestamp
+	now func() time.Time
+}
+
+func newAPIKeySignatureAuthenticator(headerName, secret string, configuration signatureConfiguration) apiKeySignatureAuthenticator {
+	return apiKeySignatureAuthenticator{
+		apiKey: apiKey{
+			name:  headerName,
+			value: secret,
+		},
+		configuration: configuration,
+		now:           time.Now,
+	}
+}
+
+func (a apiKeySignatureAuthenticator) getContext() interface{} {
+	return a.apiKey
+}
+
+// getType returns authTypeAPIKeySignature so the authenticator is run once the rest of authenticators have prepared the
+// request (e,g: added query params to the url)
+func (a apiKeySignatureAuthenticator) getType() authType {
+	return authTypeAPIKeySignature
+}
+
+// prepareAuth adds the header containing the request signature along with the timestamp header (if the timestamp is part
+// of the signature). The url remains the same
+func (a apiKeySignatureAuthenticator) prepareAuth(authContext *authContext) error {
+	timestamp := a.getTimestamp()
+	stringToSign, err := a.getStringToSign(authContext, timestamp)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(a.value))
+	mac.Write([]byte(stringToSign))
+	signature := hex.EncodeToString(mac.Sum(nil))
+	if a.configuration.encoding == signatureEncodingBase64 {
+		signature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	if a.configuration.scheme != "" {
+		signature = fmt.Sprintf("%s %s", a.configuration.scheme, signature)
+	}
+	if authContext.headers == nil {
+		authContext.headers = map[string]string{}
+	}
+	authContext.headers[a.name] = signature
+	for _, component := range a.configuration.components {
+		if component == signatureComponentTimestamp {
+			authContext.headers[a.configuration.timestampHeader] = timestamp
+		}
+	}
+	return nil
+}
+
+// getStringToSign returns the configured components of the request joined with new lines:
+// - method: the request method in upper case (e,g: POST)
+// - path: the escaped url path (e,g: /v1/cdns)
+// - query: the url query params sorted by key and value (e,g: a=1&b=2)
+// - body: the hex encoded SHA256 hash of the request body (the hash of an empty body if the request does not have body)
+// - timestamp: the request timestamp
+func (a apiKeySignatureAuthenticator) getStringToSign(authContext *authContext, timestamp string) (string, error) {
+	requestURL, err := url.Parse(authContext.url)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign request to '%s': %s", authContext.url, err)
+	}
+	var parts []string
+	for _, component := range a.configuration.components {
+		switch component {
+		case signatureComponentMethod:
+			parts = append(parts, strings.ToUpper(string(authContext.method)))
+		case signatureComponentPath:
+			path := requestURL.EscapedPath()
+			if path == "" {
+				path = "/"
+			}
+			parts = append(parts, path)
+		case signatureComponentQuery:
+			parts = append(parts, getCanonicalQuery(requestURL.Query()))
+		case signatureComponentBody:
+			bodyHash := sha256.Sum256(authContext.body)
+			parts = append(parts, hex.EncodeToString(bodyHash[:]))
+		case signatureComponentTimestamp:
+			parts = append(parts, timestamp)
+		}
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+func (a apiKeySignatureAuthenticator) getTimestamp() string {
+	now := a.now().UTC()
+	if a.configuration.timestampFormat == signatureTimestampFormatRFC3339 {
+		return now.Format(time.RFC3339)
+	}
+	return strconv.FormatInt(now.Unix(), 10)
+}
+
+// getCanonicalQuery returns the query params percent-encoded as per RFC 3986 (see rfc3986Escape) and sorted by the encoded
+// name and, for repeated names, by the encoded value (e,g: a=1&a=2&b=some%20value). The given query is not modified
+func getCanonicalQuery(query url.Values) string {
+	type queryParam struct {
+		name  string
+		value string
+	}
+	var params []queryParam
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, queryParam{name: rfc3986Escape(name), value: rfc3986Escape(value)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+		return params[i].value < params[j].value
+	})
+	canonicalParams := make([]string, 0, len(params))
+	for _, param := range params {
+		canonicalParams = append(canonicalParams, param.name+"="+param.value)
+	}
+	return strings.Join(canonicalParams, "&")
+}
+
+// rfc3986Escape percent-encodes with upper case hex digits all the characters of the given string but the unreserved ones
+// as per RFC 3986 (A-Z, a-z, 0-9, '-', '.', '_' and '~'). Unlike url.QueryEscape, spaces are encoded as %20 instead of '+'
+func rfc3986Escape(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' {
+			escaped.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", c)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_APIKeySignatureAuthenticator_Successfully_Prepares_Authorization(t *testing.T) {
+	now := func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("happy path -- AuthContext is populated with the hex encoded signature of the request and the timestamp", func(t *testing.T) {
+		configuration, _ := newSignatureConfiguration(map[string]interface{}{extTfAuthenticationSchemeSignature: true})
+		authenticator := newAPIKeySignatureAuthenticator("X-Signature", "secret", configuration)
+		authenticator.now = now
+		ctx := &authContext{method: httpPost, url: "https://api.server.com/v1/cdns?b=3&a=2&a=1", body: []byte(`{"label":"cdn"}`)}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2a49fd5559eb77af74bd4843f80c88c3b544f6ef32eef8aca263e7aca7be00ab", ctx.headers["X-Signature"])
+		assert.Equal(t, "1577836800", ctx.headers[defaultSignatureTimestampHeader])
+		assert.Equal(t, "https://api.server.com/v1/cdns?b=3&a=2&a=1", ctx.url)
+	})
+
+	t.Run("happy path -- AuthContext is populated with the signature built as configured", func(t *testing.T) {
+		configuration := signatureConfiguration{
+			scheme:          "HMAC-SHA256",
+			encoding:        signatureEncodingBase64,
+			timestampHeader: "X-Date",
+			timestampFormat: signatureTimestampFormatRFC3339,
+			components:      []string{signatureComponentMethod, signatureComponentPath, signatureComponentTimestamp},
+		}
+		authenticator := newAPIKeySignatureAuthenticator(authorizationHeader, "secret", configuration)
+		authenticator.now = now
+		ctx := &authContext{method: httpGet, url: "https://api.server.com/v1/cdns"}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "HMAC-SHA256 6Q8nHGxiJ8OEXbIBBofQgIle80NT5/7+nNyAo7Jzww0=", ctx.headers[authorizationHeader])
+		assert.Equal(t, "2020-01-01T00:00:00Z", ctx.headers["X-Date"])
+	})
+
+	t.Run("happy path -- the timestamp header is not sent if the timestamp is not part of the signature", func(t *testing.T) {
+		configuration, _ := newSignatureConfiguration(map[string]interface{}{extTfAuthenticationSchemeSignature: map[string]interface{}{"components": []interface{}{"method", "path"}}})
+		authenticator := newAPIKeySignatureAuthenticator("X-Signature", "secret", configuration)
+		ctx := &authContext{method: httpGet, url: "https://api.server.com/v1/cdns"}
+		err := authenticator.prepareAuth(ctx)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ctx.headers["X-Signature"])
+		assert.NotContains(t, ctx.headers, defaultSignatureTimestampHeader)
+	})
+
+	t.Run("crappy path -- the url of the request is not valid", func(t *testing.T) {
+		configuration, _ := newSignatureConfiguration(map[string]interface{}{extTfAuthenticationSchemeSignature: true})
+		authenticator := newAPIKeySignatureAuthenticator("X-Signature", "secret", configuration)
+		err := authenticator.prepareAuth(&authContext{method: httpGet, url: "://api.server.com"})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to sign request to '://api.server.com'")
+	})
+}
+
+func Test_GetCanonicalQuery(t *testing.T) {
+	t.Run("happy path -- query params are sorted by key and value", func(t *testing.T) {
+		query := map[string][]string{"b": {"3"}, "a": {"2", "1"}, "c": {"some value"}}
+		assert.Equal(t, "a=1&a=2&b=3&c=some%20value", getCanonicalQuery(query))
+	})
+
+	t.Run("happy path -- spaces and reserved characters are percent-encoded as per RFC 3986 while unreserved characters are not", func(t *testing.T) {
+		query := map[string][]string{
+			"filter":   {"name eq 'a+b'"},
+			"redirect": {"https://host.com/path?x=1&y=2"},
+			"a b":      {"~unreserved-chars_are.kept"},
+		}
+		assert.Equal(t, "a%20b=~unreserved-chars_are.kept&filter=name%20eq%20%27a%2Bb%27&redirect=https%3A%2F%2Fhost.com%2Fpath%3Fx%3D1%26y%3D2", getCanonicalQuery(query))
+	})
+
+	t.Run("happy path -- the query params given are not modified", func(t *testing.T) {
+		query := map[string][]string{"a": {"2", "1"}}
+		getCanonicalQuery(query)
+		assert.Equal(t, []string{"2", "1"}, query["a"])
+	})
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// defaultTokenExpiryMargin is how long before the access token expiry a new access token is requested, so requests are
+// not sent with tokens that expire in flight
+const defaultTokenExpiryMargin = time.Duration(10 * time.Second)
+
+// accessTokenCache holds the access token obtained by an authenticator so it can be reused by all the API calls (also
+// the ones performed in parallel) until it is about to expire or the API rejects it
+type accessTokenCache struct {
+	mutex sync.Mutex
+	token *oauth2Token
+	// expiryMargin is how long before the token expiry a new token is requested. Defaults to defaultTokenExpiryMargin
+	expiryMargin time.Duration
+}
+
+func newAccessTokenCache(expiryMargin time.Duration) *accessTokenCache {
+	if expiryMargin <= 0 {
+		expiryMargin = defaultTokenExpiryMargin
+	}
+	return &accessTokenCache{expiryMargin: expiryMargin}
+}
+
+// getToken returns the cached token if it is still valid; otherwise, a new token is obtained with the given function and
+// cached. The cache is locked while the token is obtained so parallel API calls wait for the same token instead of
+// requesting a new token each
+func (c *accessTokenCache) getToken(newToken func() (*oauth2Token, error)) (*oauth2Token, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.valid(c.token) {
+		return c.token, nil
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	c.token = token
+	return token, nil
+}
+
+// valid returns true if the given token is not about to expire. Tokens with zero expiry do not expire
+func (c *accessTokenCache) valid(token *oauth2Token) bool {
+	if token == nil || token.AccessToken == "" {
+		return false
+	}
+	return token.expiry.IsZero() || time.Now().Add(c.expiryMargin).Before(token.expiry)
+}
+
+// invalidate discards the given token so the next API call obtains a new one. If the cached token has already been
+// renewed (e,g: by a parallel API call that was also rejected), the current token is kept
+func (c *accessTokenCache) invalidate(token *oauth2Token) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.token == token {
+		log.Printf("[DEBUG] discarding cached access token")
+		c.token = nil
+	}
+}
+
+// sessionInvalidator returns the function that discards the given token when the API rejects it with 401 Unauthorized
+func (c *accessTokenCache) sessionInvalidator(token *oauth2Token) func() {
+	return func() {
+		c.invalidate(token)
+	}
+}
@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTokenCache(t *testing.T) {
+	t.Run("happy path -- the cached token is returned while it is not about to expire", func(t *testing.T) {
+		cache := newAccessTokenCache(0)
+		tokenRequests := 0
+		newToken := func() (*oauth2Token, error) {
+			tokenRequests++
+			return &oauth2Token{AccessToken: "accessToken", expiry: time.Now().Add(time.Hour)}, nil
+		}
+		token, err := cache.getToken(newToken)
+		assert.NoError(t, err)
+		cachedToken, err := cache.getToken(newToken)
+		assert.NoError(t, err)
+		assert.True(t, token == cachedToken)
+		assert.Equal(t, 1, tokenRequests)
+		assert.Equal(t, defaultTokenExpiryMargin, cache.expiryMargin)
+	})
+
+	t.Run("happy path -- a new token is obtained when the cached one is about to expire", func(t *testing.T) {
+		cache := newAccessTokenCache(time.Minute)
+		cache.token = &oauth2Token{AccessToken: "expiringAccessToken", expiry: time.Now().Add(30 * time.Second)}
+		token, err := cache.getToken(func() (*oauth2Token, error) {
+			return &oauth2Token{AccessToken: "accessToken"}, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "accessToken", token.AccessToken)
+	})
+
+	t.Run("happy path -- invalidating a token that has already been renewed keeps the current token", func(t *testing.T) {
+		cache := newAccessTokenCache(0)
+		rejectedToken := &oauth2Token{AccessToken: "rejectedAccessToken"}
+		cache.token = &oauth2Token{AccessToken: "accessToken"}
+		cache.sessionInvalidator(rejectedToken)()
+		assert.Equal(t, "accessToken", cache.token.AccessToken)
+		cache.invalidate(cache.token)
+		assert.Nil(t, cache.token)
+	})
+
+	t.Run("crappy path -- the error returned when obtaining the token is returned and nothing is cached", func(t *testing.T) {
+		cache := newAccessTokenCache(0)
+		_, err := cache.getToken(func() (*oauth2Token, error) {
+			return nil, errors.New("token request failed")
+		})
+		assert.EqualError(t, err, "token request failed")
+		assert.Nil(t, cache.token)
+	})
+}
@@ -8,4 +8,5 @@ type SpecBackendConfiguration interface {
 	getHostByRegion(region string) (string, error)
 	isMultiRegion() (bool, string, []string, error)
 	getDefaultRegion([]string) (string, error)
+	getHostVariables() (specHostVariables, error)
 }
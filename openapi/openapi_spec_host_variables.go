@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// specHostVariable defines a variable that is part of the host template (e,g: api.${environment}.server.com). Each host
+// variable is exposed as a provider property so users can pick the value (out of the allowed values, if any) that will
+// be used when calling the API. If the user does not provide a value, the default value will be used instead.
+type specHostVariable struct {
+	name          string
+	defaultValue  string
+	allowedValues []string
+}
+
+// specHostVariables groups a list of specHostVariable
+type specHostVariables []specHostVariable
+
+// getHostVariableValues returns a map containing the host variable names and their values. The values are read from
+// the given configured values; if a variable does not have a configured value the default value will be used instead
+func (s specHostVariables) getHostVariableValues(configuredValues map[string]string) map[string]string {
+	values := map[string]string{}
+	for _, hostVariable := range s {
+		value := configuredValues[hostVariable.name]
+		if value == "" {
+			value = hostVariable.defaultValue
+		}
+		values[hostVariable.name] = value
+	}
+	return values
+}
+
+// resolveHostVariables replaces the host variable placeholders (e,g: ${environment}) found in the given value with the
+// corresponding host variable values
+func resolveHostVariables(value string, hostVariableValues map[string]string) string {
+	for name, hostVariableValue := range hostVariableValues {
+		value = strings.Replace(value, buildHostVariablePlaceholder(name), hostVariableValue, -1)
+	}
+	return value
+}
+
+// buildHostVariablePlaceholder returns the placeholder used in the host template for the given host variable name
+func buildHostVariablePlaceholder(name string) string {
+	return fmt.Sprintf("${%s}", name)
+}
@@ -0,0 +1,53 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetHostVariableValues(t *testing.T) {
+	Convey("Given some host variables", t, func() {
+		hostVariables := specHostVariables{
+			{name: "environment", defaultValue: "prod", allowedValues: []string{"prod", "dev"}},
+			{name: "cell", defaultValue: "cell1"},
+		}
+		Convey("When getHostVariableValues is called with a value configured for one of the host variables", func() {
+			values := hostVariables.getHostVariableValues(map[string]string{"environment": "dev"})
+			Convey("Then the values returned should contain the configured value and the default value for the rest", func() {
+				So(values, ShouldResemble, map[string]string{"environment": "dev", "cell": "cell1"})
+			})
+		})
+		Convey("When getHostVariableValues is called with nil configured values", func() {
+			values := hostVariables.getHostVariableValues(nil)
+			Convey("Then the values returned should be the default values", func() {
+				So(values, ShouldResemble, map[string]string{"environment": "prod", "cell": "cell1"})
+			})
+		})
+	})
+}
+
+func TestResolveHostVariables(t *testing.T) {
+	testCases := []struct {
+		name               string
+		value              string
+		hostVariableValues map[string]string
+		expectedValue      string
+	}{
+		{name: "host with one host variable", value: "api.${environment}.server.com", hostVariableValues: map[string]string{"environment": "dev"}, expectedValue: "api.dev.server.com"},
+		{name: "host with multiple host variables", value: "${cell}.api.${environment}.server.com", hostVariableValues: map[string]string{"environment": "dev", "cell": "cell1"}, expectedValue: "cell1.api.dev.server.com"},
+		{name: "host with the same host variable twice", value: "${environment}.api.${environment}.server.com", hostVariableValues: map[string]string{"environment": "dev"}, expectedValue: "dev.api.dev.server.com"},
+		{name: "host with unknown host variable", value: "api.${tenant}.server.com", hostVariableValues: map[string]string{"environment": "dev"}, expectedValue: "api.${tenant}.server.com"},
+		{name: "host without host variables", value: "api.server.com", hostVariableValues: map[string]string{"environment": "dev"}, expectedValue: "api.server.com"},
+	}
+	for _, tc := range testCases {
+		Convey("Given a "+tc.name, t, func() {
+			Convey("When resolveHostVariables is called", func() {
+				value := resolveHostVariables(tc.value, tc.hostVariableValues)
+				Convey("Then the value returned should be the expected one", func() {
+					So(value, ShouldEqual, tc.expectedValue)
+				})
+			})
+		})
+	}
+}
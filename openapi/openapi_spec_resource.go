@@ -13,6 +13,9 @@ type SpecResource interface {
 	shouldIgnoreResource() bool
 	getResourceOperations() specResourceOperations
 	getTimeouts() (*specTimeouts, error)
+	// isSingleton returns true if the resource is a singleton (e,g: /v1/account/settings), that is a resource that does
+	// not expose a root path POST operation and is managed via PUT/GET (and optionally DELETE) against the same path
+	isSingleton() bool
 	// getParentResourceInfo returns a struct populated with relevant parentResourceInfo if the resource is considered
 	// a subresource; nil otherwise.
 	getParentResourceInfo() *parentResourceInfo
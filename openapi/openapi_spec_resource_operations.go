@@ -1,16 +1,148 @@
 package openapi
 
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Content types supported when updating resources via PATCH
+const (
+	// mergePatchContentType is the content type of JSON Merge Patch (RFC 7396) documents
+	mergePatchContentType = "application/merge-patch+json"
+	// jsonPatchContentType is the content type of JSON Patch (RFC 6902) documents
+	jsonPatchContentType = "application/json-patch+json"
+)
+
 type specResourceOperations struct {
 	List   *specResourceOperation
 	Post   *specResourceOperation
 	Get    *specResourceOperation
 	Put    *specResourceOperation
+	Patch  *specResourceOperation
 	Delete *specResourceOperation
+	// Actions contains the custom methods exposed by the resource (e,g: POST /v1/certs/{id}:rotate) that can be triggered
+	// from the terraform configuration
+	Actions []*specResourceAction
+}
+
+// specResourceAction defines a resource custom method (e,g: POST /v1/certs/{id}:rotate) that is invoked when the value of
+// the trigger attribute changes
+type specResourceAction struct {
+	// Name is the custom method name (e,g: rotate)
+	Name string
+	// TriggerAttribute is the name of the terraform attribute that triggers the custom method when its value changes
+	TriggerAttribute string
+	Operation        *specResourceOperation
+}
+
+// getCreateOperation returns the operation that should be used to create the resource along with the corresponding HTTP
+// method. POST is used if the resource exposes a POST operation on the root path; otherwise, the resource is expected to
+// be created via PUT on the instance path using a client generated ID. Nil is returned if the resource does not support
+// creation
+func (o specResourceOperations) getCreateOperation() (httpMethodSupported, *specResourceOperation) {
+	if o.Post != nil {
+		return httpPost, o.Post
+	}
+	if o.Put != nil {
+		return httpPut, o.Put
+	}
+	return "", nil
+}
+
+// getUpdateOperation returns the operation that should be used to update the resource along with the corresponding HTTP
+// method. PATCH is used if the resource does not expose a PUT operation or if the PATCH operation explicitly specifies the
+// patch content type; otherwise PUT is used. Nil is returned if the resource does not support updates
+func (o specResourceOperations) getUpdateOperation() (httpMethodSupported, *specResourceOperation) {
+	if o.Patch != nil && (o.Put == nil || o.Patch.PatchContentType != "") {
+		return httpPatch, o.Patch
+	}
+	if o.Put != nil {
+		return httpPut, o.Put
+	}
+	return "", nil
 }
 
 // specResourceOperation defines a resource operation
 type specResourceOperation struct {
 	SecuritySchemes  SpecSecuritySchemes
 	HeaderParameters SpecHeaderParameters
-	responses        specResponses
+	// Schemes contains the schemes specified at the operation level (if any). If present, they take precedence over the
+	// global schemes
+	Schemes []string
+	// Host contains the host specified at the operation level (if any). If present, it takes precedence over the global
+	// host and the resource host override
+	Host string
+	// BasePath contains the base path specified at the operation or resource level (if any). If present, it takes
+	// precedence over the global base path
+	BasePath string
+	// PatchContentType contains the content type used to encode the PATCH request payloads (only applicable to PATCH
+	// operations). Supported values are application/merge-patch+json and application/json-patch+json
+	PatchContentType string
+	// RetryableStatusCodes contains the response status codes on which the requests are retried (if any). If empty, the
+	// default retryable status codes are used instead
+	RetryableStatusCodes []int
+	// IdempotencyKeyHeader contains the name of the header used to send the idempotency key of POST requests (if any).
+	// Requests including an idempotency key are safe to retry
+	IdempotencyKeyHeader string
+	// IfMatch defines whether the requests include the If-Match header with the resource ETag (optimistic concurrency)
+	IfMatch   bool
+	responses specResponses
+}
+
+// getPatchContentType returns the content type used to encode PATCH request payloads, JSON Merge Patch being the default
+// if the operation does not specify one
+func (o *specResourceOperation) getPatchContentType() (string, error) {
+	if o == nil || o.PatchContentType == "" {
+		return mergePatchContentType, nil
+	}
+	switch o.PatchContentType {
+	case mergePatchContentType, jsonPatchContentType:
+		return o.PatchContentType, nil
+	}
+	return "", fmt.Errorf("patch content type '%s' not supported - must use %s or %s", o.PatchContentType, mergePatchContentType, jsonPatchContentType)
+}
+
+// getHTTPScheme returns the scheme configured for the operation, https is preferred if the operation supports both http
+// and https. An empty scheme is returned if the operation does not specify any schemes, in which case the global scheme
+// is expected to be used
+func (o *specResourceOperation) getHTTPScheme() (string, error) {
+	if o == nil || len(o.Schemes) == 0 {
+		return "", nil
+	}
+	var defaultScheme string
+	for _, s := range o.Schemes {
+		if s == "https" {
+			return s, nil
+		}
+		if s == "http" {
+			defaultScheme = s
+		}
+	}
+	if defaultScheme == "" {
+		return "", fmt.Errorf("specified operation schemes %s are not supported - must use http or https", o.Schemes)
+	}
+	return defaultScheme, nil
+}
+
+// getRetryableStatusCodes returns the status codes defined in the 'x-terraform-retryable-status-codes' extension (comma
+// separated values, e,g: "429, 503"). Invalid status codes are ignored
+func getRetryableStatusCodes(extensions spec.Extensions) []int {
+	value, exists := extensions.GetString(extTfRetryableStatusCodes)
+	if !exists {
+		return nil
+	}
+	var statusCodes []int
+	for _, code := range strings.Split(value, ",") {
+		statusCode, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil {
+			log.Printf("[WARN] ignoring invalid status code '%s' in extension '%s'", code, extTfRetryableStatusCodes)
+			continue
+		}
+		statusCodes = append(statusCodes, statusCode)
+	}
+	return statusCodes
 }
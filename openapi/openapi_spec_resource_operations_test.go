@@ -0,0 +1,165 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSpecResourceOperationGetHTTPScheme(t *testing.T) {
+	Convey("Given a specResourceOperation with http and https schemes", t, func() {
+		operation := &specResourceOperation{Schemes: []string{"http", "https"}}
+		Convey("When getHTTPScheme method is called", func() {
+			scheme, err := operation.getHTTPScheme()
+			Convey("Then the scheme returned should be https", func() {
+				So(err, ShouldBeNil)
+				So(scheme, ShouldEqual, "https")
+			})
+		})
+	})
+	Convey("Given a specResourceOperation with only the http scheme", t, func() {
+		operation := &specResourceOperation{Schemes: []string{"http"}}
+		Convey("When getHTTPScheme method is called", func() {
+			scheme, err := operation.getHTTPScheme()
+			Convey("Then the scheme returned should be http", func() {
+				So(err, ShouldBeNil)
+				So(scheme, ShouldEqual, "http")
+			})
+		})
+	})
+	Convey("Given a specResourceOperation with no schemes", t, func() {
+		operation := &specResourceOperation{}
+		Convey("When getHTTPScheme method is called", func() {
+			scheme, err := operation.getHTTPScheme()
+			Convey("Then the scheme returned should be empty", func() {
+				So(err, ShouldBeNil)
+				So(scheme, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given a nil specResourceOperation", t, func() {
+		var operation *specResourceOperation
+		Convey("When getHTTPScheme method is called", func() {
+			scheme, err := operation.getHTTPScheme()
+			Convey("Then the scheme returned should be empty", func() {
+				So(err, ShouldBeNil)
+				So(scheme, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given a specResourceOperation with non supported schemes", t, func() {
+		operation := &specResourceOperation{Schemes: []string{"ws", "wss"}}
+		Convey("When getHTTPScheme method is called", func() {
+			_, err := operation.getHTTPScheme()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "specified operation schemes [ws wss] are not supported - must use http or https")
+			})
+		})
+	})
+}
+
+func TestSpecResourceOperationGetPatchContentType(t *testing.T) {
+	Convey("Given a specResourceOperation that does not specify the patch content type", t, func() {
+		operation := &specResourceOperation{}
+		Convey("When getPatchContentType method is called", func() {
+			contentType, err := operation.getPatchContentType()
+			Convey("Then the content type returned should be the JSON Merge Patch one", func() {
+				So(err, ShouldBeNil)
+				So(contentType, ShouldEqual, mergePatchContentType)
+			})
+		})
+	})
+	Convey("Given a specResourceOperation with the JSON Patch content type", t, func() {
+		operation := &specResourceOperation{PatchContentType: jsonPatchContentType}
+		Convey("When getPatchContentType method is called", func() {
+			contentType, err := operation.getPatchContentType()
+			Convey("Then the content type returned should be the JSON Patch one", func() {
+				So(err, ShouldBeNil)
+				So(contentType, ShouldEqual, jsonPatchContentType)
+			})
+		})
+	})
+	Convey("Given a specResourceOperation with a non supported patch content type", t, func() {
+		operation := &specResourceOperation{PatchContentType: "application/json"}
+		Convey("When getPatchContentType method is called", func() {
+			_, err := operation.getPatchContentType()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "patch content type 'application/json' not supported - must use application/merge-patch+json or application/json-patch+json")
+			})
+		})
+	})
+}
+
+func TestSpecResourceOperationsGetUpdateOperation(t *testing.T) {
+	Convey("Given specResourceOperations with PUT and PATCH operations", t, func() {
+		operations := specResourceOperations{Put: &specResourceOperation{}, Patch: &specResourceOperation{}}
+		Convey("When getUpdateOperation method is called", func() {
+			method, operation := operations.getUpdateOperation()
+			Convey("Then the PUT operation should be returned", func() {
+				So(method, ShouldEqual, httpPut)
+				So(operation, ShouldEqual, operations.Put)
+			})
+		})
+	})
+	Convey("Given specResourceOperations with PUT and a PATCH operation that specifies the patch content type", t, func() {
+		operations := specResourceOperations{Put: &specResourceOperation{}, Patch: &specResourceOperation{PatchContentType: jsonPatchContentType}}
+		Convey("When getUpdateOperation method is called", func() {
+			method, operation := operations.getUpdateOperation()
+			Convey("Then the PATCH operation should be returned", func() {
+				So(method, ShouldEqual, httpPatch)
+				So(operation, ShouldEqual, operations.Patch)
+			})
+		})
+	})
+	Convey("Given specResourceOperations with only a PATCH operation", t, func() {
+		operations := specResourceOperations{Patch: &specResourceOperation{}}
+		Convey("When getUpdateOperation method is called", func() {
+			method, operation := operations.getUpdateOperation()
+			Convey("Then the PATCH operation should be returned", func() {
+				So(method, ShouldEqual, httpPatch)
+				So(operation, ShouldEqual, operations.Patch)
+			})
+		})
+	})
+	Convey("Given specResourceOperations with no update operations", t, func() {
+		operations := specResourceOperations{}
+		Convey("When getUpdateOperation method is called", func() {
+			_, operation := operations.getUpdateOperation()
+			Convey("Then the operation returned should be nil", func() {
+				So(operation, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestSpecResourceOperationsGetCreateOperation(t *testing.T) {
+	Convey("Given specResourceOperations with POST and PUT operations", t, func() {
+		operations := specResourceOperations{Post: &specResourceOperation{}, Put: &specResourceOperation{}}
+		Convey("When getCreateOperation method is called", func() {
+			method, operation := operations.getCreateOperation()
+			Convey("Then the POST operation should be returned", func() {
+				So(method, ShouldEqual, httpPost)
+				So(operation, ShouldEqual, operations.Post)
+			})
+		})
+	})
+	Convey("Given specResourceOperations with only a PUT operation", t, func() {
+		operations := specResourceOperations{Put: &specResourceOperation{}}
+		Convey("When getCreateOperation method is called", func() {
+			method, operation := operations.getCreateOperation()
+			Convey("Then the PUT operation should be returned", func() {
+				So(method, ShouldEqual, httpPut)
+				So(operation, ShouldEqual, operations.Put)
+			})
+		})
+	})
+	Convey("Given specResourceOperations with no create operations", t, func() {
+		operations := specResourceOperations{}
+		Convey("When getCreateOperation method is called", func() {
+			_, operation := operations.getCreateOperation()
+			Convey("Then the operation returned should be nil", func() {
+				So(operation, ShouldBeNil)
+			})
+		})
+	})
+}
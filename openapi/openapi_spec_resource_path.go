@@ -0,0 +1,183 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+const pathParameterRegex = "/({[\\w]*})*/"
+
+// resourceVersionRegexTemplate is used to identify the version attached to the given resource. The parameter in the
+// template will be replaced with the actual resource name so if there is a match the version grabbed is assured to belong
+// to the resource in question and not any other version showing in the path before the resource name
+const resourceVersionRegexTemplate = "/(v[\\d]*)/%s"
+
+const resourceNameRegex = "((/\\w*[/]?))+$"
+
+// resourceParentNameRegex is the regex used to identify the different parents from a path that is a sub-resource. If used
+// calling FindStringSubmatch, any match will contain the following groups in the corresponding array index:
+// Index 0: This value will represent the full match containing also the path parameter (e,g: /v1/cdns/{id})
+// Index 1: This value will represent the resource path (without the instance path parameter) - e,g: /v1/cdns
+// Index 2: This value will represent version if it exists in the path (e,g: v1)
+// Index 3: This value will represent the resource path name (e,g: cdns)
+//
+// - Example calling FindAllStringSubmatch with '/v1/cdns/{id}/v1/firewalls' path:
+// matches, _ := resourceParentRegex.FindAllStringSubmatch("/v1/cdns/{id}/v1/firewalls", -1)
+// matches[0][0]: Full match /v1/cdns/{id}
+// matches[0][1]: Group 1. /v1/cdns
+// matches[0][2]: Group 2. v1
+// matches[0][3]: Group 3. cdns
+
+// - Example calling FindAllStringSubmatch with '/v1/cdns/{id}/v2/firewalls/{id}/v3/rules' path
+// matches, _ := resourceParentRegex.FindAllStringSubmatch("/v1/cdns/{id}/v2/firewalls/{id}/v3/rules", -1)
+// matches[0][0]: Full match /v1/cdns/{id}
+// matches[0][1]: Group 1. /v1/cdns
+// matches[0][2]: Group 2. v1
+// matches[0][3]: Group 3. cdns
+// matches[1][0]: Full match /v2/firewalls/{id}
+// matches[1][1]: Group 1. /v2/firewalls
+// matches[1][2]: Group 2. v2
+// matches[1][3]: Group 3. firewalls
+const resourceParentNameRegex = `(\/(?:\w+\/)?(?:v\d+\/)?\w+)\/{\w+}`
+
+const resourceInstanceRegex = "((?:.*)){.*}"
+
+// customMethodPathRegex is used to identify custom method paths as defined by the Google API design guidelines (AIP-136),
+// that is paths where the last segment is followed by a colon and the custom method name (e,g: /v1/instances/{id}:start).
+// If used calling FindStringSubmatch, any match will contain the following groups in the corresponding array index:
+// Index 1: This value will represent the path the custom method applies to (e,g: /v1/instances/{id})
+// Index 2: This value will represent the custom method name (e,g: start)
+const customMethodPathRegex = `^(/.*[^/]):(\w+)$`
+
+// isCustomMethodPath checks if the given path is a custom method path (e,g: /v1/instances/{id}:start)
+func isCustomMethodPath(path string) bool {
+	customMethodRegex, _ := regexp.Compile(customMethodPathRegex)
+	return customMethodRegex.MatchString(path)
+}
+
+// getResourceCustomMethodName returns the name of the custom method if the given path is a custom method of the resource
+// identified by the resource root path (e,g: /v1/instances/{id}:start is a custom method of /v1/instances); false is
+// returned otherwise. Custom methods of singleton resources apply to the resource path itself (e,g: /v1/account/settings:reset)
+func getResourceCustomMethodName(resourcePath string, isSingleton bool, path string) (string, bool) {
+	customMethodRegex, _ := regexp.Compile(customMethodPathRegex)
+	match := customMethodRegex.FindStringSubmatch(path)
+	if len(match) != 3 {
+		return "", false
+	}
+	customMethodPath, customMethodName := match[1], match[2]
+	if isSingleton {
+		return customMethodName, customMethodPath == resourcePath
+	}
+	instancePathRegex, _ := regexp.Compile(fmt.Sprintf(`^%s/{[^/{}]+}$`, regexp.QuoteMeta(strings.TrimRight(resourcePath, "/"))))
+	return customMethodName, instancePathRegex.MatchString(customMethodPath)
+}
+
+// buildResourceNameFromPath returns the name of the resource (including the version if applicable and using the preferred name
+// if provided). The name will be calculated using the last part of the path which is meant to be the resource name that the URI
+// refers to (e,g: /resource/{id}). If the path is versioned /v1/resource/{id} then the corresponding returned name will
+// be either the built name from the path or the preferred name with the version appended at the end.
+// For instance, given the following input the output will be:
+// /cdns/{id} -> cdns
+// /cdns/{id} and preferred name being cdn -> cdn
+// /v1/cdns/{id} -> cdns_v1
+// /v1/cdns/{id} and preferred name being cdn -> cdn_v1
+func buildResourceNameFromPath(resourcePath, preferredName string) (string, error) {
+	nameRegex, _ := regexp.Compile(resourceNameRegex)
+	var resourceName string
+	matches := nameRegex.FindStringSubmatch(resourcePath)
+	if len(matches) < 2 {
+		return "", fmt.Errorf("could not find a valid name for resource instance path '%s'", resourcePath)
+	}
+	resourceName = strings.Replace(matches[len(matches)-1], "/", "", -1)
+
+	versionRegex, _ := regexp.Compile(fmt.Sprintf(resourceVersionRegexTemplate, resourceName))
+
+	if preferredName != "" {
+		resourceName = preferredName
+	}
+
+	fullResourceName := resourceName
+	v := versionRegex.FindAllStringSubmatch(resourcePath, -1)
+	if len(v) > 0 {
+		version := v[0][1]
+		fullResourceName = fmt.Sprintf("%s_%s", resourceName, version)
+	}
+
+	return fullResourceName, nil
+}
+
+// resolveResourcePath returns the given resource path with its path parameters resolved based on the ids provided. For
+// instance, considering the given resource path "/v1/cdns/{cdn_id}/v1/firewalls" and the []strin{"cdnID"} the returned
+// path will be "/v1/cdns/cdnID/v1/firewalls". If the resource path is not parameterised, then regular path will be
+// returned accordingly
+func resolveResourcePath(resourcePath string, parentIDs []string) (string, error) {
+	resolvedPath := resourcePath
+
+	pathParameterRegex, _ := regexp.Compile(pathParameterRegex)
+	pathParamsMatches := pathParameterRegex.FindAllStringSubmatch(resolvedPath, -1)
+
+	switch {
+	case len(pathParamsMatches) == 0:
+		return resolvedPath, nil
+
+	case len(parentIDs) > len(pathParamsMatches):
+		return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' with the given ids - more ids than path params: %s", resolvedPath, parentIDs)
+
+	case len(parentIDs) < len(pathParamsMatches):
+		return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' with the given ids - missing ids to resolve the path params properly: %s", resolvedPath, parentIDs)
+	}
+
+	// At this point it's assured that there is an equal number of parameters to resolved and their corresponding ID values
+	for idx, parentID := range parentIDs {
+		if strings.Contains(parentID, "/") {
+			return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' due to parent IDs (%s) containing not supported characters (forward slashes)", resolvedPath, parentIDs)
+		}
+		resolvedPath = strings.Replace(resolvedPath, pathParamsMatches[idx][1], parentIDs[idx], 1)
+	}
+
+	return resolvedPath, nil
+}
+
+// buildParentResourceInfo returns the parentResourceInfo for the given resource path if the path belongs to a subresource;
+// nil otherwise. The preferredParentName function is used to look up the preferred name of each of the parents (e,g: the
+// value of the x-terraform-resource-name extension) given the parent root URI.
+func buildParentResourceInfo(resourcePath string, preferredParentName func(parentURI string) string) *parentResourceInfo {
+	resourceParentRegex, _ := regexp.Compile(resourceParentNameRegex)
+	parentMatches := resourceParentRegex.FindAllStringSubmatch(resourcePath, -1)
+	if len(parentMatches) > 0 {
+		var parentURI string
+		var parentInstanceURI string
+
+		var parentResourceNames, parentURIs, parentInstanceURIs []string
+		for _, match := range parentMatches {
+			fullMatch := match[0]
+			rootPath := match[1]
+			parentURI = parentInstanceURI + rootPath
+			parentInstanceURI = parentInstanceURI + fullMatch
+			parentURIs = append(parentURIs, parentURI)
+			parentInstanceURIs = append(parentInstanceURIs, parentInstanceURI)
+		}
+
+		fullParentResourceName := ""
+		for _, parentURI := range parentURIs {
+			parentResourceName, err := buildResourceNameFromPath(parentURI, preferredParentName(parentURI))
+			if err != nil {
+				log.Printf("[ERROR] could not build parent resource info due to the following error: %s", err)
+				return nil //untested
+			}
+			parentResourceNames = append(parentResourceNames, parentResourceName)
+			fullParentResourceName = fullParentResourceName + parentResourceName + "_"
+		}
+		fullParentResourceName = strings.TrimRight(fullParentResourceName, "_")
+
+		return &parentResourceInfo{
+			parentResourceNames:    parentResourceNames,
+			fullParentResourceName: fullParentResourceName,
+			parentURIs:             parentURIs,
+			parentInstanceURIs:     parentInstanceURIs,
+		}
+	}
+	return nil
+}
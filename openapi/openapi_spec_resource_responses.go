@@ -1,11 +1,46 @@
 package openapi
 
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+)
+
+// Default values used when polling operation resources (see specPollOperation)
+const (
+	defaultPollOperationStatusField     = "status"
+	defaultPollOperationResourceIDField = "resourceId"
+	defaultPollOperationErrorField      = "error"
+)
+
+var defaultPollOperationCompletedStatuses = []string{"succeeded"}
+var defaultPollOperationFailedStatuses = []string{"failed", "canceled"}
+
 type specResponses map[int]*specResponse
 
 type specResponse struct {
 	isPollingEnabled    bool
 	pollTargetStatuses  []string
 	pollPendingStatuses []string
+	// pollFailedStatuses contains the statuses that make the polling fail straight away
+	pollFailedStatuses []string
+	// pollStatusField is the JSON path of the property containing the resource status (e,g: $.status.state). If empty,
+	// the resource schema status field is used instead
+	pollStatusField string
+	// pollErrorField is the JSON path of the property containing the error details when the resource reaches a failed status
+	pollErrorField string
+	// pollConfiguration contains the timings used when polling the resource (or the operation resource)
+	pollConfiguration specPollConfiguration
+	// pollOperation is only populated if the response is configured to poll the operation resource returned in the
+	// Location/Operation-Location response headers
+	pollOperation *specPollOperation
+	// isLongRunningOperation defines whether the response payload is a long-running operation as defined by the Google
+	// API design guidelines (AIP-151)
+	isLongRunningOperation bool
 }
 
 func (s specResponses) getResponse(responseStatusCode int) *specResponse {
@@ -15,3 +50,192 @@ func (s specResponses) getResponse(responseStatusCode int) *specResponse {
 	}
 	return response
 }
+
+// isPollFailedStatus returns true if the given status is one of the response poll failed statuses
+func (s *specResponse) isPollFailedStatus(status string) bool {
+	if s == nil {
+		return false
+	}
+	for _, failedStatus := range s.pollFailedStatuses {
+		if failedStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+// specPollConfiguration defines the timings used when polling a resource (or an operation resource) until it reaches a
+// completion status. Zero values mean the value is not configured and the default one will be used instead
+type specPollConfiguration struct {
+	// delay is the time to wait before polling for the first time
+	delay time.Duration
+	// interval is the time to wait between polls
+	interval time.Duration
+	// maxInterval is the maximum time to wait between polls when the interval is increased by the backoff factor
+	maxInterval time.Duration
+	// backoffFactor is the factor the interval is multiplied by after each poll. Values lower or equal to 1 disable the
+	// exponential backoff
+	backoffFactor float64
+}
+
+// newSpecPollConfiguration returns the specPollConfiguration defined by the poll timing extensions. Invalid values are
+// ignored so the default values are used instead
+func newSpecPollConfiguration(extensions spec.Extensions) specPollConfiguration {
+	pollConfiguration := specPollConfiguration{}
+	for extension, value := range map[string]*time.Duration{
+		extTfResourcePollDelay:       &pollConfiguration.delay,
+		extTfResourcePollInterval:    &pollConfiguration.interval,
+		extTfResourcePollMaxInterval: &pollConfiguration.maxInterval,
+	} {
+		if durationValue, exists := extensions.GetString(extension); exists {
+			duration, err := parsePollDuration(durationValue)
+			if err != nil {
+				log.Printf("[WARN] ignoring extension '%s': %s", extension, err)
+				continue
+			}
+			*value = duration
+		}
+	}
+	if backoffFactorValue, exists := extensions[strings.ToLower(extTfResourcePollBackoffFactor)]; exists {
+		backoffFactor, err := parsePollBackoffFactor(backoffFactorValue)
+		if err != nil {
+			log.Printf("[WARN] ignoring extension '%s': %s", extTfResourcePollBackoffFactor, err)
+		} else {
+			pollConfiguration.backoffFactor = backoffFactor
+		}
+	}
+	return pollConfiguration
+}
+
+// parsePollDuration parses the given duration (e,g: 30s, 1.5m or 1h). Negative durations are not allowed
+func parsePollDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value '%s': %s", value, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid duration value '%s': negative durations are not allowed", value)
+	}
+	return duration, nil
+}
+
+// parsePollBackoffFactor parses the given backoff factor which can either be a number or a string containing a number
+func parsePollBackoffFactor(value interface{}) (float64, error) {
+	var backoffFactor float64
+	switch v := value.(type) {
+	case float64:
+		backoffFactor = v
+	case int:
+		backoffFactor = float64(v)
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid backoff factor value '%s': %s", v, err)
+		}
+		backoffFactor = f
+	default:
+		return 0, fmt.Errorf("invalid backoff factor value '%v': value must be a number", value)
+	}
+	if backoffFactor < 1 {
+		return 0, fmt.Errorf("invalid backoff factor value '%v': value must be greater or equal to 1", value)
+	}
+	return backoffFactor, nil
+}
+
+// merge returns a copy of the poll configuration where the values configured in the given override replace the
+// existing ones
+func (s specPollConfiguration) merge(override specPollConfiguration) specPollConfiguration {
+	if override.delay > 0 {
+		s.delay = override.delay
+	}
+	if override.interval > 0 {
+		s.interval = override.interval
+	}
+	if override.maxInterval > 0 {
+		s.maxInterval = override.maxInterval
+	}
+	if override.backoffFactor > 0 {
+		s.backoffFactor = override.backoffFactor
+	}
+	return s
+}
+
+// isIntervalConfigured returns true if either the interval or the backoff factor are configured
+func (s specPollConfiguration) isIntervalConfigured() bool {
+	return s.interval > 0 || s.backoffFactor > 1
+}
+
+// nextInterval returns the interval to wait before the poll following the one that waited the given interval. The
+// interval is multiplied by the backoff factor (if configured) and is never greater than the max interval (if configured)
+func (s specPollConfiguration) nextInterval(interval time.Duration) time.Duration {
+	if s.backoffFactor > 1 {
+		interval = time.Duration(float64(interval) * s.backoffFactor)
+	}
+	if s.maxInterval > 0 && interval > s.maxInterval {
+		interval = s.maxInterval
+	}
+	return interval
+}
+
+// specPollOperation defines how the operation resource returned in the Location/Operation-Location response headers is
+// polled when an API handles requests asynchronously (long-running operations). The operation resource is expected to
+// report the status of the operation and, once completed, the ID of the resource or the error in case of failure
+type specPollOperation struct {
+	// statusField is the name of the operation property containing the operation status
+	statusField string
+	// completedStatuses contains the statuses that determine the operation completed successfully
+	completedStatuses []string
+	// failedStatuses contains the statuses that determine the operation failed
+	failedStatuses []string
+	// resourceIDField is the name of the operation property containing the ID of the resource once the operation is completed
+	resourceIDField string
+	// errorField is the name of the operation property containing the error details if the operation failed
+	errorField string
+}
+
+// newSpecPollOperation returns a specPollOperation with the given configuration. Default values are used for the
+// configuration values not provided. The field names can use dot notation to refer to nested properties (e,g: response.id)
+func newSpecPollOperation(statusField, resourceIDField, errorField string, completedStatuses, failedStatuses []string) *specPollOperation {
+	pollOperation := &specPollOperation{
+		statusField:       defaultPollOperationStatusField,
+		completedStatuses: defaultPollOperationCompletedStatuses,
+		failedStatuses:    defaultPollOperationFailedStatuses,
+		resourceIDField:   defaultPollOperationResourceIDField,
+		errorField:        defaultPollOperationErrorField,
+	}
+	if statusField != "" {
+		pollOperation.statusField = statusField
+	}
+	if resourceIDField != "" {
+		pollOperation.resourceIDField = resourceIDField
+	}
+	if errorField != "" {
+		pollOperation.errorField = errorField
+	}
+	if len(completedStatuses) > 0 {
+		pollOperation.completedStatuses = completedStatuses
+	}
+	if len(failedStatuses) > 0 {
+		pollOperation.failedStatuses = failedStatuses
+	}
+	return pollOperation
+}
+
+// isCompleted returns true if the given status is one of the completed statuses (case insensitive)
+func (s *specPollOperation) isCompleted(status string) bool {
+	return containsStatus(s.completedStatuses, status)
+}
+
+// isFailed returns true if the given status is one of the failed statuses (case insensitive)
+func (s *specPollOperation) isFailed(status string) bool {
+	return containsStatus(s.failedStatuses, status)
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
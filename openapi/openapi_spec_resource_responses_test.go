@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewSpecPollConfiguration(t *testing.T) {
+	Convey("Given a set of extensions containing the poll timing extensions", t, func() {
+		extensions := spec.Extensions{}
+		extensions.Add(extTfResourcePollDelay, "30s")
+		extensions.Add(extTfResourcePollInterval, "1m")
+		extensions.Add(extTfResourcePollMaxInterval, "1h")
+		extensions.Add(extTfResourcePollBackoffFactor, "1.5")
+		Convey("When newSpecPollConfiguration method is called", func() {
+			pollConfiguration := newSpecPollConfiguration(extensions)
+			Convey("Then the poll configuration returned should contain the extension values", func() {
+				So(pollConfiguration, ShouldResemble, specPollConfiguration{delay: 30 * time.Second, interval: time.Minute, maxInterval: time.Hour, backoffFactor: 1.5})
+			})
+		})
+	})
+	Convey("Given a set of extensions containing invalid poll timing extensions", t, func() {
+		extensions := spec.Extensions{}
+		extensions.Add(extTfResourcePollDelay, "-30s")
+		extensions.Add(extTfResourcePollInterval, "often")
+		extensions.Add(extTfResourcePollBackoffFactor, 0.5)
+		Convey("When newSpecPollConfiguration method is called", func() {
+			pollConfiguration := newSpecPollConfiguration(extensions)
+			Convey("Then the invalid values should be ignored", func() {
+				So(pollConfiguration, ShouldResemble, specPollConfiguration{})
+			})
+		})
+	})
+}
+
+func TestSpecPollConfigurationMerge(t *testing.T) {
+	Convey("Given a specPollConfiguration", t, func() {
+		pollConfiguration := specPollConfiguration{delay: time.Second, interval: time.Minute, backoffFactor: 2}
+		Convey("When merge method is called with an override that has some values configured", func() {
+			mergedPollConfiguration := pollConfiguration.merge(specPollConfiguration{interval: 5 * time.Minute, maxInterval: time.Hour})
+			Convey("Then the configured override values should replace the existing ones", func() {
+				So(mergedPollConfiguration, ShouldResemble, specPollConfiguration{delay: time.Second, interval: 5 * time.Minute, maxInterval: time.Hour, backoffFactor: 2})
+			})
+		})
+	})
+}
+
+func TestSpecPollConfigurationNextInterval(t *testing.T) {
+	Convey("Given a specPollConfiguration with a backoff factor and a max interval", t, func() {
+		pollConfiguration := specPollConfiguration{backoffFactor: 2, maxInterval: 3 * time.Minute}
+		Convey("When nextInterval method is called", func() {
+			Convey("Then the interval should be multiplied by the backoff factor", func() {
+				So(pollConfiguration.nextInterval(time.Minute), ShouldEqual, 2*time.Minute)
+			})
+			Convey("And the interval should never be greater than the max interval", func() {
+				So(pollConfiguration.nextInterval(2*time.Minute), ShouldEqual, 3*time.Minute)
+			})
+		})
+	})
+	Convey("Given a specPollConfiguration without backoff factor", t, func() {
+		pollConfiguration := specPollConfiguration{interval: time.Minute}
+		Convey("When nextInterval method is called", func() {
+			Convey("Then the interval should not change", func() {
+				So(pollConfiguration.nextInterval(time.Minute), ShouldEqual, time.Minute)
+			})
+		})
+	})
+}
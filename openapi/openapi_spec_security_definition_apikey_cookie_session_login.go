@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"fmt"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
+
+// specAPIKeyCookieSessionLoginSecurityDefinition defines a cookie security definition where the cookie is the session
+// obtained by logging in to the session login URL with the credentials provided by the user
+type specAPIKeyCookieSessionLoginSecurityDefinition struct {
+	name     string
+	apiKey   specAPIKey
+	loginURL string
+}
+
+// newAPIKeyCookieSessionLoginSecurityDefinition constructs a SpecSecurityDefinition of Cookie type using a session login.
+// The secDefName value is the identifier of the security definition, the cookieName is the name of the session cookie
+// and the loginURL is the URL where the credentials are posted to obtain the session cookie
+func newAPIKeyCookieSessionLoginSecurityDefinition(secDefName, cookieName, loginURL string) specAPIKeyCookieSessionLoginSecurityDefinition {
+	return specAPIKeyCookieSessionLoginSecurityDefinition{
+		name:     secDefName,
+		apiKey:   newAPIKeyCookie(cookieName),
+		loginURL: loginURL,
+	}
+}
+
+func (s specAPIKeyCookieSessionLoginSecurityDefinition) getName() string {
+	return s.name
+}
+
+func (s specAPIKeyCookieSessionLoginSecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionAPIKeySessionLogin
+}
+
+func (s specAPIKeyCookieSessionLoginSecurityDefinition) getTerraformConfigurationName() string {
+	return terraformutils.ConvertToTerraformCompliantName(s.name)
+}
+
+func (s specAPIKeyCookieSessionLoginSecurityDefinition) getAPIKey() specAPIKey {
+	apiKey := s.apiKey
+	apiKey.Metadata = map[apiKeyMetadataKey]interface{}{
+		sessionLoginURLKey: s.loginURL,
+	}
+	return apiKey
+}
+
+func (s specAPIKeyCookieSessionLoginSecurityDefinition) buildValue(credentials string) string {
+	return credentials
+}
+
+func (s specAPIKeyCookieSessionLoginSecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specAPIKeyCookieSessionLoginSecurityDefinition missing mandatory security definition name")
+	}
+	if s.apiKey.Name == "" {
+		return fmt.Errorf("specAPIKeyCookieSessionLoginSecurityDefinition missing mandatory apiKey name")
+	}
+	if !isURL(s.loginURL) {
+		return fmt.Errorf("session login URL must be a valid URL")
+	}
+	return nil
+}
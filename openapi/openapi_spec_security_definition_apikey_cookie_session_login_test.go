@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewAPIKeyCookieSessionLoginSecurityDefinition(t *testing.T) {
+	Convey("Given a name, a cookie name and a session login URL", t, func() {
+		name := "session_auth"
+		cookieName := "session_id"
+		loginURL := "https://api.server.com/login"
+		Convey("When newAPIKeyCookieSessionLoginSecurityDefinition method is called", func() {
+			securityDefinition := newAPIKeyCookieSessionLoginSecurityDefinition(name, cookieName, loginURL)
+			Convey("Then the security definition should comply with SpecSecurityDefinition interface", func() {
+				var _ SpecSecurityDefinition = securityDefinition
+			})
+			Convey("And the security definition should be of type securityDefinitionAPIKeySessionLogin", func() {
+				So(securityDefinition.getType(), ShouldEqual, securityDefinitionAPIKeySessionLogin)
+			})
+			Convey("And the api key should be the session cookie containing the session login URL as metadata", func() {
+				apiKey := securityDefinition.getAPIKey()
+				So(apiKey.In, ShouldEqual, inCookie)
+				So(apiKey.Name, ShouldEqual, cookieName)
+				So(apiKey.Metadata[sessionLoginURLKey], ShouldEqual, loginURL)
+			})
+			Convey("And the security definition should be valid", func() {
+				So(securityDefinition.validate(), ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a session login security definition with an invalid session login URL", t, func() {
+		securityDefinition := newAPIKeyCookieSessionLoginSecurityDefinition("session_auth", "session_id", "not a url")
+		Convey("When validate method is called", func() {
+			err := securityDefinition.validate()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "session login URL must be a valid URL")
+			})
+		})
+	})
+}
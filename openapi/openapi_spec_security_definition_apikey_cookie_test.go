@@ -0,0 +1,42 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewAPIKeyCookieSecurityDefinition(t *testing.T) {
+	Convey("Given a name and a cookie name", t, func() {
+		name := "cookie_auth"
+		cookieName := "session_id"
+		Convey("When newAPIKeyCookieSecurityDefinition method is called", func() {
+			apiKeyCookieSecurityDefinition := newAPIKeyCookieSecurityDefinition(name, cookieName)
+			Convey("Then the apiKeyCookieSecurityDefinition should comply with SpecSecurityDefinition interface", func() {
+				var _ SpecSecurityDefinition = apiKeyCookieSecurityDefinition
+			})
+			Convey("And the security definition should be of type securityDefinitionAPIKey", func() {
+				So(apiKeyCookieSecurityDefinition.getType(), ShouldEqual, securityDefinitionAPIKey)
+			})
+			Convey("And the api key should be a cookie with the name specified", func() {
+				So(apiKeyCookieSecurityDefinition.getAPIKey().In, ShouldEqual, inCookie)
+				So(apiKeyCookieSecurityDefinition.getAPIKey().Name, ShouldEqual, cookieName)
+			})
+			Convey("And the value built should be the same as the value provided", func() {
+				So(apiKeyCookieSecurityDefinition.buildValue("value"), ShouldEqual, "value")
+			})
+			Convey("And the security definition should be valid", func() {
+				So(apiKeyCookieSecurityDefinition.validate(), ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a cookie security definition missing the cookie name", t, func() {
+		apiKeyCookieSecurityDefinition := newAPIKeyCookieSecurityDefinition("cookie_auth", "")
+		Convey("When validate method is called", func() {
+			err := apiKeyCookieSecurityDefinition.validate()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "specAPIKeyCookieSecurityDefinition missing mandatory apiKey name")
+			})
+		})
+	})
+}
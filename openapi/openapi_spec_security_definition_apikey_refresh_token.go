@@ -2,20 +2,59 @@ package openapi
 
 import (
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+	"github.com/go-openapi/spec"
 )
 
+// refreshTokenConfiguration defines how the access token returned by the refresh token URL is read and cached
+type refreshTokenConfiguration struct {
+	// expiryHeader is the response header containing the access token expiry. If empty (or not present in the response),
+	// the expiry is read from the access token 'exp' claim if it is a JWT
+	expiryHeader string
+	// expiryMargin is how long before the access token expiry a new access token is requested
+	expiryMargin time.Duration
+	// responseProperty is the response body property containing the access token. If empty, the access token is read
+	// from the response Authorization header
+	responseProperty string
+}
+
+// newRefreshTokenConfiguration returns the refreshTokenConfiguration defined by the refresh token extensions. Invalid
+// values are ignored so the default values are used instead
+func newRefreshTokenConfiguration(extensions spec.Extensions) refreshTokenConfiguration {
+	configuration := refreshTokenConfiguration{}
+	configuration.expiryHeader, _ = extensions.GetString(extTfAuthenticationRefreshTokenExpiryHeader)
+	configuration.responseProperty, _ = extensions.GetString(extTfAuthenticationRefreshTokenResponseProperty)
+	if expiryMarginValue, exists := extensions.GetString(extTfAuthenticationRefreshTokenExpiryMargin); exists {
+		expiryMargin, err := time.ParseDuration(expiryMarginValue)
+		if err != nil {
+			log.Printf("[WARN] ignoring extension '%s': %s", extTfAuthenticationRefreshTokenExpiryMargin, err)
+		} else {
+			configuration.expiryMargin = expiryMargin
+		}
+	}
+	return configuration
+}
+
 type specAPIKeyHeaderRefreshTokenSecurityDefinition struct {
 	name            string
 	refreshTokenURL string
+	configuration   refreshTokenConfiguration
 }
 
 // newAPIKeyHeaderRefreshTokenSecurityDefinition constructs a SpecSecurityDefinition of Header type using the Bearer authentication
 // scheme. The secDefName value is the identifier of the security definition, and the refreshTokenURL is the URL that the openapi_spec_authenticator_refresh_token.go
 func newAPIKeyHeaderRefreshTokenSecurityDefinition(secDefName string, refreshTokenURL string) specAPIKeyHeaderRefreshTokenSecurityDefinition {
-	return specAPIKeyHeaderRefreshTokenSecurityDefinition{secDefName, refreshTokenURL}
+	return specAPIKeyHeaderRefreshTokenSecurityDefinition{name: secDefName, refreshTokenURL: refreshTokenURL}
+}
+
+// withConfiguration returns a copy of the security definition using the given refresh token configuration
+func (s specAPIKeyHeaderRefreshTokenSecurityDefinition) withConfiguration(configuration refreshTokenConfiguration) specAPIKeyHeaderRefreshTokenSecurityDefinition {
+	s.configuration = configuration
+	return s
 }
 
 func (s specAPIKeyHeaderRefreshTokenSecurityDefinition) getName() string {
@@ -33,7 +72,8 @@ func (s specAPIKeyHeaderRefreshTokenSecurityDefinition) getTerraformConfiguratio
 func (s specAPIKeyHeaderRefreshTokenSecurityDefinition) getAPIKey() specAPIKey {
 	apiKey := newAPIKeyHeader(authorizationHeader)
 	apiKey.Metadata = map[apiKeyMetadataKey]interface{}{
-		refreshTokenURLKey: s.refreshTokenURL,
+		refreshTokenURLKey:           s.refreshTokenURL,
+		refreshTokenConfigurationKey: s.configuration,
 	}
 	return apiKey
 }
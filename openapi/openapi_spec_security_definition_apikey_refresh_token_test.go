@@ -3,7 +3,9 @@ package openapi
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/go-openapi/spec"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -124,3 +126,28 @@ func TestAPIKeyHeaderRefreshTokenSecurityDefinitionValidate(t *testing.T) {
 		})
 	})
 }
+
+func TestNewRefreshTokenConfiguration(t *testing.T) {
+	Convey("Given a set of extensions containing the refresh token extensions", t, func() {
+		extensions := spec.Extensions{}
+		extensions.Add(extTfAuthenticationRefreshTokenExpiryHeader, "X-Expires-In")
+		extensions.Add(extTfAuthenticationRefreshTokenExpiryMargin, "30s")
+		extensions.Add(extTfAuthenticationRefreshTokenResponseProperty, "access_token")
+		Convey("When newRefreshTokenConfiguration method is called", func() {
+			configuration := newRefreshTokenConfiguration(extensions)
+			Convey("Then the configuration returned should contain the extension values", func() {
+				So(configuration, ShouldResemble, refreshTokenConfiguration{expiryHeader: "X-Expires-In", expiryMargin: 30 * time.Second, responseProperty: "access_token"})
+			})
+		})
+	})
+	Convey("Given a set of extensions containing an invalid expiry margin", t, func() {
+		extensions := spec.Extensions{}
+		extensions.Add(extTfAuthenticationRefreshTokenExpiryMargin, "soon")
+		Convey("When newRefreshTokenConfiguration method is called", func() {
+			configuration := newRefreshTokenConfiguration(extensions)
+			Convey("Then the invalid value should be ignored", func() {
+				So(configuration, ShouldResemble, refreshTokenConfiguration{})
+			})
+		})
+	})
+}
@@ -0,0 +1,173 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+	"github.com/go-openapi/spec"
+)
+
+// Parts of the request that can be included in the string to sign
+const (
+	signatureComponentMethod    = "method"
+	signatureComponentPath      = "path"
+	signatureComponentQuery     = "query"
+	signatureComponentBody      = "body"
+	signatureComponentTimestamp = "timestamp"
+)
+
+// Supported signature encodings and timestamp formats
+const (
+	signatureEncodingHex            = "hex"
+	signatureEncodingBase64         = "base64"
+	signatureTimestampFormatUnix    = "unix"
+	signatureTimestampFormatRFC3339 = "rfc3339"
+)
+
+// Fields of the x-terraform-authentication-scheme-signature extension object
+const (
+	signatureExtensionScheme          = "scheme"
+	signatureExtensionEncoding        = "encoding"
+	signatureExtensionTimestampHeader = "timestamp-header"
+	signatureExtensionTimestampFormat = "timestamp-format"
+	signatureExtensionComponents      = "components"
+)
+
+const defaultSignatureTimestampHeader = "X-Timestamp"
+
+var defaultSignatureComponents = []string{signatureComponentMethod, signatureComponentPath, signatureComponentQuery, signatureComponentBody, signatureComponentTimestamp}
+
+// signatureConfiguration defines how the requests are canonicalized and signed
+type signatureConfiguration struct {
+	// scheme is the prefix of the signature header value (e,g: HMAC-SHA256). If empty, the header only contains the signature
+	scheme string
+	// encoding is the encoding of the signature, either hex or base64
+	encoding string
+	// timestampHeader is the header containing the timestamp that is part of the signature
+	timestampHeader string
+	// timestampFormat is the format of the timestamp, either unix (seconds) or rfc3339
+	timestampFormat string
+	// components are the parts of the request, in order, joined with new lines to build the string to sign
+	components []string
+}
+
+// newSignatureConfiguration returns the signatureConfiguration defined by the x-terraform-authentication-scheme-signature
+// extension and whether the extension is present. The extension can either be an object describing the signature or
+// true to use the default configuration
+func newSignatureConfiguration(extensions spec.Extensions) (signatureConfiguration, bool) {
+	configuration := signatureConfiguration{
+		encoding:        signatureEncodingHex,
+		timestampHeader: defaultSignatureTimestampHeader,
+		timestampFormat: signatureTimestampFormatUnix,
+		components:      defaultSignatureComponents,
+	}
+	switch extension := extensions[extTfAuthenticationSchemeSignature].(type) {
+	case bool:
+		return configuration, extension
+	case map[string]interface{}:
+		for field, value := range extension {
+			stringValue := fmt.Sprintf("%v", value)
+			switch strings.ToLower(field) {
+			case signatureExtensionScheme:
+				configuration.scheme = stringValue
+			case signatureExtensionEncoding:
+				configuration.encoding = strings.ToLower(stringValue)
+			case signatureExtensionTimestampHeader:
+				configuration.timestampHeader = stringValue
+			case signatureExtensionTimestampFormat:
+				configuration.timestampFormat = strings.ToLower(stringValue)
+			case signatureExtensionComponents:
+				configuration.components = nil
+				components, isList := value.([]interface{})
+				if !isList {
+					components = []interface{}{value}
+				}
+				for _, component := range components {
+					configuration.components = append(configuration.components, strings.ToLower(fmt.Sprintf("%v", component)))
+				}
+			}
+		}
+		return configuration, true
+	}
+	return configuration, false
+}
+
+func (c signatureConfiguration) validate() error {
+	if c.encoding != signatureEncodingHex && c.encoding != signatureEncodingBase64 {
+		return fmt.Errorf("signature encoding '%s' not supported, only '%s' and '%s' values are valid", c.encoding, signatureEncodingHex, signatureEncodingBase64)
+	}
+	if c.timestampFormat != signatureTimestampFormatUnix && c.timestampFormat != signatureTimestampFormatRFC3339 {
+		return fmt.Errorf("signature timestamp format '%s' not supported, only '%s' and '%s' values are valid", c.timestampFormat, signatureTimestampFormatUnix, signatureTimestampFormatRFC3339)
+	}
+	if c.timestampHeader == "" {
+		return fmt.Errorf("signature timestamp header must not be empty")
+	}
+	if len(c.components) == 0 {
+		return fmt.Errorf("signature components must not be empty")
+	}
+	for _, component := range c.components {
+		switch component {
+		case signatureComponentMethod, signatureComponentPath, signatureComponentQuery, signatureComponentBody, signatureComponentTimestamp:
+		default:
+			return fmt.Errorf("signature component '%s' not supported, only %s values are valid", component, strings.Join(defaultSignatureComponents, ", "))
+		}
+	}
+	return nil
+}
+
+// specAPIKeyHeaderSignatureSecurityDefinition defines an apiKey header security definition where the value configured by
+// the user is the secret used to sign the requests (HMAC-SHA256) instead of being sent as is
+type specAPIKeyHeaderSignatureSecurityDefinition struct {
+	name          string
+	apiKey        specAPIKey
+	configuration signatureConfiguration
+}
+
+// newAPIKeyHeaderSignatureSecurityDefinition constructs a SpecSecurityDefinition of Header type that signs the requests.
+// The secDefName value is the identifier of the security definition, and the headerName is the header where the
+// signature is placed (e,g: Authorization or X-Signature)
+func newAPIKeyHeaderSignatureSecurityDefinition(secDefName, headerName string, configuration signatureConfiguration) specAPIKeyHeaderSignatureSecurityDefinition {
+	return specAPIKeyHeaderSignatureSecurityDefinition{
+		name:          secDefName,
+		apiKey:        newAPIKeyHeader(headerName),
+		configuration: configuration,
+	}
+}
+
+func (s specAPIKeyHeaderSignatureSecurityDefinition) getName() string {
+	return s.name
+}
+
+func (s specAPIKeyHeaderSignatureSecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionAPIKeySignature
+}
+
+func (s specAPIKeyHeaderSignatureSecurityDefinition) getTerraformConfigurationName() string {
+	return terraformutils.ConvertToTerraformCompliantName(s.name)
+}
+
+func (s specAPIKeyHeaderSignatureSecurityDefinition) getAPIKey() specAPIKey {
+	apiKey := s.apiKey
+	apiKey.Metadata = map[apiKeyMetadataKey]interface{}{
+		signatureConfigurationKey: s.configuration,
+	}
+	return apiKey
+}
+
+func (s specAPIKeyHeaderSignatureSecurityDefinition) buildValue(secret string) string {
+	return secret
+}
+
+func (s specAPIKeyHeaderSignatureSecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specAPIKeyHeaderSignatureSecurityDefinition missing mandatory security definition name")
+	}
+	if s.apiKey.Name == "" {
+		return fmt.Errorf("specAPIKeyHeaderSignatureSecurityDefinition missing mandatory apiKey name")
+	}
+	if err := s.configuration.validate(); err != nil {
+		return fmt.Errorf("security definition '%s' contains an invalid %s extension: %s", s.name, extTfAuthenticationSchemeSignature, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewSignatureConfiguration(t *testing.T) {
+	Convey("Given extensions containing the signature extension set to true", t, func() {
+		extensions := spec.Extensions{extTfAuthenticationSchemeSignature: true}
+		Convey("When newSignatureConfiguration method is called", func() {
+			configuration, isSignatureAuth := newSignatureConfiguration(extensions)
+			Convey("Then the default signature configuration should be returned", func() {
+				So(isSignatureAuth, ShouldBeTrue)
+				So(configuration, ShouldResemble, signatureConfiguration{encoding: "hex", timestampHeader: "X-Timestamp", timestampFormat: "unix", components: defaultSignatureComponents})
+			})
+		})
+	})
+	Convey("Given extensions containing the signature extension describing the signature", t, func() {
+		extensions := spec.Extensions{
+			extTfAuthenticationSchemeSignature: map[string]interface{}{
+				"scheme":           "HMAC-SHA256",
+				"encoding":         "BASE64",
+				"timestamp-header": "X-Date",
+				"timestamp-format": "rfc3339",
+				"components":       []interface{}{"method", "path", "timestamp"},
+			},
+		}
+		Convey("When newSignatureConfiguration method is called", func() {
+			configuration, isSignatureAuth := newSignatureConfiguration(extensions)
+			Convey("Then the signature configuration should contain the values specified", func() {
+				So(isSignatureAuth, ShouldBeTrue)
+				So(configuration, ShouldResemble, signatureConfiguration{scheme: "HMAC-SHA256", encoding: "base64", timestampHeader: "X-Date", timestampFormat: "rfc3339", components: []string{"method", "path", "timestamp"}})
+				So(configuration.validate(), ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given extensions that do not contain the signature extension or have it disabled", t, func() {
+		Convey("When newSignatureConfiguration method is called", func() {
+			_, isSignatureAuthMissing := newSignatureConfiguration(spec.Extensions{})
+			_, isSignatureAuthDisabled := newSignatureConfiguration(spec.Extensions{extTfAuthenticationSchemeSignature: false})
+			Convey("Then the security definition should not be considered a signature one", func() {
+				So(isSignatureAuthMissing, ShouldBeFalse)
+				So(isSignatureAuthDisabled, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestSignatureConfigurationValidate(t *testing.T) {
+	Convey("Given a signature configuration containing a non supported component", t, func() {
+		configuration, _ := newSignatureConfiguration(spec.Extensions{extTfAuthenticationSchemeSignature: map[string]interface{}{"components": []interface{}{"method", "headers"}}})
+		Convey("When validate method is called", func() {
+			err := configuration.validate()
+			Convey("Then the error returned should describe the non supported component", func() {
+				So(err.Error(), ShouldEqual, "signature component 'headers' not supported, only method, path, query, body, timestamp values are valid")
+			})
+		})
+	})
+	Convey("Given a signature configuration containing a non supported timestamp format", t, func() {
+		configuration, _ := newSignatureConfiguration(spec.Extensions{extTfAuthenticationSchemeSignature: map[string]interface{}{"timestamp-format": "millis"}})
+		Convey("When validate method is called", func() {
+			err := configuration.validate()
+			Convey("Then the error returned should describe the non supported timestamp format", func() {
+				So(err.Error(), ShouldEqual, "signature timestamp format 'millis' not supported, only 'unix' and 'rfc3339' values are valid")
+			})
+		})
+	})
+}
+
+func TestNewAPIKeyHeaderSignatureSecurityDefinition(t *testing.T) {
+	Convey("Given a name, a header name and a signature configuration", t, func() {
+		configuration, _ := newSignatureConfiguration(spec.Extensions{extTfAuthenticationSchemeSignature: true})
+		Convey("When newAPIKeyHeaderSignatureSecurityDefinition method is called", func() {
+			securityDefinition := newAPIKeyHeaderSignatureSecurityDefinition("signature_auth", "X-Signature", configuration)
+			Convey("Then the security definition should comply with SpecSecurityDefinition interface", func() {
+				var _ SpecSecurityDefinition = securityDefinition
+			})
+			Convey("And the security definition should be of type securityDefinitionAPIKeySignature", func() {
+				So(securityDefinition.getType(), ShouldEqual, securityDefinitionAPIKeySignature)
+			})
+			Convey("And the api key should be the signature header containing the signature configuration as metadata", func() {
+				apiKey := securityDefinition.getAPIKey()
+				So(apiKey.In, ShouldEqual, inHeader)
+				So(apiKey.Name, ShouldEqual, "X-Signature")
+				So(apiKey.Metadata[signatureConfigurationKey], ShouldResemble, configuration)
+			})
+			Convey("And the value built should be the secret as is", func() {
+				So(securityDefinition.buildValue("secret"), ShouldEqual, "secret")
+			})
+			Convey("And the security definition should be valid", func() {
+				So(securityDefinition.validate(), ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a signature security definition missing the header name", t, func() {
+		configuration, _ := newSignatureConfiguration(spec.Extensions{extTfAuthenticationSchemeSignature: true})
+		securityDefinition := newAPIKeyHeaderSignatureSecurityDefinition("signature_auth", "", configuration)
+		Convey("When validate method is called", func() {
+			err := securityDefinition.validate()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "specAPIKeyHeaderSignatureSecurityDefinition missing mandatory apiKey name")
+			})
+		})
+	})
+}
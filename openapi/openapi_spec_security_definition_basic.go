@@ -0,0 +1,52 @@
+package openapi
+
+import "fmt"
+
+// basicScheme is the HTTP authentication scheme used by the basic security definitions
+const basicScheme = "Basic"
+
+// Suffixes of the provider properties used to configure the basic security definitions
+const (
+	basicUsernamePropertySuffix = "username"
+	basicPasswordPropertySuffix = "password"
+)
+
+type specBasicSecurityDefinition struct {
+	specAuthorizationSecurityDefinition
+}
+
+// newBasicSecurityDefinition constructs a SpecSecurityDefinition of basic type (HTTP Basic authentication). The secDefName
+// value is the identifier of the security definition
+func newBasicSecurityDefinition(secDefName string) specBasicSecurityDefinition {
+	return specBasicSecurityDefinition{specAuthorizationSecurityDefinition{secDefName}}
+}
+
+func (s specBasicSecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionBasic
+}
+
+func (s specBasicSecurityDefinition) buildValue(credentials string) string {
+	return fmt.Sprintf("%s %s", basicScheme, credentials)
+}
+
+func (s specBasicSecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specBasicSecurityDefinition missing mandatory security definition name")
+	}
+	return nil
+}
+
+// getTerraformConfigurationProperties returns the provider properties where the username and the password are
+// configured. The properties are prefixed with the security definition name (e,g: basic_auth_username)
+func (s specBasicSecurityDefinition) getTerraformConfigurationProperties() []specSecurityDefinitionProperty {
+	return []specSecurityDefinitionProperty{
+		{name: s.getPropertyName(basicUsernamePropertySuffix), required: true},
+		{name: s.getPropertyName(basicPasswordPropertySuffix), required: true, sensitive: true},
+	}
+}
+
+func (s specBasicSecurityDefinition) createAuthenticator(values map[string]string) (specAPIKeyAuthenticator, error) {
+	username := values[s.getPropertyName(basicUsernamePropertySuffix)]
+	password := values[s.getPropertyName(basicPasswordPropertySuffix)]
+	return newBasicAuthenticator(s.getTerraformConfigurationName(), username, password), nil
+}
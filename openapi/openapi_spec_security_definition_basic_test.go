@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewBasicSecurityDefinition(t *testing.T) {
+	Convey("Given a security definition name", t, func() {
+		name := "basic_auth"
+		Convey("When newBasicSecurityDefinition method is called", func() {
+			basicSecurityDefinition := newBasicSecurityDefinition(name)
+			Convey("Then the security definition should comply with SpecSecurityDefinition and specMultiPropertySecurityDefinition interfaces", func() {
+				var _ SpecSecurityDefinition = basicSecurityDefinition
+				var _ specMultiPropertySecurityDefinition = basicSecurityDefinition
+			})
+			Convey("And the security definition type should be securityDefinitionBasic", func() {
+				So(basicSecurityDefinition.getType(), ShouldEqual, securityDefinitionBasic)
+			})
+			Convey("And the api key should be the Authorization header", func() {
+				So(basicSecurityDefinition.getAPIKey(), ShouldResemble, newAPIKeyHeader(authorizationHeader))
+			})
+		})
+	})
+}
+
+func TestBasicSecurityDefinitionGetTerraformConfigurationProperties(t *testing.T) {
+	Convey("Given a BasicSecurityDefinition with a NON compliant name", t, func() {
+		basicSecurityDefinition := newBasicSecurityDefinition("basicAuth")
+		Convey("When getTerraformConfigurationProperties method is called", func() {
+			properties := basicSecurityDefinition.getTerraformConfigurationProperties()
+			Convey("Then the properties should be prefixed with the terraform compliant name and the password should be sensitive", func() {
+				So(properties, ShouldResemble, []specSecurityDefinitionProperty{
+					{name: "basic_auth_username", required: true},
+					{name: "basic_auth_password", required: true, sensitive: true},
+				})
+			})
+		})
+	})
+}
+
+func TestBasicSecurityDefinitionValidate(t *testing.T) {
+	Convey("Given a BasicSecurityDefinition without name", t, func() {
+		basicSecurityDefinition := newBasicSecurityDefinition("")
+		Convey("When validate method is called", func() {
+			err := basicSecurityDefinition.validate()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "specBasicSecurityDefinition missing mandatory security definition name")
+			})
+		})
+	})
+}
+
+func TestBasicSecurityDefinitionCreateAuthenticator(t *testing.T) {
+	Convey("Given a BasicSecurityDefinition", t, func() {
+		basicSecurityDefinition := newBasicSecurityDefinition("basic_auth")
+		Convey("When createAuthenticator method is called with the provider property values", func() {
+			authenticator, err := basicSecurityDefinition.createAuthenticator(map[string]string{
+				"basic_auth_username": "user",
+				"basic_auth_password": "secret",
+			})
+			Convey("Then the authenticator returned should be configured with the username and password", func() {
+				So(err, ShouldBeNil)
+				So(authenticator, ShouldResemble, newBasicAuthenticator("basic_auth", "user", "secret"))
+			})
+		})
+	})
+}
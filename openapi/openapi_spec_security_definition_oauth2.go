@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
+
+// Suffixes of the provider properties used to configure the OAuth2 security definitions
+const (
+	oauth2ClientIDPropertySuffix     = "client_id"
+	oauth2ClientSecretPropertySuffix = "client_secret"
+	oauth2ScopesPropertySuffix       = "scopes"
+)
+
+// specAuthorizationSecurityDefinition contains the behaviour shared by the security definitions whose credentials are
+// configured in multiple provider properties (prefixed with the security definition name) and sent in the Authorization
+// header (e,g: basic and OAuth2 security definitions)
+type specAuthorizationSecurityDefinition struct {
+	name string
+}
+
+func (s specAuthorizationSecurityDefinition) getName() string {
+	return s.name
+}
+
+func (s specAuthorizationSecurityDefinition) getTerraformConfigurationName() string {
+	return terraformutils.ConvertToTerraformCompliantName(s.name)
+}
+
+func (s specAuthorizationSecurityDefinition) getAPIKey() specAPIKey {
+	return newAPIKeyHeader(authorizationHeader)
+}
+
+// getPropertyName returns the name of the provider property with the given suffix, which is prefixed with the security
+// definition name (e,g: oauth2_auth_client_id)
+func (s specAuthorizationSecurityDefinition) getPropertyName(suffix string) string {
+	return fmt.Sprintf("%s_%s", s.getTerraformConfigurationName(), suffix)
+}
+
+// specOAuth2SecurityDefinition contains the behaviour shared by the OAuth2 security definitions, which send the access
+// tokens obtained from the token URL using the bearer scheme. The OAuth2 flows embed it and add the provider properties
+// and the authenticator specific to the flow
+type specOAuth2SecurityDefinition struct {
+	specAuthorizationSecurityDefinition
+	tokenURL string
+}
+
+func newOAuth2SecurityDefinition(secDefName string, tokenURL string) specOAuth2SecurityDefinition {
+	return specOAuth2SecurityDefinition{specAuthorizationSecurityDefinition{secDefName}, tokenURL}
+}
+
+func (s specOAuth2SecurityDefinition) getType() securityDefinitionType {
+	return securityDefinitionOAuth2
+}
+
+func (s specOAuth2SecurityDefinition) buildValue(accessToken string) string {
+	return fmt.Sprintf("%s %s", bearerScheme, accessToken)
+}
+
+func (s specOAuth2SecurityDefinition) validate() error {
+	if s.name == "" {
+		return fmt.Errorf("specOAuth2SecurityDefinition missing mandatory security definition name")
+	}
+	if s.tokenURL == "" {
+		return fmt.Errorf("specOAuth2SecurityDefinition missing mandatory token URL")
+	}
+	if !isURL(s.tokenURL) {
+		return fmt.Errorf("OAuth2 token URL must be a valid URL")
+	}
+	return nil
+}
+
+// parseOAuth2Scopes returns the scopes contained in the given value, which can be separated by commas or spaces
+func parseOAuth2Scopes(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,29 @@
+package openapi
+
+type specOAuth2ClientCredentialsSecurityDefinition struct {
+	specOAuth2SecurityDefinition
+}
+
+// newOAuth2ClientCredentialsSecurityDefinition constructs a SpecSecurityDefinition of OAuth2 type using the client
+// credentials flow (in OpenAPI v2 also known as application flow). The secDefName value is the identifier of the security
+// definition, and the tokenURL is the URL where the access tokens are requested
+func newOAuth2ClientCredentialsSecurityDefinition(secDefName string, tokenURL string) specOAuth2ClientCredentialsSecurityDefinition {
+	return specOAuth2ClientCredentialsSecurityDefinition{newOAuth2SecurityDefinition(secDefName, tokenURL)}
+}
+
+// getTerraformConfigurationProperties returns the provider properties where the client id, the client secret and the
+// scopes (optional) are configured. The properties are prefixed with the security definition name (e,g: oauth2_auth_client_id)
+func (s specOAuth2ClientCredentialsSecurityDefinition) getTerraformConfigurationProperties() []specSecurityDefinitionProperty {
+	return []specSecurityDefinitionProperty{
+		{name: s.getPropertyName(oauth2ClientIDPropertySuffix), required: true},
+		{name: s.getPropertyName(oauth2ClientSecretPropertySuffix), required: true, sensitive: true},
+		{name: s.getPropertyName(oauth2ScopesPropertySuffix), required: false},
+	}
+}
+
+func (s specOAuth2ClientCredentialsSecurityDefinition) createAuthenticator(values map[string]string) (specAPIKeyAuthenticator, error) {
+	clientID := values[s.getPropertyName(oauth2ClientIDPropertySuffix)]
+	clientSecret := values[s.getPropertyName(oauth2ClientSecretPropertySuffix)]
+	scopes := parseOAuth2Scopes(values[s.getPropertyName(oauth2ScopesPropertySuffix)])
+	return newOAuth2ClientCredentialsAuthenticator(s.getTerraformConfigurationName(), s.tokenURL, clientID, clientSecret, scopes), nil
+}
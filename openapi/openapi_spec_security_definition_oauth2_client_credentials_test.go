@@ -0,0 +1,44 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOAuth2ClientCredentialsSecurityDefinitionGetTerraformConfigurationProperties(t *testing.T) {
+	Convey("Given an OAuth2ClientCredentialsSecurityDefinition with a NON compliant name", t, func() {
+		oauth2SecurityDefinition := newOAuth2ClientCredentialsSecurityDefinition("oauth2Auth", "https://api.iam.com/oauth2/token")
+		Convey("When getTerraformConfigurationProperties method is called", func() {
+			properties := oauth2SecurityDefinition.getTerraformConfigurationProperties()
+			Convey("Then the properties should be prefixed with the terraform compliant security definition name", func() {
+				So(properties, ShouldResemble, []specSecurityDefinitionProperty{
+					{name: "oauth2_auth_client_id", required: true},
+					{name: "oauth2_auth_client_secret", required: true, sensitive: true},
+					{name: "oauth2_auth_scopes", required: false},
+				})
+			})
+		})
+	})
+}
+
+func TestOAuth2ClientCredentialsSecurityDefinitionCreateAuthenticator(t *testing.T) {
+	Convey("Given an OAuth2ClientCredentialsSecurityDefinition", t, func() {
+		oauth2SecurityDefinition := newOAuth2ClientCredentialsSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token")
+		Convey("When createAuthenticator method is called with the provider property values", func() {
+			authenticator, err := oauth2SecurityDefinition.createAuthenticator(map[string]string{
+				"oauth2_auth_client_id":     "clientID",
+				"oauth2_auth_client_secret": "clientSecret",
+				"oauth2_auth_scopes":        "read, write admin",
+			})
+			Convey("Then the authenticator returned should be configured with the values provided", func() {
+				So(err, ShouldBeNil)
+				oauth2Authenticator := authenticator.(*oauth2Authenticator)
+				So(oauth2Authenticator.tokenURL, ShouldEqual, "https://api.iam.com/oauth2/token")
+				So(oauth2Authenticator.clientID, ShouldEqual, "clientID")
+				So(oauth2Authenticator.clientSecret, ShouldEqual, "clientSecret")
+				So(oauth2Authenticator.scopes, ShouldResemble, []string{"read", "write", "admin"})
+			})
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package openapi
+
+// Suffixes of the provider properties used to configure the OAuth2 password security definitions
+const (
+	oauth2UsernamePropertySuffix = "username"
+	oauth2PasswordPropertySuffix = "password"
+)
+
+type specOAuth2PasswordSecurityDefinition struct {
+	specOAuth2SecurityDefinition
+}
+
+// newOAuth2PasswordSecurityDefinition constructs a SpecSecurityDefinition of OAuth2 type using the resource owner password
+// flow. The secDefName value is the identifier of the security definition, and the tokenURL is the URL where the access
+// tokens are requested
+func newOAuth2PasswordSecurityDefinition(secDefName string, tokenURL string) specOAuth2PasswordSecurityDefinition {
+	return specOAuth2PasswordSecurityDefinition{newOAuth2SecurityDefinition(secDefName, tokenURL)}
+}
+
+// getTerraformConfigurationProperties returns the provider properties where the username, the password and optionally
+// the client id, client secret and scopes are configured. The properties are prefixed with the security definition name
+// (e,g: oauth2_auth_username)
+func (s specOAuth2PasswordSecurityDefinition) getTerraformConfigurationProperties() []specSecurityDefinitionProperty {
+	return []specSecurityDefinitionProperty{
+		{name: s.getPropertyName(oauth2UsernamePropertySuffix), required: true},
+		{name: s.getPropertyName(oauth2PasswordPropertySuffix), required: true, sensitive: true},
+		{name: s.getPropertyName(oauth2ClientIDPropertySuffix), required: false},
+		{name: s.getPropertyName(oauth2ClientSecretPropertySuffix), required: false, sensitive: true},
+		{name: s.getPropertyName(oauth2ScopesPropertySuffix), required: false},
+	}
+}
+
+func (s specOAuth2PasswordSecurityDefinition) createAuthenticator(values map[string]string) (specAPIKeyAuthenticator, error) {
+	username := values[s.getPropertyName(oauth2UsernamePropertySuffix)]
+	password := values[s.getPropertyName(oauth2PasswordPropertySuffix)]
+	clientID := values[s.getPropertyName(oauth2ClientIDPropertySuffix)]
+	clientSecret := values[s.getPropertyName(oauth2ClientSecretPropertySuffix)]
+	scopes := parseOAuth2Scopes(values[s.getPropertyName(oauth2ScopesPropertySuffix)])
+	return newOAuth2PasswordAuthenticator(s.getTerraformConfigurationName(), s.tokenURL, username, password, clientID, clientSecret, scopes), nil
+}
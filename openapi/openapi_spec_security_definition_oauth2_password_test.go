@@ -0,0 +1,48 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOAuth2PasswordSecurityDefinitionGetTerraformConfigurationProperties(t *testing.T) {
+	Convey("Given an OAuth2PasswordSecurityDefinition", t, func() {
+		oauth2SecurityDefinition := newOAuth2PasswordSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token")
+		Convey("When getTerraformConfigurationProperties method is called", func() {
+			properties := oauth2SecurityDefinition.getTerraformConfigurationProperties()
+			Convey("Then the username and password properties should be required and the client credentials and scopes optional", func() {
+				So(properties, ShouldResemble, []specSecurityDefinitionProperty{
+					{name: "oauth2_auth_username", required: true},
+					{name: "oauth2_auth_password", required: true, sensitive: true},
+					{name: "oauth2_auth_client_id", required: false},
+					{name: "oauth2_auth_client_secret", required: false, sensitive: true},
+					{name: "oauth2_auth_scopes", required: false},
+				})
+			})
+		})
+	})
+}
+
+func TestOAuth2PasswordSecurityDefinitionCreateAuthenticator(t *testing.T) {
+	Convey("Given an OAuth2PasswordSecurityDefinition", t, func() {
+		oauth2SecurityDefinition := newOAuth2PasswordSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token")
+		Convey("When createAuthenticator method is called with the provider property values", func() {
+			authenticator, err := oauth2SecurityDefinition.createAuthenticator(map[string]string{
+				"oauth2_auth_username": "user",
+				"oauth2_auth_password": "secret",
+				"oauth2_auth_scopes":   "read",
+			})
+			Convey("Then the authenticator returned should be configured with the password grant", func() {
+				So(err, ShouldBeNil)
+				oauth2Authenticator := authenticator.(*oauth2Authenticator)
+				So(oauth2Authenticator.tokenURL, ShouldEqual, "https://api.iam.com/oauth2/token")
+				So(oauth2Authenticator.grant.Get("grant_type"), ShouldEqual, oauth2PasswordGrantType)
+				So(oauth2Authenticator.grant.Get("username"), ShouldEqual, "user")
+				So(oauth2Authenticator.grant.Get("password"), ShouldEqual, "secret")
+				So(oauth2Authenticator.clientID, ShouldBeEmpty)
+				So(oauth2Authenticator.scopes, ShouldResemble, []string{"read"})
+			})
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// oauth2SecurityDefinitionConstructors contains the constructors of the security definitions of the supported OAuth2 flows
+var oauth2SecurityDefinitionConstructors = []struct {
+	name                  string
+	newSecurityDefinition func(secDefName, tokenURL string) specMultiPropertySecurityDefinition
+}{
+	{name: "OAuth2ClientCredentialsSecurityDefinition", newSecurityDefinition: func(secDefName, tokenURL string) specMultiPropertySecurityDefinition {
+		return newOAuth2ClientCredentialsSecurityDefinition(secDefName, tokenURL)
+	}},
+	{name: "OAuth2PasswordSecurityDefinition", newSecurityDefinition: func(secDefName, tokenURL string) specMultiPropertySecurityDefinition {
+		return newOAuth2PasswordSecurityDefinition(secDefName, tokenURL)
+	}},
+	{name: "OAuth2TokenFileSecurityDefinition", newSecurityDefinition: func(secDefName, tokenURL string) specMultiPropertySecurityDefinition {
+		return newOAuth2TokenFileSecurityDefinition(secDefName, tokenURL)
+	}},
+}
+
+func TestNewOAuth2SecurityDefinitions(t *testing.T) {
+	for _, tc := range oauth2SecurityDefinitionConstructors {
+		Convey("Given a name and a token URL", t, func() {
+			name := "oauth2Auth"
+			tokenURL := "https://api.iam.com/oauth2/token"
+			Convey("When the "+tc.name+" is created", func() {
+				oauth2SecurityDefinition := tc.newSecurityDefinition(name, tokenURL)
+				Convey("Then the security definition name should be the given one and the terraform name should be compliant", func() {
+					So(oauth2SecurityDefinition.getName(), ShouldEqual, name)
+					So(oauth2SecurityDefinition.getTerraformConfigurationName(), ShouldEqual, "oauth2_auth")
+				})
+				Convey("And the security definition type should be securityDefinitionOAuth2", func() {
+					So(oauth2SecurityDefinition.getType(), ShouldEqual, securityDefinitionOAuth2)
+				})
+				Convey("And the access token should be sent in the Authorization header using the bearer scheme", func() {
+					So(oauth2SecurityDefinition.getAPIKey(), ShouldResemble, newAPIKeyHeader(authorizationHeader))
+					So(oauth2SecurityDefinition.buildValue("someAccessToken"), ShouldEqual, "Bearer someAccessToken")
+				})
+			})
+		})
+	}
+}
+
+func TestOAuth2SecurityDefinitionsValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		secDefName    string
+		tokenURL      string
+		expectedError string
+	}{
+		{name: "valid token URL", secDefName: "oauth2_auth", tokenURL: "https://api.iam.com/oauth2/token", expectedError: ""},
+		{name: "missing name", secDefName: "", tokenURL: "https://api.iam.com/oauth2/token", expectedError: "specOAuth2SecurityDefinition missing mandatory security definition name"},
+		{name: "missing token URL", secDefName: "oauth2_auth", tokenURL: "", expectedError: "specOAuth2SecurityDefinition missing mandatory token URL"},
+		{name: "invalid token URL", secDefName: "oauth2_auth", tokenURL: "/oauth2/token", expectedError: "OAuth2 token URL must be a valid URL"},
+	}
+	for _, constructor := range oauth2SecurityDefinitionConstructors {
+		for _, tc := range testCases {
+			Convey("Given an "+constructor.name+" with "+tc.name, t, func() {
+				oauth2SecurityDefinition := constructor.newSecurityDefinition(tc.secDefName, tc.tokenURL)
+				Convey("When validate method is called", func() {
+					err := oauth2SecurityDefinition.validate()
+					Convey("Then the error returned should be the expected one", func() {
+						if tc.expectedError == "" {
+							So(err, ShouldBeNil)
+						} else {
+							So(err.Error(), ShouldEqual, tc.expectedError)
+						}
+					})
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package openapi
+
+// oauth2TokenFilePropertySuffix is the suffix of the provider property used to configure the path of the OAuth2 token file
+const oauth2TokenFilePropertySuffix = "token_file"
+
+type specOAuth2TokenFileSecurityDefinition struct {
+	specOAuth2SecurityDefinition
+}
+
+// newOAuth2TokenFileSecurityDefinition constructs a SpecSecurityDefinition of OAuth2 type using the authorization code
+// flow (in OpenAPI v2 also known as accessCode flow). As the authorization code login is interactive, the tokens are read
+// from a local token file (e,g: written by a CLI) instead. The secDefName value is the identifier of the security definition,
+// and the tokenURL is the URL where the access tokens are refreshed
+func newOAuth2TokenFileSecurityDefinition(secDefName string, tokenURL string) specOAuth2TokenFileSecurityDefinition {
+	return specOAuth2TokenFileSecurityDefinition{newOAuth2SecurityDefinition(secDefName, tokenURL)}
+}
+
+// getTerraformConfigurationProperties returns the provider properties where the token file path and optionally the client
+// id and client secret used to refresh the tokens are configured. The properties are prefixed with the security definition
+// name (e,g: oauth2_auth_token_file)
+func (s specOAuth2TokenFileSecurityDefinition) getTerraformConfigurationProperties() []specSecurityDefinitionProperty {
+	return []specSecurityDefinitionProperty{
+		{name: s.getPropertyName(oauth2TokenFilePropertySuffix), required: true},
+		{name: s.getPropertyName(oauth2ClientIDPropertySuffix), required: false},
+		{name: s.getPropertyName(oauth2ClientSecretPropertySuffix), required: false, sensitive: true},
+	}
+}
+
+func (s specOAuth2TokenFileSecurityDefinition) createAuthenticator(values map[string]string) (specAPIKeyAuthenticator, error) {
+	tokenFile, err := expandPath(values[s.getPropertyName(oauth2TokenFilePropertySuffix)])
+	if err != nil {
+		return nil, err
+	}
+	clientID := values[s.getPropertyName(oauth2ClientIDPropertySuffix)]
+	clientSecret := values[s.getPropertyName(oauth2ClientSecretPropertySuffix)]
+	return newOAuth2TokenFileAuthenticator(s.getTerraformConfigurationName(), s.tokenURL, tokenFile, clientID, clientSecret), nil
+}
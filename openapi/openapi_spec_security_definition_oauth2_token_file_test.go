@@ -0,0 +1,45 @@
+package openapi
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOAuth2TokenFileSecurityDefinitionGetTerraformConfigurationProperties(t *testing.T) {
+	Convey("Given an OAuth2TokenFileSecurityDefinition", t, func() {
+		oauth2SecurityDefinition := newOAuth2TokenFileSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token")
+		Convey("When getTerraformConfigurationProperties method is called", func() {
+			properties := oauth2SecurityDefinition.getTerraformConfigurationProperties()
+			Convey("Then the token file property should be required and the client credentials optional", func() {
+				So(properties, ShouldResemble, []specSecurityDefinitionProperty{
+					{name: "oauth2_auth_token_file", required: true},
+					{name: "oauth2_auth_client_id", required: false},
+					{name: "oauth2_auth_client_secret", required: false, sensitive: true},
+				})
+			})
+		})
+	})
+}
+
+func TestOAuth2TokenFileSecurityDefinitionCreateAuthenticator(t *testing.T) {
+	Convey("Given an OAuth2TokenFileSecurityDefinition", t, func() {
+		oauth2SecurityDefinition := newOAuth2TokenFileSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token")
+		Convey("When createAuthenticator method is called with a token file relative to the home directory", func() {
+			authenticator, err := oauth2SecurityDefinition.createAuthenticator(map[string]string{
+				"oauth2_auth_token_file": "~/.cli/token.json",
+				"oauth2_auth_client_id":  "clientID",
+			})
+			Convey("Then the authenticator returned should be configured with the expanded token file path", func() {
+				So(err, ShouldBeNil)
+				homeDir, _ := homedir.Dir()
+				tokenFileAuthenticator := authenticator.(*oauth2TokenFileAuthenticator)
+				So(tokenFileAuthenticator.tokenURL, ShouldEqual, "https://api.iam.com/oauth2/token")
+				So(tokenFileAuthenticator.tokenFile, ShouldEqual, filepath.Join(homeDir, ".cli", "token.json"))
+				So(tokenFileAuthenticator.clientID, ShouldEqual, "clientID")
+			})
+		})
+	})
+}
@@ -5,12 +5,16 @@ type apiKeyIn string
 const (
 	inHeader apiKeyIn = "header"
 	inQuery  apiKeyIn = "query"
+	inCookie apiKeyIn = "cookie"
 )
 
 type apiKeyMetadataKey string
 
 const (
-	refreshTokenURLKey apiKeyMetadataKey = "refreshTokenURL"
+	refreshTokenURLKey           apiKeyMetadataKey = "refreshTokenURL"
+	refreshTokenConfigurationKey apiKeyMetadataKey = "refreshTokenConfiguration"
+	signatureConfigurationKey    apiKeyMetadataKey = "signatureConfiguration"
+	sessionLoginURLKey           apiKeyMetadataKey = "sessionLoginURL"
 )
 
 type specAPIKey struct {
@@ -27,6 +31,10 @@ func newAPIKeyQuery(name string) specAPIKey {
 	return newAPIKey(name, inQuery)
 }
 
+func newAPIKeyCookie(name string) specAPIKey {
+	return newAPIKey(name, inCookie)
+}
+
 func newAPIKey(name string, in apiKeyIn) specAPIKey {
 	return specAPIKey{
 		Name: name,
@@ -17,6 +17,10 @@ type securityDefinitionType string
 const (
 	securityDefinitionAPIKey             securityDefinitionType = "apiKey"
 	securityDefinitionAPIKeyRefreshToken securityDefinitionType = "apiKeyRefreshToken"
+	securityDefinitionAPIKeySignature    securityDefinitionType = "apiKeySignature"
+	securityDefinitionAPIKeySessionLogin securityDefinitionType = "apiKeySessionLogin"
+	securityDefinitionOAuth2             securityDefinitionType = "oauth2"
+	securityDefinitionBasic              securityDefinitionType = "basic"
 )
 
 // SpecSecurityDefinition defines the behaviour expected for security definition implementations. This interface creates
@@ -40,3 +44,26 @@ type SpecSecurityDefinition interface {
 	// including security definition name and any extra validation on the specAPIKey
 	validate() error
 }
+
+// specSecurityDefinitionProperty describes one of the provider properties used to configure the credentials of a
+// specMultiPropertySecurityDefinition
+type specSecurityDefinitionProperty struct {
+	// name is the terraform compliant name of the provider property (e,g: oauth2_auth_client_id)
+	name string
+	// required defines whether the property must be configured when the security definition is required
+	required bool
+	// sensitive defines whether the property holds a secret that should not be displayed (e,g: a password)
+	sensitive bool
+}
+
+// specMultiPropertySecurityDefinition is implemented by the security definitions whose credentials are made up of
+// multiple values (e,g: OAuth2 client id and client secret). Each value is configured in its own provider property
+// instead of the single property named after the security definition
+type specMultiPropertySecurityDefinition interface {
+	SpecSecurityDefinition
+	// getTerraformConfigurationProperties returns the provider properties used to configure the security definition
+	getTerraformConfigurationProperties() []specSecurityDefinitionProperty
+	// createAuthenticator returns the authenticator configured with the given provider property values (keyed by
+	// the property name)
+	createAuthenticator(values map[string]string) (specAPIKeyAuthenticator, error)
+}
@@ -1,24 +1,47 @@
 package openapi
 
-import "github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+import (
+	"fmt"
+	"strings"
 
-// SpecSecuritySchemes groups a list of SpecSecurityScheme
+	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
+)
+
+// SpecSecuritySchemes groups a list of SpecSecurityScheme. The security schemes are grouped in security requirements
+// (see getRequirements), being all the security schemes of a requirement needed together (AND) and each requirement an
+// alternative to the others (OR)
 type SpecSecuritySchemes []SpecSecurityScheme
 
+// createSecuritySchemes translates the given OpenAPI security requirements into SpecSecuritySchemes. The order of the
+// requirements is kept as it defines the priority by which they are selected. An empty security requirement ({}) makes
+// the security optional, hence it is kept as a requirement with no security schemes that is always satisfied (see
+// SpecSecurityScheme.isEmptyRequirement)
 func createSecuritySchemes(securitySchemes []map[string][]string) SpecSecuritySchemes {
 	schemes := SpecSecuritySchemes{}
-	for _, securityScheme := range securitySchemes {
+	for requirement, securityScheme := range securitySchemes {
+		if len(securityScheme) == 0 {
+			schemes = append(schemes, SpecSecurityScheme{requirement: requirement})
+			continue
+		}
 		for securitySchemeName := range securityScheme {
-			schemes = append(schemes, SpecSecurityScheme{Name: securitySchemeName})
+			schemes = append(schemes, SpecSecurityScheme{Name: securitySchemeName, requirement: requirement})
 		}
-		// Choosing the first set of security schemes as defined by the service provider. The order defines the priority
-		// by which security schemes are selected, in this case the first set. Hence, disregarding the rest of security
-		// schemes (if defined)
-		break
 	}
 	return schemes
 }
 
+// getRequirements returns the security schemes grouped by security requirement in order of preference
+func (s SpecSecuritySchemes) getRequirements() []SpecSecuritySchemes {
+	var requirements []SpecSecuritySchemes
+	for i, securityScheme := range s {
+		if i == 0 || securityScheme.requirement != s[i-1].requirement {
+			requirements = append(requirements, SpecSecuritySchemes{})
+		}
+		requirements[len(requirements)-1] = append(requirements[len(requirements)-1], securityScheme)
+	}
+	return requirements
+}
+
 func (s SpecSecuritySchemes) securitySchemeExists(secDef SpecSecurityDefinition) bool {
 	for _, securityScheme := range s {
 		if securityScheme.getTerraformConfigurationName() == secDef.getTerraformConfigurationName() {
@@ -28,10 +51,42 @@ func (s SpecSecuritySchemes) securitySchemeExists(secDef SpecSecurityDefinition)
 	return false
 }
 
+// securitySchemeRequired returns true if the given security definition is part of all the security requirements, and
+// therefore must be configured regardless of the requirement selected
+func (s SpecSecuritySchemes) securitySchemeRequired(secDef SpecSecurityDefinition) bool {
+	requirements := s.getRequirements()
+	if len(requirements) == 0 {
+		return false
+	}
+	for _, requirement := range requirements {
+		if !requirement.securitySchemeExists(secDef) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the security schemes names joined with AND (e,g: [api_key AND tenant_token])
+func (s SpecSecuritySchemes) String() string {
+	var names []string
+	for _, securityScheme := range s {
+		names = append(names, securityScheme.Name)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(names, " AND "))
+}
+
 // SpecSecurityScheme defines a security scheme. This struct serves as a translation between the OpenAPI document
 // and the scheme that will be used by the OpenAPI Terraform provider when making API calls to the backend
 type SpecSecurityScheme struct {
 	Name string
+	// requirement is the index of the security requirement the security scheme belongs to
+	requirement int
+}
+
+// isEmptyRequirement returns true if the security scheme stands for an empty security requirement ({}), which does not
+// require any security scheme
+func (o *SpecSecurityScheme) isEmptyRequirement() bool {
+	return o.Name == ""
 }
 
 func (o *SpecSecurityScheme) getTerraformConfigurationName() string {
@@ -62,6 +62,42 @@ func TestSecuritySchemeExists(t *testing.T) {
 	})
 }
 
+func TestSecuritySchemeRequired(t *testing.T) {
+	Convey("Given a list of specSecuritySchemes with two security requirements (api_key AND tenant_token) OR (api_key AND oauth2_auth)", t, func() {
+		specSecuritySchemes := createSecuritySchemes([]map[string][]string{
+			{
+				"api_key":      {},
+				"tenant_token": {},
+			},
+			{
+				"api_key":     {},
+				"oauth2_auth": {},
+			},
+		})
+		Convey("When securitySchemeRequired method is called with a security definition that is part of all the requirements", func() {
+			required := specSecuritySchemes.securitySchemeRequired(newAPIKeyHeaderSecurityDefinition("api_key", "X-API-KEY"))
+			Convey("Then the security definition should be required", func() {
+				So(required, ShouldBeTrue)
+			})
+		})
+		Convey("When securitySchemeRequired method is called with a security definition that is part of only one of the requirements", func() {
+			required := specSecuritySchemes.securitySchemeRequired(newAPIKeyHeaderSecurityDefinition("tenant_token", "X-TENANT-TOKEN"))
+			Convey("Then the security definition should not be required as the user can choose the other requirement", func() {
+				So(required, ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given an empty list of specSecuritySchemes", t, func() {
+		specSecuritySchemes := createSecuritySchemes([]map[string][]string{})
+		Convey("When securitySchemeRequired method is called", func() {
+			required := specSecuritySchemes.securitySchemeRequired(newAPIKeyHeaderSecurityDefinition("api_key", "X-API-KEY"))
+			Convey("Then the security definition should not be required", func() {
+				So(required, ShouldBeFalse)
+			})
+		})
+	})
+}
+
 func TestCreateSecuritySchemes(t *testing.T) {
 	Convey("Given a map of securitySchemes with multi auth AND support", t, func() {
 		securitySchemes := []map[string][]string{
@@ -97,12 +133,35 @@ func TestCreateSecuritySchemes(t *testing.T) {
 			Convey("Then the specSecuritySchemes should not be empty", func() {
 				So(specSecuritySchemes, ShouldNotBeEmpty)
 			})
-			Convey("Then the specSecuritySchemes name contain the expected items which are the first one in the array (by design these take preference)", func() {
-				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef1"})
-				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef2"})
+			Convey("Then the specSecuritySchemes should contain all the security requirements keeping their order", func() {
+				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef1", requirement: 0})
+				So(specSecuritySchemes, ShouldContain, SpecSecurityScheme{Name: "secDef2", requirement: 0})
+				So(specSecuritySchemes[2], ShouldResemble, SpecSecurityScheme{Name: "secDef3", requirement: 1})
+			})
+			Convey("And the security requirements should group the security schemes that must be satisfied together", func() {
+				requirements := specSecuritySchemes.getRequirements()
+				So(requirements, ShouldHaveLength, 2)
+				So(requirements[0], ShouldHaveLength, 2)
+				So(requirements[1], ShouldResemble, SpecSecuritySchemes{{Name: "secDef3", requirement: 1}})
 			})
-			Convey("Then the specSecuritySchemes should not contain anything else", func() {
-				So(specSecuritySchemes, ShouldNotContain, SpecSecurityScheme{Name: "secDef3"})
+		})
+	})
+
+	Convey("Given a map of securitySchemes containing an empty security requirement that makes the security optional", t, func() {
+		securitySchemes := []map[string][]string{
+			{
+				"secDef1": {},
+			},
+			{},
+		}
+		Convey("When createSecuritySchemes method is called with the securitySchemes", func() {
+			specSecuritySchemes := createSecuritySchemes(securitySchemes)
+			Convey("Then the empty security requirement should be kept as a requirement with no security schemes", func() {
+				requirements := specSecuritySchemes.getRequirements()
+				So(requirements, ShouldHaveLength, 2)
+				So(requirements[0], ShouldResemble, SpecSecuritySchemes{{Name: "secDef1", requirement: 0}})
+				So(requirements[1], ShouldHaveLength, 1)
+				So(requirements[1][0].isEmptyRequirement(), ShouldBeTrue)
 			})
 		})
 	})
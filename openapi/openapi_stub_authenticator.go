@@ -17,10 +17,12 @@ func newStubAuthenticator(expectedHeader, expectedHeaderValue string, err error)
 	}
 }
 
-func (s *specStubAuthenticator) prepareAuth(url string, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error) {
+func (s *specStubAuthenticator) prepareAuth(method httpMethodSupported, url string, body []byte, operationSecuritySchemes SpecSecuritySchemes, providerConfig providerConfiguration) (*authContext, error) {
 	// mimicking api key header auth which does not change the url at all
 	if s.authContext.url == "" {
 		s.authContext.url = url
 	}
+	s.authContext.method = method
+	s.authContext.body = body
 	return s.authContext, s.err
 }
@@ -15,6 +15,8 @@ type specStubBackendConfiguration struct {
 	hostErr          error
 	defaultRegionErr error
 	hostByRegionErr  error
+	hostVariables    specHostVariables
+	hostVariablesErr error
 
 	getHTTPSchemeBehavior func() (string, error)
 }
@@ -81,3 +83,10 @@ func (s *specStubBackendConfiguration) isMultiRegion() (bool, string, []string,
 	}
 	return false, "", nil, nil
 }
+
+func (s *specStubBackendConfiguration) getHostVariables() (specHostVariables, error) {
+	if s.hostVariablesErr != nil {
+		return nil, s.hostVariablesErr
+	}
+	return s.hostVariables, nil
+}
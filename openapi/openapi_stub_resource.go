@@ -6,12 +6,15 @@ type specStubResource struct {
 	host                    string
 	path                    string
 	shouldIgnore            bool
+	singleton               bool
 	schemaDefinition        *specSchemaDefinition
 	resourceGetOperation    *specResourceOperation
 	resourcePostOperation   *specResourceOperation
 	resourceListOperation   *specResourceOperation
 	resourcePutOperation    *specResourceOperation
+	resourcePatchOperation  *specResourceOperation
 	resourceDeleteOperation *specResourceOperation
+	resourceActions         []*specResourceAction
 	timeouts                *specTimeouts
 
 	parentResourceNames    []string
@@ -64,14 +67,18 @@ func (s *specStubResource) shouldIgnoreResource() bool { return s.shouldIgnore }
 
 func (s *specStubResource) getResourceOperations() specResourceOperations {
 	return specResourceOperations{
-		List:   s.resourceListOperation,
-		Post:   s.resourcePostOperation,
-		Get:    s.resourceGetOperation,
-		Put:    s.resourcePutOperation,
-		Delete: s.resourceDeleteOperation,
+		List:    s.resourceListOperation,
+		Post:    s.resourcePostOperation,
+		Get:     s.resourceGetOperation,
+		Put:     s.resourcePutOperation,
+		Patch:   s.resourcePatchOperation,
+		Delete:  s.resourceDeleteOperation,
+		Actions: s.resourceActions,
 	}
 }
 
+func (s *specStubResource) isSingleton() bool { return s.singleton }
+
 func (s *specStubResource) getTimeouts() (*specTimeouts, error) {
 	return s.timeouts, nil
 }
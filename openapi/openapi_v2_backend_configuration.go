@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/dikhan/terraform-provider-openapi/openapi/openapiutils"
@@ -12,6 +13,7 @@ import (
 
 const extTfProviderMultiRegionFQDN = "x-terraform-provider-multiregion-fqdn"
 const extTfProviderRegions = "x-terraform-provider-regions"
+const extTfProviderHostVariablePrefix = "x-terraform-provider-host-variable-"
 
 type specV2BackendConfiguration struct {
 	openAPIDocumentURL string
@@ -115,6 +117,33 @@ func (o specV2BackendConfiguration) getProviderRegions() ([]string, error) {
 	return regions, nil
 }
 
+// getHostVariables returns the host variables defined in the swagger root level extensions following the pattern
+// 'x-terraform-provider-host-variable-${name}'. The value of the extension is a comma separated list containing the
+// allowed values for the host variable, the first value being the default one. For instance, the following extension
+// 'x-terraform-provider-host-variable-environment: "prod, staging, dev"' would define the 'environment' host variable
+// that can be used in the swagger host like 'api.${environment}.server.com'
+func (o specV2BackendConfiguration) getHostVariables() (specHostVariables, error) {
+	hostVariables := specHostVariables{}
+	for extensionName := range o.spec.Extensions {
+		if !strings.HasPrefix(extensionName, extTfProviderHostVariablePrefix) {
+			continue
+		}
+		hostVariableName := strings.TrimPrefix(extensionName, extTfProviderHostVariablePrefix)
+		allowedValuesExtensionValue, _ := o.spec.Extensions.GetString(extensionName)
+		if allowedValuesExtensionValue == "" {
+			return nil, fmt.Errorf("host variable '%s' extension '%s' empty value provided", hostVariableName, extensionName)
+		}
+		allowedValues := strings.Split(strings.Replace(allowedValuesExtensionValue, " ", "", -1), ",")
+		hostVariables = append(hostVariables, specHostVariable{
+			name:          hostVariableName,
+			defaultValue:  allowedValues[0],
+			allowedValues: allowedValues,
+		})
+	}
+	sort.Slice(hostVariables, func(i, j int) bool { return hostVariables[i].name < hostVariables[j].name })
+	return hostVariables, nil
+}
+
 func (o specV2BackendConfiguration) getBasePath() string {
 	return o.spec.BasePath
 }
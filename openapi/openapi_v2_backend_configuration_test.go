@@ -564,6 +564,68 @@ func TestGetBasePath(t *testing.T) {
 	})
 }
 
+func TestGetHostVariables(t *testing.T) {
+	Convey("Given a specV2BackendConfiguration with host variable extensions", t, func() {
+		spec := &spec.Swagger{
+			VendorExtensible: spec.VendorExtensible{
+				Extensions: spec.Extensions{
+					"x-terraform-provider-host-variable-environment": "prod, staging, dev",
+					"x-terraform-provider-host-variable-cell":        "cell1",
+				},
+			},
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+				Host:    "${cell}.api.${environment}.server.com",
+			},
+		}
+		specV2BackendConfiguration, _ := newOpenAPIBackendConfigurationV2(spec, "www.domain.com")
+		Convey("When getHostVariables method is called", func() {
+			hostVariables, err := specV2BackendConfiguration.getHostVariables()
+			Convey("Then the host variables should be the expected ones, the default value being the first allowed value", func() {
+				So(err, ShouldBeNil)
+				So(hostVariables, ShouldResemble, specHostVariables{
+					{name: "cell", defaultValue: "cell1", allowedValues: []string{"cell1"}},
+					{name: "environment", defaultValue: "prod", allowedValues: []string{"prod", "staging", "dev"}},
+				})
+			})
+		})
+	})
+	Convey("Given a specV2BackendConfiguration with a host variable extension with an empty value", t, func() {
+		spec := &spec.Swagger{
+			VendorExtensible: spec.VendorExtensible{
+				Extensions: spec.Extensions{
+					"x-terraform-provider-host-variable-environment": "",
+				},
+			},
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+			},
+		}
+		specV2BackendConfiguration, _ := newOpenAPIBackendConfigurationV2(spec, "www.domain.com")
+		Convey("When getHostVariables method is called", func() {
+			_, err := specV2BackendConfiguration.getHostVariables()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "host variable 'environment' extension 'x-terraform-provider-host-variable-environment' empty value provided")
+			})
+		})
+	})
+	Convey("Given a specV2BackendConfiguration without host variable extensions", t, func() {
+		spec := &spec.Swagger{
+			SwaggerProps: spec.SwaggerProps{
+				Swagger: "2.0",
+			},
+		}
+		specV2BackendConfiguration, _ := newOpenAPIBackendConfigurationV2(spec, "www.domain.com")
+		Convey("When getHostVariables method is called", func() {
+			hostVariables, err := specV2BackendConfiguration.getHostVariables()
+			Convey("Then the host variables returned should be empty", func() {
+				So(err, ShouldBeNil)
+				So(hostVariables, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
 func TestGetHTTPSchemes(t *testing.T) {
 	testCases := []struct {
 		name           string
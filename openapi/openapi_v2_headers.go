@@ -1,6 +1,8 @@
 package openapi
 
 import (
+	"strings"
+
 	"github.com/go-openapi/spec"
 )
 
@@ -28,6 +30,10 @@ func getHeaderConfigurationsForParameterGroups(parametersGroup parameterGroups)
 				headers[parameter.Name] = parameter.Name
 				switch parameter.In {
 				case "header":
+					// the If-Match header value is the resource ETag, hence it is not configured by the user
+					if isIfMatchHeader(parameter.Name) {
+						continue
+					}
 					if preferredName, exists := parameter.Extensions.GetString(extTfHeader); exists {
 						headerParameters = append(headerParameters, SpecHeaderParam{Name: parameter.Name, TerraformName: preferredName})
 					} else {
@@ -47,6 +53,7 @@ func getPathHeaderParams(path spec.PathItem) SpecHeaderParameters {
 	parametersGroup = appendOperationParametersIfPresent(parametersGroup, path.Post)
 	parametersGroup = appendOperationParametersIfPresent(parametersGroup, path.Get)
 	parametersGroup = appendOperationParametersIfPresent(parametersGroup, path.Put)
+	parametersGroup = appendOperationParametersIfPresent(parametersGroup, path.Patch)
 	parametersGroup = appendOperationParametersIfPresent(parametersGroup, path.Delete)
 	return getHeaderConfigurationsForParameterGroups(parametersGroup)
 }
@@ -73,3 +80,18 @@ func appendOperationParametersIfPresent(parametersGroups parameterGroups, operat
 	}
 	return parametersGroups
 }
+
+// isIfMatchHeaderDeclared returns true if the given parameters contain the If-Match header
+func isIfMatchHeaderDeclared(parameters []spec.Parameter) bool {
+	for _, parameter := range parameters {
+		if parameter.In == "header" && isIfMatchHeader(parameter.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIfMatchHeader returns true if the given header name is If-Match (header names are case insensitive)
+func isIfMatchHeader(name string) bool {
+	return strings.EqualFold(name, ifMatchHeader)
+}
@@ -156,6 +156,46 @@ func TestGetHeaderConfigurations(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a list of parameters containing the If-Match header parameter", t, func() {
+		parameters := parameterGroups{
+			[]spec.Parameter{
+				{
+					ParamProps: spec.ParamProps{
+						Name:     "If-Match",
+						In:       "header",
+						Required: true,
+					},
+				},
+			},
+		}
+		Convey("When GetHeaderConfigurationsForParameterGroups method is called", func() {
+			headerConfigProps := getHeaderConfigurationsForParameterGroups(parameters)
+			Convey("Then the header configs returned should be empty as the If-Match header value is the resource ETag", func() {
+				So(headerConfigProps, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestIsIfMatchHeaderDeclared(t *testing.T) {
+	Convey("Given a list of parameters containing the If-Match header parameter", t, func() {
+		parameters := []spec.Parameter{{ParamProps: spec.ParamProps{Name: "if-match", In: "header"}}}
+		Convey("When isIfMatchHeaderDeclared method is called", func() {
+			declared := isIfMatchHeaderDeclared(parameters)
+			Convey("Then the result should be true", func() {
+				So(declared, ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given a list of parameters that does not contain the If-Match header parameter", t, func() {
+		parameters := []spec.Parameter{{ParamProps: spec.ParamProps{Name: "X-Request-ID", In: "header"}}}
+		Convey("When isIfMatchHeaderDeclared method is called", func() {
+			declared := isIfMatchHeaderDeclared(parameters)
+			Convey("Then the result should be false", func() {
+				So(declared, ShouldBeFalse)
+			})
+		})
+	})
 }
 
 func TestGetAllHeaderParameters(t *testing.T) {
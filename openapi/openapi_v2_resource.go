@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,43 +12,6 @@ import (
 	"github.com/go-openapi/spec"
 )
 
-const pathParameterRegex = "/({[\\w]*})*/"
-
-// resourceVersionRegexTemplate is used to identify the version attached to the given resource. The parameter in the
-// template will be replaced with the actual resource name so if there is a match the version grabbed is assured to belong
-// to the resource in question and not any other version showing in the path before the resource name
-const resourceVersionRegexTemplate = "/(v[\\d]*)/%s"
-
-const resourceNameRegex = "((/\\w*[/]?))+$"
-
-// resourceParentNameRegex is the regex used to identify the different parents from a path that is a sub-resource. If used
-// calling FindStringSubmatch, any match will contain the following groups in the corresponding array index:
-// Index 0: This value will represent the full match containing also the path parameter (e,g: /v1/cdns/{id})
-// Index 1: This value will represent the resource path (without the instance path parameter) - e,g: /v1/cdns
-// Index 2: This value will represent version if it exists in the path (e,g: v1)
-// Index 3: This value will represent the resource path name (e,g: cdns)
-//
-// - Example calling FindAllStringSubmatch with '/v1/cdns/{id}/v1/firewalls' path:
-// matches, _ := resourceParentRegex.FindAllStringSubmatch("/v1/cdns/{id}/v1/firewalls", -1)
-// matches[0][0]: Full match /v1/cdns/{id}
-// matches[0][1]: Group 1. /v1/cdns
-// matches[0][2]: Group 2. v1
-// matches[0][3]: Group 3. cdns
-
-// - Example calling FindAllStringSubmatch with '/v1/cdns/{id}/v2/firewalls/{id}/v3/rules' path
-// matches, _ := resourceParentRegex.FindAllStringSubmatch("/v1/cdns/{id}/v2/firewalls/{id}/v3/rules", -1)
-// matches[0][0]: Full match /v1/cdns/{id}
-// matches[0][1]: Group 1. /v1/cdns
-// matches[0][2]: Group 2. v1
-// matches[0][3]: Group 3. cdns
-// matches[1][0]: Full match /v2/firewalls/{id}
-// matches[1][1]: Group 1. /v2/firewalls
-// matches[1][2]: Group 2. v2
-// matches[1][3]: Group 3. firewalls
-const resourceParentNameRegex = `(\/(?:\w+\/)?(?:v\d+\/)?\w+)\/{\w+}`
-
-const resourceInstanceRegex = "((?:.*)){.*}"
-
 // Definition level extensions
 const extTfImmutable = "x-terraform-immutable"
 const extTfForceNew = "x-terraform-force-new"
@@ -60,12 +24,33 @@ const extTfComplexObjectType = "x-terraform-complex-object-legacy-config"
 
 // Operation level extensions
 const extTfResourceTimeout = "x-terraform-resource-timeout"
+const extTfRetryableStatusCodes = "x-terraform-retryable-status-codes"
+const extTfResourceIdempotencyKeyHeader = "x-terraform-resource-idempotency-key-header"
+const extTfETag = "x-terraform-etag"
 const extTfResourcePollEnabled = "x-terraform-resource-poll-enabled"
 const extTfResourcePollTargetStatuses = "x-terraform-resource-poll-completed-statuses"
 const extTfResourcePollPendingStatuses = "x-terraform-resource-poll-pending-statuses"
+const extTfResourcePollFailedStatuses = "x-terraform-resource-poll-failed-statuses"
+const extTfResourcePollStatusField = "x-terraform-resource-poll-status-field"
+const extTfResourcePollErrorField = "x-terraform-resource-poll-error-field"
+const extTfResourcePollDelay = "x-terraform-resource-poll-delay"
+const extTfResourcePollInterval = "x-terraform-resource-poll-interval"
+const extTfResourcePollMaxInterval = "x-terraform-resource-poll-max-interval"
+const extTfResourcePollBackoffFactor = "x-terraform-resource-poll-backoff-factor"
+const extTfResourcePollOperationLocation = "x-terraform-resource-poll-operation-location"
+const extTfResourcePollOperationStatusField = "x-terraform-resource-poll-operation-status-field"
+const extTfResourcePollOperationCompletedStatuses = "x-terraform-resource-poll-operation-completed-statuses"
+const extTfResourcePollOperationFailedStatuses = "x-terraform-resource-poll-operation-failed-statuses"
+const extTfResourcePollOperationResourceIDField = "x-terraform-resource-poll-operation-resource-id-field"
+const extTfResourcePollOperationErrorField = "x-terraform-resource-poll-operation-error-field"
+const extTfResourceLongRunningOperation = "x-terraform-resource-long-running-operation"
+const extTfResourceActionTrigger = "x-terraform-resource-action-trigger"
 const extTfExcludeResource = "x-terraform-exclude-resource"
 const extTfResourceName = "x-terraform-resource-name"
 const extTfResourceURL = "x-terraform-resource-host"
+const extTfResourceBasePath = "x-terraform-resource-base-path"
+const extTfResourcePatchContentType = "x-terraform-resource-patch-content-type"
+const extTfSingleton = "x-terraform-singleton"
 
 // SpecV2Resource defines a struct that implements the SpecResource interface and it's based on OpenAPI v2 specification
 type SpecV2Resource struct {
@@ -85,6 +70,9 @@ type SpecV2Resource struct {
 	SchemaDefinitions map[string]spec.Schema
 
 	Paths map[string]spec.PathItem
+
+	// actions contains the resource custom methods; these are computed once when the resource is created (see getResourceActions)
+	actions []*specResourceAction
 }
 
 // newSpecV2Resource creates a SpecV2Resource with no region and default host
@@ -107,6 +95,7 @@ func newSpecV2DataSource(path string, schemaDefinition spec.Schema, rootPathItem
 		return nil, fmt.Errorf("could not build resource name for '%s': %s", path, err)
 	}
 	resource.Name = name
+	resource.actions = resource.getResourceActions()
 	return resource, nil
 }
 
@@ -139,6 +128,7 @@ func newSpecV2ResourceWithConfig(region, path string, schemaDefinition spec.Sche
 		return nil, fmt.Errorf("could not build resource name for '%s': %s", path, err)
 	}
 	resource.Name = name
+	resource.actions = resource.getResourceActions()
 	return resource, nil
 }
 
@@ -157,7 +147,7 @@ func (o *SpecV2Resource) buildResourceName() (string, error) {
 	if preferred := o.getResourceTerraformName(); preferred != "" {
 		preferredName = preferred
 	}
-	fullResourceName, err := o.buildResourceNameFromPath(o.Path, preferredName)
+	fullResourceName, err := buildResourceNameFromPath(o.Path, preferredName)
 	if err != nil {
 		return "", err
 	}
@@ -168,76 +158,18 @@ func (o *SpecV2Resource) buildResourceName() (string, error) {
 	return fullResourceName, nil
 }
 
-// buildResourceNameFromPath returns the name of the resource (including the version if applicable and using the preferred name
-// if provided). The name will be calculated using the last part of the path which is meant to be the resource name that the URI
-// refers to (e,g: /resource/{id}). If the path is versioned /v1/resource/{id} then the corresponding returned name will
-// be either the built name from the path or the preferred name with the version appended at the end.
-// For instance, given the following input the output will be:
-// /cdns/{id} -> cdns
-// /cdns/{id} and preferred name being cdn -> cdn
-// /v1/cdns/{id} -> cdns_v1
-// /v1/cdns/{id} and preferred name being cdn -> cdn_v1
-func (o *SpecV2Resource) buildResourceNameFromPath(resourcePath, preferredName string) (string, error) {
-	nameRegex, _ := regexp.Compile(resourceNameRegex)
-	var resourceName string
-	matches := nameRegex.FindStringSubmatch(resourcePath)
-	if len(matches) < 2 {
-		return "", fmt.Errorf("could not find a valid name for resource instance path '%s'", resourcePath)
-	}
-	resourceName = strings.Replace(matches[len(matches)-1], "/", "", -1)
-
-	versionRegex, _ := regexp.Compile(fmt.Sprintf(resourceVersionRegexTemplate, resourceName))
-
-	if preferredName != "" {
-		resourceName = preferredName
-	}
-
-	fullResourceName := resourceName
-	v := versionRegex.FindAllStringSubmatch(resourcePath, -1)
-	if len(v) > 0 {
-		version := v[0][1]
-		fullResourceName = fmt.Sprintf("%s_%s", resourceName, version)
-	}
-
-	return fullResourceName, nil
-}
-
 // getResourcePath returns the root path of the resource. If the resource is a subresource and therefore the path contains
 // path parameters these will be resolved accordingly based on the ids provided. For instance, considering the given
 // resource path "/v1/cdns/{cdn_id}/v1/firewalls" and the []strin{"cdnID"} the returned path will be "/v1/cdns/cdnID/v1/firewalls".
 // If the resource path is not parameterised, then regular path will be returned accordingly
 func (o *SpecV2Resource) getResourcePath(parentIDs []string) (string, error) {
-	resolvedPath := o.Path
-
-	pathParameterRegex, _ := regexp.Compile(pathParameterRegex)
-	pathParamsMatches := pathParameterRegex.FindAllStringSubmatch(resolvedPath, -1)
-
-	switch {
-	case len(pathParamsMatches) == 0:
-		return resolvedPath, nil
-
-	case len(parentIDs) > len(pathParamsMatches):
-		return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' with the given ids - more ids than path params: %s", resolvedPath, parentIDs)
-
-	case len(parentIDs) < len(pathParamsMatches):
-		return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' with the given ids - missing ids to resolve the path params properly: %s", resolvedPath, parentIDs)
-	}
-
-	// At this point it's assured that there is an equal number of parameters to resolved and their corresponding ID values
-	for idx, parentID := range parentIDs {
-		if strings.Contains(parentID, "/") {
-			return "", fmt.Errorf("could not resolve sub-resource path correctly '%s' due to parent IDs (%s) containing not supported characters (forward slashes)", resolvedPath, parentIDs)
-		}
-		resolvedPath = strings.Replace(resolvedPath, pathParamsMatches[idx][1], parentIDs[idx], 1)
-	}
-
-	return resolvedPath, nil
+	return resolveResourcePath(o.Path, parentIDs)
 }
 
 // getHost can return an empty host in which case the expectation is that the host used will be the one specified in the
 // swagger host attribute or if not present the host used will be the host where the swagger file was served
 func (o *SpecV2Resource) getHost() (string, error) {
-	overrideHost := getResourceOverrideHost(o.RootPathItem.Post)
+	overrideHost := getResourceOverrideHost(o.getResourceRootOperation())
 	if overrideHost == "" {
 		return "", nil
 	}
@@ -251,21 +183,77 @@ func (o *SpecV2Resource) getHost() (string, error) {
 	return overrideHost, nil
 }
 
+// isSingleton returns true if the resource instance path PUT operation has the 'x-terraform-singleton' extension set to
+// true. Singleton resources (e,g: /v1/account/settings) do not have a root path POST operation and are created and
+// updated via PUT, read via GET and deleted (if the DELETE operation is exposed) via DELETE against the same path
+func (o *SpecV2Resource) isSingleton() bool {
+	if o.InstancePathItem.Put == nil {
+		return false
+	}
+	return o.isBoolExtensionEnabled(o.InstancePathItem.Put.Extensions, extTfSingleton)
+}
+
+// getResourceRootOperation returns the operation that holds the resource level extensions (e,g: x-terraform-resource-name),
+// that is the root path POST operation or, in the case of resources created via PUT (singletons and resources with client
+// generated IDs), the instance path PUT operation
+func (o *SpecV2Resource) getResourceRootOperation() *spec.Operation {
+	if o.RootPathItem.Post == nil {
+		return o.InstancePathItem.Put
+	}
+	return o.RootPathItem.Post
+}
+
 func (o *SpecV2Resource) getResourceOperations() specResourceOperations {
 	return specResourceOperations{
-		List:   o.createResourceOperation(o.RootPathItem.Get),
-		Post:   o.createResourceOperation(o.RootPathItem.Post),
-		Get:    o.createResourceOperation(o.InstancePathItem.Get),
-		Put:    o.createResourceOperation(o.InstancePathItem.Put),
-		Delete: o.createResourceOperation(o.InstancePathItem.Delete),
+		List:    o.createResourceOperation(o.RootPathItem.Get, o.RootPathItem),
+		Post:    o.createResourceOperation(o.RootPathItem.Post, o.RootPathItem),
+		Get:     o.createResourceOperation(o.InstancePathItem.Get, o.InstancePathItem),
+		Put:     o.createResourceOperation(o.InstancePathItem.Put, o.InstancePathItem),
+		Patch:   o.createResourceOperation(o.InstancePathItem.Patch, o.InstancePathItem),
+		Delete:  o.createResourceOperation(o.InstancePathItem.Delete, o.InstancePathItem),
+		Actions: o.actions,
+	}
+}
+
+// getResourceActions returns the custom methods of the resource (e,g: POST /v1/certs/{id}:rotate) that have the
+// 'x-terraform-resource-action-trigger' extension defined. The extension value is the name of the terraform attribute that
+// triggers the custom method when its value changes. Custom methods without the extension are ignored
+func (o *SpecV2Resource) getResourceActions() []*specResourceAction {
+	var paths []string
+	for path := range o.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var actions []*specResourceAction
+	for _, path := range paths {
+		name, isCustomMethod := getResourceCustomMethodName(o.Path, o.isSingleton(), path)
+		if !isCustomMethod {
+			continue
+		}
+		pathItem := o.Paths[path]
+		if pathItem.Post == nil {
+			log.Printf("[DEBUG] ignoring resource '%s' custom method '%s' as it does not have a POST operation", o.Path, path)
+			continue
+		}
+		triggerAttribute := o.getExtensionStringValue(pathItem.Post.Extensions, extTfResourceActionTrigger)
+		if triggerAttribute == "" {
+			log.Printf("[DEBUG] ignoring resource '%s' custom method '%s' as it does not have the '%s' extension", o.Path, path, extTfResourceActionTrigger)
+			continue
+		}
+		actions = append(actions, &specResourceAction{
+			Name:             name,
+			TriggerAttribute: triggerAttribute,
+			Operation:        o.createResourceOperation(pathItem.Post, pathItem),
+		})
 	}
+	return actions
 }
 
 // shouldIgnoreResource checks whether the POST operation for a given resource as the 'x-terraform-exclude-resource' extension
 // defined with true value. If so, the resource will not be exposed to the OpenAPI Terraform provider; otherwise it will
 // be exposed and users will be able to manage such resource via terraform.
 func (o *SpecV2Resource) shouldIgnoreResource() bool {
-	postOperation := o.RootPathItem.Post
+	postOperation := o.getResourceRootOperation()
 	if postOperation != nil {
 		if postOperation.Extensions != nil {
 			if o.isBoolExtensionEnabled(postOperation.Extensions, extTfExcludeResource) {
@@ -277,55 +265,25 @@ func (o *SpecV2Resource) shouldIgnoreResource() bool {
 }
 
 func (o *SpecV2Resource) getParentResourceInfo() *parentResourceInfo {
-	resourceParentRegex, _ := regexp.Compile(resourceParentNameRegex)
-	parentMatches := resourceParentRegex.FindAllStringSubmatch(o.Path, -1)
-	if len(parentMatches) > 0 {
-		var parentURI string
-		var parentInstanceURI string
-
-		var parentResourceNames, parentURIs, parentInstanceURIs []string
-		for _, match := range parentMatches {
-			fullMatch := match[0]
-			rootPath := match[1]
-			parentURI = parentInstanceURI + rootPath
-			parentInstanceURI = parentInstanceURI + fullMatch
-			parentURIs = append(parentURIs, parentURI)
-			parentInstanceURIs = append(parentInstanceURIs, parentInstanceURI)
-		}
-
-		fullParentResourceName := ""
-		preferredParentName := ""
-		for _, parentURI := range parentURIs {
-			// `o.Paths` is used to read the preferred name over that resource if `x-terraform-preferred-name` is set
-			if o.Paths != nil {
-				if parent, ok := o.Paths[parentURI]; ok {
-					preferredParentName, _ = parent.Post.Extensions.GetString(extTfResourceName)
-				} else {
-					// Falling back to checking path with trailing slash
-					if parent, ok := o.Paths[parentURI+"/"]; ok {
-						preferredParentName, _ = parent.Post.Extensions.GetString(extTfResourceName)
-					}
-				}
-			}
-			parentResourceName, err := o.buildResourceNameFromPath(parentURI, preferredParentName)
-			if err != nil {
-				log.Printf("[ERROR] could not build parent resource info due to the following error: %s", err)
-				return nil //untested
-			}
-			parentResourceNames = append(parentResourceNames, parentResourceName)
-			fullParentResourceName = fullParentResourceName + parentResourceName + "_"
-		}
-		fullParentResourceName = strings.TrimRight(fullParentResourceName, "_")
+	return buildParentResourceInfo(o.Path, o.getPreferredParentName)
+}
 
-		sub := &parentResourceInfo{
-			parentResourceNames:    parentResourceNames,
-			fullParentResourceName: fullParentResourceName,
-			parentURIs:             parentURIs,
-			parentInstanceURIs:     parentInstanceURIs,
+// getPreferredParentName uses `o.Paths` to read the preferred name over the parent resource if `x-terraform-resource-name` is set
+func (o *SpecV2Resource) getPreferredParentName(parentURI string) string {
+	if o.Paths == nil {
+		return ""
+	}
+	parent, ok := o.Paths[parentURI]
+	if !ok {
+		// Falling back to checking path with trailing slash
+		if parent, ok = o.Paths[parentURI+"/"]; !ok {
+			return ""
 		}
-		return sub
 	}
-	return nil
+	if parent.Post == nil {
+		return ""
+	}
+	return o.getExtensionStringValue(parent.Post.Extensions, extTfResourceName)
 }
 
 func (o *SpecV2Resource) getResourceSchema() (*specSchemaDefinition, error) {
@@ -633,10 +591,11 @@ func (o *SpecV2Resource) isRequired(propertyName string, requiredProps []string)
 }
 
 func (o *SpecV2Resource) getResourceTerraformName() string {
-	if o.RootPathItem.Post == nil {
+	rootOperation := o.getResourceRootOperation()
+	if rootOperation == nil {
 		return ""
 	}
-	return o.getExtensionStringValue(o.RootPathItem.Post.Extensions, extTfResourceName)
+	return o.getExtensionStringValue(rootOperation.Extensions, extTfResourceName)
 }
 
 func (o *SpecV2Resource) getExtensionStringValue(extensions spec.Extensions, key string) string {
@@ -646,31 +605,80 @@ func (o *SpecV2Resource) getExtensionStringValue(extensions spec.Extensions, key
 	return ""
 }
 
-func (o *SpecV2Resource) createResourceOperation(operation *spec.Operation) *specResourceOperation {
+// createResourceOperation returns the specResourceOperation for the given operation. The path item the operation belongs
+// to is used to look up the parameters that apply to all the path operations (e,g: the If-Match header)
+func (o *SpecV2Resource) createResourceOperation(operation *spec.Operation, pathItem spec.PathItem) *specResourceOperation {
 	if operation == nil {
 		return nil
 	}
 	headerParameters := getHeaderConfigurations(operation.Parameters)
 	securitySchemes := createSecuritySchemes(operation.Security)
 	return &specResourceOperation{
-		HeaderParameters: headerParameters,
-		SecuritySchemes:  securitySchemes,
-		responses:        o.createResponses(operation),
+		HeaderParameters:     headerParameters,
+		SecuritySchemes:      securitySchemes,
+		Schemes:              operation.Schemes,
+		BasePath:             o.getResourceOverrideBasePath(operation),
+		PatchContentType:     o.getExtensionStringValue(operation.Extensions, extTfResourcePatchContentType),
+		RetryableStatusCodes: getRetryableStatusCodes(operation.Extensions),
+		IdempotencyKeyHeader: o.getExtensionStringValue(operation.Extensions, extTfResourceIdempotencyKeyHeader),
+		IfMatch:              o.isBoolExtensionEnabled(operation.Extensions, extTfETag) || isIfMatchHeaderDeclared(operation.Parameters) || isIfMatchHeaderDeclared(pathItem.Parameters),
+		responses:            o.createResponses(operation),
+	}
+}
+
+// getResourceOverrideBasePath returns the value of the x-terraform-resource-base-path extension. The extension can be
+// defined at the operation level, in which case it only applies to the given operation, or in the root path POST
+// operation, in which case it applies to all the resource operations. An empty string is returned if the extension is
+// not present
+func (o *SpecV2Resource) getResourceOverrideBasePath(operation *spec.Operation) string {
+	for _, op := range []*spec.Operation{operation, o.getResourceRootOperation()} {
+		if op == nil {
+			continue
+		}
+		if basePath, exists := op.Extensions.GetString(extTfResourceBasePath); exists && basePath != "" {
+			return basePath
+		}
 	}
+	return ""
 }
 
 func (o *SpecV2Resource) createResponses(operation *spec.Operation) specResponses {
 	responses := specResponses{}
-	for statusCode, response := range operation.Responses.StatusCodeResponses { //panics on ImportState if the swagger doesn't define status code responses
+	// operations that do not define any response (e,g: 'responses' missing in the swagger) do not have responses configuration
+	if operation.Responses == nil {
+		return responses
+	}
+	for statusCode, response := range operation.Responses.StatusCodeResponses {
 		responses[statusCode] = &specResponse{
-			isPollingEnabled:    o.isResourcePollingEnabled(response),
-			pollTargetStatuses:  o.getResourcePollTargetStatuses(response),
-			pollPendingStatuses: o.getResourcePollPendingStatuses(response),
+			isPollingEnabled:       o.isResourcePollingEnabled(response),
+			pollTargetStatuses:     o.getResourcePollTargetStatuses(response),
+			pollPendingStatuses:    o.getResourcePollPendingStatuses(response),
+			pollFailedStatuses:     o.getPollingStatuses(response, extTfResourcePollFailedStatuses),
+			pollStatusField:        o.getExtensionStringValue(response.Extensions, extTfResourcePollStatusField),
+			pollErrorField:         o.getExtensionStringValue(response.Extensions, extTfResourcePollErrorField),
+			pollConfiguration:      newSpecPollConfiguration(response.Extensions),
+			pollOperation:          o.getResourcePollOperation(response),
+			isLongRunningOperation: o.isBoolExtensionEnabled(response.Extensions, extTfResourceLongRunningOperation),
 		}
 	}
 	return responses
 }
 
+// getResourcePollOperation returns the configuration to poll the operation resource returned in the Location or
+// Operation-Location headers if the response contains the extension 'x-terraform-resource-poll-operation-location' set
+// to true; otherwise nil is returned
+func (o *SpecV2Resource) getResourcePollOperation(response spec.Response) *specPollOperation {
+	if !o.isBoolExtensionEnabled(response.Extensions, extTfResourcePollOperationLocation) {
+		return nil
+	}
+	return newSpecPollOperation(
+		o.getExtensionStringValue(response.Extensions, extTfResourcePollOperationStatusField),
+		o.getExtensionStringValue(response.Extensions, extTfResourcePollOperationResourceIDField),
+		o.getExtensionStringValue(response.Extensions, extTfResourcePollOperationErrorField),
+		o.getPollingStatuses(response, extTfResourcePollOperationCompletedStatuses),
+		o.getPollingStatuses(response, extTfResourcePollOperationFailedStatuses))
+}
+
 // isResourcePollingEnabled checks whether there is any response code defined for the given responseStatusCode and if so
 // whether that response contains the extension 'x-terraform-resource-poll-enabled' set to true returning true;
 // otherwise false is returned
@@ -704,7 +712,7 @@ func (o *SpecV2Resource) getTimeouts() (*specTimeouts, error) {
 	var putTimeout *time.Duration
 	var deleteTimeout *time.Duration
 	var err error
-	if postTimeout, err = o.getResourceTimeout(o.RootPathItem.Post); err != nil {
+	if postTimeout, err = o.getResourceTimeout(o.getResourceRootOperation()); err != nil {
 		return nil, err
 	}
 	if getTimeout, err = o.getResourceTimeout(o.InstancePathItem.Get); err != nil {
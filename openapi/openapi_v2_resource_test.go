@@ -589,10 +589,9 @@ func TestBuildResourceNameFromPath(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		Convey("Given a SpecV2Resource", t, func() {
-			r := SpecV2Resource{}
-			Convey("When buildResourceName is called with the given path and preferred name", func() {
-				resourceName, err := r.buildResourceNameFromPath(tc.path, tc.preferredName)
+		Convey("Given a resource path and a preferred name", t, func() {
+			Convey("When buildResourceNameFromPath is called with the given path and preferred name", func() {
+				resourceName, err := buildResourceNameFromPath(tc.path, tc.preferredName)
 				if tc.expectedError != nil {
 					Convey("Then the error returned should be the expected one", func() {
 						So(err.Error(), ShouldEqual, tc.expectedError.Error())
@@ -3393,6 +3392,13 @@ func TestCreateResponses(t *testing.T) {
 			extensions.Add(extTfResourcePollEnabled, true)
 			extensions.Add(extTfResourcePollTargetStatuses, expectedTarget)
 			extensions.Add(extTfResourcePollPendingStatuses, expectedStatus)
+			extensions.Add(extTfResourcePollFailedStatuses, "deploy_failed, rollback_failed")
+			extensions.Add(extTfResourcePollStatusField, "$.status.state")
+			extensions.Add(extTfResourcePollErrorField, "status.error")
+			extensions.Add(extTfResourcePollDelay, "30s")
+			extensions.Add(extTfResourcePollInterval, "1m")
+			extensions.Add(extTfResourcePollMaxInterval, "10m")
+			extensions.Add(extTfResourcePollBackoffFactor, 1.5)
 			operation := &spec.Operation{
 				OperationProps: spec.OperationProps{
 					Responses: &spec.Responses{
@@ -3419,6 +3425,70 @@ func TestCreateResponses(t *testing.T) {
 				So(specResponses[http.StatusAccepted].isPollingEnabled, ShouldBeTrue)
 				So(specResponses[http.StatusAccepted].pollTargetStatuses, ShouldContain, expectedTarget)
 				So(specResponses[http.StatusAccepted].pollPendingStatuses, ShouldContain, expectedStatus)
+				So(specResponses[http.StatusAccepted].pollFailedStatuses, ShouldResemble, []string{"deploy_failed", "rollback_failed"})
+				So(specResponses[http.StatusAccepted].pollStatusField, ShouldEqual, "$.status.state")
+				So(specResponses[http.StatusAccepted].pollErrorField, ShouldEqual, "status.error")
+				So(specResponses[http.StatusAccepted].pollConfiguration, ShouldResemble, specPollConfiguration{delay: 30 * time.Second, interval: time.Minute, maxInterval: 10 * time.Minute, backoffFactor: 1.5})
+			})
+		})
+
+		Convey("When createResponses method is called with an operation that has the 'x-terraform-resource-poll-operation-location' extension set to true", func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourcePollOperationLocation, true)
+			extensions.Add(extTfResourcePollOperationStatusField, "state")
+			extensions.Add(extTfResourcePollOperationCompletedStatuses, "done")
+			operation := &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Responses: &spec.Responses{
+						ResponsesProps: spec.ResponsesProps{
+							StatusCodeResponses: map[int]spec.Response{
+								http.StatusAccepted: {
+									VendorExtensible: spec.VendorExtensible{
+										Extensions: extensions,
+									},
+								},
+								http.StatusOK: {},
+							},
+						},
+					},
+				},
+			}
+			specResponses := r.createResponses(operation)
+			Convey("Then the 202 response should be configured to poll the operation using the extension values and the defaults for the rest", func() {
+				So(specResponses[http.StatusAccepted].pollOperation, ShouldResemble, &specPollOperation{
+					statusField:       "state",
+					completedStatuses: []string{"done"},
+					failedStatuses:    defaultPollOperationFailedStatuses,
+					resourceIDField:   defaultPollOperationResourceIDField,
+					errorField:        defaultPollOperationErrorField,
+				})
+			})
+			Convey("And the 200 response should not be configured to poll the operation", func() {
+				So(specResponses[http.StatusOK].pollOperation, ShouldBeNil)
+			})
+		})
+
+		Convey("When createResponses method is called with an operation that has the 'x-terraform-resource-long-running-operation' extension set to true", func() {
+			extensions := spec.Extensions{}
+			extensions.Add(extTfResourceLongRunningOperation, true)
+			operation := &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Responses: &spec.Responses{
+						ResponsesProps: spec.ResponsesProps{
+							StatusCodeResponses: map[int]spec.Response{
+								http.StatusOK: {
+									VendorExtensible: spec.VendorExtensible{
+										Extensions: extensions,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			specResponses := r.createResponses(operation)
+			Convey("Then the response should be flagged as a long-running operation", func() {
+				So(specResponses[http.StatusOK].isLongRunningOperation, ShouldBeTrue)
 			})
 		})
 
@@ -4019,3 +4089,277 @@ func TestGetResourceOverrideHost(t *testing.T) {
 		})
 	})
 }
+
+func TestGetResourceOverrideBasePath(t *testing.T) {
+	Convey("Given a terraform compliant resource that has a POST operation containing the x-terraform-resource-base-path extension", t, func() {
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								extTfResourceBasePath: "/resource-api",
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When getResourceOverrideBasePath method is called with an operation that does not have the extension", func() {
+			basePath := r.getResourceOverrideBasePath(&spec.Operation{})
+			Convey("Then the value returned should be the resource level base path", func() {
+				So(basePath, ShouldEqual, "/resource-api")
+			})
+		})
+		Convey("When getResourceOverrideBasePath method is called with an operation that has the extension", func() {
+			operation := &spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfResourceBasePath: "/operation-api",
+					},
+				},
+			}
+			basePath := r.getResourceOverrideBasePath(operation)
+			Convey("Then the value returned should be the operation level base path", func() {
+				So(basePath, ShouldEqual, "/operation-api")
+			})
+		})
+	})
+
+	Convey("Given a terraform resource that doesn't have a POST operation", t, func() {
+		r := SpecV2Resource{}
+		Convey("When getResourceOverrideBasePath method is called with a nil operation", func() {
+			basePath := r.getResourceOverrideBasePath(nil)
+			Convey("Then the value returned should be an empty string", func() {
+				So(basePath, ShouldEqual, "")
+			})
+		})
+	})
+}
+
+func TestSpecV2ResourceIsSingleton(t *testing.T) {
+	Convey("Given a resource with a PUT instance operation containing the x-terraform-singleton extension set to true", t, func() {
+		put := &spec.Operation{
+			VendorExtensible: spec.VendorExtensible{
+				Extensions: spec.Extensions{
+					extTfSingleton:        true,
+					extTfResourceBasePath: "/settings-api",
+				},
+			},
+		}
+		r := SpecV2Resource{
+			InstancePathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Put: put,
+				},
+			},
+		}
+		Convey("When isSingleton method is called", func() {
+			isSingleton := r.isSingleton()
+			Convey("Then the value returned should be true", func() {
+				So(isSingleton, ShouldBeTrue)
+			})
+		})
+		Convey("When getResourceRootOperation method is called", func() {
+			operation := r.getResourceRootOperation()
+			Convey("Then the operation returned should be the instance PUT operation", func() {
+				So(operation, ShouldEqual, put)
+			})
+		})
+		Convey("When getResourceOverrideBasePath method is called", func() {
+			basePath := r.getResourceOverrideBasePath(nil)
+			Convey("Then the value returned should be the one configured in the PUT operation", func() {
+				So(basePath, ShouldEqual, "/settings-api")
+			})
+		})
+	})
+	Convey("Given a regular resource with a root POST operation", t, func() {
+		post := &spec.Operation{}
+		r := SpecV2Resource{
+			RootPathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: post,
+				},
+			},
+			InstancePathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Put: &spec.Operation{},
+				},
+			},
+		}
+		Convey("When isSingleton method is called", func() {
+			isSingleton := r.isSingleton()
+			Convey("Then the value returned should be false", func() {
+				So(isSingleton, ShouldBeFalse)
+			})
+		})
+		Convey("When getResourceRootOperation method is called", func() {
+			operation := r.getResourceRootOperation()
+			Convey("Then the operation returned should be the root POST operation", func() {
+				So(operation, ShouldEqual, post)
+			})
+		})
+	})
+}
+
+func TestSpecV2ResourceGetResourceActions(t *testing.T) {
+	Convey("Given a resource with custom methods defined in the paths", t, func() {
+		newCustomMethodPathItem := func(extensions spec.Extensions) spec.PathItem {
+			return spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: extensions}, OperationProps: spec.OperationProps{Responses: &spec.Responses{}}},
+				},
+			}
+		}
+		r := SpecV2Resource{
+			Path: "/v1/certs",
+			Paths: map[string]spec.PathItem{
+				"/v1/certs":                              {},
+				"/v1/certs/{id}":                         {},
+				"/v1/certs/{id}:rotate":                  newCustomMethodPathItem(spec.Extensions{extTfResourceActionTrigger: "rotation_trigger"}),
+				"/v1/certs/{id}:verify":                  newCustomMethodPathItem(spec.Extensions{}),
+				"/v1/certs/{id}:revoke":                  {},
+				"/v1/certs/{id}/v1/keys/{key_id}:rotate": newCustomMethodPathItem(spec.Extensions{extTfResourceActionTrigger: "key_rotation_trigger"}),
+				"/v1/certificates/{id}:rotate":           newCustomMethodPathItem(spec.Extensions{extTfResourceActionTrigger: "other_trigger"}),
+			},
+		}
+		Convey("When getResourceActions method is called", func() {
+			actions := r.getResourceActions()
+			Convey("Then only the resource custom methods with a POST operation and the trigger extension should be returned", func() {
+				So(actions, ShouldHaveLength, 1)
+				So(actions[0].Name, ShouldEqual, "rotate")
+				So(actions[0].TriggerAttribute, ShouldEqual, "rotation_trigger")
+				So(actions[0].Operation, ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given a singleton resource with a custom method defined in the paths", t, func() {
+		r := SpecV2Resource{
+			Path: "/v1/account/settings",
+			InstancePathItem: spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Put: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfSingleton: true}}, OperationProps: spec.OperationProps{Responses: &spec.Responses{}}},
+				},
+			},
+			Paths: map[string]spec.PathItem{
+				"/v1/account/settings:reset": {
+					PathItemProps: spec.PathItemProps{
+						Post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfResourceActionTrigger: "reset_trigger"}}, OperationProps: spec.OperationProps{Responses: &spec.Responses{}}},
+					},
+				},
+			},
+		}
+		Convey("When getResourceActions method is called", func() {
+			actions := r.getResourceActions()
+			Convey("Then the custom method applying to the singleton path should be returned", func() {
+				So(actions, ShouldHaveLength, 1)
+				So(actions[0].Name, ShouldEqual, "reset")
+				So(actions[0].TriggerAttribute, ShouldEqual, "reset_trigger")
+			})
+		})
+	})
+	Convey("Given a resource created with newSpecV2Resource that has a custom method defined in the paths", t, func() {
+		paths := map[string]spec.PathItem{
+			"/v1/certs/{id}:rotate": {
+				PathItemProps: spec.PathItemProps{
+					Post: &spec.Operation{VendorExtensible: spec.VendorExtensible{Extensions: spec.Extensions{extTfResourceActionTrigger: "rotation_trigger"}}, OperationProps: spec.OperationProps{Responses: &spec.Responses{}}},
+				},
+			},
+		}
+		r, err := newSpecV2Resource("/v1/certs", spec.Schema{}, spec.PathItem{}, spec.PathItem{}, nil, paths)
+		So(err, ShouldBeNil)
+		Convey("When getResourceOperations method is called", func() {
+			actions := r.getResourceOperations().Actions
+			Convey("Then the actions computed when the resource was created should be returned", func() {
+				So(actions, ShouldHaveLength, 1)
+				So(actions[0].Name, ShouldEqual, "rotate")
+				So(actions[0].TriggerAttribute, ShouldEqual, "rotation_trigger")
+				So(actions, ShouldResemble, r.actions)
+			})
+		})
+	})
+}
+
+func TestCreateResourceOperation(t *testing.T) {
+	Convey("Given a SpecV2Resource", t, func() {
+		r := SpecV2Resource{}
+		Convey("When createResourceOperation method is called with an operation that specifies schemes and the base path extension", func() {
+			operation := &spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfResourceBasePath: "/operation-api",
+					},
+				},
+				OperationProps: spec.OperationProps{
+					Schemes: []string{"http"},
+				},
+			}
+			resourceOperation := r.createResourceOperation(operation, spec.PathItem{})
+			Convey("Then the resource operation returned should contain the operation schemes and base path", func() {
+				So(resourceOperation.Schemes, ShouldResemble, []string{"http"})
+				So(resourceOperation.BasePath, ShouldEqual, "/operation-api")
+				So(resourceOperation.Host, ShouldBeEmpty)
+			})
+			Convey("And the resource operation returned should not contain any response as the operation does not define them", func() {
+				So(resourceOperation.responses, ShouldBeEmpty)
+			})
+		})
+		Convey("When createResourceOperation method is called with an operation that specifies the retryable status codes and idempotency key header extensions", func() {
+			operation := &spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfRetryableStatusCodes:         "409, 503, invalid",
+						extTfResourceIdempotencyKeyHeader: "Idempotency-Key",
+					},
+				},
+			}
+			resourceOperation := r.createResourceOperation(operation, spec.PathItem{})
+			Convey("Then the resource operation returned should contain the valid retryable status codes and the idempotency key header", func() {
+				So(resourceOperation.RetryableStatusCodes, ShouldResemble, []int{409, 503})
+				So(resourceOperation.IdempotencyKeyHeader, ShouldEqual, "Idempotency-Key")
+			})
+		})
+		Convey("When createResourceOperation method is called with an operation that specifies the 'x-terraform-etag' extension", func() {
+			operation := &spec.Operation{
+				VendorExtensible: spec.VendorExtensible{
+					Extensions: spec.Extensions{
+						extTfETag: true,
+					},
+				},
+			}
+			resourceOperation := r.createResourceOperation(operation, spec.PathItem{})
+			Convey("Then the resource operation returned should send the If-Match header", func() {
+				So(resourceOperation.IfMatch, ShouldBeTrue)
+			})
+		})
+		Convey("When createResourceOperation method is called with an operation that declares the If-Match header parameter", func() {
+			operation := &spec.Operation{
+				OperationProps: spec.OperationProps{
+					Parameters: []spec.Parameter{{ParamProps: spec.ParamProps{Name: "If-Match", In: "header", Required: true}}},
+				},
+			}
+			resourceOperation := r.createResourceOperation(operation, spec.PathItem{})
+			Convey("Then the resource operation returned should send the If-Match header and it should not be part of the header parameters", func() {
+				So(resourceOperation.IfMatch, ShouldBeTrue)
+				So(resourceOperation.HeaderParameters, ShouldBeEmpty)
+			})
+		})
+		Convey("When createResourceOperation method is called with an operation which path declares the If-Match header parameter", func() {
+			pathItem := spec.PathItem{
+				PathItemProps: spec.PathItemProps{
+					Parameters: []spec.Parameter{{ParamProps: spec.ParamProps{Name: "If-Match", In: "header", Required: true}}},
+				},
+			}
+			resourceOperation := r.createResourceOperation(&spec.Operation{}, pathItem)
+			Convey("Then the resource operation returned should send the If-Match header", func() {
+				So(resourceOperation.IfMatch, ShouldBeTrue)
+			})
+		})
+		Convey("When createResourceOperation method is called with a nil operation", func() {
+			resourceOperation := r.createResourceOperation(nil, spec.PathItem{})
+			Convey("Then the resource operation returned should be nil", func() {
+				So(resourceOperation, ShouldBeNil)
+			})
+		})
+	})
+}
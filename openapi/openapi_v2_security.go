@@ -2,11 +2,18 @@ package openapi
 
 import (
 	"fmt"
+	"log"
+
 	"github.com/go-openapi/spec"
 )
 
 const extTfAuthenticationSchemeBearer = "x-terraform-authentication-scheme-bearer"
 const extTfAuthenticationRefreshToken = "x-terraform-refresh-token-url"
+const extTfAuthenticationRefreshTokenExpiryHeader = "x-terraform-refresh-token-expiry-header"
+const extTfAuthenticationRefreshTokenExpiryMargin = "x-terraform-refresh-token-expiry-margin"
+const extTfAuthenticationRefreshTokenResponseProperty = "x-terraform-refresh-token-response-property"
+const extTfAuthenticationSchemeSignature = "x-terraform-authentication-scheme-signature"
+const extTfAuthenticationSessionLogin = "x-terraform-session-login-url"
 
 type specV2Security struct {
 	SecurityDefinitions spec.SecurityDefinitions
@@ -14,16 +21,20 @@ type specV2Security struct {
 }
 
 // GetAPIKeySecurityDefinitions returns a list of SpecSecurityDefinition after looping through the SecurityDefinitions
-// and selecting only the SecurityDefinitions of type apiKey
+// and selecting only the SecurityDefinitions of type apiKey, type basic or type oauth2 using the application (client credentials),
+// password or accessCode (authorization code) flows
 func (s *specV2Security) GetAPIKeySecurityDefinitions() (*SpecSecurityDefinitions, error) {
 	securityDefinitions := &SpecSecurityDefinitions{}
 	for secDefName, secDef := range s.SecurityDefinitions {
-		if secDef.Type == "apiKey" {
-			var securityDefinition SpecSecurityDefinition
+		var securityDefinition SpecSecurityDefinition
+		switch secDef.Type {
+		case "apiKey":
 			switch secDef.In {
 			case "header":
 				if refreshTokenURL := s.isRefreshTokenAuth(secDef); refreshTokenURL != "" {
-					securityDefinition = newAPIKeyHeaderRefreshTokenSecurityDefinition(secDefName, refreshTokenURL)
+					securityDefinition = newAPIKeyHeaderRefreshTokenSecurityDefinition(secDefName, refreshTokenURL).withConfiguration(newRefreshTokenConfiguration(secDef.Extensions))
+				} else if configuration, isSignatureAuth := newSignatureConfiguration(secDef.Extensions); isSignatureAuth {
+					securityDefinition = newAPIKeyHeaderSignatureSecurityDefinition(secDefName, secDef.Name, configuration)
 				} else if s.isBearerScheme(secDef) {
 					securityDefinition = newAPIKeyHeaderBearerSecurityDefinition(secDefName)
 				} else {
@@ -35,14 +46,36 @@ func (s *specV2Security) GetAPIKeySecurityDefinitions() (*SpecSecurityDefinition
 				} else {
 					securityDefinition = newAPIKeyQuerySecurityDefinition(secDefName, secDef.Name)
 				}
+			case "cookie":
+				if loginURL := s.isSessionLoginAuth(secDef); loginURL != "" {
+					securityDefinition = newAPIKeyCookieSessionLoginSecurityDefinition(secDefName, secDef.Name, loginURL)
+				} else {
+					securityDefinition = newAPIKeyCookieSecurityDefinition(secDefName, secDef.Name)
+				}
 			default:
-				return nil, fmt.Errorf("apiKey In value '%s' not supported, only 'header' and 'query' values are valid", secDef.In)
+				return nil, fmt.Errorf("apiKey In value '%s' not supported, only 'header', 'query' and 'cookie' values are valid", secDef.In)
 			}
-			if err := securityDefinition.validate(); err != nil {
-				return nil, err
+		case "basic":
+			securityDefinition = newBasicSecurityDefinition(secDefName)
+		case "oauth2":
+			switch secDef.Flow {
+			case "application":
+				securityDefinition = newOAuth2ClientCredentialsSecurityDefinition(secDefName, secDef.TokenURL)
+			case "password":
+				securityDefinition = newOAuth2PasswordSecurityDefinition(secDefName, secDef.TokenURL)
+			case "accessCode":
+				securityDefinition = newOAuth2TokenFileSecurityDefinition(secDefName, secDef.TokenURL)
+			default:
+				log.Printf("[WARN] ignoring security definition '%s' as the oauth2 flow '%s' is not supported, only the 'application', 'password' and 'accessCode' flows are supported", secDefName, secDef.Flow)
+				continue
 			}
-			*securityDefinitions = append(*securityDefinitions, securityDefinition)
+		default:
+			continue
+		}
+		if err := securityDefinition.validate(); err != nil {
+			return nil, err
 		}
+		*securityDefinitions = append(*securityDefinitions, securityDefinition)
 	}
 	return securityDefinitions, nil
 }
@@ -63,17 +96,28 @@ func (s *specV2Security) isRefreshTokenAuth(secDef *spec.SecurityScheme) string
 	return ""
 }
 
+func (s *specV2Security) isSessionLoginAuth(secDef *spec.SecurityScheme) string {
+	loginURL, isSessionLoginAuth := secDef.Extensions.GetString(extTfAuthenticationSessionLogin)
+	if isSessionLoginAuth {
+		return loginURL
+	}
+	return ""
+}
+
 // GetGlobalSecuritySchemes returns a list of SpecSecuritySchemes that have their corresponding SpecSecurityDefinition
 func (s *specV2Security) GetGlobalSecuritySchemes() (SpecSecuritySchemes, error) {
 	securitySchemes := createSecuritySchemes(s.GlobalSecurity)
 	for _, securityScheme := range securitySchemes {
+		if securityScheme.isEmptyRequirement() {
+			continue
+		}
 		secDef, err := s.GetAPIKeySecurityDefinitions()
 		if err != nil {
 			return SpecSecuritySchemes{}, nil
 		}
 		secDefFound := secDef.findSecurityDefinitionFor(securityScheme.Name)
 		if secDefFound == nil {
-			return nil, fmt.Errorf("global security scheme '%s' not found or not matching supported 'apiKey' type", securityScheme.Name)
+			return nil, fmt.Errorf("global security scheme '%s' not found or not matching supported 'apiKey', 'basic' or 'oauth2' type", securityScheme.Name)
 		}
 	}
 	return securitySchemes, nil
@@ -4,6 +4,7 @@ import (
 	"github.com/go-openapi/spec"
 	. "github.com/smartystreets/goconvey/convey"
 	"testing"
+	"time"
 )
 
 func TestGetAPIKeySecurityDefinitions(t *testing.T) {
@@ -167,6 +168,98 @@ func TestGetAPIKeySecurityDefinitions(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a specV2Security loaded with a header refresh token security definition that configures how the access token is read and cached", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"apikey_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuthenticationRefreshToken:                 "http://some-refresh-token-url.com/api/token",
+							extTfAuthenticationRefreshTokenExpiryHeader:     "X-Expires-In",
+							extTfAuthenticationRefreshTokenExpiryMargin:     "2m",
+							extTfAuthenticationRefreshTokenResponseProperty: "access_token",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the security definition api key metadata should contain the refresh token configuration", func() {
+				So(err, ShouldBeNil)
+				So(secDefs[0].getAPIKey().Metadata[refreshTokenConfigurationKey], ShouldResemble, refreshTokenConfiguration{expiryHeader: "X-Expires-In", expiryMargin: 2 * time.Minute, responseProperty: "access_token"})
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a header security definition that signs the requests", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"signature_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Name: "X-Signature",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuthenticationSchemeSignature: map[string]interface{}{
+								"scheme":           "HMAC-SHA256",
+								"encoding":         "base64",
+								"timestamp-header": "X-Date",
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the security definition should be a signature security definition using the header and configuration specified", func() {
+				So(err, ShouldBeNil)
+				So(secDefs[0].getType(), ShouldEqual, securityDefinitionAPIKeySignature)
+				So(secDefs[0].getAPIKey().Name, ShouldEqual, "X-Signature")
+				So(secDefs[0].getAPIKey().Metadata[signatureConfigurationKey], ShouldResemble, signatureConfiguration{scheme: "HMAC-SHA256", encoding: "base64", timestampHeader: "X-Date", timestampFormat: "unix", components: defaultSignatureComponents})
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with a header security definition that signs the requests using a non supported encoding", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"signature_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "header",
+						Name: "X-Signature",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuthenticationSchemeSignature: map[string]interface{}{
+								"encoding": "base32",
+							},
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			_, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the error returned should describe the invalid extension", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "security definition 'signature_auth' contains an invalid x-terraform-authentication-scheme-signature extension: signature encoding 'base32' not supported, only 'hex' and 'base64' values are valid")
+			})
+		})
+	})
 
 	Convey("Given a specV2Security loaded with a security definition of type header bearer", t, func() {
 		specV2Security := specV2Security{
@@ -248,6 +341,48 @@ func TestGetAPIKeySecurityDefinitions(t *testing.T) {
 		})
 	})
 
+	Convey("Given a specV2Security loaded with a cookie security definition and a cookie session login security definition", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"cookie_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "cookie",
+						Name: "api_key",
+						Type: "apiKey",
+					},
+				},
+				"session_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						In:   "cookie",
+						Name: "session_id",
+						Type: "apiKey",
+					},
+					VendorExtensible: spec.VendorExtensible{
+						Extensions: spec.Extensions{
+							extTfAuthenticationSessionLogin: "https://api.server.com/login",
+						},
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the security definitions should be cookie security definitions", func() {
+				cookieSecDef := securityDefinitions.findSecurityDefinitionFor("cookie_auth")
+				So(cookieSecDef.getType(), ShouldEqual, securityDefinitionAPIKey)
+				So(cookieSecDef.getAPIKey(), ShouldResemble, newAPIKeyCookie("api_key"))
+				sessionSecDef := securityDefinitions.findSecurityDefinitionFor("session_auth")
+				So(sessionSecDef.getType(), ShouldEqual, securityDefinitionAPIKeySessionLogin)
+				So(sessionSecDef.getAPIKey().In, ShouldEqual, inCookie)
+				So(sessionSecDef.getAPIKey().Metadata[sessionLoginURLKey], ShouldEqual, "https://api.server.com/login")
+			})
+		})
+	})
+
 	Convey("Given a specV2Security loaded with a apiKey type but the location (In) is not supported", t, func() {
 		specV2Security := specV2Security{
 			GlobalSecurity: []map[string][]string{},
@@ -263,7 +398,107 @@ func TestGetAPIKeySecurityDefinitions(t *testing.T) {
 		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
 			_, err := specV2Security.GetAPIKeySecurityDefinitions()
 			Convey("And the error should match the expected one", func() {
-				So(err.Error(), ShouldEqual, "apiKey In value 'some_other_location' not supported, only 'header' and 'query' values are valid")
+				So(err.Error(), ShouldEqual, "apiKey In value 'some_other_location' not supported, only 'header', 'query' and 'cookie' values are valid")
+			})
+		})
+	})
+
+	Convey("Given a specV2Security loaded with security definitions of type oauth2 using the application flow and the implicit flow", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"oauth2_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type:     "oauth2",
+						Flow:     "application",
+						TokenURL: "https://api.iam.com/oauth2/token",
+					},
+				},
+				"oauth2_implicit_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type:             "oauth2",
+						Flow:             "implicit",
+						AuthorizationURL: "https://api.iam.com/oauth2/authorize",
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			secDefs := *securityDefinitions
+			Convey("Then the security definitions should only contain the oauth2 client credentials one", func() {
+				So(err, ShouldBeNil)
+				So(secDefs, ShouldHaveLength, 1)
+				So(secDefs[0], ShouldResemble, newOAuth2ClientCredentialsSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token"))
+			})
+		})
+	})
+	Convey("Given a specV2Security loaded with security definitions of type oauth2 using the password flow and the accessCode flow", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"oauth2_password_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type:     "oauth2",
+						Flow:     "password",
+						TokenURL: "https://api.iam.com/oauth2/token",
+					},
+				},
+				"oauth2_access_code_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type:             "oauth2",
+						Flow:             "accessCode",
+						AuthorizationURL: "https://api.iam.com/oauth2/authorize",
+						TokenURL:         "https://api.iam.com/oauth2/token",
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the security definitions should contain the oauth2 password and token file ones", func() {
+				So(err, ShouldBeNil)
+				So(*securityDefinitions, ShouldHaveLength, 2)
+				So(securityDefinitions.findSecurityDefinitionFor("oauth2_password_auth"), ShouldResemble, newOAuth2PasswordSecurityDefinition("oauth2_password_auth", "https://api.iam.com/oauth2/token"))
+				So(securityDefinitions.findSecurityDefinitionFor("oauth2_access_code_auth"), ShouldResemble, newOAuth2TokenFileSecurityDefinition("oauth2_access_code_auth", "https://api.iam.com/oauth2/token"))
+			})
+		})
+	})
+	Convey("Given a specV2Security loaded with a security definition of type basic", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"basic_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "basic",
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			securityDefinitions, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the security definitions should contain the basic one", func() {
+				So(err, ShouldBeNil)
+				So(*securityDefinitions, ShouldResemble, SpecSecurityDefinitions{newBasicSecurityDefinition("basic_auth")})
+			})
+		})
+	})
+	Convey("Given a specV2Security loaded with a security definition of type oauth2 using the application flow without token URL", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"oauth2_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "oauth2",
+						Flow: "application",
+					},
+				},
+			},
+		}
+		Convey("When GetAPIKeySecurityDefinitions method is called", func() {
+			_, err := specV2Security.GetAPIKeySecurityDefinitions()
+			Convey("Then the error should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "specOAuth2SecurityDefinition missing mandatory token URL")
 			})
 		})
 	})
@@ -301,6 +536,29 @@ func TestGetGlobalSecuritySchemes(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a specV2Security loaded with a global security scheme of type basic", t, func() {
+		specV2Security := specV2Security{
+			GlobalSecurity: []map[string][]string{
+				{
+					"basic_auth": []string{},
+				},
+			},
+			SecurityDefinitions: spec.SecurityDefinitions{
+				"basic_auth": &spec.SecurityScheme{
+					SecuritySchemeProps: spec.SecuritySchemeProps{
+						Type: "basic",
+					},
+				},
+			},
+		}
+		Convey("When GetGlobalSecuritySchemes method is called", func() {
+			specSecuritySchemes, err := specV2Security.GetGlobalSecuritySchemes()
+			Convey("Then the the error returned should be nil and the security schemes should contain the basic one", func() {
+				So(err, ShouldBeNil)
+				So(specSecuritySchemes, ShouldResemble, SpecSecuritySchemes{{Name: "basic_auth"}})
+			})
+		})
+	})
 	Convey("Given a specV2Security loaded with a NON defined global security scheme", t, func() {
 		specV2Security := specV2Security{
 			GlobalSecurity: []map[string][]string{
@@ -324,7 +582,7 @@ func TestGetGlobalSecuritySchemes(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the security schemes should not be empty", func() {
-				So(err.Error(), ShouldEqual, "global security scheme 'nonExistingScheme' not found or not matching supported 'apiKey' type")
+				So(err.Error(), ShouldEqual, "global security scheme 'nonExistingScheme' not found or not matching supported 'apiKey', 'basic' or 'oauth2' type")
 			})
 		})
 	})
@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -25,12 +26,22 @@ type specV2Analyser struct {
 }
 
 // newSpecAnalyserV2 creates an instance of specV2Analyser which implements the SpecAnalyser interface
-// This implementation provides an analyser that understands an OpenAPI v2 document
-func newSpecAnalyserV2(openAPIDocumentFilename string) (*specV2Analyser, error) {
+// This implementation provides an analyser that understands an OpenAPI v2 document. The httpClient is used to retrieve
+// the document when served over http
+func newSpecAnalyserV2(openAPIDocumentFilename string, httpClient *http.Client) (*specV2Analyser, error) {
 	if openAPIDocumentFilename == "" {
 		return nil, errors.New("open api document filename argument empty, please provide the url of the OpenAPI document")
 	}
-	apiSpec, err := loads.JSONSpec(openAPIDocumentFilename)
+	content, err := readOpenAPIDocument(openAPIDocumentFilename, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
+	}
+	return loadSpecAnalyserV2(openAPIDocumentFilename, content)
+}
+
+// loadSpecAnalyserV2 returns a specV2Analyser for the given OpenAPI document content already retrieved from openAPIDocumentFilename
+func loadSpecAnalyserV2(openAPIDocumentFilename string, content []byte) (*specV2Analyser, error) {
+	apiSpec, err := loads.Analyzed(json.RawMessage(content), "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
 	}
@@ -86,6 +97,21 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecReso
 	spec := specAnalyser.d.Spec()
 	paths := spec.Paths
 	for resourcePath, pathItem := range paths.Paths {
+		if isCustomMethodPath(resourcePath) {
+			log.Printf("[DEBUG] resource path '%s' is a custom method, it will be attached to the resource it belongs to (if any)", resourcePath)
+			continue
+		}
+		if specAnalyser.isSingletonEndPoint(pathItem) {
+			r, err := specAnalyser.createSingletonResource(resourcePath, pathItem)
+			if err != nil {
+				log.Printf("[WARN] ignoring singleton resource '%s' due to not meeting validation requirements: %s", resourcePath, err)
+				continue
+			}
+			log.Printf("[INFO] found terraform compliant singleton resource [name='%s', path='%s']", r.getResourceName(), resourcePath)
+			resources = append(resources, r)
+			continue
+		}
+
 		resourceRootPath, resourceRoot, resourcePayloadSchemaDef, err := specAnalyser.isEndPointFullyTerraformResourceCompliant(resourcePath)
 		if err != nil {
 			log.Printf("[DEBUG] resource path '%s' not terraform compliant: %s", resourcePath, err)
@@ -127,6 +153,38 @@ func (specAnalyser *specV2Analyser) GetTerraformCompliantResources() ([]SpecReso
 	return resources, nil
 }
 
+// isSingletonEndPoint returns true if the given path has a PUT operation with the 'x-terraform-singleton' extension set to true
+func (specAnalyser *specV2Analyser) isSingletonEndPoint(pathItem spec.PathItem) bool {
+	if pathItem.Put == nil {
+		return false
+	}
+	isSingleton, exists := pathItem.Put.Extensions.GetBool(extTfSingleton)
+	return exists && isSingleton
+}
+
+// createSingletonResource returns a singleton SpecV2Resource for the given path only if:
+// - The path has a GET operation defined (required). DELETE is optional and if present it is expected to reset the resource to its defaults
+// - The PUT operation has a parameter of type 'body' with a schema property referencing to an existing definition object
+// - If the path belongs to a subresource (e,g: /v1/projects/{project_id}/quota), the parent paths must exist
+// As opposed to regular resources, the schema definition does not require a property that uniquely identifies the resource
+func (specAnalyser *specV2Analyser) createSingletonResource(resourcePath string, pathItem spec.PathItem) (*SpecV2Resource, error) {
+	if pathItem.Get == nil {
+		return nil, fmt.Errorf("singleton resource path '%s' missing required GET operation", resourcePath)
+	}
+	resourcePayloadSchemaDef, err := specAnalyser.getBodyParameterBodySchema(pathItem.Put)
+	if err != nil {
+		return nil, fmt.Errorf("singleton resource path '%s' PUT operation validation error: %s", resourcePath, err)
+	}
+	r, err := newSpecV2Resource(resourcePath, *resourcePayloadSchemaDef, spec.PathItem{}, pathItem, specAnalyser.d.Spec().Definitions, specAnalyser.d.Spec().Paths.Paths)
+	if err != nil {
+		return nil, err
+	}
+	if err := specAnalyser.validateSubResourceTerraformCompliance(*r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
 func (specAnalyser *specV2Analyser) validateSubResourceTerraformCompliance(r SpecV2Resource) error {
 	parentResourceInfo := r.getParentResourceInfo()
 	if parentResourceInfo != nil {
@@ -280,7 +338,12 @@ func (specAnalyser *specV2Analyser) isEndPointFullyTerraformResourceCompliant(re
 	}
 	resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, err := specAnalyser.validateRootPath(resourcePath)
 	if err != nil {
-		return "", nil, nil, err
+		var putErr error
+		resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, putErr = specAnalyser.validateInstancePathPut(resourcePath)
+		if putErr != nil {
+			log.Printf("[DEBUG] resource instance path '%s' can not be created via PUT either: %s", resourcePath, putErr)
+			return "", nil, nil, err
+		}
 	}
 	err = specAnalyser.validateResourceSchemaDefinition(resourceRootPostSchemaDef)
 	if err != nil {
@@ -349,6 +412,55 @@ func (specAnalyser *specV2Analyser) validateRootPath(resourcePath string) (strin
 	return resourceRootPath, &resourceRootPathItem, resourceRootPostSchemaDef, nil
 }
 
+// validateInstancePathPut validates resources with client generated IDs that are created via PUT on the instance path
+// (e,g: PUT /v1/buckets/{name}) instead of POST on the root path. The resource is considered compliant only if:
+// - The instance path has a PUT operation with a parameter of type 'body' with a schema property referencing to an existing definition object
+// - The root path (e,g: /v1/buckets) does not expose a POST operation. Note the root path itself is optional
+// - The resource schema definition contains a property with the 'x-terraform-id' extension set to true which is not readOnly,
+// as its value is expected to be provided by the user
+func (specAnalyser *specV2Analyser) validateInstancePathPut(resourcePath string) (string, *spec.PathItem, *spec.Schema, error) {
+	resourceInstancePathItem := specAnalyser.d.Spec().Paths.Paths[resourcePath]
+	if resourceInstancePathItem.Put == nil {
+		return "", nil, nil, fmt.Errorf("resource instance path '%s' missing PUT operation", resourcePath)
+	}
+	resourceRootPathItem := spec.PathItem{}
+	resourceRootPath, err := specAnalyser.findMatchingResourceRootPath(resourcePath)
+	if err == nil {
+		resourceRootPathItem = specAnalyser.d.Spec().Paths.Paths[resourceRootPath]
+		if resourceRootPathItem.Post != nil {
+			return "", nil, nil, fmt.Errorf("resource root path '%s' exposes a POST operation", resourceRootPath)
+		}
+	} else {
+		resourceRootPath, err = specAnalyser.buildResourceRootPath(resourcePath)
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+	resourceSchemaDef, err := specAnalyser.getBodyParameterBodySchema(resourceInstancePathItem.Put)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("resource instance path '%s' PUT operation validation error: %s", resourcePath, err)
+	}
+	err = specAnalyser.validateClientGeneratedIdentifier(resourceSchemaDef)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return resourceRootPath, &resourceRootPathItem, resourceSchemaDef, nil
+}
+
+// validateClientGeneratedIdentifier checks that the given schema contains a property with the 'x-terraform-id' extension
+// set to true that can be configured by the user (not readOnly)
+func (specAnalyser *specV2Analyser) validateClientGeneratedIdentifier(schema *spec.Schema) error {
+	for propertyName, property := range schema.Properties {
+		if useAsIdentifier, exists := property.Extensions.GetBool(extTfID); exists && useAsIdentifier {
+			if property.ReadOnly {
+				return fmt.Errorf("resource schema identifier property '%s' must not be readOnly as its value is expected to be provided by the user", propertyName)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("resource schema is missing a property with the extension '%s' set to true, which is required for resources created via PUT", extTfID)
+}
+
 func (specAnalyser *specV2Analyser) validateResourceSchemaDefinition(schema *spec.Schema) error {
 	identifier := ""
 	for propertyName, property := range schema.Properties {
@@ -432,6 +544,20 @@ func (specAnalyser *specV2Analyser) isResourceInstanceEndPoint(p string) (bool,
 	return r.MatchString(p), nil
 }
 
+// buildResourceRootPath returns the root path for the given resource instance path (e,g: /v1/buckets for /v1/buckets/{name})
+// without checking whether the root path exists in the document
+func (specAnalyser *specV2Analyser) buildResourceRootPath(resourcePath string) (string, error) {
+	r, err := specAnalyser.resourceInstanceRegex()
+	if err != nil {
+		return "", err
+	}
+	result := r.FindStringSubmatch(resourcePath)
+	if len(result) != 2 {
+		return "", fmt.Errorf("resource instance path '%s' missing valid resource root path, more than two results returned from match '%s'", resourcePath, result)
+	}
+	return strings.TrimRight(result[1], "/"), nil
+}
+
 // findMatchingResourceRootPath returns the corresponding POST root and path for a given end point
 // Example: Given 'resourcePath' being "/users/{username}" the result could be "/users" or "/users/" depending on
 // how the POST operation (resourceRootPath) of the given resource is defined in swagger.
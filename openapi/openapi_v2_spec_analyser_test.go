@@ -278,7 +278,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		swaggerFile := initAPISpecFile(swaggerJSON)
 		defer os.Remove(swaggerFile.Name())
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -312,7 +312,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		defer os.Remove(swaggerFile.Name())
 
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -341,7 +341,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		defer os.Remove(swaggerFile.Name())
 
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldContainSubstring, "error = invalid character 'h' after object key:value pair")
 			})
@@ -363,7 +363,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		defer os.Remove(swaggerFile.Name())
 
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be the expected error", func() {
 				So(err.Error(), ShouldContainSubstring, "error = read .: is a directory")
 			})
@@ -457,7 +457,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		swaggerFile := initAPISpecFile(swaggerJSON)
 		defer os.Remove(swaggerFile.Name())
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -512,7 +512,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		swaggerFile := initAPISpecFile(swaggerJSON)
 		defer os.Remove(swaggerFile.Name())
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -557,7 +557,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		swaggerFile := initAPISpecFile(swaggerJSON)
 		defer os.Remove(swaggerFile.Name())
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldContainSubstring, "error = object has no key \"NonExistingDef\"")
 			})
@@ -591,7 +591,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		swaggerFile := initAPISpecFile(swaggerJSON)
 		defer os.Remove(swaggerFile.Name())
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldContainSubstring, "error = invalid character '}' looking for beginning of value")
 			})
@@ -607,7 +607,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 		swaggerFile := initAPISpecFile(swaggerJSON)
 		defer os.Remove(swaggerFile.Name())
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned should be not nil", func() {
 				So(err.Error(), ShouldContainSubstring, "failed to expand the OpenAPI document from ")
 				So(err.Error(), ShouldContainSubstring, " - error = open nosuchfile.json: no such file or directory")
@@ -619,7 +619,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 	})
 
 	Convey("When newSpecAnalyserV2 method is called with an empty string for openAPIDocumentFilename", t, func() {
-		specAnalyserV2, err := newSpecAnalyserV2("")
+		specAnalyserV2, err := newSpecAnalyserV2("", http.DefaultClient)
 		Convey("Then the error returned should be not nil", func() {
 			So(err.Error(), ShouldEqual, "open api document filename argument empty, please provide the url of the OpenAPI document")
 		})
@@ -629,7 +629,7 @@ func TestNewSpecAnalyserV2(t *testing.T) {
 	})
 
 	Convey("When newSpecAnalyserV2 method is called with a bogus value openAPIDocumentFilename", t, func() {
-		specAnalyserV2, err := newSpecAnalyserV2("nosuchthing")
+		specAnalyserV2, err := newSpecAnalyserV2("nosuchthing", http.DefaultClient)
 		Convey("Then the error returned should be not nil", func() {
 			So(err.Error(), ShouldEqual, "failed to retrieve the OpenAPI document from 'nosuchthing' - error = open nosuchthing: no such file or directory")
 		})
@@ -2964,7 +2964,7 @@ definitions:
 		defer os.Remove(swaggerFile.Name())
 
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned by calling newSpecAnalyserV2 should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -3228,7 +3228,7 @@ definitions:
 		defer os.Remove(swaggerFile.Name())
 
 		Convey("When newSpecAnalyserV2 method is called", func() {
-			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name())
+			specAnalyserV2, err := newSpecAnalyserV2(swaggerFile.Name(), http.DefaultClient)
 			Convey("Then the error returned by calling newSpecAnalyserV2 should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -3358,6 +3358,266 @@ definitions:
 			})
 		})
 	})
+
+	Convey("Given an specV2Analyser loaded with a swagger file containing a singleton resource /v1/account/settings", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1/account/settings:
+    put:
+      x-terraform-singleton: true
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/AccountSettings"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/AccountSettings"
+    get:
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/AccountSettings"
+definitions:
+  AccountSettings:
+    type: "object"
+    properties:
+      timezone:
+        type: "string"`
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When GetTerraformCompliantResources method is called", func() {
+			r, err := a.GetTerraformCompliantResources()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the singleton resource should be returned even though the schema does not have an id property", func() {
+				So(len(r), ShouldEqual, 1)
+				So(r[0].getResourceName(), ShouldEqual, "settings")
+				So(r[0].isSingleton(), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given an specV2Analyser loaded with a swagger file containing a singleton resource missing the GET operation", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1/account/settings:
+    put:
+      x-terraform-singleton: true
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/AccountSettings"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/AccountSettings"
+definitions:
+  AccountSettings:
+    type: "object"
+    properties:
+      timezone:
+        type: "string"`
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When GetTerraformCompliantResources method is called", func() {
+			r, err := a.GetTerraformCompliantResources()
+			Convey("Then the err returned should be nil and the singleton resource should be ignored", func() {
+				So(err, ShouldBeNil)
+				So(r, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an specV2Analyser loaded with a swagger file containing a resource created via PUT on the instance path /v1/buckets/{name}", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1/buckets/{name}:
+    put:
+      parameters:
+      - name: "name"
+        in: "path"
+        required: true
+        type: "string"
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/Bucket"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/Bucket"
+    get:
+      parameters:
+      - name: "name"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/Bucket"
+definitions:
+  Bucket:
+    type: "object"
+    properties:
+      name:
+        type: "string"
+        x-terraform-id: true
+        x-terraform-force-new: true
+      label:
+        type: "string"`
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When GetTerraformCompliantResources method is called", func() {
+			r, err := a.GetTerraformCompliantResources()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the resource should be returned with the root path built from the instance path and no POST operation", func() {
+				So(len(r), ShouldEqual, 1)
+				So(r[0].getResourceName(), ShouldEqual, "buckets_v1")
+				path, _ := r[0].getResourcePath(nil)
+				So(path, ShouldEqual, "/v1/buckets")
+				method, operation := r[0].getResourceOperations().getCreateOperation()
+				So(method, ShouldEqual, httpPut)
+				So(operation, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given an specV2Analyser loaded with a swagger file containing a resource created via PUT where the identifier property is readOnly", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1/buckets:
+    get:
+      responses:
+        200:
+          schema:
+            type: "array"
+            items:
+              $ref: "#/definitions/Bucket"
+  /v1/buckets/{name}:
+    put:
+      parameters:
+      - name: "name"
+        in: "path"
+        required: true
+        type: "string"
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/Bucket"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/Bucket"
+    get:
+      parameters:
+      - name: "name"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/Bucket"
+definitions:
+  Bucket:
+    type: "object"
+    properties:
+      name:
+        type: "string"
+        readOnly: true
+        x-terraform-id: true
+      label:
+        type: "string"`
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When validateInstancePathPut method is called", func() {
+			_, _, _, err := a.validateInstancePathPut("/v1/buckets/{name}")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "resource schema identifier property 'name' must not be readOnly as its value is expected to be provided by the user")
+			})
+		})
+		Convey("When GetTerraformCompliantResources method is called", func() {
+			r, err := a.GetTerraformCompliantResources()
+			Convey("Then the err returned should be nil and the resource should be ignored", func() {
+				So(err, ShouldBeNil)
+				So(r, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an specV2Analyser loaded with a swagger file containing a resource with a custom method /v1/certs/{id}:rotate", t, func() {
+		swaggerContent := `swagger: "2.0"
+paths:
+  /v1/certs:
+    post:
+      parameters:
+      - in: "body"
+        name: "body"
+        required: true
+        schema:
+          $ref: "#/definitions/Cert"
+      responses:
+        201:
+          schema:
+            $ref: "#/definitions/Cert"
+  /v1/certs/{id}:
+    get:
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        200:
+          schema:
+            $ref: "#/definitions/Cert"
+  /v1/certs/{id}:rotate:
+    post:
+      x-terraform-resource-action-trigger: rotation_trigger
+      parameters:
+      - name: "id"
+        in: "path"
+        required: true
+        type: "string"
+      responses:
+        202:
+          description: "rotation accepted"
+          x-terraform-resource-poll-enabled: true
+          x-terraform-resource-poll-completed-statuses: "active"
+          x-terraform-resource-poll-pending-statuses: "rotating"
+definitions:
+  Cert:
+    type: "object"
+    properties:
+      id:
+        type: "string"
+        readOnly: true
+      name:
+        type: "string"`
+		a := initAPISpecAnalyser(swaggerContent)
+		Convey("When GetTerraformCompliantResources method is called", func() {
+			r, err := a.GetTerraformCompliantResources()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the custom method should not be exposed as a resource but attached to the resource it belongs to", func() {
+				So(len(r), ShouldEqual, 1)
+				So(r[0].getResourceName(), ShouldEqual, "certs_v1")
+				actions := r[0].getResourceOperations().Actions
+				So(actions, ShouldHaveLength, 1)
+				So(actions[0].Name, ShouldEqual, "rotate")
+				So(actions[0].TriggerAttribute, ShouldEqual, "rotation_trigger")
+				So(actions[0].Operation.responses[http.StatusAccepted].isPollingEnabled, ShouldBeTrue)
+			})
+		})
+	})
 }
 
 func assertPropertyExists(properties specSchemaDefinitionProperties, name string) (bool, int) {
@@ -3372,7 +3632,7 @@ func assertPropertyExists(properties specSchemaDefinitionProperties, name string
 func initAPISpecAnalyser(swaggerContent string) specV2Analyser {
 	file := initAPISpecFile(swaggerContent)
 	defer os.Remove(file.Name())
-	specV2Analyser, err := newSpecAnalyserV2(file.Name())
+	specV2Analyser, err := newSpecAnalyserV2(file.Name(), http.DefaultClient)
 	if err != nil {
 		log.Panic("newSpecAnalyserV2 failed: ", err)
 	}
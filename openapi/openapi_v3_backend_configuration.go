@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/openapiutils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-openapi/spec"
+)
+
+type specV3BackendConfiguration struct {
+	openAPIDocumentURL string
+	spec               *openapi3.Swagger
+}
+
+func newOpenAPIBackendConfigurationV3(spec *openapi3.Swagger, openAPIDocumentURL string) (*specV3BackendConfiguration, error) {
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi version '%s' not supported, specV3BackendConfiguration only supports 3.x", spec.OpenAPI)
+	}
+	if openAPIDocumentURL == "" {
+		return nil, fmt.Errorf("missing mandatory parameter openAPIDocumentURL")
+	}
+	return &specV3BackendConfiguration{openAPIDocumentURL, spec}, nil
+}
+
+func (o specV3BackendConfiguration) getHost() (string, error) {
+	serverURL, err := o.getServerURL()
+	if err != nil {
+		return "", err
+	}
+	if serverURL.Host == "" {
+		log.Printf("[WARN] servers field does not specify a host in the OpenAPI document, falling back to retrieving the host from where the OpenAPI document is served: '%s'", o.openAPIDocumentURL)
+		hostFromURL := openapiutils.GetHostFromURL(o.openAPIDocumentURL)
+		if hostFromURL == "" {
+			return "", fmt.Errorf("could not find valid host from URL provided: '%s'", o.openAPIDocumentURL)
+		}
+		return hostFromURL, nil
+	}
+	return serverURL.Host, nil
+}
+
+func (o specV3BackendConfiguration) getHostByRegion(region string) (string, error) {
+	if region == "" {
+		return "", fmt.Errorf("can't get host by region, missing region value")
+	}
+	isMultiRegion, host, allowedRegions, err := o.isMultiRegion()
+	if err != nil {
+		return "", err
+	}
+	if !isMultiRegion {
+		return "", fmt.Errorf("missing '%s' extension or value provided not matching multiregion host format", extTfProviderMultiRegionFQDN)
+	}
+	if err := o.validateRegion(region, allowedRegions); err != nil {
+		return "", err
+	}
+	return openapiutils.GetMultiRegionHost(host, region)
+}
+
+func (o specV3BackendConfiguration) validateRegion(region string, allowedRegions []string) error {
+	for _, r := range allowedRegions {
+		if r == region {
+			return nil
+		}
+	}
+	return fmt.Errorf("region %s not matching allowed ones %+v", region, allowedRegions)
+}
+
+func (o specV3BackendConfiguration) getDefaultRegion(regions []string) (string, error) {
+	if regions == nil || len(regions) == 0 {
+		return "", fmt.Errorf("empty regions provided")
+	}
+	return regions[0], nil
+}
+
+// isMultiRegion relies on the same root level extensions as the OpenAPI v2 backend configuration, namely
+// 'x-terraform-provider-multiregion-fqdn' and 'x-terraform-provider-regions'
+func (o specV3BackendConfiguration) isMultiRegion() (bool, string, []string, error) {
+	extensions := convertV3Extensions(o.spec.ExtensionProps)
+	host, exists := extensions.GetString(extTfProviderMultiRegionFQDN)
+	if !exists {
+		return false, "", nil, nil
+	}
+	if isMultiRegion, _ := openapiutils.IsMultiRegionHost(host); !isMultiRegion {
+		return false, "", nil, fmt.Errorf("'%s' extension value provided not matching multiregion host format", extTfProviderMultiRegionFQDN)
+	}
+	regions, err := o.getProviderRegions(extensions)
+	if err != nil {
+		return false, "", nil, err
+	}
+	return true, host, regions, nil
+}
+
+func (o specV3BackendConfiguration) getProviderRegions(extensions spec.Extensions) ([]string, error) {
+	regionsExtensionValue, regionsExtensionExists := extensions.GetString(extTfProviderRegions)
+	if !regionsExtensionExists {
+		return nil, fmt.Errorf("mandatory multiregion '%s' extension missing", extTfProviderRegions)
+	}
+	if regionsExtensionValue == "" {
+		return nil, fmt.Errorf("mandatory multiregion '%s' extension empty value provided", extTfProviderRegions)
+	}
+	return strings.Split(strings.Replace(regionsExtensionValue, " ", "", -1), ","), nil
+}
+
+func (o specV3BackendConfiguration) getBasePath() string {
+	serverURL, err := o.getServerURL()
+	if err != nil {
+		log.Printf("[WARN] could not read the base path from the servers field: %s", err)
+		return ""
+	}
+	return serverURL.Path
+}
+
+// getHTTPScheme returns the scheme of the server URL. If the server URL is relative (e,g: /api/v1) the scheme used will
+// be the one from the URL where the OpenAPI document is served
+func (o specV3BackendConfiguration) getHTTPScheme() (string, error) {
+	serverURL, err := o.getServerURL()
+	if err != nil {
+		return "", err
+	}
+	scheme := serverURL.Scheme
+	if scheme == "" {
+		if documentURL, err := url.Parse(o.openAPIDocumentURL); err == nil {
+			scheme = documentURL.Scheme
+		}
+	}
+	if scheme == "" {
+		return "", errors.New("no schemes specified - must use http or https")
+	}
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("specified scheme %s is not supported - must use http or https", scheme)
+	}
+	return scheme, nil
+}
+
+// getServerURL returns the URL of the server that will be used to communicate with the API. If multiple servers are
+// defined the first one exposing https is preferred, otherwise the first server will be used. Server variables used in
+// the host or path are kept as host variable placeholders (e,g: ${environment}) so they can be resolved at runtime with
+// the values provided by the user. If no servers are defined, as per the OpenAPI specification, the default server is
+// considered to be '/'
+func (o specV3BackendConfiguration) getServerURL() (*url.URL, error) {
+	server := selectV3Server(o.spec.Servers)
+	if server == nil {
+		return &url.URL{Path: "/"}, nil
+	}
+	return parseV3ServerURL(server)
+}
+
+// getHostVariables returns the variables of the server used to communicate with the API. The enum values of the server
+// variable are considered the allowed values and the default value is used when the user does not provide a value
+func (o specV3BackendConfiguration) getHostVariables() (specHostVariables, error) {
+	hostVariables := specHostVariables{}
+	server := selectV3Server(o.spec.Servers)
+	if server == nil {
+		return hostVariables, nil
+	}
+	for variableName, variable := range server.Variables {
+		if variable == nil || variable.Default == nil {
+			return nil, fmt.Errorf("server variable '%s' is missing the default value", variableName)
+		}
+		var allowedValues []string
+		for _, enumValue := range variable.Enum {
+			allowedValues = append(allowedValues, fmt.Sprintf("%v", enumValue))
+		}
+		hostVariables = append(hostVariables, specHostVariable{
+			name:          variableName,
+			defaultValue:  fmt.Sprintf("%v", variable.Default),
+			allowedValues: allowedValues,
+		})
+	}
+	sort.Slice(hostVariables, func(i, j int) bool { return hostVariables[i].name < hostVariables[j].name })
+	return hostVariables, nil
+}
+
+// selectV3Server returns the first server exposing https, otherwise the first server. Nil is returned if no servers are
+// provided
+func selectV3Server(servers openapi3.Servers) *openapi3.Server {
+	var selectedServer *openapi3.Server
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		if strings.HasPrefix(server.URL, "https://") {
+			return server
+		}
+		if selectedServer == nil {
+			selectedServer = server
+		}
+	}
+	return selectedServer
+}
+
+// parseV3ServerURL parses the server URL keeping the server variables found in the host and path as host variable
+// placeholders (e,g: https://{environment}.server.com is parsed into a URL with host ${environment}.server.com). Server
+// variables are not supported in the scheme
+func parseV3ServerURL(server *openapi3.Server) (*url.URL, error) {
+	serverURL := server.URL
+	tokens := map[string]string{}
+	for variableName, variable := range server.Variables {
+		if variable == nil || variable.Default == nil {
+			return nil, fmt.Errorf("server variable '%s' is missing the default value", variableName)
+		}
+		// placeholders like ${environment} are not valid URL characters, hence these get temporarily replaced with a
+		// token that can be safely parsed
+		token := fmt.Sprintf("serverurlvariable%dend", len(tokens))
+		tokens[token] = buildHostVariablePlaceholder(variableName)
+		serverURL = strings.Replace(serverURL, fmt.Sprintf("{%s}", variableName), token, -1)
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server url '%s': %s", server.URL, err)
+	}
+	for token, placeholder := range tokens {
+		if strings.Contains(u.Scheme, token) {
+			return nil, fmt.Errorf("failed to parse server url '%s': server variables are not supported in the scheme", server.URL)
+		}
+		u.Host = strings.Replace(u.Host, token, placeholder, -1)
+		u.Path = strings.Replace(u.Path, token, placeholder, -1)
+	}
+	return u, nil
+}
@@ -0,0 +1,240 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewOpenAPIBackendConfigurationV3(t *testing.T) {
+	Convey("Given a spec of version 3.0.1 and an openAPIDocumentURL", t, func() {
+		spec := &openapi3.Swagger{OpenAPI: "3.0.1"}
+		Convey("When newOpenAPIBackendConfigurationV3 method is called", func() {
+			specV3BackendConfiguration, err := newOpenAPIBackendConfigurationV3(spec, "https://www.some-backend.com/openapi.yaml")
+			Convey("Then the error returned should be nil and the backend configuration should comply with the interface", func() {
+				So(err, ShouldBeNil)
+				var _ SpecBackendConfiguration = specV3BackendConfiguration
+			})
+		})
+	})
+	Convey("Given a spec of version 2.0", t, func() {
+		spec := &openapi3.Swagger{OpenAPI: "2.0"}
+		Convey("When newOpenAPIBackendConfigurationV3 method is called", func() {
+			_, err := newOpenAPIBackendConfigurationV3(spec, "https://www.some-backend.com/openapi.yaml")
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "openapi version '2.0' not supported, specV3BackendConfiguration only supports 3.x")
+			})
+		})
+	})
+	Convey("Given a spec of version 3.0.1 and an empty openAPIDocumentURL", t, func() {
+		spec := &openapi3.Swagger{OpenAPI: "3.0.1"}
+		Convey("When newOpenAPIBackendConfigurationV3 method is called", func() {
+			_, err := newOpenAPIBackendConfigurationV3(spec, "")
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "missing mandatory parameter openAPIDocumentURL")
+			})
+		})
+	})
+}
+
+func TestSpecV3BackendConfigurationGetHost(t *testing.T) {
+	Convey("Given a specV3BackendConfiguration with a server url", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "https://www.some-backend.com/openapi.yaml",
+			spec:               &openapi3.Swagger{Servers: openapi3.Servers{{URL: "https://api.server.com/v1"}}},
+		}
+		Convey("When getHost method is called", func() {
+			host, err := specV3BackendConfiguration.getHost()
+			Convey("Then the host returned should be the server host", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "api.server.com")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a relative server url", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "https://www.some-backend.com/openapi.yaml",
+			spec:               &openapi3.Swagger{Servers: openapi3.Servers{{URL: "/v1"}}},
+		}
+		Convey("When getHost method is called", func() {
+			host, err := specV3BackendConfiguration.getHost()
+			Convey("Then the host returned should be the one where the OpenAPI document is served from", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "www.some-backend.com")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a server url containing variables", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "https://www.some-backend.com/openapi.yaml",
+			spec: &openapi3.Swagger{Servers: openapi3.Servers{{
+				URL:       "https://{environment}.server.com/{basePath}",
+				Variables: map[string]*openapi3.ServerVariable{"environment": {Default: "api"}, "basePath": {Default: "v2"}},
+			}}},
+		}
+		Convey("When getHost and getBasePath methods are called", func() {
+			host, err := specV3BackendConfiguration.getHost()
+			basePath := specV3BackendConfiguration.getBasePath()
+			Convey("Then the values returned should contain the host variable placeholders", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "${environment}.server.com")
+				So(basePath, ShouldEqual, "/${basePath}")
+			})
+		})
+		Convey("When getHostVariables method is called", func() {
+			hostVariables, err := specV3BackendConfiguration.getHostVariables()
+			Convey("Then the host variables returned should match the server variables", func() {
+				So(err, ShouldBeNil)
+				So(hostVariables, ShouldResemble, specHostVariables{
+					{name: "basePath", defaultValue: "v2"},
+					{name: "environment", defaultValue: "api"},
+				})
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a server url containing a variable with enum values", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "https://www.some-backend.com/openapi.yaml",
+			spec: &openapi3.Swagger{Servers: openapi3.Servers{{
+				URL:       "https://api.{environment}.server.com",
+				Variables: map[string]*openapi3.ServerVariable{"environment": {Default: "prod", Enum: []interface{}{"prod", "staging", "dev"}}},
+			}}},
+		}
+		Convey("When getHostVariables method is called", func() {
+			hostVariables, err := specV3BackendConfiguration.getHostVariables()
+			Convey("Then the allowed values should be the enum values", func() {
+				So(err, ShouldBeNil)
+				So(hostVariables, ShouldResemble, specHostVariables{{name: "environment", defaultValue: "prod", allowedValues: []string{"prod", "staging", "dev"}}})
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a server url containing a variable in the scheme", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "https://www.some-backend.com/openapi.yaml",
+			spec: &openapi3.Swagger{Servers: openapi3.Servers{{
+				URL:       "{scheme}://api.server.com",
+				Variables: map[string]*openapi3.ServerVariable{"scheme": {Default: "https"}},
+			}}},
+		}
+		Convey("When getHost method is called", func() {
+			_, err := specV3BackendConfiguration.getHost()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "failed to parse server url '{scheme}://api.server.com': server variables are not supported in the scheme")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a server url containing a variable without default", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "https://www.some-backend.com/openapi.yaml",
+			spec: &openapi3.Swagger{Servers: openapi3.Servers{{
+				URL:       "https://{environment}.server.com",
+				Variables: map[string]*openapi3.ServerVariable{"environment": {}},
+			}}},
+		}
+		Convey("When getHost method is called", func() {
+			_, err := specV3BackendConfiguration.getHost()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "server variable 'environment' is missing the default value")
+			})
+		})
+	})
+}
+
+func TestSpecV3BackendConfigurationGetHTTPScheme(t *testing.T) {
+	Convey("Given a specV3BackendConfiguration with http and https servers", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "http://www.some-backend.com/openapi.yaml",
+			spec:               &openapi3.Swagger{Servers: openapi3.Servers{{URL: "http://api.server.com"}, {URL: "https://api.server.com"}}},
+		}
+		Convey("When getHTTPScheme method is called", func() {
+			scheme, err := specV3BackendConfiguration.getHTTPScheme()
+			Convey("Then the scheme returned should be https", func() {
+				So(err, ShouldBeNil)
+				So(scheme, ShouldEqual, "https")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with no servers", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "http://www.some-backend.com/openapi.yaml",
+			spec:               &openapi3.Swagger{},
+		}
+		Convey("When getHTTPScheme and getBasePath methods are called", func() {
+			scheme, err := specV3BackendConfiguration.getHTTPScheme()
+			basePath := specV3BackendConfiguration.getBasePath()
+			Convey("Then the scheme returned should be the one used to serve the OpenAPI document and the base path should be /", func() {
+				So(err, ShouldBeNil)
+				So(scheme, ShouldEqual, "http")
+				So(basePath, ShouldEqual, "/")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a relative server and an OpenAPI document served from a file", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "/tmp/openapi.yaml",
+			spec:               &openapi3.Swagger{Servers: openapi3.Servers{{URL: "/v1"}}},
+		}
+		Convey("When getHTTPScheme method is called", func() {
+			_, err := specV3BackendConfiguration.getHTTPScheme()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "no schemes specified - must use http or https")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration with a non supported scheme", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{
+			openAPIDocumentURL: "http://www.some-backend.com/openapi.yaml",
+			spec:               &openapi3.Swagger{Servers: openapi3.Servers{{URL: "ws://api.server.com"}}},
+		}
+		Convey("When getHTTPScheme method is called", func() {
+			_, err := specV3BackendConfiguration.getHTTPScheme()
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "specified scheme ws is not supported - must use http or https")
+			})
+		})
+	})
+}
+
+func TestSpecV3BackendConfigurationIsMultiRegion(t *testing.T) {
+	Convey("Given a specV3BackendConfiguration with the multi region extensions", t, func() {
+		spec := &openapi3.Swagger{}
+		spec.Extensions = map[string]interface{}{
+			extTfProviderMultiRegionFQDN: "service.api.${region}.hostname.com",
+			extTfProviderRegions:         "rst1, dub1",
+		}
+		specV3BackendConfiguration := specV3BackendConfiguration{openAPIDocumentURL: "http://www.some-backend.com/openapi.yaml", spec: spec}
+		Convey("When isMultiRegion method is called", func() {
+			isMultiRegion, host, regions, err := specV3BackendConfiguration.isMultiRegion()
+			Convey("Then the values returned should match the extension values", func() {
+				So(err, ShouldBeNil)
+				So(isMultiRegion, ShouldBeTrue)
+				So(host, ShouldEqual, "service.api.${region}.hostname.com")
+				So(regions, ShouldResemble, []string{"rst1", "dub1"})
+			})
+		})
+		Convey("When getHostByRegion method is called with an allowed region", func() {
+			host, err := specV3BackendConfiguration.getHostByRegion("dub1")
+			Convey("Then the host returned should contain the region", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "service.api.dub1.hostname.com")
+			})
+		})
+		Convey("When getHostByRegion method is called with a non allowed region", func() {
+			_, err := specV3BackendConfiguration.getHostByRegion("nonExisting")
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "region nonExisting not matching allowed ones [rst1 dub1]")
+			})
+		})
+	})
+	Convey("Given a specV3BackendConfiguration without the multi region extensions", t, func() {
+		specV3BackendConfiguration := specV3BackendConfiguration{openAPIDocumentURL: "http://www.some-backend.com/openapi.yaml", spec: &openapi3.Swagger{}}
+		Convey("When isMultiRegion method is called", func() {
+			isMultiRegion, _, _, err := specV3BackendConfiguration.isMultiRegion()
+			Convey("Then the backend configuration should not be multi region", func() {
+				So(err, ShouldBeNil)
+				So(isMultiRegion, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// getV3HeaderConfigurations gets all the header configurations for the given OpenAPI v3 operation parameters. The
+// header name used in the provider configuration will either be the value specified in the extTfHeader or if not present
+// the name of the header
+func getV3HeaderConfigurations(parameters openapi3.Parameters) SpecHeaderParameters {
+	headerParameters := SpecHeaderParameters{}
+	for _, parameterRef := range parameters {
+		if parameterRef == nil || parameterRef.Value == nil || parameterRef.Value.In != openapi3.ParameterInHeader {
+			continue
+		}
+		parameter := parameterRef.Value
+		// the If-Match header value is the resource ETag, hence it is not configured by the user
+		if isIfMatchHeader(parameter.Name) {
+			continue
+		}
+		headerParam := SpecHeaderParam{Name: parameter.Name}
+		if preferredName, exists := convertV3Extensions(parameter.ExtensionProps).GetString(extTfHeader); exists {
+			headerParam.TerraformName = preferredName
+		}
+		// The below statement avoids dup headers in the list. Note subsequent encounters with a header type that has
+		// already been registered will be ignored
+		if !headerParameters.specHeaderExists(headerParam) {
+			headerParameters = append(headerParameters, headerParam)
+		}
+	}
+	return headerParameters
+}
+
+// isV3IfMatchHeaderDeclared returns true if the given OpenAPI v3 operation parameters contain the If-Match header
+func isV3IfMatchHeaderDeclared(parameters openapi3.Parameters) bool {
+	for _, parameterRef := range parameters {
+		if parameterRef != nil && parameterRef.Value != nil && parameterRef.Value.In == openapi3.ParameterInHeader && isIfMatchHeader(parameterRef.Value.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getV3AllHeaderParameters aggregates all header type parameters found in the operations of the given paths
+func getV3AllHeaderParameters(paths openapi3.Paths) SpecHeaderParameters {
+	specHeaderParameters := SpecHeaderParameters{}
+	for _, pathItem := range paths {
+		if pathItem == nil {
+			continue
+		}
+		for _, operation := range []*openapi3.Operation{pathItem.Post, pathItem.Get, pathItem.Put, pathItem.Patch, pathItem.Delete} {
+			if operation == nil {
+				continue
+			}
+			for _, headerParam := range getV3HeaderConfigurations(operation.Parameters) {
+				if !specHeaderParameters.specHeaderExists(headerParam) {
+					specHeaderParameters = append(specHeaderParameters, headerParam)
+				}
+			}
+		}
+	}
+	return specHeaderParameters
+}
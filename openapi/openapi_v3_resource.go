@@ -0,0 +1,600 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/openapiutils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-openapi/spec"
+)
+
+const jsonMediaType = "application/json"
+
+// SpecV3Resource defines a struct that implements the SpecResource interface and it's based on OpenAPI v3 specification
+type SpecV3Resource struct {
+	Name   string
+	Region string
+	// Path contains the full relative path to the resource e,g: /v1/resource
+	Path string
+	// SchemaDefinition represents the representational state (aka model) of the resource. The schema is expected to
+	// have all the refs already resolved
+	SchemaDefinition *openapi3.Schema
+	// RootPathItem contains info about the resource root path e,g: /resource, including the POST operation used to create instances of this resource
+	RootPathItem openapi3.PathItem
+	// InstancePathItem contains info about the resource's instance /resource/{id}, including GET, PUT and REMOVE operations if applicable
+	InstancePathItem openapi3.PathItem
+
+	Paths openapi3.Paths
+
+	// actions contains the resource custom methods; these are computed once when the resource is created (see getResourceActions)
+	actions []*specResourceAction
+}
+
+// newSpecV3Resource creates a SpecV3Resource with no region and default host
+func newSpecV3Resource(path string, schemaDefinition *openapi3.Schema, rootPathItem, instancePathItem openapi3.PathItem, paths openapi3.Paths) (*SpecV3Resource, error) {
+	return newSpecV3ResourceWithConfig("", path, schemaDefinition, rootPathItem, instancePathItem, paths)
+}
+
+func newSpecV3DataSource(path string, schemaDefinition *openapi3.Schema, rootPathItem openapi3.PathItem, paths openapi3.Paths) (*SpecV3Resource, error) {
+	return newSpecV3ResourceWithConfig("", path, schemaDefinition, rootPathItem, openapi3.PathItem{}, paths)
+}
+
+// newSpecV3ResourceWithRegion creates a SpecV3Resource with the region configured making the returned SpecV3Resource region based.
+func newSpecV3ResourceWithRegion(region, path string, schemaDefinition *openapi3.Schema, rootPathItem, instancePathItem openapi3.PathItem, paths openapi3.Paths) (*SpecV3Resource, error) {
+	if region == "" {
+		return nil, fmt.Errorf("region must not be empty")
+	}
+	return newSpecV3ResourceWithConfig(region, path, schemaDefinition, rootPathItem, instancePathItem, paths)
+}
+
+func newSpecV3ResourceWithConfig(region, path string, schemaDefinition *openapi3.Schema, rootPathItem, instancePathItem openapi3.PathItem, paths openapi3.Paths) (*SpecV3Resource, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path must not be empty")
+	}
+	if paths == nil {
+		return nil, fmt.Errorf("paths must not be nil")
+	}
+	if schemaDefinition == nil {
+		return nil, fmt.Errorf("schema definition must not be nil")
+	}
+	resource := &SpecV3Resource{
+		Path:             path,
+		Region:           region,
+		SchemaDefinition: schemaDefinition,
+		RootPathItem:     rootPathItem,
+		InstancePathItem: instancePathItem,
+		Paths:            paths,
+	}
+	name, err := resource.buildResourceName()
+	if err != nil {
+		return nil, fmt.Errorf("could not build resource name for '%s': %s", path, err)
+	}
+	resource.Name = name
+	resource.actions = resource.getResourceActions()
+	return resource, nil
+}
+
+func (o *SpecV3Resource) getResourceName() string {
+	if o.Region != "" {
+		return fmt.Sprintf("%s_%s", o.Name, o.Region)
+	}
+	return o.Name
+}
+
+// buildResourceName returns the name of the resource (including the version if applicable). The name is build from the resource
+// root path /resource/{id} or if specified the value set in the x-terraform-resource-name extension is used instead along
+// with the version (if applicable)
+func (o *SpecV3Resource) buildResourceName() (string, error) {
+	fullResourceName, err := buildResourceNameFromPath(o.Path, o.getResourceTerraformName())
+	if err != nil {
+		return "", err
+	}
+	parentResourceInfo := o.getParentResourceInfo()
+	if parentResourceInfo != nil {
+		fullResourceName = parentResourceInfo.fullParentResourceName + "_" + fullResourceName
+	}
+	return fullResourceName, nil
+}
+
+// getResourcePath returns the root path of the resource with the path parameters (if any) resolved based on the ids provided
+func (o *SpecV3Resource) getResourcePath(parentIDs []string) (string, error) {
+	return resolveResourcePath(o.Path, parentIDs)
+}
+
+// getHost can return an empty host in which case the expectation is that the host used will be the one specified in the
+// servers section of the OpenAPI document or if not present the host used will be the host where the document was served
+func (o *SpecV3Resource) getHost() (string, error) {
+	overrideHost := getV3ResourceOverrideHost(o.getResourceRootOperation())
+	if overrideHost == "" {
+		return "", nil
+	}
+	multiRegionHost, err := openapiutils.GetMultiRegionHost(overrideHost, o.Region)
+	if err != nil {
+		return "", err
+	}
+	if multiRegionHost != "" {
+		return multiRegionHost, nil
+	}
+	return overrideHost, nil
+}
+
+// isSingleton returns true if the resource instance path PUT operation has the 'x-terraform-singleton' extension set to
+// true (see SpecV2Resource.isSingleton for more details)
+func (o *SpecV3Resource) isSingleton() bool {
+	if o.InstancePathItem.Put == nil {
+		return false
+	}
+	return o.isBoolExtensionEnabled(convertV3Extensions(o.InstancePathItem.Put.ExtensionProps), extTfSingleton)
+}
+
+// getResourceRootOperation returns the operation that holds the resource level extensions (e,g: x-terraform-resource-name),
+// that is the root path POST operation or, in the case of resources created via PUT (singletons and resources with client
+// generated IDs), the instance path PUT operation
+func (o *SpecV3Resource) getResourceRootOperation() *openapi3.Operation {
+	if o.RootPathItem.Post == nil {
+		return o.InstancePathItem.Put
+	}
+	return o.RootPathItem.Post
+}
+
+func (o *SpecV3Resource) getResourceOperations() specResourceOperations {
+	return specResourceOperations{
+		List:    o.createResourceOperation(o.RootPathItem.Get, o.RootPathItem),
+		Post:    o.createResourceOperation(o.RootPathItem.Post, o.RootPathItem),
+		Get:     o.createResourceOperation(o.InstancePathItem.Get, o.InstancePathItem),
+		Put:     o.createResourceOperation(o.InstancePathItem.Put, o.InstancePathItem),
+		Patch:   o.createResourceOperation(o.InstancePathItem.Patch, o.InstancePathItem),
+		Delete:  o.createResourceOperation(o.InstancePathItem.Delete, o.InstancePathItem),
+		Actions: o.actions,
+	}
+}
+
+// getResourceActions follows the same rules as the OpenAPI v2 implementation (see SpecV2Resource.getResourceActions)
+func (o *SpecV3Resource) getResourceActions() []*specResourceAction {
+	var paths []string
+	for path := range o.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var actions []*specResourceAction
+	for _, path := range paths {
+		name, isCustomMethod := getResourceCustomMethodName(o.Path, o.isSingleton(), path)
+		if !isCustomMethod {
+			continue
+		}
+		pathItem := o.Paths[path]
+		if pathItem == nil || pathItem.Post == nil {
+			log.Printf("[DEBUG] ignoring resource '%s' custom method '%s' as it does not have a POST operation", o.Path, path)
+			continue
+		}
+		triggerAttribute := o.getExtensionStringValue(convertV3Extensions(pathItem.Post.ExtensionProps), extTfResourceActionTrigger)
+		if triggerAttribute == "" {
+			log.Printf("[DEBUG] ignoring resource '%s' custom method '%s' as it does not have the '%s' extension", o.Path, path, extTfResourceActionTrigger)
+			continue
+		}
+		actions = append(actions, &specResourceAction{
+			Name:             name,
+			TriggerAttribute: triggerAttribute,
+			Operation:        o.createResourceOperation(pathItem.Post, *pathItem),
+		})
+	}
+	return actions
+}
+
+// shouldIgnoreResource checks whether the POST operation for a given resource as the 'x-terraform-exclude-resource' extension
+// defined with true value.
+func (o *SpecV3Resource) shouldIgnoreResource() bool {
+	postOperation := o.getResourceRootOperation()
+	if postOperation != nil {
+		return o.isBoolExtensionEnabled(convertV3Extensions(postOperation.ExtensionProps), extTfExcludeResource)
+	}
+	return false
+}
+
+func (o *SpecV3Resource) getParentResourceInfo() *parentResourceInfo {
+	return buildParentResourceInfo(o.Path, o.getPreferredParentName)
+}
+
+// getPreferredParentName uses `o.Paths` to read the preferred name over the parent resource if `x-terraform-resource-name` is set
+func (o *SpecV3Resource) getPreferredParentName(parentURI string) string {
+	parent := o.Paths[parentURI]
+	if parent == nil {
+		// Falling back to checking path with trailing slash
+		parent = o.Paths[parentURI+"/"]
+	}
+	if parent == nil || parent.Post == nil {
+		return ""
+	}
+	return o.getExtensionStringValue(convertV3Extensions(parent.Post.ExtensionProps), extTfResourceName)
+}
+
+func (o *SpecV3Resource) getResourceSchema() (*specSchemaDefinition, error) {
+	return o.getSchemaDefinition(o.SchemaDefinition)
+}
+
+func (o *SpecV3Resource) getSchemaDefinition(schema *openapi3.Schema) (*specSchemaDefinition, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema argument must not be nil")
+	}
+	schemaDefinition := &specSchemaDefinition{}
+	schemaDefinition.Properties = specSchemaDefinitionProperties{}
+	for propertyName, property := range schema.Properties {
+		if property == nil || property.Value == nil {
+			return nil, fmt.Errorf("failed to process property '%s': missing schema, check that the ref is valid", propertyName)
+		}
+		schemaDefinitionProperty, err := o.createSchemaDefinitionProperty(propertyName, property.Value, schema.Required)
+		if err != nil {
+			return nil, err
+		}
+		schemaDefinition.Properties = append(schemaDefinition.Properties, schemaDefinitionProperty)
+	}
+
+	parentResourceInfo := o.getParentResourceInfo()
+	if parentResourceInfo != nil {
+		parentPropertyNames := parentResourceInfo.getParentPropertiesNames()
+		for _, parentPropertyName := range parentPropertyNames {
+			pr, _ := o.createSchemaDefinitionProperty(parentPropertyName, openapi3.NewStringSchema(), []string{parentPropertyName})
+			pr.IsParentProperty = true
+			schemaDefinition.Properties = append(schemaDefinition.Properties, pr)
+		}
+	}
+	return schemaDefinition, nil
+}
+
+func (o *SpecV3Resource) createSchemaDefinitionProperty(propertyName string, property *openapi3.Schema, requiredProperties []string) (*specSchemaDefinitionProperty, error) {
+	schemaDefinitionProperty := &specSchemaDefinitionProperty{}
+
+	if o.isObjectProperty(property) {
+		objectSchemaDefinition, err := o.getSchemaDefinition(property)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process object type property '%s': %s", propertyName, err)
+		}
+		schemaDefinitionProperty.SpecSchemaDefinition = objectSchemaDefinition
+		log.Printf("[DEBUG] found object type property '%s'", propertyName)
+	} else if isArray, itemsType, itemsSchema, err := o.isArrayProperty(property); isArray || err != nil {
+		if err != nil {
+			return nil, fmt.Errorf("failed to process array type property '%s': %s", propertyName, err)
+		}
+		schemaDefinitionProperty.ArrayItemsType = itemsType
+		schemaDefinitionProperty.SpecSchemaDefinition = itemsSchema // only diff than nil if type is object
+		log.Printf("[DEBUG] found array type property '%s' with items of type '%s'", propertyName, itemsType)
+	}
+
+	propertyType, err := o.getPropertyType(property)
+	if err != nil {
+		return nil, err
+	}
+	schemaDefinitionProperty.Type = propertyType
+
+	schemaDefinitionProperty.Name = propertyName
+
+	extensions := convertV3Extensions(property.ExtensionProps)
+
+	if preferredPropertyName, exists := extensions.GetString(extTfFieldName); exists {
+		schemaDefinitionProperty.PreferredName = preferredPropertyName
+	}
+
+	// Set the property as required (if not required the property will be considered optional)
+	required := o.isRequired(propertyName, requiredProperties)
+	if required {
+		schemaDefinitionProperty.Required = true
+		if property.ReadOnly {
+			return nil, fmt.Errorf("failed to process property '%s': a required property cannot be readOnly too", propertyName)
+		}
+		schemaDefinitionProperty.Computed = false
+	} else {
+		schemaDefinitionProperty.Required = false
+
+		optionalComputed, err := o.isOptionalComputedProperty(propertyName, property, extensions)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only set to true if property is computed OR optional-computed, purely optional properties are not computed since
+		// API is not expected to auto-generate any value by default if value is not provided
+		schemaDefinitionProperty.Computed = property.ReadOnly || optionalComputed
+	}
+
+	schemaDefinitionProperty.ReadOnly = property.ReadOnly
+	schemaDefinitionProperty.ForceNew = o.isBoolExtensionEnabled(extensions, extTfForceNew)
+	// writeOnly properties are usually secrets (e,g: passwords) that are never returned by the API
+	schemaDefinitionProperty.Sensitive = o.isBoolExtensionEnabled(extensions, extTfSensitive) || property.WriteOnly
+	schemaDefinitionProperty.IsIdentifier = o.isBoolExtensionEnabled(extensions, extTfID)
+	schemaDefinitionProperty.Immutable = o.isBoolExtensionEnabled(extensions, extTfImmutable)
+	schemaDefinitionProperty.IsStatusIdentifier = o.isBoolExtensionEnabled(extensions, extTfFieldStatus)
+	schemaDefinitionProperty.EnableLegacyComplexObjectBlockConfiguration = o.isBoolExtensionEnabled(extensions, extTfComplexObjectType)
+	schemaDefinitionProperty.Default = property.Default
+
+	return schemaDefinitionProperty, nil
+}
+
+func (o *SpecV3Resource) isBoolExtensionEnabled(extensions spec.Extensions, extension string) bool {
+	if enabled, ok := extensions.GetBool(extension); ok && enabled {
+		return true
+	}
+	return false
+}
+
+// isOptionalComputedProperty returns true if the property is either optional with a default value or explicitly marked
+// with the 'x-terraform-computed' extension (see SpecV2Resource.isOptionalComputedProperty for more details)
+func (o *SpecV3Resource) isOptionalComputedProperty(propertyName string, property *openapi3.Schema, extensions spec.Extensions) (bool, error) {
+	isComputed := o.isBoolExtensionEnabled(extensions, extTfComputed)
+	if !property.ReadOnly && property.Default != nil {
+		if isComputed {
+			return false, fmt.Errorf("optional computed property validation failed for property '%s': optional computed properties with default attributes should not have '%s' extension too", propertyName, extTfComputed)
+		}
+		return true, nil
+	}
+	if isComputed {
+		if property.ReadOnly {
+			return false, fmt.Errorf("optional computed property validation failed for property '%s': optional computed properties marked with '%s' can not be readOnly", propertyName, extTfComputed)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (o *SpecV3Resource) isArrayItemPrimitiveType(propertyType schemaDefinitionPropertyType) bool {
+	return propertyType == typeString || propertyType == typeInt || propertyType == typeFloat || propertyType == typeBool
+}
+
+func (o *SpecV3Resource) isArrayProperty(property *openapi3.Schema) (bool, schemaDefinitionPropertyType, *specSchemaDefinition, error) {
+	if property.Type != "array" {
+		return false, "", nil, nil
+	}
+	if property.Items == nil || property.Items.Value == nil {
+		return false, "", nil, fmt.Errorf("array property is missing items schema definition")
+	}
+	items := property.Items.Value
+	if items.Type == "array" {
+		return false, "", nil, fmt.Errorf("array property can not have items of type 'array'")
+	}
+	itemsType, err := o.getPropertyType(items)
+	if err != nil {
+		return false, "", nil, err
+	}
+	if o.isArrayItemPrimitiveType(itemsType) {
+		return true, itemsType, nil, nil
+	}
+	if itemsType != typeObject {
+		return false, "", nil, fmt.Errorf("array item type '%s' not supported", itemsType)
+	}
+	objectSchemaDefinition, err := o.getSchemaDefinition(items)
+	if err != nil {
+		return true, itemsType, nil, err
+	}
+	return true, itemsType, objectSchemaDefinition, nil
+}
+
+func (o *SpecV3Resource) getPropertyType(property *openapi3.Schema) (schemaDefinitionPropertyType, error) {
+	switch {
+	case property.Type == "array":
+		return typeList, nil
+	case o.isObjectProperty(property):
+		return typeObject, nil
+	case property.Type == "string":
+		return typeString, nil
+	case property.Type == "integer":
+		return typeInt, nil
+	case property.Type == "number":
+		return typeFloat, nil
+	case property.Type == "boolean":
+		return typeBool, nil
+	}
+	return "", fmt.Errorf("non supported '%+v' type", property.Type)
+}
+
+// isObjectProperty returns true if the property is of type object or it does not specify the type but has properties
+// (which is the case of schemas that only reference other schema definitions)
+func (o *SpecV3Resource) isObjectProperty(property *openapi3.Schema) bool {
+	return property.Type == "object" || (property.Type == "" && len(property.Properties) > 0)
+}
+
+func (o *SpecV3Resource) isRequired(propertyName string, requiredProps []string) bool {
+	for _, f := range requiredProps {
+		if f == propertyName {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *SpecV3Resource) getResourceTerraformName() string {
+	rootOperation := o.getResourceRootOperation()
+	if rootOperation == nil {
+		return ""
+	}
+	return o.getExtensionStringValue(convertV3Extensions(rootOperation.ExtensionProps), extTfResourceName)
+}
+
+func (o *SpecV3Resource) getExtensionStringValue(extensions spec.Extensions, key string) string {
+	if value, exists := extensions.GetString(key); exists && value != "" {
+		return value
+	}
+	return ""
+}
+
+func (o *SpecV3Resource) createResourceOperation(operation *openapi3.Operation, pathItem openapi3.PathItem) *specResourceOperation {
+	if operation == nil {
+		return nil
+	}
+	extensions := convertV3Extensions(operation.ExtensionProps)
+	resourceOperation := &specResourceOperation{
+		HeaderParameters:     getV3HeaderConfigurations(operation.Parameters),
+		SecuritySchemes:      createSecuritySchemes(convertV3SecurityRequirements(operation.Security)),
+		PatchContentType:     o.getExtensionStringValue(extensions, extTfResourcePatchContentType),
+		RetryableStatusCodes: getRetryableStatusCodes(extensions),
+		IdempotencyKeyHeader: o.getExtensionStringValue(extensions, extTfResourceIdempotencyKeyHeader),
+		IfMatch:              o.isBoolExtensionEnabled(extensions, extTfETag) || isV3IfMatchHeaderDeclared(operation.Parameters) || isV3IfMatchHeaderDeclared(pathItem.Parameters),
+		responses:            o.createResponses(operation),
+	}
+	if serverURL := o.getOperationServerURL(operation, pathItem); serverURL != nil {
+		if serverURL.Scheme != "" {
+			resourceOperation.Schemes = []string{serverURL.Scheme}
+		}
+		resourceOperation.Host = serverURL.Host
+		resourceOperation.BasePath = serverURL.Path
+		if resourceOperation.BasePath == "" {
+			resourceOperation.BasePath = "/"
+		}
+	}
+	if basePath := o.getResourceOverrideBasePath(operation); basePath != "" {
+		resourceOperation.BasePath = basePath
+	}
+	return resourceOperation
+}
+
+// getOperationServerURL returns the URL of the server defined at the operation level or, if not present, at the path
+// level. These servers override the global ones for the given operation. Nil is returned if neither the operation nor
+// the path define servers or the server URL can not be parsed
+func (o *SpecV3Resource) getOperationServerURL(operation *openapi3.Operation, pathItem openapi3.PathItem) *url.URL {
+	servers := pathItem.Servers
+	if operation.Servers != nil && len(*operation.Servers) > 0 {
+		servers = *operation.Servers
+	}
+	server := selectV3Server(servers)
+	if server == nil {
+		return nil
+	}
+	serverURL, err := parseV3ServerURL(server)
+	if err != nil {
+		log.Printf("[WARN] ignoring server '%s' defined for resource '%s': %s", server.URL, o.Path, err)
+		return nil
+	}
+	return serverURL
+}
+
+// getResourceOverrideBasePath returns the value of the x-terraform-resource-base-path extension defined in the given
+// operation or, if not present, in the root path POST operation. An empty string is returned if the extension is not present
+func (o *SpecV3Resource) getResourceOverrideBasePath(operation *openapi3.Operation) string {
+	for _, op := range []*openapi3.Operation{operation, o.getResourceRootOperation()} {
+		if op == nil {
+			continue
+		}
+		if basePath := o.getExtensionStringValue(convertV3Extensions(op.ExtensionProps), extTfResourceBasePath); basePath != "" {
+			return basePath
+		}
+	}
+	return ""
+}
+
+func (o *SpecV3Resource) createResponses(operation *openapi3.Operation) specResponses {
+	responses := specResponses{}
+	for code, response := range operation.Responses {
+		statusCode, err := strconv.Atoi(code)
+		if err != nil || response == nil || response.Value == nil {
+			// skipping the default response and response ranges (e,g: 2XX) as the polling configuration is expected to
+			// be defined for specific status codes
+			continue
+		}
+		extensions := convertV3Extensions(response.Value.ExtensionProps)
+		responses[statusCode] = &specResponse{
+			isPollingEnabled:       o.isBoolExtensionEnabled(extensions, extTfResourcePollEnabled),
+			pollTargetStatuses:     o.getPollingStatuses(extensions, extTfResourcePollTargetStatuses),
+			pollPendingStatuses:    o.getPollingStatuses(extensions, extTfResourcePollPendingStatuses),
+			pollFailedStatuses:     o.getPollingStatuses(extensions, extTfResourcePollFailedStatuses),
+			pollStatusField:        o.getExtensionStringValue(extensions, extTfResourcePollStatusField),
+			pollErrorField:         o.getExtensionStringValue(extensions, extTfResourcePollErrorField),
+			pollConfiguration:      newSpecPollConfiguration(extensions),
+			pollOperation:          o.getPollOperation(extensions),
+			isLongRunningOperation: o.isBoolExtensionEnabled(extensions, extTfResourceLongRunningOperation),
+		}
+	}
+	return responses
+}
+
+// getPollOperation follows the same rules as the OpenAPI v2 implementation (see SpecV2Resource.getResourcePollOperation)
+func (o *SpecV3Resource) getPollOperation(extensions spec.Extensions) *specPollOperation {
+	if !o.isBoolExtensionEnabled(extensions, extTfResourcePollOperationLocation) {
+		return nil
+	}
+	return newSpecPollOperation(
+		o.getExtensionStringValue(extensions, extTfResourcePollOperationStatusField),
+		o.getExtensionStringValue(extensions, extTfResourcePollOperationResourceIDField),
+		o.getExtensionStringValue(extensions, extTfResourcePollOperationErrorField),
+		o.getPollingStatuses(extensions, extTfResourcePollOperationCompletedStatuses),
+		o.getPollingStatuses(extensions, extTfResourcePollOperationFailedStatuses))
+}
+
+func (o *SpecV3Resource) getPollingStatuses(extensions spec.Extensions, extension string) []string {
+	var statuses []string
+	if resourcePollTargets, exists := extensions.GetString(extension); exists {
+		spaceTrimmedTargets := strings.Replace(resourcePollTargets, " ", "", -1)
+		statuses = strings.Split(spaceTrimmedTargets, ",")
+	}
+	return statuses
+}
+
+func (o *SpecV3Resource) getTimeouts() (*specTimeouts, error) {
+	var postTimeout *time.Duration
+	var getTimeout *time.Duration
+	var putTimeout *time.Duration
+	var deleteTimeout *time.Duration
+	var err error
+	if postTimeout, err = o.getResourceTimeout(o.getResourceRootOperation()); err != nil {
+		return nil, err
+	}
+	if getTimeout, err = o.getResourceTimeout(o.InstancePathItem.Get); err != nil {
+		return nil, err
+	}
+	if putTimeout, err = o.getResourceTimeout(o.InstancePathItem.Put); err != nil {
+		return nil, err
+	}
+	if deleteTimeout, err = o.getResourceTimeout(o.InstancePathItem.Delete); err != nil {
+		return nil, err
+	}
+	return &specTimeouts{
+		Post:   postTimeout,
+		Get:    getTimeout,
+		Put:    putTimeout,
+		Delete: deleteTimeout,
+	}, nil
+}
+
+func (o *SpecV3Resource) getResourceTimeout(operation *openapi3.Operation) (*time.Duration, error) {
+	if operation == nil {
+		return nil, nil
+	}
+	value, exists := convertV3Extensions(operation.ExtensionProps).GetString(extTfResourceTimeout)
+	if !exists {
+		return nil, nil
+	}
+	regex, err := regexp.Compile("^\\d+(\\.\\d+)?[smh]{1}$")
+	if err != nil {
+		return nil, err
+	}
+	if !regex.Match([]byte(value)) {
+		return nil, fmt.Errorf("invalid duration value: '%s'. The value must be a sequence of decimal numbers each with optional fraction and a unit suffix (negative durations are not allowed). The value must be formatted either in seconds (s), minutes (m) or hours (h)", value)
+	}
+	duration, err := time.ParseDuration(value)
+	return &duration, err
+}
+
+// getV3ResourceOverrideHost checks if the x-terraform-resource-host extension is present and if so returns its value. This
+// value will override the global host value, and the API calls for this resource will be made against the value returned
+func getV3ResourceOverrideHost(rootPathItemPost *openapi3.Operation) string {
+	if rootPathItemPost == nil {
+		return ""
+	}
+	if resourceURL, exists := convertV3Extensions(rootPathItemPost.ExtensionProps).GetString(extTfResourceURL); exists && resourceURL != "" {
+		return resourceURL
+	}
+	return ""
+}
+
+// getV3JSONSchema returns the schema of the 'application/json' media type in the given content; nil if the content does
+// not define a JSON schema
+func getV3JSONSchema(content openapi3.Content) *openapi3.Schema {
+	mediaType := content.Get(jsonMediaType)
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+	return mediaType.Schema.Value
+}
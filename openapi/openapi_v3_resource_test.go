@@ -0,0 +1,228 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewSpecV3ResourceWithConfig(t *testing.T) {
+	Convey("Given a versioned path, a schema and paths", t, func() {
+		path := "/v1/cdns"
+		schema := openapi3.NewObjectSchema()
+		Convey("When newSpecV3ResourceWithConfig method is called", func() {
+			r, err := newSpecV3ResourceWithConfig("", path, schema, openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+			Convey("Then the resource returned should have the expected name and comply with the SpecResource interface", func() {
+				So(err, ShouldBeNil)
+				So(r.getResourceName(), ShouldEqual, "cdns_v1")
+				var _ SpecResource = r
+			})
+		})
+		Convey("When newSpecV3ResourceWithRegion method is called", func() {
+			r, err := newSpecV3ResourceWithRegion("rst1", path, schema, openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+			Convey("Then the resource name returned should contain the region", func() {
+				So(err, ShouldBeNil)
+				So(r.getResourceName(), ShouldEqual, "cdns_v1_rst1")
+			})
+		})
+		Convey("When newSpecV3ResourceWithRegion method is called with an empty region", func() {
+			_, err := newSpecV3ResourceWithRegion("", path, schema, openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "region must not be empty")
+			})
+		})
+	})
+	Convey("Given a root path with a preferred resource name", t, func() {
+		post := &openapi3.Operation{}
+		post.Extensions = map[string]interface{}{extTfResourceName: "cdn"}
+		Convey("When newSpecV3ResourceWithConfig method is called", func() {
+			r, err := newSpecV3ResourceWithConfig("", "/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{Post: post}, openapi3.PathItem{}, openapi3.Paths{})
+			Convey("Then the resource name should be the preferred one", func() {
+				So(err, ShouldBeNil)
+				So(r.getResourceName(), ShouldEqual, "cdn_v1")
+			})
+		})
+	})
+	Convey("Given a nil schema", t, func() {
+		Convey("When newSpecV3ResourceWithConfig method is called", func() {
+			_, err := newSpecV3ResourceWithConfig("", "/v1/cdns", nil, openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "schema definition must not be nil")
+			})
+		})
+	})
+	Convey("Given an empty path", t, func() {
+		Convey("When newSpecV3ResourceWithConfig method is called", func() {
+			_, err := newSpecV3ResourceWithConfig("", "", openapi3.NewObjectSchema(), openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "path must not be empty")
+			})
+		})
+	})
+}
+
+func TestSpecV3ResourceGetHost(t *testing.T) {
+	Convey("Given a resource with the host override extension", t, func() {
+		post := &openapi3.Operation{}
+		post.Extensions = map[string]interface{}{extTfResourceURL: "some.api.domain.com"}
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{Post: post}, openapi3.PathItem{}, openapi3.Paths{})
+		Convey("When getHost method is called", func() {
+			host, err := r.getHost()
+			Convey("Then the host returned should be the override one", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "some.api.domain.com")
+			})
+		})
+	})
+	Convey("Given a multi region resource with the host override extension", t, func() {
+		post := &openapi3.Operation{}
+		post.Extensions = map[string]interface{}{extTfResourceURL: "some.api.${region}.domain.com"}
+		r, _ := newSpecV3ResourceWithRegion("rst1", "/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{Post: post}, openapi3.PathItem{}, openapi3.Paths{})
+		Convey("When getHost method is called", func() {
+			host, err := r.getHost()
+			Convey("Then the host returned should contain the region", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "some.api.rst1.domain.com")
+			})
+		})
+	})
+	Convey("Given a resource without the host override extension", t, func() {
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+		Convey("When getHost method is called", func() {
+			host, err := r.getHost()
+			Convey("Then the host returned should be empty", func() {
+				So(err, ShouldBeNil)
+				So(host, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestSpecV3ResourceIsSingleton(t *testing.T) {
+	Convey("Given a resource with a PUT instance operation containing the x-terraform-singleton extension set to true", t, func() {
+		put := &openapi3.Operation{}
+		put.Extensions = map[string]interface{}{extTfSingleton: true, extTfResourceURL: "settings.api.domain.com"}
+		r, _ := newSpecV3Resource("/v1/account/settings", openapi3.NewObjectSchema(), openapi3.PathItem{}, openapi3.PathItem{Put: put}, openapi3.Paths{})
+		Convey("When isSingleton and getHost methods are called", func() {
+			isSingleton := r.isSingleton()
+			host, err := r.getHost()
+			Convey("Then the resource should be a singleton and the host should be the one configured in the PUT operation", func() {
+				So(isSingleton, ShouldBeTrue)
+				So(err, ShouldBeNil)
+				So(host, ShouldEqual, "settings.api.domain.com")
+			})
+		})
+	})
+	Convey("Given a resource without the x-terraform-singleton extension", t, func() {
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{Post: &openapi3.Operation{}}, openapi3.PathItem{Put: &openapi3.Operation{}}, openapi3.Paths{})
+		Convey("When isSingleton method is called", func() {
+			isSingleton := r.isSingleton()
+			Convey("Then the value returned should be false", func() {
+				So(isSingleton, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestSpecV3ResourceGetResourceSchemaUnsupportedType(t *testing.T) {
+	Convey("Given a resource with a property of a non supported type", t, func() {
+		schema := openapi3.NewObjectSchema()
+		schema.Properties = map[string]*openapi3.SchemaRef{"id": {Value: &openapi3.Schema{Type: "file"}}}
+		r, _ := newSpecV3Resource("/v1/cdns", schema, openapi3.PathItem{}, openapi3.PathItem{}, openapi3.Paths{})
+		Convey("When getResourceSchema method is called", func() {
+			_, err := r.getResourceSchema()
+			Convey("Then the error returned should not be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestSpecV3ResourceGetResourceOperations(t *testing.T) {
+	Convey("Given a resource with operation and path level servers", t, func() {
+		operationServers := openapi3.Servers{{URL: "http://operation.server.com/v2"}}
+		rootPathItem := openapi3.PathItem{
+			Get:     &openapi3.Operation{Servers: &operationServers},
+			Post:    &openapi3.Operation{},
+			Servers: openapi3.Servers{{URL: "https://path.server.com"}},
+		}
+		instancePathItem := openapi3.PathItem{Get: &openapi3.Operation{}}
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), rootPathItem, instancePathItem, openapi3.Paths{})
+		Convey("When getResourceOperations method is called", func() {
+			operations := r.getResourceOperations()
+			Convey("Then the operation level servers should take precedence over the path level ones", func() {
+				So(operations.List.Schemes, ShouldResemble, []string{"http"})
+				So(operations.List.Host, ShouldEqual, "operation.server.com")
+				So(operations.List.BasePath, ShouldEqual, "/v2")
+			})
+			Convey("And the path level servers should be used for operations without servers", func() {
+				So(operations.Post.Schemes, ShouldResemble, []string{"https"})
+				So(operations.Post.Host, ShouldEqual, "path.server.com")
+				So(operations.Post.BasePath, ShouldEqual, "/")
+			})
+			Convey("And the operations of paths without servers should not override the global configuration", func() {
+				So(operations.Get.Schemes, ShouldBeEmpty)
+				So(operations.Get.Host, ShouldBeEmpty)
+				So(operations.Get.BasePath, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given a resource with the base path override extension", t, func() {
+		post := &openapi3.Operation{}
+		post.Extensions = map[string]interface{}{extTfResourceBasePath: "/resource-api"}
+		get := &openapi3.Operation{}
+		get.Extensions = map[string]interface{}{extTfResourceBasePath: "/operation-api"}
+		rootPathItem := openapi3.PathItem{
+			Post:    post,
+			Servers: openapi3.Servers{{URL: "https://path.server.com/v2"}},
+		}
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), rootPathItem, openapi3.PathItem{Get: get, Delete: &openapi3.Operation{}}, openapi3.Paths{})
+		Convey("When getResourceOperations method is called", func() {
+			operations := r.getResourceOperations()
+			Convey("Then the resource level base path should apply to all the operations and take precedence over the servers", func() {
+				So(operations.Post.BasePath, ShouldEqual, "/resource-api")
+				So(operations.Post.Host, ShouldEqual, "path.server.com")
+				So(operations.Delete.BasePath, ShouldEqual, "/resource-api")
+			})
+			Convey("And the operation level base path should take precedence over the resource level one", func() {
+				So(operations.Get.BasePath, ShouldEqual, "/operation-api")
+			})
+		})
+	})
+	Convey("Given a resource which update and delete operations support ETags", t, func() {
+		put := &openapi3.Operation{}
+		put.Extensions = map[string]interface{}{extTfETag: true}
+		del := &openapi3.Operation{
+			Parameters: openapi3.Parameters{{Value: &openapi3.Parameter{Name: "If-Match", In: openapi3.ParameterInHeader, Required: true}}},
+		}
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{Post: &openapi3.Operation{}}, openapi3.PathItem{Get: &openapi3.Operation{}, Put: put, Delete: del}, openapi3.Paths{})
+		Convey("When getResourceOperations method is called", func() {
+			operations := r.getResourceOperations()
+			Convey("Then the operations with the 'x-terraform-etag' extension or the If-Match header should send the If-Match header", func() {
+				So(operations.Put.IfMatch, ShouldBeTrue)
+				So(operations.Delete.IfMatch, ShouldBeTrue)
+				So(operations.Get.IfMatch, ShouldBeFalse)
+			})
+			Convey("And the If-Match header should not be part of the header parameters", func() {
+				So(operations.Delete.HeaderParameters, ShouldBeEmpty)
+			})
+		})
+	})
+	Convey("Given a resource which instance path declares the If-Match header parameter", t, func() {
+		instancePathItem := openapi3.PathItem{
+			Put:        &openapi3.Operation{},
+			Delete:     &openapi3.Operation{},
+			Parameters: openapi3.Parameters{{Value: &openapi3.Parameter{Name: "If-Match", In: openapi3.ParameterInHeader, Required: true}}},
+		}
+		r, _ := newSpecV3Resource("/v1/cdns", openapi3.NewObjectSchema(), openapi3.PathItem{Post: &openapi3.Operation{}}, instancePathItem, openapi3.Paths{})
+		Convey("When getResourceOperations method is called", func() {
+			operations := r.getResourceOperations()
+			Convey("Then the instance path operations should send the If-Match header", func() {
+				So(operations.Put.IfMatch, ShouldBeTrue)
+				So(operations.Delete.IfMatch, ShouldBeTrue)
+				So(operations.Post.IfMatch, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,142 @@
+package openapi
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type specV3Security struct {
+	SecuritySchemes map[string]*openapi3.SecuritySchemeRef
+	GlobalSecurity  openapi3.SecurityRequirements
+}
+
+// GetAPIKeySecurityDefinitions returns a list of SpecSecurityDefinition after looping through the components security
+// schemes and selecting only the ones of type apiKey, type http using the bearer scheme (which is translated into an
+// apiKey header bearer security definition) or the basic scheme, or type oauth2 using the client credentials, password
+// or authorization code flows
+func (s *specV3Security) GetAPIKeySecurityDefinitions() (*SpecSecurityDefinitions, error) {
+	securityDefinitions := &SpecSecurityDefinitions{}
+	for secDefName, secDefRef := range s.SecuritySchemes {
+		if secDefRef == nil || secDefRef.Value == nil {
+			continue
+		}
+		secDef := secDefRef.Value
+		var securityDefinition SpecSecurityDefinition
+		switch secDef.Type {
+		case "apiKey":
+			switch secDef.In {
+			case "header":
+				if refreshTokenURL := s.isRefreshTokenAuth(secDef); refreshTokenURL != "" {
+					securityDefinition = newAPIKeyHeaderRefreshTokenSecurityDefinition(secDefName, refreshTokenURL).withConfiguration(newRefreshTokenConfiguration(convertV3Extensions(secDef.ExtensionProps)))
+				} else if configuration, isSignatureAuth := newSignatureConfiguration(convertV3Extensions(secDef.ExtensionProps)); isSignatureAuth {
+					securityDefinition = newAPIKeyHeaderSignatureSecurityDefinition(secDefName, secDef.Name, configuration)
+				} else if s.isBearerScheme(secDef) {
+					securityDefinition = newAPIKeyHeaderBearerSecurityDefinition(secDefName)
+				} else {
+					securityDefinition = newAPIKeyHeaderSecurityDefinition(secDefName, secDef.Name)
+				}
+			case "query":
+				if s.isBearerScheme(secDef) {
+					securityDefinition = newAPIKeyQueryBearerSecurityDefinition(secDefName)
+				} else {
+					securityDefinition = newAPIKeyQuerySecurityDefinition(secDefName, secDef.Name)
+				}
+			case "cookie":
+				if loginURL := s.isSessionLoginAuth(secDef); loginURL != "" {
+					securityDefinition = newAPIKeyCookieSessionLoginSecurityDefinition(secDefName, secDef.Name, loginURL)
+				} else {
+					securityDefinition = newAPIKeyCookieSecurityDefinition(secDefName, secDef.Name)
+				}
+			default:
+				return nil, fmt.Errorf("apiKey In value '%s' not supported, only 'header', 'query' and 'cookie' values are valid", secDef.In)
+			}
+		case "http":
+			switch strings.ToLower(secDef.Scheme) {
+			case "bearer":
+				securityDefinition = newAPIKeyHeaderBearerSecurityDefinition(secDefName)
+			case "basic":
+				securityDefinition = newBasicSecurityDefinition(secDefName)
+			default:
+				continue
+			}
+		case "oauth2":
+			switch {
+			case secDef.Flows != nil && secDef.Flows.ClientCredentials != nil:
+				securityDefinition = newOAuth2ClientCredentialsSecurityDefinition(secDefName, secDef.Flows.ClientCredentials.TokenURL)
+			case secDef.Flows != nil && secDef.Flows.Password != nil:
+				securityDefinition = newOAuth2PasswordSecurityDefinition(secDefName, secDef.Flows.Password.TokenURL)
+			case secDef.Flows != nil && secDef.Flows.AuthorizationCode != nil:
+				securityDefinition = newOAuth2TokenFileSecurityDefinition(secDefName, secDef.Flows.AuthorizationCode.TokenURL)
+			default:
+				log.Printf("[WARN] ignoring security scheme '%s' as it does not define any of the supported oauth2 flows (clientCredentials, password or authorizationCode)", secDefName)
+				continue
+			}
+		default:
+			continue
+		}
+		if err := securityDefinition.validate(); err != nil {
+			return nil, err
+		}
+		*securityDefinitions = append(*securityDefinitions, securityDefinition)
+	}
+	return securityDefinitions, nil
+}
+
+func (s *specV3Security) isBearerScheme(secDef *openapi3.SecurityScheme) bool {
+	authScheme, enabled := convertV3Extensions(secDef.ExtensionProps).GetBool(extTfAuthenticationSchemeBearer)
+	if authScheme && enabled {
+		return true
+	}
+	return false
+}
+
+func (s *specV3Security) isRefreshTokenAuth(secDef *openapi3.SecurityScheme) string {
+	refreshTokenURL, isRefreshTokenAuth := convertV3Extensions(secDef.ExtensionProps).GetString(extTfAuthenticationRefreshToken)
+	if isRefreshTokenAuth {
+		return refreshTokenURL
+	}
+	return ""
+}
+
+func (s *specV3Security) isSessionLoginAuth(secDef *openapi3.SecurityScheme) string {
+	loginURL, isSessionLoginAuth := convertV3Extensions(secDef.ExtensionProps).GetString(extTfAuthenticationSessionLogin)
+	if isSessionLoginAuth {
+		return loginURL
+	}
+	return ""
+}
+
+// GetGlobalSecuritySchemes returns a list of SpecSecuritySchemes that have their corresponding SpecSecurityDefinition
+func (s *specV3Security) GetGlobalSecuritySchemes() (SpecSecuritySchemes, error) {
+	securitySchemes := createSecuritySchemes(convertV3SecurityRequirements(&s.GlobalSecurity))
+	for _, securityScheme := range securitySchemes {
+		if securityScheme.isEmptyRequirement() {
+			continue
+		}
+		secDef, err := s.GetAPIKeySecurityDefinitions()
+		if err != nil {
+			return SpecSecuritySchemes{}, nil
+		}
+		secDefFound := secDef.findSecurityDefinitionFor(securityScheme.Name)
+		if secDefFound == nil {
+			return nil, fmt.Errorf("global security scheme '%s' not found or not matching supported 'apiKey', 'http bearer', 'http basic' or 'oauth2' type", securityScheme.Name)
+		}
+	}
+	return securitySchemes, nil
+}
+
+// convertV3SecurityRequirements translates the OpenAPI v3 security requirements into the generic representation used
+// by createSecuritySchemes
+func convertV3SecurityRequirements(securityRequirements *openapi3.SecurityRequirements) []map[string][]string {
+	if securityRequirements == nil {
+		return nil
+	}
+	requirements := []map[string][]string{}
+	for _, securityRequirement := range *securityRequirements {
+		requirements = append(requirements, securityRequirement)
+	}
+	return requirements
+}
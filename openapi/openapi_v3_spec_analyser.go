@@ -0,0 +1,501 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dikhan/terraform-provider-openapi/openapi/openapiutils"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-openapi/spec"
+)
+
+// specV3Analyser defines an SpecAnalyser implementation for OpenAPI v3 specification
+// Forcing creation of this object via constructor so proper input validation is performed before creating the struct
+// instance
+type specV3Analyser struct {
+	openAPIDocumentURL string
+	d                  *openapi3.Swagger
+}
+
+// newSpecAnalyserV3 creates an instance of specV3Analyser which implements the SpecAnalyser interface
+// This implementation provides an analyser that understands an OpenAPI v3 document. The httpClient is used to retrieve
+// the document (and its external references) when served over http
+func newSpecAnalyserV3(openAPIDocumentFilename string, httpClient *http.Client) (*specV3Analyser, error) {
+	if openAPIDocumentFilename == "" {
+		return nil, errors.New("open api document filename argument empty, please provide the url of the OpenAPI document")
+	}
+	content, err := readOpenAPIDocument(openAPIDocumentFilename, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
+	}
+	return loadSpecAnalyserV3(openAPIDocumentFilename, content, httpClient)
+}
+
+// loadSpecAnalyserV3 returns a specV3Analyser for the given OpenAPI document content already retrieved from
+// openAPIDocumentFilename. The external references of the document are read using the httpClient when served over http
+func loadSpecAnalyserV3(openAPIDocumentFilename string, content []byte, httpClient *http.Client) (*specV3Analyser, error) {
+	loader := openapi3.NewSwaggerLoader()
+	loader.IsExternalRefsAllowed = true
+	// the document and its external references are read using the httpClient when served over http
+	loader.LoadSwaggerFromURIFunc = func(loader *openapi3.SwaggerLoader, location *url.URL) (*openapi3.Swagger, error) {
+		documentLocation := location.Path
+		if location.Scheme != "" || location.Host != "" {
+			documentLocation = location.String()
+		}
+		content, err := readOpenAPIDocument(documentLocation, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return loader.LoadSwaggerFromDataWithPath(content, location)
+	}
+	documentLocation := &url.URL{Path: openAPIDocumentFilename}
+	if isURL(openAPIDocumentFilename) {
+		var err error
+		if documentLocation, err = url.Parse(openAPIDocumentFilename); err != nil {
+			return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
+		}
+	}
+	apiSpec, err := loader.LoadSwaggerFromDataWithPath(content, documentLocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the OpenAPI document from '%s' - error = %s", openAPIDocumentFilename, err)
+	}
+	return &specV3Analyser{
+		d:                  apiSpec,
+		openAPIDocumentURL: openAPIDocumentFilename,
+	}, nil
+}
+
+func (specAnalyser *specV3Analyser) GetTerraformCompliantResources() ([]SpecResource, error) {
+	var resources []SpecResource
+	start := time.Now()
+	for resourcePath, pathItem := range specAnalyser.d.Paths {
+		if isCustomMethodPath(resourcePath) {
+			log.Printf("[DEBUG] resource path '%s' is a custom method, it will be attached to the resource it belongs to (if any)", resourcePath)
+			continue
+		}
+		if specAnalyser.isSingletonEndPoint(pathItem) {
+			r, err := specAnalyser.createSingletonResource(resourcePath, pathItem)
+			if err != nil {
+				log.Printf("[WARN] ignoring singleton resource '%s' due to not meeting validation requirements: %s", resourcePath, err)
+				continue
+			}
+			log.Printf("[INFO] found terraform compliant singleton resource [name='%s', path='%s']", r.getResourceName(), resourcePath)
+			resources = append(resources, r)
+			continue
+		}
+
+		resourceRootPath, resourceRoot, resourcePayloadSchemaDef, err := specAnalyser.isEndPointFullyTerraformResourceCompliant(resourcePath)
+		if err != nil {
+			log.Printf("[DEBUG] resource path '%s' not terraform compliant: %s", resourcePath, err)
+			continue
+		}
+		instancePathItem := *specAnalyser.d.Paths[resourcePath]
+
+		isMultiRegion, regions, err := specAnalyser.isMultiRegionResource(resourceRoot)
+		if err != nil {
+			log.Printf("multi region configuration for resource '%s' is not valid: %s", resourceRootPath, err)
+			continue
+		}
+		if isMultiRegion {
+			log.Printf("[INFO] resource '%s' is configured with host override AND multi region; creating one reasource per region", resourceRootPath)
+			for _, regionName := range regions {
+				r, err := newSpecV3ResourceWithRegion(regionName, resourceRootPath, resourcePayloadSchemaDef, *resourceRoot, instancePathItem, specAnalyser.d.Paths)
+				if err != nil {
+					log.Printf("[WARN] ignoring multiregion resource '%s' due to an error: %s", resourceRootPath, err)
+					continue
+				}
+				log.Printf("[INFO] multi region resource name = %s, region = '%s'", r.getResourceName(), regionName)
+				resources = append(resources, r)
+			}
+			continue
+		}
+
+		r, err := newSpecV3Resource(resourceRootPath, resourcePayloadSchemaDef, *resourceRoot, instancePathItem, specAnalyser.d.Paths)
+		if err != nil {
+			log.Printf("[WARN] ignoring resource '%s' due to an error while creating a creating the SpecV3Resource: %s", resourceRootPath, err)
+			continue
+		}
+
+		err = specAnalyser.validateSubResourceTerraformCompliance(*r)
+		if err != nil {
+			log.Printf("[WARN] ignoring subresource name='%s' with rootPath='%s' due to not meeting validation requirements: %s", r.getResourceName(), resourceRootPath, err)
+			continue
+		}
+
+		log.Printf("[INFO] found terraform compliant resource [name='%s', rootPath='%s', instancePath='%s']", r.getResourceName(), resourceRootPath, resourcePath)
+		resources = append(resources, r)
+	}
+	log.Printf("[INFO] found %d terraform compliant resources (time: %s)", len(resources), time.Since(start))
+	return resources, nil
+}
+
+func (specAnalyser *specV3Analyser) GetTerraformCompliantDataSources() []SpecResource {
+	var dataSources []SpecResource
+	for resourcePath, pathItem := range specAnalyser.d.Paths {
+		schemaDefinition, err := specAnalyser.isEndPointTerraformDataSourceCompliant(pathItem)
+		if err != nil {
+			log.Printf("[DEBUG] resource path '%s' not terraform data source compliant: %s", resourcePath, err)
+			continue
+		}
+
+		d, err := newSpecV3DataSource(resourcePath, schemaDefinition, *pathItem, specAnalyser.d.Paths)
+		if err != nil {
+			log.Printf("[WARN] ignoring data source '%s' due to an error while creating a creating the SpecV3Resource: %s", resourcePath, err)
+			continue
+		}
+
+		log.Printf("[INFO] found terraform compliant data source [name='%s', rootPath='%s']", d.getResourceName(), resourcePath)
+		dataSources = append(dataSources, d)
+	}
+	return dataSources
+}
+
+func (specAnalyser *specV3Analyser) GetSecurity() SpecSecurity {
+	return &specV3Security{
+		SecuritySchemes: specAnalyser.d.Components.SecuritySchemes,
+		GlobalSecurity:  specAnalyser.d.Security,
+	}
+}
+
+// GetAllHeaderParameters gets all the parameters of type headers present in the operations of the OpenAPI document
+func (specAnalyser *specV3Analyser) GetAllHeaderParameters() (SpecHeaderParameters, error) {
+	return getV3AllHeaderParameters(specAnalyser.d.Paths), nil
+}
+
+func (specAnalyser *specV3Analyser) GetAPIBackendConfiguration() (SpecBackendConfiguration, error) {
+	return newOpenAPIBackendConfigurationV3(specAnalyser.d, specAnalyser.openAPIDocumentURL)
+}
+
+// isEndPointFullyTerraformResourceCompliant returns the resource root path, the root path item and the resource schema
+// only if the following criteria is met (similarly to the OpenAPI v2 implementation):
+// - The path given 'resourcePath' is an instance path (e,g: "/users/{username}") with a GET operation defined
+// - The root path for the given path 'resourcePath' is found (e,g: "/users") and has a POST operation defined
+// - The root path POST operation has a requestBody with 'application/json' content and a schema with properties
+// - The resource schema definition must contain a field that uniquely identifies the resource or have a field with the 'x-terraform-id' extension set to true
+func (specAnalyser *specV3Analyser) isEndPointFullyTerraformResourceCompliant(resourcePath string) (string, *openapi3.PathItem, *openapi3.Schema, error) {
+	err := specAnalyser.validateInstancePath(resourcePath)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, err := specAnalyser.validateRootPath(resourcePath)
+	if err != nil {
+		var putErr error
+		resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, putErr = specAnalyser.validateInstancePathPut(resourcePath)
+		if putErr != nil {
+			log.Printf("[DEBUG] resource instance path '%s' can not be created via PUT either: %s", resourcePath, putErr)
+			return "", nil, nil, err
+		}
+	}
+	err = specAnalyser.validateResourceSchemaDefinition(resourceRootPostSchemaDef)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, nil
+}
+
+func (specAnalyser *specV3Analyser) isEndPointTerraformDataSourceCompliant(path *openapi3.PathItem) (*openapi3.Schema, error) {
+	if path == nil || path.Get == nil {
+		return nil, errors.New("missing get operation")
+	}
+	if path.Get.Responses == nil {
+		return nil, errors.New("missing get responses")
+	}
+	response := path.Get.Responses.Get(http.StatusOK)
+	if response == nil || response.Value == nil {
+		return nil, errors.New("missing get 200 OK response specification")
+	}
+	schema := getV3JSONSchema(response.Value.Content)
+	if schema == nil {
+		return nil, errors.New("missing response schema")
+	}
+	if schema.Type != "array" {
+		return nil, errors.New("response does not return an array of items")
+	}
+	if schema.Items == nil || schema.Items.Value == nil || schema.Items.Value.Type != "object" || len(schema.Items.Value.Properties) == 0 {
+		return nil, errors.New("the response schema is missing the items schema specification or the items schema is not properly defined as object with properties configured")
+	}
+	return schema.Items.Value, nil
+}
+
+func (specAnalyser *specV3Analyser) validateInstancePath(path string) error {
+	isResourceInstance, err := specAnalyser.isResourceInstanceEndPoint(path)
+	if err != nil {
+		return fmt.Errorf("error occurred while checking if path '%s' is a resource instance path", path)
+	}
+	if !isResourceInstance {
+		return fmt.Errorf("path '%s' is not a resource instance path", path)
+	}
+	endPoint := specAnalyser.d.Paths[path]
+	if endPoint == nil || endPoint.Get == nil {
+		return fmt.Errorf("resource instance path '%s' missing required GET operation", path)
+	}
+	return nil
+}
+
+func (specAnalyser *specV3Analyser) validateRootPath(resourcePath string) (string, *openapi3.PathItem, *openapi3.Schema, error) {
+	resourceRootPath, err := specAnalyser.findMatchingResourceRootPath(resourcePath)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	resourceRootPathItem := specAnalyser.d.Paths[resourceRootPath]
+	if resourceRootPathItem.Post == nil {
+		return "", nil, nil, fmt.Errorf("resource root path '%s' missing required POST operation", resourceRootPath)
+	}
+	resourceRootPostSchemaDef, err := specAnalyser.getRequestBodySchema(resourceRootPathItem.Post)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("resource root path '%s' POST operation validation error: %s", resourceRootPath, err)
+	}
+	return resourceRootPath, resourceRootPathItem, resourceRootPostSchemaDef, nil
+}
+
+// validateInstancePathPut follows the same rules as the OpenAPI v2 implementation (see specV2Analyser.validateInstancePathPut)
+// reading the resource schema from the PUT operation request body
+func (specAnalyser *specV3Analyser) validateInstancePathPut(resourcePath string) (string, *openapi3.PathItem, *openapi3.Schema, error) {
+	resourceInstancePathItem := specAnalyser.d.Paths[resourcePath]
+	if resourceInstancePathItem == nil || resourceInstancePathItem.Put == nil {
+		return "", nil, nil, fmt.Errorf("resource instance path '%s' missing PUT operation", resourcePath)
+	}
+	resourceRootPathItem := &openapi3.PathItem{}
+	resourceRootPath, err := specAnalyser.findMatchingResourceRootPath(resourcePath)
+	if err == nil {
+		resourceRootPathItem = specAnalyser.d.Paths[resourceRootPath]
+		if resourceRootPathItem.Post != nil {
+			return "", nil, nil, fmt.Errorf("resource root path '%s' exposes a POST operation", resourceRootPath)
+		}
+	} else {
+		resourceRootPath, err = specAnalyser.buildResourceRootPath(resourcePath)
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+	resourceSchemaDef, err := specAnalyser.getRequestBodySchema(resourceInstancePathItem.Put)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("resource instance path '%s' PUT operation validation error: %s", resourcePath, err)
+	}
+	err = specAnalyser.validateClientGeneratedIdentifier(resourceSchemaDef)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return resourceRootPath, resourceRootPathItem, resourceSchemaDef, nil
+}
+
+// validateClientGeneratedIdentifier checks that the given schema contains a property with the 'x-terraform-id' extension
+// set to true that can be configured by the user (not readOnly)
+func (specAnalyser *specV3Analyser) validateClientGeneratedIdentifier(schema *openapi3.Schema) error {
+	for propertyName, property := range schema.Properties {
+		if property == nil || property.Value == nil {
+			continue
+		}
+		if useAsIdentifier, exists := convertV3Extensions(property.Value.ExtensionProps).GetBool(extTfID); exists && useAsIdentifier {
+			if property.Value.ReadOnly {
+				return fmt.Errorf("resource schema identifier property '%s' must not be readOnly as its value is expected to be provided by the user", propertyName)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("resource schema is missing a property with the extension '%s' set to true, which is required for resources created via PUT", extTfID)
+}
+
+func (specAnalyser *specV3Analyser) validateResourceSchemaDefinition(schema *openapi3.Schema) error {
+	for propertyName, property := range schema.Properties {
+		if propertyName == "id" {
+			return nil
+		}
+		if property == nil || property.Value == nil {
+			continue
+		}
+		if useAsIdentifier, exists := convertV3Extensions(property.Value.ExtensionProps).GetBool(extTfID); exists && useAsIdentifier {
+			return nil
+		}
+	}
+	return fmt.Errorf("resource schema is missing a property that uniquely identifies the resource, either a property named 'id' or a property with the extension '%s' set to true", extTfID)
+}
+
+func (specAnalyser *specV3Analyser) getRequestBodySchema(operation *openapi3.Operation) (*openapi3.Schema, error) {
+	if operation.RequestBody == nil || operation.RequestBody.Value == nil {
+		return nil, fmt.Errorf("resource root operation missing the request body")
+	}
+	schema := getV3JSONSchema(operation.RequestBody.Value.Content)
+	if schema == nil {
+		return nil, fmt.Errorf("resource root operation missing the '%s' schema for the POST operation request body", jsonMediaType)
+	}
+	if len(schema.Properties) > 0 {
+		return schema, nil
+	}
+	return nil, fmt.Errorf("POST operation contains an schema with no properties")
+}
+
+// isSingletonEndPoint follows the same rules as the OpenAPI v2 implementation (see specV2Analyser.isSingletonEndPoint)
+func (specAnalyser *specV3Analyser) isSingletonEndPoint(pathItem *openapi3.PathItem) bool {
+	if pathItem == nil || pathItem.Put == nil {
+		return false
+	}
+	isSingleton, exists := convertV3Extensions(pathItem.Put.ExtensionProps).GetBool(extTfSingleton)
+	return exists && isSingleton
+}
+
+// createSingletonResource follows the same rules as the OpenAPI v2 implementation (see specV2Analyser.createSingletonResource)
+// reading the resource schema from the PUT operation request body
+func (specAnalyser *specV3Analyser) createSingletonResource(resourcePath string, pathItem *openapi3.PathItem) (*SpecV3Resource, error) {
+	if pathItem.Get == nil {
+		return nil, fmt.Errorf("singleton resource path '%s' missing required GET operation", resourcePath)
+	}
+	resourcePayloadSchemaDef, err := specAnalyser.getRequestBodySchema(pathItem.Put)
+	if err != nil {
+		return nil, fmt.Errorf("singleton resource path '%s' PUT operation validation error: %s", resourcePath, err)
+	}
+	r, err := newSpecV3Resource(resourcePath, resourcePayloadSchemaDef, openapi3.PathItem{}, *pathItem, specAnalyser.d.Paths)
+	if err != nil {
+		return nil, err
+	}
+	if err := specAnalyser.validateSubResourceTerraformCompliance(*r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (specAnalyser *specV3Analyser) validateSubResourceTerraformCompliance(r SpecV3Resource) error {
+	parentResourceInfo := r.getParentResourceInfo()
+	if parentResourceInfo != nil {
+		resourcePath := r.Path
+		for _, parentInstanceURIs := range parentResourceInfo.parentInstanceURIs {
+			if pathExists, _ := specAnalyser.pathExists(parentInstanceURIs); !pathExists {
+				return fmt.Errorf("subresource with path '%s' is missing parent path instance definition '%s'", resourcePath, parentInstanceURIs)
+			}
+		}
+		for _, parentURI := range parentResourceInfo.parentURIs {
+			parentPathExists, parentPathItem := specAnalyser.pathExists(parentURI)
+			if !parentPathExists {
+				return fmt.Errorf("subresource with path '%s' is missing parent root path definition '%s'", resourcePath, parentURI)
+			}
+			parentResource := SpecV3Resource{RootPathItem: *parentPathItem}
+			if parentResource.shouldIgnoreResource() {
+				return fmt.Errorf("subresource with path '%s' contains a parent %s that is marked as ignored, therefore ignoring the subresource too", resourcePath, parentURI)
+			}
+		}
+	}
+	return nil
+}
+
+func (specAnalyser *specV3Analyser) pathExists(path string) (bool, *openapi3.PathItem) {
+	p := specAnalyser.d.Paths[path]
+	if p == nil {
+		log.Printf("[WARN] path %s not found, falling back to checking if the path with trailing slash %s/ exists", path, path)
+		p = specAnalyser.d.Paths[path+"/"]
+		if p == nil {
+			return false, nil
+		}
+	}
+	return true, p
+}
+
+// isMultiRegionResource follows the same rules as the OpenAPI v2 implementation (see specV2Analyser.isMultiRegionResource)
+// using the 'x-terraform-resource-regions-${keyword}' extensions defined at the root level of the document
+func (specAnalyser *specV3Analyser) isMultiRegionResource(resourceRoot *openapi3.PathItem) (bool, []string, error) {
+	overrideHost := getV3ResourceOverrideHost(resourceRoot.Post)
+	if overrideHost == "" {
+		return false, nil, nil
+	}
+	isMultiRegionHost, regex := openapiutils.IsMultiRegionHost(overrideHost)
+	if !isMultiRegionHost {
+		return false, nil, nil
+	}
+	region := regex.FindStringSubmatch(overrideHost)
+	if len(region) != 5 {
+		return false, nil, fmt.Errorf("override host %s provided does not comply with expected regex format", overrideHost)
+	}
+	regionIdentifier := region[3]
+	regionExtensionName := fmt.Sprintf(extTfResourceRegionsFmt, regionIdentifier)
+	extensions := convertV3Extensions(specAnalyser.d.ExtensionProps)
+	if resourceRegions, exists := openapiutils.StringExtensionExists(extensions, regionExtensionName); exists {
+		resourceRegions = strings.Replace(resourceRegions, " ", "", -1)
+		regions := strings.Split(resourceRegions, ",")
+		if len(regions) < 1 || regions[0] == "" {
+			return false, nil, fmt.Errorf("could not find any region for '%s' matching region extension %s: '%s'", regionIdentifier, regionExtensionName, resourceRegions)
+		}
+		return true, regions, nil
+	}
+	return false, nil, fmt.Errorf("missing matching '%s' root level region extension '%s'", regionIdentifier, regionExtensionName)
+}
+
+// isResourceInstanceEndPoint checks if the given path is of form /resource/{id}
+func (specAnalyser *specV3Analyser) isResourceInstanceEndPoint(p string) (bool, error) {
+	r, err := regexp.Compile(resourceInstanceRegex)
+	if err != nil {
+		return false, fmt.Errorf("an error occurred while compiling the resourceInstanceRegex regex '%s': %s", resourceInstanceRegex, err)
+	}
+	return r.MatchString(p), nil
+}
+
+// buildResourceRootPath returns the root path for the given resource instance path (e,g: /v1/buckets for /v1/buckets/{name})
+// without checking whether the root path exists in the document
+func (specAnalyser *specV3Analyser) buildResourceRootPath(resourcePath string) (string, error) {
+	r, err := regexp.Compile(resourceInstanceRegex)
+	if err != nil {
+		return "", fmt.Errorf("an error occurred while compiling the resourceInstanceRegex regex '%s': %s", resourceInstanceRegex, err)
+	}
+	result := r.FindStringSubmatch(resourcePath)
+	if len(result) != 2 {
+		return "", fmt.Errorf("resource instance path '%s' missing valid resource root path, more than two results returned from match '%s'", resourcePath, result)
+	}
+	return strings.TrimRight(result[1], "/"), nil
+}
+
+// findMatchingResourceRootPath returns the corresponding POST root and path for a given end point
+// Example: Given 'resourcePath' being "/users/{username}" the result could be "/users" or "/users/" depending on
+// how the POST operation (resourceRootPath) of the given resource is defined in the OpenAPI document.
+func (specAnalyser *specV3Analyser) findMatchingResourceRootPath(resourcePath string) (string, error) {
+	r, err := regexp.Compile(resourceInstanceRegex)
+	if err != nil {
+		return "", fmt.Errorf("an error occurred while compiling the resourceInstanceRegex regex '%s': %s", resourceInstanceRegex, err)
+	}
+	result := r.FindStringSubmatch(resourcePath)
+	log.Printf("[DEBUG] resource root path match result - %s", result)
+	if len(result) != 2 {
+		return "", fmt.Errorf("resource instance path '%s' missing valid resource root path, more than two results returned from match '%s'", resourcePath, result)
+	}
+
+	resourceRootPath := result[1] // e,g: /v1/cdns/{id} /v1/cdns/
+	if specAnalyser.d.Paths[resourceRootPath] != nil {
+		log.Printf("[DEBUG] found resource root path with trailing '/' - %+s", resourceRootPath)
+		return resourceRootPath, nil
+	}
+
+	// Handles the case where the document root path does not have a trailing slash in the path
+	resourceRootPath = strings.TrimRight(resourceRootPath, "/")
+	if specAnalyser.d.Paths[resourceRootPath] != nil {
+		log.Printf("[DEBUG] found resource root path without trailing '/' - %+s", resourceRootPath)
+		return resourceRootPath, nil
+	}
+
+	return "", fmt.Errorf("resource instance path '%s' missing resource root path", resourcePath)
+}
+
+// convertV3Extensions translates the OpenAPI v3 extension properties into spec.Extensions so the extensions can be
+// looked up the same way regardless of the OpenAPI version. The kin-openapi library keeps all the fields of the object
+// (not only the extensions) as raw JSON messages, hence only the 'x-' prefixed ones are decoded and added.
+func convertV3Extensions(extensionProps openapi3.ExtensionProps) spec.Extensions {
+	extensions := spec.Extensions{}
+	for key, value := range extensionProps.Extensions {
+		if !strings.HasPrefix(strings.ToLower(key), "x-") {
+			continue
+		}
+		if rawValue, isRawMessage := value.(json.RawMessage); isRawMessage {
+			var decodedValue interface{}
+			if err := json.Unmarshal(rawValue, &decodedValue); err != nil {
+				log.Printf("[WARN] ignoring extension '%s' due to invalid value: %s", key, err)
+				continue
+			}
+			value = decodedValue
+		}
+		extensions.Add(key, value)
+	}
+	return extensions
+}
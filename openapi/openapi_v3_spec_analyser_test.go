@@ -0,0 +1,513 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const openAPIV3Document = `openapi: 3.0.1
+info:
+  title: CDN API
+  version: 1.0.0
+servers:
+  - url: http://api.cdn.com/api
+  - url: https://api.cdn.com/api
+security:
+  - apikey_auth: []
+paths:
+  /v1/cdns:
+    post:
+      x-terraform-resource-timeout: 30s
+      parameters:
+        - name: X-Request-ID
+          in: header
+          required: true
+          schema:
+            type: string
+          x-terraform-header: x_request_id
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/ContentDeliveryNetwork'
+      responses:
+        201:
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ContentDeliveryNetwork'
+        202:
+          description: accepted
+          x-terraform-resource-poll-enabled: true
+          x-terraform-resource-poll-completed-statuses: "deployed"
+          x-terraform-resource-poll-pending-statuses: "deploying, pending"
+        default:
+          description: error
+    get:
+      responses:
+        200:
+          description: list
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/ContentDeliveryNetwork'
+  /v1/cdns/{cdn_id}:
+    get:
+      security:
+        - bearer_auth: []
+      parameters:
+        - name: cdn_id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/ContentDeliveryNetwork'
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/ContentDeliveryNetwork'
+      responses:
+        200:
+          description: ok
+    delete:
+      responses:
+        204:
+          description: deleted
+  /v1/cdns/{cdn_id}/v1/firewalls:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                id:
+                  type: string
+                  readOnly: true
+                name:
+                  type: string
+      responses:
+        201:
+          description: created
+  /v1/cdns/{cdn_id}/v1/firewalls/{fw_id}:
+    get:
+      responses:
+        200:
+          description: ok
+  /v1/lbs:
+    post:
+      x-terraform-exclude-resource: true
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/ContentDeliveryNetwork'
+      responses:
+        201:
+          description: created
+  /v1/lbs/{id}:
+    get:
+      responses:
+        200:
+          description: ok
+components:
+  securitySchemes:
+    apikey_auth:
+      type: apiKey
+      in: header
+      name: Authorization
+    bearer_auth:
+      type: http
+      scheme: bearer
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.cdn.com/token
+          scopes: {}
+  schemas:
+    ContentDeliveryNetwork:
+      type: object
+      required:
+        - label
+      properties:
+        id:
+          type: string
+          readOnly: true
+        label:
+          type: string
+          x-terraform-force-new: true
+        password:
+          type: string
+          writeOnly: true
+        ips:
+          type: array
+          items:
+            type: string
+        object_property:
+          $ref: '#/components/schemas/ObjectProperty'
+        list_objects:
+          type: array
+          items:
+            $ref: '#/components/schemas/ObjectProperty'
+        optional_computed:
+          type: string
+          x-terraform-computed: true
+        with_default:
+          type: integer
+          default: 5
+    ObjectProperty:
+      type: object
+      properties:
+        account:
+          type: string
+        enabled:
+          type: boolean
+        ratio:
+          type: number`
+
+func initAPISpecAnalyserV3(t *testing.T, document string) *specV3Analyser {
+	file := initAPISpecFile(document)
+	defer os.Remove(file.Name())
+	specAnalyser, err := newSpecAnalyserV3(file.Name(), http.DefaultClient)
+	require.NoError(t, err)
+	return specAnalyser
+}
+
+func TestNewSpecAnalyserV3(t *testing.T) {
+	Convey("Given an empty openAPIDocumentURL", t, func() {
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			_, err := newSpecAnalyserV3("", http.DefaultClient)
+			Convey("Then the error message returned should be", func() {
+				So(err.Error(), ShouldEqual, "open api document filename argument empty, please provide the url of the OpenAPI document")
+			})
+		})
+	})
+	Convey("Given an openAPIDocumentURL pointing at a non existing file", t, func() {
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			_, err := newSpecAnalyserV3("some non valid spec file", http.DefaultClient)
+			Convey("Then the error message returned should be", func() {
+				So(err.Error(), ShouldEqual, "failed to retrieve the OpenAPI document from 'some non valid spec file' - error = open some non valid spec file: no such file or directory")
+			})
+		})
+	})
+	Convey("Given a valid OpenAPI v3 document", t, func() {
+		file := initAPISpecFile(openAPIV3Document)
+		defer os.Remove(file.Name())
+		Convey("When newSpecAnalyserV3 method is called", func() {
+			specAnalyser, err := newSpecAnalyserV3(file.Name(), http.DefaultClient)
+			Convey("Then the error returned should be nil and the specAnalyser should comply with the SpecAnalyser interface", func() {
+				So(err, ShouldBeNil)
+				var _ SpecAnalyser = specAnalyser
+			})
+		})
+	})
+}
+
+func TestSpecV3AnalyserGetTerraformCompliantResources(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, openAPIV3Document)
+
+	resources, err := specAnalyser.GetTerraformCompliantResources()
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	resourcesByName := map[string]SpecResource{}
+	for _, r := range resources {
+		resourcesByName[r.getResourceName()] = r
+	}
+	assert.Contains(t, resourcesByName, "cdns_v1")
+	assert.Contains(t, resourcesByName, "cdns_v1_firewalls_v1")
+	assert.Contains(t, resourcesByName, "lbs_v1")
+	assert.True(t, resourcesByName["lbs_v1"].shouldIgnoreResource())
+
+	cdn := resourcesByName["cdns_v1"]
+	path, err := cdn.getResourcePath(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/cdns", path)
+
+	timeouts, err := cdn.getTimeouts()
+	assert.NoError(t, err)
+	assert.Equal(t, "30s", timeouts.Post.String())
+	assert.Nil(t, timeouts.Get)
+
+	operations := cdn.getResourceOperations()
+	assert.NotNil(t, operations.List)
+	assert.NotNil(t, operations.Put)
+	assert.NotNil(t, operations.Delete)
+	assert.Equal(t, SpecHeaderParameters{{Name: "X-Request-ID", TerraformName: "x_request_id"}}, operations.Post.HeaderParameters)
+	assert.Equal(t, SpecSecuritySchemes{{Name: "bearer_auth"}}, operations.Get.SecuritySchemes)
+	assert.Empty(t, operations.Post.SecuritySchemes)
+	assert.Nil(t, operations.Post.responses.getResponse(201).pollTargetStatuses)
+	assert.True(t, operations.Post.responses.getResponse(202).isPollingEnabled)
+	assert.Equal(t, []string{"deployed"}, operations.Post.responses.getResponse(202).pollTargetStatuses)
+	assert.Equal(t, []string{"deploying", "pending"}, operations.Post.responses.getResponse(202).pollPendingStatuses)
+
+	s, err := cdn.getResourceSchema()
+	require.NoError(t, err)
+	assert.Len(t, s.Properties, 8)
+
+	id, _ := s.getProperty("id")
+	assert.True(t, id.ReadOnly)
+	assert.True(t, id.Computed)
+
+	label, _ := s.getProperty("label")
+	assert.True(t, label.Required)
+	assert.True(t, label.ForceNew)
+
+	password, _ := s.getProperty("password")
+	assert.True(t, password.Sensitive)
+
+	ips, _ := s.getProperty("ips")
+	assert.Equal(t, typeList, ips.Type)
+	assert.Equal(t, typeString, ips.ArrayItemsType)
+
+	objectProperty, _ := s.getProperty("object_property")
+	assert.Equal(t, typeObject, objectProperty.Type)
+	assert.Len(t, objectProperty.SpecSchemaDefinition.Properties, 3)
+
+	listObjects, _ := s.getProperty("list_objects")
+	assert.Equal(t, typeObject, listObjects.ArrayItemsType)
+	assert.Len(t, listObjects.SpecSchemaDefinition.Properties, 3)
+
+	optionalComputed, _ := s.getProperty("optional_computed")
+	assert.True(t, optionalComputed.isOptionalComputed())
+
+	withDefault, _ := s.getProperty("with_default")
+	assert.Equal(t, typeInt, withDefault.Type)
+	assert.Equal(t, float64(5), withDefault.Default)
+
+	firewall := resourcesByName["cdns_v1_firewalls_v1"]
+	path, err = firewall.getResourcePath([]string{"cdnID"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/cdns/cdnID/v1/firewalls", path)
+	s, err = firewall.getResourceSchema()
+	require.NoError(t, err)
+	parentProperty, err := s.getProperty("cdns_v1_id")
+	require.NoError(t, err)
+	assert.True(t, parentProperty.IsParentProperty)
+}
+
+func TestSpecV3AnalyserGetTerraformCompliantResourcesSingleton(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, `openapi: 3.0.1
+info:
+  title: Singleton API
+  version: 1.0.0
+paths:
+  /v1/account/settings:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/AccountSettings'
+    put:
+      x-terraform-singleton: true
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/AccountSettings'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/AccountSettings'
+components:
+  schemas:
+    AccountSettings:
+      type: object
+      properties:
+        timezone:
+          type: string`)
+
+	resources, err := specAnalyser.GetTerraformCompliantResources()
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "settings", resources[0].getResourceName())
+	assert.True(t, resources[0].isSingleton())
+
+	path, err := resources[0].getResourcePath(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/account/settings", path)
+}
+
+func TestSpecV3AnalyserGetTerraformCompliantResourcesCreatedViaPut(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, `openapi: 3.0.1
+info:
+  title: Buckets API
+  version: 1.0.0
+paths:
+  /v1/buckets/{name}:
+    get:
+      parameters:
+      - name: name
+        in: path
+        required: true
+        schema:
+          type: string
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Bucket'
+    put:
+      parameters:
+      - name: name
+        in: path
+        required: true
+        schema:
+          type: string
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Bucket'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Bucket'
+components:
+  schemas:
+    Bucket:
+      type: object
+      properties:
+        name:
+          type: string
+          x-terraform-id: true
+        label:
+          type: string`)
+
+	resources, err := specAnalyser.GetTerraformCompliantResources()
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "buckets_v1", resources[0].getResourceName())
+
+	path, err := resources[0].getResourcePath(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/buckets", path)
+
+	method, operation := resources[0].getResourceOperations().getCreateOperation()
+	assert.Equal(t, httpPut, method)
+	assert.NotNil(t, operation)
+}
+
+func TestSpecV3AnalyserGetTerraformCompliantDataSources(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, openAPIV3Document)
+	dataSources := specAnalyser.GetTerraformCompliantDataSources()
+	require.Len(t, dataSources, 1)
+	assert.Equal(t, "cdns_v1", dataSources[0].getResourceName())
+}
+
+func TestSpecV3AnalyserGetSecurity(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, openAPIV3Document)
+	security := specAnalyser.GetSecurity()
+
+	securityDefinitions, err := security.GetAPIKeySecurityDefinitions()
+	require.NoError(t, err)
+	assert.Len(t, *securityDefinitions, 3)
+	apiKeyAuth := securityDefinitions.findSecurityDefinitionFor("apikey_auth")
+	require.NotNil(t, apiKeyAuth)
+	assert.Equal(t, newAPIKeyHeader("Authorization"), apiKeyAuth.getAPIKey())
+	bearerAuth := securityDefinitions.findSecurityDefinitionFor("bearer_auth")
+	require.NotNil(t, bearerAuth)
+	assert.Equal(t, "Bearer token", bearerAuth.buildValue("token"))
+	oauth := securityDefinitions.findSecurityDefinitionFor("oauth")
+	assert.Equal(t, newOAuth2ClientCredentialsSecurityDefinition("oauth", "https://auth.cdn.com/token"), oauth)
+
+	globalSecuritySchemes, err := security.GetGlobalSecuritySchemes()
+	require.NoError(t, err)
+	assert.Equal(t, SpecSecuritySchemes{{Name: "apikey_auth"}}, globalSecuritySchemes)
+}
+
+func TestSpecV3AnalyserGetAllHeaderParameters(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, openAPIV3Document)
+	headers, err := specAnalyser.GetAllHeaderParameters()
+	require.NoError(t, err)
+	assert.Equal(t, SpecHeaderParameters{{Name: "X-Request-ID", TerraformName: "x_request_id"}}, headers)
+}
+
+func TestSpecV3AnalyserGetAPIBackendConfiguration(t *testing.T) {
+	specAnalyser := initAPISpecAnalyserV3(t, openAPIV3Document)
+	backendConfiguration, err := specAnalyser.GetAPIBackendConfiguration()
+	require.NoError(t, err)
+	host, err := backendConfiguration.getHost()
+	assert.NoError(t, err)
+	assert.Equal(t, "api.cdn.com", host)
+	assert.Equal(t, "/api", backendConfiguration.getBasePath())
+	scheme, err := backendConfiguration.getHTTPScheme()
+	assert.NoError(t, err)
+	assert.Equal(t, "https", scheme)
+}
+
+func TestSpecV3AnalyserIsMultiRegionResource(t *testing.T) {
+	specAnalyser := &specV3Analyser{d: &openapi3.Swagger{}}
+	specAnalyser.d.Extensions = map[string]interface{}{"x-terraform-resource-regions-myregion": "rst1, dub1"}
+	Convey("Given a resource root path with a multi region host override", t, func() {
+		post := &openapi3.Operation{}
+		post.Extensions = map[string]interface{}{extTfResourceURL: "some.api.${myregion}.domain.com"}
+		Convey("When isMultiRegionResource method is called", func() {
+			isMultiRegion, regions, err := specAnalyser.isMultiRegionResource(&openapi3.PathItem{Post: post})
+			Convey("Then the resource should be multi region with the regions configured", func() {
+				So(err, ShouldBeNil)
+				So(isMultiRegion, ShouldBeTrue)
+				So(regions, ShouldResemble, []string{"rst1", "dub1"})
+			})
+		})
+	})
+	Convey("Given a resource root path with a multi region host override missing the regions extension", t, func() {
+		post := &openapi3.Operation{}
+		post.Extensions = map[string]interface{}{extTfResourceURL: "some.api.${otherregion}.domain.com"}
+		Convey("When isMultiRegionResource method is called", func() {
+			_, _, err := specAnalyser.isMultiRegionResource(&openapi3.PathItem{Post: post})
+			Convey("Then the error returned should be", func() {
+				So(err.Error(), ShouldEqual, "missing matching 'otherregion' root level region extension 'x-terraform-resource-regions-otherregion'")
+			})
+		})
+	})
+}
+
+func TestConvertV3Extensions(t *testing.T) {
+	extensionProps := openapi3.ExtensionProps{
+		Extensions: map[string]interface{}{
+			"x-terraform-raw-bool":   json.RawMessage(`true`),
+			"x-terraform-bool":       true,
+			"x-terraform-raw-string": json.RawMessage(`"value"`),
+			"description":            json.RawMessage(`"some description"`),
+		},
+	}
+	extensions := convertV3Extensions(extensionProps)
+	rawBool, _ := extensions.GetBool("x-terraform-raw-bool")
+	assert.True(t, rawBool)
+	boolValue, _ := extensions.GetBool("x-terraform-bool")
+	assert.True(t, boolValue)
+	stringValue, _ := extensions.GetString("x-terraform-raw-string")
+	assert.Equal(t, "value", stringValue)
+	assert.NotContains(t, extensions, "description")
+}
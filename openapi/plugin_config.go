@@ -2,10 +2,10 @@ package openapi
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"github.com/dikhan/terraform-provider-openapi/openapi/terraformutils"
 	"github.com/dikhan/terraform-provider-openapi/openapi/version"
-	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
 	"log"
@@ -20,6 +20,9 @@ const OpenAPIPluginConfigurationFileName = "terraform-provider-openapi.yaml"
 const otfVarSwaggerURL = "OTF_VAR_%s_SWAGGER_URL"
 const otfVarInsecureSkipVerify = "OTF_INSECURE_SKIP_VERIFY"
 const otfVarPluginConfigurationFile = "OTF_VAR_%s_PLUGIN_CONFIGURATION_FILE"
+const otfVarSwaggerHeaders = "OTF_VAR_%s_SWAGGER_HEADERS"
+const otfVarSwaggerBearerToken = "OTF_VAR_%s_SWAGGER_BEARER_TOKEN"
+const otfVarSwaggerTokenCommand = "OTF_VAR_%s_SWAGGER_TOKEN_COMMAND"
 
 // PluginConfiguration defines the OpenAPI plugin's configuration
 type PluginConfiguration struct {
@@ -94,11 +97,16 @@ func (p *PluginConfiguration) getServiceConfiguration() (ServiceConfiguration, e
 	if err != nil {
 		return nil, err
 	}
+	swaggerAuthConfiguration, err := p.getSwaggerAuthConfigurationFromEnv()
+	if err != nil {
+		return nil, err
+	}
 	// Found OTF_VAR_%s_SWAGGER_URL env variable
 	if apiDiscoveryURL != "" {
 		log.Printf("[INFO] %s set with value %s", swaggerURLEnvVar, apiDiscoveryURL)
 		pluginConfigV1.Services = map[string]*ServiceConfigV1{}
 		pluginConfigV1.Services[p.ProviderName] = NewServiceConfigV1(apiDiscoveryURL, skipVerify)
+		pluginConfigV1.Services[p.ProviderName].SwaggerAuthConfigurationV1 = swaggerAuthConfiguration
 		serviceConfig, err = pluginConfigV1.GetServiceConfig(p.ProviderName)
 		if err != nil {
 			return nil, err
@@ -112,11 +120,10 @@ func (p *PluginConfiguration) getServiceConfiguration() (ServiceConfiguration, e
 			if err != nil {
 				return nil, fmt.Errorf("failed to read %s configuration file", OpenAPIPluginConfigurationFileName)
 			}
-			err = yaml.Unmarshal(source, pluginConfigV1)
+			pluginConfig, err = unmarshalPluginConfigSchema(source)
 			if err != nil {
 				return nil, fmt.Errorf("failed to unmarshall %s configuration file - error = %s", OpenAPIPluginConfigurationFileName, err)
 			}
-			pluginConfig = PluginConfigSchema(pluginConfigV1)
 			if err = pluginConfig.Validate(); err != nil {
 				return nil, fmt.Errorf("error occurred while validating '%s' - error = %s", OpenAPIPluginConfigurationFileName, err)
 			}
@@ -124,6 +131,15 @@ func (p *PluginConfiguration) getServiceConfiguration() (ServiceConfiguration, e
 			if err != nil {
 				return nil, fmt.Errorf("error occurred when getting service configuration from plugin configuration file %s - error = %s", OpenAPIPluginConfigurationFileName, err)
 			}
+			// The swagger auth configuration provided in the env variables takes preference over the one in the file
+			if swaggerAuthConfiguration != nil {
+				switch c := serviceConfig.(type) {
+				case *ServiceConfigV1:
+					c.SwaggerAuthConfigurationV1 = swaggerAuthConfiguration
+				case *ServiceConfigV2:
+					c.SwaggerAuthConfigurationV1 = swaggerAuthConfiguration
+				}
+			}
 		}
 	}
 
@@ -139,3 +155,66 @@ func (p *PluginConfiguration) getServiceConfiguration() (ServiceConfiguration, e
 
 	return serviceConfig, err
 }
+
+// getSwaggerAuthConfigurationFromEnv returns the swagger auth configuration provided in the OTF_VAR_<provider_name>_SWAGGER_HEADERS,
+// OTF_VAR_<provider_name>_SWAGGER_BEARER_TOKEN and OTF_VAR_<provider_name>_SWAGGER_TOKEN_COMMAND env variables. Nil is
+// returned if none of them are set. The headers are expected to be comma separated name=value pairs (e,g: X-API-Key=secret,X-Team=infra)
+// and the token command is parsed as described in parseSwaggerTokenCommand
+func (p *PluginConfiguration) getSwaggerAuthConfigurationFromEnv() (*ServiceSwaggerAuthConfigurationV1, error) {
+	headers, err := p.getEnvVar(otfVarSwaggerHeaders)
+	if err != nil {
+		return nil, err
+	}
+	bearerToken, err := p.getEnvVar(otfVarSwaggerBearerToken)
+	if err != nil {
+		return nil, err
+	}
+	tokenCommand, err := p.getEnvVar(otfVarSwaggerTokenCommand)
+	if err != nil {
+		return nil, err
+	}
+	if headers == "" && bearerToken == "" && tokenCommand == "" {
+		return nil, nil
+	}
+	swaggerAuthConfiguration := &ServiceSwaggerAuthConfigurationV1{BearerToken: bearerToken}
+	if tokenCommand != "" {
+		if swaggerAuthConfiguration.TokenCommand, err = parseSwaggerTokenCommand(tokenCommand); err != nil {
+			return nil, fmt.Errorf("%s env variable not valid, %s", fmt.Sprintf(otfVarSwaggerTokenCommand, p.ProviderName), err)
+		}
+	}
+	if headers != "" {
+		swaggerAuthConfiguration.Headers = map[string]string{}
+		for _, header := range strings.Split(headers, ",") {
+			nameValue := strings.SplitN(header, "=", 2)
+			if len(nameValue) != 2 || strings.TrimSpace(nameValue[0]) == "" {
+				return nil, fmt.Errorf("%s env variable not valid, headers must be provided as comma separated name=value pairs (e,g: X-API-Key=secret,X-Team=infra)", fmt.Sprintf(otfVarSwaggerHeaders, p.ProviderName))
+			}
+			swaggerAuthConfiguration.Headers[strings.TrimSpace(nameValue[0])] = strings.TrimSpace(nameValue[1])
+		}
+	}
+	log.Printf("[INFO] swagger auth configuration provided in the env variables")
+	return swaggerAuthConfiguration, nil
+}
+
+// parseSwaggerTokenCommand returns the command (and its arguments) contained in the given token command value. Commands
+// with arguments are expected to be provided as a JSON array of strings, same as the 'command' in the plugin configuration
+// file (e,g: ["vault", "read", "-field=token", "secret/swagger"]), so arguments containing white spaces are passed as is.
+// Otherwise, the value is the path of the command, which is executed without arguments
+func parseSwaggerTokenCommand(tokenCommand string) ([]string, error) {
+	tokenCommand = strings.TrimSpace(tokenCommand)
+	if !strings.HasPrefix(tokenCommand, "[") {
+		return []string{tokenCommand}, nil
+	}
+	var command []string
+	if err := json.Unmarshal([]byte(tokenCommand), &command); err != nil || len(command) == 0 || command[0] == "" {
+		return nil, fmt.Errorf(`the token command must be a JSON array of strings containing the command and its arguments (e,g: ["vault", "read", "-field=token", "secret/swagger"])`)
+	}
+	return command, nil
+}
+
+// getEnvVar returns the value of the given OTF_VAR_<provider_name>_* env variable, looking up both the lower and upper
+// case provider name versions
+func (p *PluginConfiguration) getEnvVar(envVarFormat string) (string, error) {
+	envVar := fmt.Sprintf(envVarFormat, p.ProviderName)
+	return terraformutils.MultiEnvDefaultString([]string{envVar, strings.ToUpper(envVar)}, "")
+}
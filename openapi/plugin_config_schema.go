@@ -86,3 +86,99 @@ func (p *PluginConfigSchemaV1) Marshal() ([]byte, error) {
 	out, err := yaml.Marshal(p)
 	return out, err
 }
+
+// PluginConfigSchemaV2 defines PluginConfigSchema version 2. On top of the version 1 configuration, the services support
+// configuring how the service API is connected to (TLS, proxy, request timeout, retry policy and static headers) as well
+// as the default timeouts of the resources. Version 1 plugin configuration files are still supported as is
+// Configuration example:
+// version: '2'
+// services:
+//   monitor:
+//     swagger-url: http://monitor-api.com/swagger.json
+//     tls:
+//       ca_bundle: /etc/ssl/company-ca.pem
+//       client_cert: /etc/ssl/monitor-client.pem
+//       client_key: /etc/ssl/monitor-client-key.pem
+//     proxy: http://proxy.company.com:3128
+//     request_timeout: 30s
+//     retry_policy:
+//       max_retries: 5
+//       max_wait: 1m
+//     headers:
+//       X-Team: infra
+//     resource_timeouts:
+//       create: 30m
+//   cdn:
+//     swagger-url: https://cdn-api.com/swagger.json
+type PluginConfigSchemaV2 struct {
+	Version  string                      `yaml:"version"`
+	Services map[string]*ServiceConfigV2 `yaml:"services"`
+}
+
+// NewPluginConfigSchemaV2 creates a new PluginConfigSchemaV2 that implements PluginConfigSchema interface
+func NewPluginConfigSchemaV2(services map[string]*ServiceConfigV2) *PluginConfigSchemaV2 {
+	return &PluginConfigSchemaV2{
+		Version:  "2",
+		Services: services,
+	}
+}
+
+// Validate makes sure that schema data is correct
+func (p *PluginConfigSchemaV2) Validate() error {
+	if p.Version != "2" {
+		return fmt.Errorf("provider configuration version not matching current implementation, please use version '2' of provider configuration specification")
+	}
+	return nil
+}
+
+// GetServiceConfig returns the configuration for the given provider name
+func (p *PluginConfigSchemaV2) GetServiceConfig(providerName string) (ServiceConfiguration, error) {
+	if providerName == "" {
+		return nil, fmt.Errorf("providerName not specified")
+	}
+	serviceConfig, exists := p.Services[providerName]
+	if !exists {
+		return nil, fmt.Errorf("'%s' not found in provider's services configuration", providerName)
+	}
+	return serviceConfig, nil
+}
+
+// GetVersion returns the plugin configuration version
+func (p *PluginConfigSchemaV2) GetVersion() (string, error) {
+	return p.Version, nil
+}
+
+// GetAllServiceConfigurations returns all the service configuration
+func (p *PluginConfigSchemaV2) GetAllServiceConfigurations() (ServiceConfigurations, error) {
+	serviceConfigurations := ServiceConfigurations{}
+	for k, v := range p.Services {
+		serviceConfigurations[k] = v
+	}
+	return serviceConfigurations, nil
+}
+
+// Marshal serializes the value provided into a YAML document
+func (p *PluginConfigSchemaV2) Marshal() ([]byte, error) {
+	out, err := yaml.Marshal(p)
+	return out, err
+}
+
+// unmarshalPluginConfigSchema returns the PluginConfigSchema matching the version of the given plugin configuration
+// document, so version 1 documents keep working as is. Documents with any other version are read using the latest
+// version of the schema, whose validation reports the version expected
+func unmarshalPluginConfigSchema(source []byte) (PluginConfigSchema, error) {
+	pluginConfigVersion := struct {
+		Version string `yaml:"version"`
+	}{}
+	if err := yaml.Unmarshal(source, &pluginConfigVersion); err != nil {
+		return nil, err
+	}
+	var pluginConfig PluginConfigSchema = &PluginConfigSchemaV2{}
+	if pluginConfigVersion.Version == "1" {
+		pluginConfig = &PluginConfigSchemaV1{}
+	}
+	if err := yaml.Unmarshal(source, pluginConfig); err != nil {
+		return nil, err
+	}
+	return pluginConfig, nil
+}
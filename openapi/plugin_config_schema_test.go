@@ -270,3 +270,204 @@ services:
 		})
 	})
 }
+
+func TestPluginConfigSchemaV2(t *testing.T) {
+	Convey("Given a map of services", t, func() {
+		services := map[string]*ServiceConfigV2{}
+		Convey("When NewPluginConfigSchemaV2 method is called", func() {
+			pluginConfigSchemaV2 := NewPluginConfigSchemaV2(services)
+			Convey("Then the pluginConfigSchemaV2 should comply with PluginConfigSchema interface", func() {
+				var _ PluginConfigSchema = pluginConfigSchemaV2
+			})
+			Convey("And the version should be 2", func() {
+				version, err := pluginConfigSchemaV2.GetVersion()
+				So(err, ShouldBeNil)
+				So(version, ShouldEqual, "2")
+			})
+		})
+	})
+}
+
+func TestPluginConfigSchemaV2Validate(t *testing.T) {
+	Convey("Given a PluginConfigSchemaV2 containing a version supported", t, func() {
+		pluginConfigSchema := NewPluginConfigSchemaV2(map[string]*ServiceConfigV2{})
+		Convey("When Validate method is called", func() {
+			err := pluginConfigSchema.Validate()
+			Convey("Then the error returned should be nil as configuration is correct", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a PluginConfigSchemaV2 containing a version that is NOT supported", t, func() {
+		pluginConfigSchema := &PluginConfigSchemaV2{Version: "3"}
+		Convey("When Validate method is called", func() {
+			err := pluginConfigSchema.Validate()
+			Convey("Then the error returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "provider configuration version not matching current implementation, please use version '2' of provider configuration specification")
+			})
+		})
+	})
+}
+
+func TestPluginConfigSchemaV2GetServiceConfig(t *testing.T) {
+	Convey("Given a PluginConfigSchemaV2 containing a service", t, func() {
+		serviceConfig := &ServiceConfigV2{
+			ServiceConfigV1: ServiceConfigV1{SwaggerURL: "http://sevice-api.com/swagger.yaml"},
+			RequestTimeout:  "30s",
+		}
+		pluginConfigSchema := NewPluginConfigSchemaV2(map[string]*ServiceConfigV2{"test": serviceConfig})
+		Convey("When GetServiceConfig method is called with the service name", func() {
+			serviceConfiguration, err := pluginConfigSchema.GetServiceConfig("test")
+			Convey("Then the service configuration returned should be the service one", func() {
+				So(err, ShouldBeNil)
+				So(serviceConfiguration, ShouldEqual, serviceConfig)
+			})
+		})
+		Convey("When GetServiceConfig method is called with a service name that does not exist", func() {
+			_, err := pluginConfigSchema.GetServiceConfig("non_existing")
+			Convey("Then the error returned should be", func() {
+				So(err.Error(), ShouldEqual, "'non_existing' not found in provider's services configuration")
+			})
+		})
+		Convey("When GetAllServiceConfigurations method is called", func() {
+			serviceConfigurations, err := pluginConfigSchema.GetAllServiceConfigurations()
+			Convey("Then the service configurations returned should contain the service", func() {
+				So(err, ShouldBeNil)
+				So(serviceConfigurations, ShouldResemble, ServiceConfigurations{"test": serviceConfig})
+			})
+		})
+	})
+}
+
+func TestPluginConfigSchemaV2Marshal(t *testing.T) {
+	Convey("Given a PluginConfigSchemaV2 containing a service with service connection configuration", t, func() {
+		pluginConfigSchema := NewPluginConfigSchemaV2(map[string]*ServiceConfigV2{
+			"test": {
+				ServiceConfigV1:  ServiceConfigV1{SwaggerURL: "http://sevice-api.com/swagger.yaml"},
+				TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: "/path/to/ca.pem"},
+				RequestTimeout:   "30s",
+				Headers:          map[string]string{"X-Team": "infra"},
+			},
+		})
+		Convey("When Marshal method is called", func() {
+			marshalConfig, err := pluginConfigSchema.Marshal()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the marshalConfig should contain the version 1 service configuration inlined along with the configured service connection configuration", func() {
+				So(string(marshalConfig), ShouldEqual, `version: "2"
+services:
+  test:
+    swagger-url: http://sevice-api.com/swagger.yaml
+    insecure_skip_verify: false
+    schema_configuration: []
+    tls:
+      ca_bundle: /path/to/ca.pem
+    request_timeout: 30s
+    headers:
+      X-Team: infra
+`)
+			})
+		})
+	})
+}
+
+func TestUnmarshalPluginConfigSchema(t *testing.T) {
+	Convey("Given a version 1 plugin configuration document", t, func() {
+		source := []byte(`version: '1'
+services:
+  test:
+    swagger-url: http://sevice-api.com/swagger.yaml`)
+		Convey("When unmarshalPluginConfigSchema is called", func() {
+			pluginConfigSchema, err := unmarshalPluginConfigSchema(source)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the plugin config schema returned should be a valid version 1 schema", func() {
+				So(pluginConfigSchema, ShouldHaveSameTypeAs, &PluginConfigSchemaV1{})
+				So(pluginConfigSchema.Validate(), ShouldBeNil)
+			})
+			Convey("And the service configuration should not support the version 2 configuration", func() {
+				serviceConfiguration, err := pluginConfigSchema.GetServiceConfig("test")
+				So(err, ShouldBeNil)
+				So(serviceConfiguration.GetSwaggerURL(), ShouldEqual, "http://sevice-api.com/swagger.yaml")
+				_, ok := serviceConfiguration.(ServiceConfigurationV2)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given a version 2 plugin configuration document", t, func() {
+		source := []byte(`version: '2'
+services:
+  test:
+    swagger-url: http://sevice-api.com/swagger.yaml
+    insecure_skip_verify: true
+    tls:
+      ca_bundle: /path/to/ca.pem
+      client_cert: /path/to/client.pem
+      client_key: /path/to/client-key.pem
+    proxy: http://proxy.company.com:3128
+    request_timeout: 30s
+    retry_policy:
+      max_retries: 0
+      max_wait: 1m
+    headers:
+      X-Team: infra
+    resource_timeouts:
+      create: 30m`)
+		Convey("When unmarshalPluginConfigSchema is called", func() {
+			pluginConfigSchema, err := unmarshalPluginConfigSchema(source)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the plugin config schema returned should be a valid version 2 schema", func() {
+				So(pluginConfigSchema, ShouldHaveSameTypeAs, &PluginConfigSchemaV2{})
+				So(pluginConfigSchema.Validate(), ShouldBeNil)
+			})
+			Convey("And the service configuration should contain the version 1 and version 2 configuration", func() {
+				serviceConfiguration, err := pluginConfigSchema.GetServiceConfig("test")
+				So(err, ShouldBeNil)
+				maxRetries := 0
+				So(serviceConfiguration, ShouldResemble, &ServiceConfigV2{
+					ServiceConfigV1: ServiceConfigV1{
+						SwaggerURL:         "http://sevice-api.com/swagger.yaml",
+						InsecureSkipVerify: true,
+					},
+					TLSConfiguration: &ServiceTLSConfigurationV2{
+						CABundle:   "/path/to/ca.pem",
+						ClientCert: "/path/to/client.pem",
+						ClientKey:  "/path/to/client-key.pem",
+					},
+					Proxy:                         "http://proxy.company.com:3128",
+					RequestTimeout:                "30s",
+					RetryPolicyConfiguration:      &ServiceRetryPolicyConfigurationV2{MaxRetries: &maxRetries, MaxWait: "1m"},
+					Headers:                       map[string]string{"X-Team": "infra"},
+					ResourceTimeoutsConfiguration: &ServiceResourceTimeoutsConfigurationV2{Create: "30m"},
+				})
+			})
+		})
+	})
+	Convey("Given a plugin configuration document with a version that is not supported", t, func() {
+		source := []byte(`version: '3'`)
+		Convey("When unmarshalPluginConfigSchema is called", func() {
+			pluginConfigSchema, err := unmarshalPluginConfigSchema(source)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the plugin config schema returned should be the latest version so the validation reports the version supported", func() {
+				So(pluginConfigSchema, ShouldHaveSameTypeAs, &PluginConfigSchemaV2{})
+				So(pluginConfigSchema.Validate(), ShouldNotBeNil)
+			})
+		})
+	})
+	Convey("Given a plugin configuration document that is not valid yaml", t, func() {
+		source := []byte(`	wrong yaml`)
+		Convey("When unmarshalPluginConfigSchema is called", func() {
+			_, err := unmarshalPluginConfigSchema(source)
+			Convey("Then the error returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
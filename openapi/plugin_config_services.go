@@ -1,9 +1,14 @@
 package openapi
 
 import (
+	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"github.com/asaskevich/govalidator"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 )
 
 // ServiceConfiguration defines the interface/expected behaviour for ServiceConfiguration implementations.
@@ -17,10 +22,38 @@ type ServiceConfiguration interface {
 	IsInsecureSkipVerifyEnabled() bool
 	// GetSchemaPropertyConfiguration returns the schema configuration for the given schemaPropertyName
 	GetSchemaPropertyConfiguration(schemaPropertyName string) ServiceSchemaPropertyConfiguration
+	// GetResourcePollConfiguration returns the poll configuration for the given resourceName
+	GetResourcePollConfiguration(resourceName string) *ServiceResourcePollConfigurationV1
 	// Validate makes sure the configuration is valid
 	Validate(runningPluginVersion string) error
 }
 
+// ServiceConfigurationV2 defines the interface/expected behaviour for the ServiceConfiguration implementations that support
+// the settings introduced in the plugin configuration version 2. These settings are reached with a type assertion on the
+// ServiceConfiguration, so implementations that do not support them are not required to implement this interface
+type ServiceConfigurationV2 interface {
+	// GetTLSConfiguration returns the TLS configuration used to connect to the service API
+	GetTLSConfiguration() *ServiceTLSConfigurationV2
+	// GetProxyURL returns the URL of the proxy used to connect to the service API
+	GetProxyURL() string
+	// GetRequestTimeout returns the max time to wait for the service API to respond to a request; zero means no timeout
+	GetRequestTimeout() time.Duration
+	// GetRetryPolicyConfiguration returns the retry policy used when requests fail due to transient errors
+	GetRetryPolicyConfiguration() *ServiceRetryPolicyConfigurationV2
+	// GetHeaders returns the static headers sent along with all the requests made to the service API
+	GetHeaders() map[string]string
+	// GetResourceTimeoutsConfiguration returns the default timeouts of the resources
+	GetResourceTimeoutsConfiguration() *ServiceResourceTimeoutsConfigurationV2
+}
+
+// ServiceSwaggerAuthConfiguration defines the interface/expected behaviour for the ServiceConfiguration implementations
+// that support sending credentials along with the request that retrieves the swagger file. As with ServiceConfigurationV2,
+// it is reached with a type assertion on the ServiceConfiguration
+type ServiceSwaggerAuthConfiguration interface {
+	// GetSwaggerAuthConfiguration returns the credentials sent along with the request that retrieves the swagger file
+	GetSwaggerAuthConfiguration() *ServiceSwaggerAuthConfigurationV1
+}
+
 // ServiceConfigV1 defines configuration for the service provider
 type ServiceConfigV1 struct {
 	// SwaggerURL defines where the swagger is located
@@ -32,6 +65,108 @@ type ServiceConfigV1 struct {
 	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
 	// SchemaConfigurationV1 represents the list of schema property configurations
 	SchemaConfigurationV1 []ServiceSchemaPropertyConfigurationV1 `yaml:"schema_configuration"`
+	// PollConfigurationV1 represents the list of resource poll configurations
+	PollConfigurationV1 []ServiceResourcePollConfigurationV1 `yaml:"poll_configuration,omitempty"`
+	// SwaggerAuthConfigurationV1 defines the credentials sent along with the request that retrieves the swagger file
+	SwaggerAuthConfigurationV1 *ServiceSwaggerAuthConfigurationV1 `yaml:"swagger_auth,omitempty"`
+}
+
+// ServiceSwaggerAuthConfigurationV1 defines the credentials sent along with the request that retrieves the swagger file.
+// The credentials are only sent to the host the swagger file is served from
+type ServiceSwaggerAuthConfigurationV1 struct {
+	// Headers defines headers sent along with the request (e,g: X-API-Key: secret)
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// BearerToken defines the token sent in the Authorization header (Authorization: Bearer <token>)
+	BearerToken string `yaml:"bearer_token,omitempty"`
+	// TokenCommand defines the command whose output (stdout) is used as bearer token. Leading and trailing white spaces
+	// are removed from the output
+	TokenCommand []string `yaml:"token_cmd,flow,omitempty"`
+	// TokenCommandTimeout defines the time in seconds the command is given to finish (default 10s)
+	TokenCommandTimeout int `yaml:"token_cmd_timeout,omitempty"`
+}
+
+// ServiceResourcePollConfigurationV1 defines the timings used when polling the given resource. The values configured
+// take precedence over the ones defined in the OpenAPI document
+type ServiceResourcePollConfigurationV1 struct {
+	// ResourceName defines the name of the resource as defined in the OpenAPI document (e,g: cdn_v1)
+	ResourceName string `yaml:"resource_name"`
+	// Delay defines the time to wait before polling for the first time (e,g: 30s)
+	Delay string `yaml:"delay"`
+	// Interval defines the time to wait between polls (e,g: 1m)
+	Interval string `yaml:"interval"`
+	// MaxInterval defines the maximum time to wait between polls when the backoff factor is configured (e,g: 5m)
+	MaxInterval string `yaml:"max_interval"`
+	// BackoffFactor defines the factor the interval is multiplied by after each poll (e,g: 2)
+	BackoffFactor float64 `yaml:"backoff_factor"`
+}
+
+// ServiceConfigV2 defines configuration for the service provider. On top of the version 1 configuration, it supports
+// configuring how the service API is connected to (TLS, proxy, request timeout, retry policy and static headers) as well
+// as the default timeouts of the resources
+type ServiceConfigV2 struct {
+	ServiceConfigV1 `yaml:",inline"`
+	// TLSConfiguration defines the certificates used to connect to the service API
+	TLSConfiguration *ServiceTLSConfigurationV2 `yaml:"tls,omitempty"`
+	// Proxy defines the URL of the proxy used to connect to the service API (e,g: http://proxy.company.com:3128). If not
+	// set, the proxy is read from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	Proxy string `yaml:"proxy,omitempty"`
+	// RequestTimeout defines the max time to wait for the service API to respond to a request (e,g: 30s)
+	RequestTimeout string `yaml:"request_timeout,omitempty"`
+	// RetryPolicyConfiguration defines how requests that failed due to transient errors are retried
+	RetryPolicyConfiguration *ServiceRetryPolicyConfigurationV2 `yaml:"retry_policy,omitempty"`
+	// Headers defines static headers sent along with all the requests made to the service API (e,g: X-Team: infra)
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// ResourceTimeoutsConfiguration defines the default timeouts of the resources
+	ResourceTimeoutsConfiguration *ServiceResourceTimeoutsConfigurationV2 `yaml:"resource_timeouts,omitempty"`
+}
+
+// ServiceTLSConfigurationV2 defines the certificates used to connect to the service API
+type ServiceTLSConfigurationV2 struct {
+	// CABundle defines the path to the PEM encoded CA certificates trusted (on top of the system ones) when verifying the
+	// service API server certificate
+	CABundle string `yaml:"ca_bundle,omitempty"`
+	// ClientCert defines the path to the PEM encoded client certificate presented to the service API (mutual TLS)
+	ClientCert string `yaml:"client_cert,omitempty"`
+	// ClientKey defines the path to the PEM encoded private key of the client certificate
+	ClientKey string `yaml:"client_key,omitempty"`
+	// MinVersion defines the minimum TLS version accepted when connecting to the service API (1.0, 1.1, 1.2 or 1.3)
+	MinVersion string `yaml:"min_version,omitempty"`
+	// PinnedPublicKeys defines the base64 encoded SHA-256 hashes of the public keys (SubjectPublicKeyInfo) the service API
+	// certificate chain is pinned to. If configured, connections are only established if one of the certificates in the
+	// chain has one of the pinned public keys
+	PinnedPublicKeys []string `yaml:"pinned_public_keys,omitempty"`
+}
+
+// tlsVersions contains the TLS versions supported in the ServiceTLSConfigurationV2 MinVersion field
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ServiceRetryPolicyConfigurationV2 defines the default values of the provider retry properties (max_retries and
+// retry_max_wait). The values provided by the user in the terraform configuration take precedence
+type ServiceRetryPolicyConfigurationV2 struct {
+	// MaxRetries defines the maximum number of times a request is retried. Zero disables the retries
+	MaxRetries *int `yaml:"max_retries,omitempty"`
+	// MaxWait defines the maximum time to wait between retries (e,g: 30s)
+	MaxWait string `yaml:"max_wait,omitempty"`
+}
+
+// ServiceResourceTimeoutsConfigurationV2 defines the default timeouts of the resources. The timeouts defined in the
+// OpenAPI document (x-terraform-resource-timeout) take precedence over these ones
+type ServiceResourceTimeoutsConfigurationV2 struct {
+	// Create defines the default timeout of the create operations (e,g: 30m)
+	Create string `yaml:"create,omitempty"`
+	// Read defines the default timeout of the read operations (e,g: 5m)
+	Read string `yaml:"read,omitempty"`
+	// Update defines the default timeout of the update operations (e,g: 30m)
+	Update string `yaml:"update,omitempty"`
+	// Delete defines the default timeout of the delete operations (e,g: 30m)
+	Delete string `yaml:"delete,omitempty"`
+	// Default defines the timeout of the operations that do not have a specific timeout (e,g: 10m)
+	Default string `yaml:"default,omitempty"`
 }
 
 // NewServiceConfigV1 creates a new instance of NewServiceConfigV1 struct with the values provided
@@ -70,8 +205,25 @@ func (s *ServiceConfigV1) GetSchemaPropertyConfiguration(schemaPropertyName stri
 	return nil
 }
 
+// GetResourcePollConfiguration returns the poll configuration for the given resource name; nil is returned if no such
+// resource is configured
+func (s *ServiceConfigV1) GetResourcePollConfiguration(resourceName string) *ServiceResourcePollConfigurationV1 {
+	for _, pollConfiguration := range s.PollConfigurationV1 {
+		if pollConfiguration.ResourceName == resourceName {
+			return &pollConfiguration
+		}
+	}
+	return nil
+}
+
+// GetSwaggerAuthConfiguration returns the credentials sent along with the request that retrieves the swagger file
+func (s *ServiceConfigV1) GetSwaggerAuthConfiguration() *ServiceSwaggerAuthConfigurationV1 {
+	return s.SwaggerAuthConfigurationV1
+}
+
 // Validate makes sure the configuration is valid:
 // - if the user has specified an OpenAPI plugin version, and if the plugin does not match the version then something is off
+// - the poll configurations must contain valid durations and backoff factors
 func (s *ServiceConfigV1) Validate(runningPluginVersion string) error {
 	if !govalidator.IsURL(s.SwaggerURL) {
 		// fall back to try to load the swagger file from disk in case the path provided is a path to a file on disk
@@ -84,6 +236,270 @@ func (s *ServiceConfigV1) Validate(runningPluginVersion string) error {
 			return fmt.Errorf("plugin version '%s' in the plugin configuration file does not match the version of the OpenAPI plugin that is running '%s'", s.PluginVersion, runningPluginVersion)
 		}
 	}
+	for _, pollConfiguration := range s.PollConfigurationV1 {
+		if _, err := pollConfiguration.getPollConfiguration(); err != nil {
+			return err
+		}
+	}
+	if s.SwaggerAuthConfigurationV1 != nil {
+		if err := s.SwaggerAuthConfigurationV1.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate makes sure the swagger auth configuration is valid
+func (s ServiceSwaggerAuthConfigurationV1) validate() error {
+	if s.BearerToken != "" && len(s.TokenCommand) > 0 {
+		return fmt.Errorf("swagger auth configuration not valid: 'bearer_token' and 'token_cmd' are mutually exclusive")
+	}
+	for name := range s.Headers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("swagger auth configuration not valid: header names must not be empty")
+		}
+	}
+	// zero means the token command is given the default timeout
+	if s.TokenCommandTimeout < 0 {
+		return fmt.Errorf("swagger auth configuration not valid: 'token_cmd_timeout' must not be negative")
+	}
+	return nil
+}
+
+// getHeaders returns the headers sent along with the request that retrieves the swagger file. If the token command is
+// configured, it is executed and its output is sent as bearer token
+func (s ServiceSwaggerAuthConfigurationV1) getHeaders() (map[string]string, error) {
+	headers := map[string]string{}
+	for name, value := range s.Headers {
+		headers[name] = value
+	}
+	token := s.BearerToken
+	if len(s.TokenCommand) > 0 {
+		tokenCommand := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "swagger_auth",
+			Command:            s.TokenCommand,
+			CommandTimeout:     s.TokenCommandTimeout,
+			CommandOutput:      ServiceSchemaPropertyCommandOutputV1{ContentType: "raw"},
+		}
+		var err error
+		if token, err = tokenCommand.GetDefaultValue(); err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return nil, fmt.Errorf("swagger auth 'token_cmd' command '%s' did not output any token", s.TokenCommand)
+		}
+	}
+	if token != "" {
+		headers[authorizationHeader] = fmt.Sprintf("%s %s", bearerScheme, token)
+	}
+	return headers, nil
+}
+
+// getPollConfiguration returns the specPollConfiguration containing the configured values. An error is returned if any
+// of the values is not valid
+func (s ServiceResourcePollConfigurationV1) getPollConfiguration() (specPollConfiguration, error) {
+	pollConfiguration := specPollConfiguration{}
+	var err error
+	if s.Delay != "" {
+		if pollConfiguration.delay, err = parsePollDuration(s.Delay); err != nil {
+			return pollConfiguration, fmt.Errorf("resource '%s' poll configuration 'delay' is not valid: %s", s.ResourceName, err)
+		}
+	}
+	if s.Interval != "" {
+		if pollConfiguration.interval, err = parsePollDuration(s.Interval); err != nil {
+			return pollConfiguration, fmt.Errorf("resource '%s' poll configuration 'interval' is not valid: %s", s.ResourceName, err)
+		}
+	}
+	if s.MaxInterval != "" {
+		if pollConfiguration.maxInterval, err = parsePollDuration(s.MaxInterval); err != nil {
+			return pollConfiguration, fmt.Errorf("resource '%s' poll configuration 'max_interval' is not valid: %s", s.ResourceName, err)
+		}
+	}
+	if s.BackoffFactor != 0 {
+		if pollConfiguration.backoffFactor, err = parsePollBackoffFactor(s.BackoffFactor); err != nil {
+			return pollConfiguration, fmt.Errorf("resource '%s' poll configuration 'backoff_factor' is not valid: %s", s.ResourceName, err)
+		}
+	}
+	return pollConfiguration, nil
+}
+
+// GetTLSConfiguration returns the TLS configuration used to connect to the service API; nil is returned if not configured
+func (s *ServiceConfigV2) GetTLSConfiguration() *ServiceTLSConfigurationV2 {
+	return s.TLSConfiguration
+}
+
+// GetProxyURL returns the URL of the proxy used to connect to the service API; empty string is returned if not configured
+func (s *ServiceConfigV2) GetProxyURL() string {
+	return s.Proxy
+}
+
+// GetRequestTimeout returns the max time to wait for the service API to respond to a request; zero is returned if not
+// configured
+func (s *ServiceConfigV2) GetRequestTimeout() time.Duration {
+	requestTimeout, _ := time.ParseDuration(s.RequestTimeout)
+	return requestTimeout
+}
+
+// GetRetryPolicyConfiguration returns the retry policy configuration; nil is returned if not configured
+func (s *ServiceConfigV2) GetRetryPolicyConfiguration() *ServiceRetryPolicyConfigurationV2 {
+	return s.RetryPolicyConfiguration
+}
+
+// GetHeaders returns the static headers sent along with all the requests made to the service API
+func (s *ServiceConfigV2) GetHeaders() map[string]string {
+	return s.Headers
+}
+
+// GetResourceTimeoutsConfiguration returns the default timeouts of the resources; nil is returned if not configured
+func (s *ServiceConfigV2) GetResourceTimeoutsConfiguration() *ServiceResourceTimeoutsConfigurationV2 {
+	return s.ResourceTimeoutsConfiguration
+}
+
+// Validate makes sure the configuration is valid. On top of the version 1 validations:
+// - the TLS configuration files must exist, the client certificate and key must be configured together and the min
+// version and pinned public keys must be valid
+// - the proxy must be a valid URL
+// - the request timeout, retry policy and resource timeouts must contain valid values
+func (s *ServiceConfigV2) Validate(runningPluginVersion string) error {
+	if err := s.ServiceConfigV1.Validate(runningPluginVersion); err != nil {
+		return err
+	}
+	if s.TLSConfiguration != nil {
+		if err := s.TLSConfiguration.validate(); err != nil {
+			return err
+		}
+	}
+	if s.Proxy != "" {
+		if proxyURL, err := url.Parse(s.Proxy); err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return fmt.Errorf("service proxy configuration not valid ('%s'), proxy must be a valid formed URL (e,g: http://proxy.company.com:3128)", s.Proxy)
+		}
+	}
+	if s.RequestTimeout != "" {
+		if _, err := parsePositiveDuration(s.RequestTimeout); err != nil {
+			return fmt.Errorf("service request_timeout configuration not valid: %s", err)
+		}
+	}
+	if s.RetryPolicyConfiguration != nil {
+		if err := s.RetryPolicyConfiguration.validate(); err != nil {
+			return err
+		}
+	}
+	for headerName := range s.Headers {
+		if headerName == "" {
+			return fmt.Errorf("service headers configuration not valid, header names can not be empty")
+		}
+	}
+	if s.ResourceTimeoutsConfiguration != nil {
+		if _, _, err := s.ResourceTimeoutsConfiguration.getTimeouts(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t ServiceTLSConfigurationV2) validate() error {
+	if (t.ClientCert == "") != (t.ClientKey == "") {
+		return fmt.Errorf("service tls configuration not valid, 'client_cert' and 'client_key' must be configured together")
+	}
+	for _, file := range []string{t.CABundle, t.ClientCert, t.ClientKey} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("service tls configuration not valid, file '%s' can not be read: %s", file, err)
+		}
+	}
+	if _, err := t.getMinVersion(); err != nil {
+		return fmt.Errorf("service tls configuration not valid: %s", err)
+	}
+	if _, err := t.getPinnedPublicKeys(); err != nil {
+		return fmt.Errorf("service tls configuration not valid: %s", err)
+	}
+	return nil
+}
+
+// getMinVersion returns the tls package value of the minimum TLS version configured; zero is returned if not configured
+// so the tls package default applies
+func (t ServiceTLSConfigurationV2) getMinVersion() (uint16, error) {
+	if t.MinVersion == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[t.MinVersion]
+	if !ok {
+		return 0, fmt.Errorf("'min_version' value '%s' not supported, supported values are [1.0, 1.1, 1.2, 1.3]", t.MinVersion)
+	}
+	return version, nil
+}
 
+// getPinnedPublicKeys returns the decoded SHA-256 hashes of the pinned public keys
+func (t ServiceTLSConfigurationV2) getPinnedPublicKeys() ([][]byte, error) {
+	var pins [][]byte
+	for _, pinnedPublicKey := range t.PinnedPublicKeys {
+		pin, err := base64.StdEncoding.DecodeString(pinnedPublicKey)
+		if err != nil || len(pin) != 32 {
+			return nil, fmt.Errorf("'pinned_public_keys' value '%s' is not a base64 encoded SHA-256 hash", pinnedPublicKey)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+func (r ServiceRetryPolicyConfigurationV2) validate() error {
+	if r.MaxRetries != nil && *r.MaxRetries < 0 {
+		return fmt.Errorf("service retry_policy configuration not valid, 'max_retries' must be greater or equal to 0")
+	}
+	if r.MaxWait != "" {
+		if _, err := parsePositiveDuration(r.MaxWait); err != nil {
+			return fmt.Errorf("service retry_policy configuration 'max_wait' not valid: %s", err)
+		}
+	}
 	return nil
 }
+
+// getTimeouts returns the specTimeouts containing the configured operation timeouts (nil if not configured) along with
+// the default timeout (nil if not configured). An error is returned if any of the values is not valid
+func (r ServiceResourceTimeoutsConfigurationV2) getTimeouts() (*specTimeouts, *time.Duration, error) {
+	timeouts := &specTimeouts{}
+	var defaultTimeout *time.Duration
+	var err error
+	if timeouts.Post, err = r.parseTimeout("create", r.Create); err != nil {
+		return nil, nil, err
+	}
+	if timeouts.Get, err = r.parseTimeout("read", r.Read); err != nil {
+		return nil, nil, err
+	}
+	if timeouts.Put, err = r.parseTimeout("update", r.Update); err != nil {
+		return nil, nil, err
+	}
+	if timeouts.Delete, err = r.parseTimeout("delete", r.Delete); err != nil {
+		return nil, nil, err
+	}
+	if defaultTimeout, err = r.parseTimeout("default", r.Default); err != nil {
+		return nil, nil, err
+	}
+	return timeouts, defaultTimeout, nil
+}
+
+func (r ServiceResourceTimeoutsConfigurationV2) parseTimeout(name, value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+	timeout, err := parsePositiveDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("service resource_timeouts configuration '%s' not valid: %s", name, err)
+	}
+	return &timeout, nil
+}
+
+// parsePositiveDuration parses the given duration (e,g: 30s) making sure it is greater than zero
+func parsePositiveDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value '%s': %s", value, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("invalid duration value '%s': duration must be greater than zero", value)
+	}
+	return duration, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/oliveagle/jsonpath"
 	"log"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -27,9 +28,19 @@ type ServiceSchemaPropertyConfigurationV1 struct {
 	DefaultValue          string                                       `yaml:"default_value"`
 	Command               []string                                     `yaml:"cmd,flow"`
 	CommandTimeout        int                                          `yaml:"cmd_timeout"`
+	CommandOutput         ServiceSchemaPropertyCommandOutputV1         `yaml:"cmd_output,omitempty"`
 	ExternalConfiguration ServiceSchemaPropertyExternalConfigurationV1 `yaml:"schema_property_external_configuration"`
 }
 
+// ServiceSchemaPropertyCommandOutputV1 defines how the output (stdout) of the command is used as the value of a provider
+// property. If neither the ContentType nor the KeyName are set, the command output is not used as value
+type ServiceSchemaPropertyCommandOutputV1 struct {
+	// KeyName defines the specific key to look for within the command output (only when json content type)
+	KeyName string `yaml:"key_name"`
+	// ContentType defines the type of content the command outputs. If not set, json is assumed when KeyName is set
+	ContentType string `yaml:"content_type"` // Currently supported types: raw, json
+}
+
 // ServiceSchemaPropertyExternalConfigurationV1 defines the external configuration for a provider property.
 type ServiceSchemaPropertyExternalConfigurationV1 struct {
 	// File defines the file containing the value of the schema property
@@ -42,6 +53,9 @@ type ServiceSchemaPropertyExternalConfigurationV1 struct {
 
 // GetDefaultValue returns the default value for the schema property configuration. The following logic defines the preference
 // when deciding what should be the default value of the property:
+// - if the property has the command output configuration ('cmd_output') then the command is executed and:
+//    - If the 'content_type' is raw the command output (with leading and trailing white spaces removed) will be used as default value
+//    - If the 'content_type' is json then the command output must be json structure and the default value used will be the one defined in the 'key_name'
 // - if the property does not have external configuration ('schema_property_external_configuration') and it does have a 'default_value' is set, then value used will be the one specified in the 'default_value' field
 // - if the property has both the external configuration ('schema_property_external_configuration') and the 'default_value' fields set:
 //    - If 'file' field is populated then:
@@ -49,6 +63,9 @@ type ServiceSchemaPropertyExternalConfigurationV1 struct {
 //      - If the 'content_type' is json then the content of the 'file' must be json structure and the default value used will be the one defined in the 'key_name'
 //    - An error is thrown otherwise
 func (s ServiceSchemaPropertyConfigurationV1) GetDefaultValue() (string, error) {
+	if s.CommandOutput.isEnabled() {
+		return s.getCommandOutputValue()
+	}
 	if &s.ExternalConfiguration != nil {
 		if s.ExternalConfiguration.File != "" {
 			log.Printf("[DEBUG] provider schema property '%s' configured to use as default value [ContentType=%s; File=%s, KeyName=%s]", s.SchemaPropertyName, s.ExternalConfiguration.ContentType, s.ExternalConfiguration.File, s.ExternalConfiguration.KeyName)
@@ -67,11 +84,15 @@ func (s ServiceSchemaPropertyConfigurationV1) GetDefaultValue() (string, error)
 }
 
 // ExecuteCommand run the 'Command' configured in the ServiceSchemaPropertyConfigurationV1 struct if applicable.
+// - If the command output is used as value ('cmd_output'), the command is not executed here but when GetDefaultValue is called
 // - If the command fails to execute the appropriate error will be returned including the error returned by exec
 // - If the command execution does not finish within the expected time (either before CommandTimeout or before the default timeout 10s)
 // a timeout error will be returned
 // - Otherwise, a nil error will be returned should the command executes successfully with a clean exit code
 func (s ServiceSchemaPropertyConfigurationV1) ExecuteCommand() error {
+	if s.CommandOutput.isEnabled() {
+		return nil
+	}
 	doneChan := make(chan error)
 	// execute the command in a routine and wait for completion
 	go s.exec(doneChan)
@@ -82,7 +103,34 @@ func (s ServiceSchemaPropertyConfigurationV1) ExecuteCommand() error {
 	return nil
 }
 
+// getCommandOutputValue executes the command and returns the value read from its output as configured in 'cmd_output'
+func (s ServiceSchemaPropertyConfigurationV1) getCommandOutputValue() (string, error) {
+	if len(s.Command) == 0 {
+		return "", fmt.Errorf("provider schema property '%s' is configured to use the command output as value but the command is missing", s.SchemaPropertyName)
+	}
+	output, err := s.run()
+	if err != nil {
+		return "", err
+	}
+	parser, err := newSchemaValueParser(s.CommandOutput.ContentType, strings.TrimSpace(output), s.CommandOutput.KeyName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the output of '%s' command '%s': %s", s.SchemaPropertyName, s.Command, err)
+	}
+	value, err := parser.getValue()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the output of '%s' command '%s': %s", s.SchemaPropertyName, s.Command, err)
+	}
+	return value, nil
+}
+
 func (s ServiceSchemaPropertyConfigurationV1) exec(doneChan chan error) {
+	_, err := s.run()
+	doneChan <- err
+}
+
+// run executes the command (if any) and returns its output (stdout). The output is only logged if it is not used as the
+// value of the property, as it might contain secrets
+func (s ServiceSchemaPropertyConfigurationV1) run() (string, error) {
 	if len(s.Command) > 0 {
 		start := time.Now()
 		log.Printf("[INFO] executing '%s' command '%s'", s.SchemaPropertyName, s.Command)
@@ -109,18 +157,25 @@ func (s ServiceSchemaPropertyConfigurationV1) exec(doneChan chan error) {
 		// We want to check the context error to see if the timeout was executed. The error returned by cmd.Output() will be OS specific based on what
 		// happens when a process is killed.
 		if ctx.Err() == context.DeadlineExceeded {
-			doneChan <- fmt.Errorf("command '%s' did not finish executing within the expected time %ds (%s)", s.Command, timeout, err)
-			return
+			return "", fmt.Errorf("command '%s' did not finish executing within the expected time %ds (%s)", s.Command, timeout, err)
 		}
 
 		// If there's no context error, we know the command completed (or errored).
 		if err != nil {
-			doneChan <- fmt.Errorf("failed to execute '%s' command '%s': %s(%s)", s.SchemaPropertyName, s.Command, stderr.String(), err)
-			return
+			return "", fmt.Errorf("failed to execute '%s' command '%s': %s(%s)", s.SchemaPropertyName, s.Command, stderr.String(), err)
+		}
+		if s.CommandOutput.isEnabled() {
+			log.Printf("[INFO] provider schema property '%s' command '%s' executed successfully (time:%s), the output is used as value", s.SchemaPropertyName, s.Command, time.Since(start))
+		} else {
+			log.Printf("[INFO] provider schema property '%s' command '%s' executed successfully (time:%s): %s", s.SchemaPropertyName, s.Command, time.Since(start), stdout.String())
 		}
-		log.Printf("[INFO] provider schema property '%s' command '%s' executed successfully (time:%s): %s", s.SchemaPropertyName, s.Command, time.Since(start), stdout.String())
+		return stdout.String(), nil
 	}
-	doneChan <- nil
+	return "", nil
+}
+
+func (c ServiceSchemaPropertyCommandOutputV1) isEnabled() bool {
+	return c.ContentType != "" || c.KeyName != ""
 }
 
 func (c ServiceSchemaPropertyExternalConfigurationV1) getFileParser() (schemaFileParser, error) {
@@ -128,16 +183,25 @@ func (c ServiceSchemaPropertyExternalConfigurationV1) getFileParser() (schemaFil
 	if err != nil {
 		return nil, err
 	}
-	switch c.ContentType {
+	return newSchemaValueParser(c.ContentType, schemaFileContent, c.KeyName)
+}
+
+// newSchemaValueParser returns the parser for the given content type. If the content type is not set but a key name is
+// provided, the content is considered json
+func newSchemaValueParser(contentType, content, keyName string) (schemaFileParser, error) {
+	if contentType == "" && keyName != "" {
+		contentType = "json"
+	}
+	switch contentType {
 	case "raw":
-		if c.KeyName != "" {
-			log.Printf("[WARN] service external configuration of type 'raw' configured with key value '%s'", c.KeyName)
+		if keyName != "" {
+			log.Printf("[WARN] service external configuration of type 'raw' configured with key value '%s'", keyName)
 		}
-		return parserRaw{content: schemaFileContent}, nil
+		return parserRaw{content: content}, nil
 	case "json":
-		return parserJSON{jsonContent: schemaFileContent, keyName: c.KeyName}, nil
+		return parserJSON{jsonContent: content, keyName: keyName}, nil
 	default:
-		return nil, fmt.Errorf("'%s' content type not supported", c.ContentType)
+		return nil, fmt.Errorf("'%s' content type not supported", contentType)
 	}
 }
 
@@ -160,10 +224,16 @@ type parserJSON struct {
 
 func (p parserJSON) getValue() (string, error) {
 	var jsonData interface{}
-	json.Unmarshal([]byte(p.jsonContent), &jsonData)
+	if err := json.Unmarshal([]byte(p.jsonContent), &jsonData); err != nil {
+		return "", fmt.Errorf("content is not valid json: %s", err)
+	}
 	res, err := jsonpath.JsonPathLookup(jsonData, p.keyName)
 	if err != nil {
 		return "", err
 	}
-	return res.(string), nil
+	value, isString := res.(string)
+	if !isString {
+		return "", fmt.Errorf("value of key '%s' is not a string", p.keyName)
+	}
+	return value, nil
 }
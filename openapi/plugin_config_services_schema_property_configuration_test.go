@@ -76,6 +76,24 @@ func TestServiceSchemaConfigurationV1ExecuteCommand(t *testing.T) {
 	})
 }
 
+func TestServiceSchemaConfigurationV1ExecuteCommandWithCommandOutput(t *testing.T) {
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command (that exists with error) configured and the command output used as value", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "some_property_name",
+			Command:            []string{"cat", "nonexistingfile"},
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				ContentType: "raw",
+			},
+		}
+		Convey("When ExecuteCommand method is called", func() {
+			err := serviceSchemaConfigurationV1.ExecuteCommand()
+			Convey("Then the err returned should be nil as the command is executed when the default value is read", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
 func TestServiceSchemaConfigurationV1Exec(t *testing.T) {
 	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command (that exists successfully) configured and a channel", t, func() {
 		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
@@ -221,6 +239,125 @@ func TestServiceSchemaConfigurationV1GetDefaultValue(t *testing.T) {
 	})
 }
 
+func TestServiceSchemaConfigurationV1GetDefaultValueFromCommandOutput(t *testing.T) {
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a default value, external config and a command with 'raw' output configuration", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "schemaPropertyName",
+			DefaultValue:       "defaultValue",
+			Command:            []string{"echo", "  some secret  "},
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				ContentType: "raw",
+			},
+			ExternalConfiguration: ServiceSchemaPropertyExternalConfigurationV1{
+				ContentType: "raw",
+				File:        "/path/to/credentials",
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			value, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the value returned should be the command output without the leading and trailing white spaces", func() {
+				So(value, ShouldEqual, "some secret")
+			})
+		})
+	})
+
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command with 'json' output configuration", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "schemaPropertyName",
+			Command:            []string{"echo", `{"data":{"token":"some token"}}`},
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				ContentType: "json",
+				KeyName:     "$.data.token",
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			value, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the value returned should be the value of the key in the command output", func() {
+				So(value, ShouldEqual, "some token")
+			})
+		})
+	})
+
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command with output configuration that only has the key name", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "schemaPropertyName",
+			Command:            []string{"echo", `{"token":"some token"}`},
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				KeyName: "$.token",
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			value, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the command output should be considered json", func() {
+				So(value, ShouldEqual, "some token")
+			})
+		})
+	})
+
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command with 'json' output configuration that outputs non json content", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "schemaPropertyName",
+			Command:            []string{"echo", "some secret"},
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				ContentType: "json",
+				KeyName:     "$.token",
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			_, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
+			})
+			Convey("And the err message should not expose the command output", func() {
+				So(err.Error(), ShouldStartWith, "failed to read the output of 'schemaPropertyName' command '[echo some secret]': content is not valid json")
+			})
+		})
+	})
+
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with a command (that exists with error) and output configuration", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "schemaPropertyName",
+			DefaultValue:       "defaultValue",
+			Command:            []string{"cat", "nonexistingfile"},
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				ContentType: "raw",
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			_, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should be the command execution error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to execute 'schemaPropertyName' command '[cat nonexistingfile]'")
+			})
+		})
+	})
+
+	Convey("Given a ServiceSchemaPropertyConfigurationV1 with output configuration but no command", t, func() {
+		serviceSchemaConfigurationV1 := ServiceSchemaPropertyConfigurationV1{
+			SchemaPropertyName: "schemaPropertyName",
+			CommandOutput: ServiceSchemaPropertyCommandOutputV1{
+				ContentType: "raw",
+			},
+		}
+		Convey("When GetDefaultValue method is called", func() {
+			_, err := serviceSchemaConfigurationV1.GetDefaultValue()
+			Convey("Then the err returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "provider schema property 'schemaPropertyName' is configured to use the command output as value but the command is missing")
+			})
+		})
+	})
+}
+
 func TestServiceExternalConfigurationV1GetFileParser(t *testing.T) {
 	Convey("Given a ServiceSchemaPropertyExternalConfigurationV1 configured with 'raw' content", t, func() {
 		expectedValue := "some content"
@@ -383,4 +520,32 @@ func TestSchemaFileParserJsonGetValue(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a jsonContent and a key name which value is not a string", t, func() {
+		parserJSON := parserJSON{
+			jsonContent: `{"user":{"firstName":"someName"}}`,
+			keyName:     "$.user",
+		}
+		Convey("When getValue method is called", func() {
+			_, err := parserJSON.getValue()
+			Convey("Then the error returned should be", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "value of key '$.user' is not a string")
+			})
+		})
+	})
+
+	Convey("Given a content that is not json", t, func() {
+		parserJSON := parserJSON{
+			jsonContent: "someName",
+			keyName:     "$.firstName",
+		}
+		Convey("When getValue method is called", func() {
+			_, err := parserJSON.getValue()
+			Convey("Then the error returned should NOT be nil", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "content is not valid json")
+			})
+		})
+	})
 }
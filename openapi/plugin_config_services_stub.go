@@ -1,13 +1,24 @@
 package openapi
 
-// ServiceConfigStub implements the ServiceConfiguration interface and can be used to simplify the creation of the ProviderOpenAPI
-// provider by calling the CreateSchemaProviderWithConfiguration function passing in the stub wit the swagger URL populated
-// with the URL where the openapi doc is hosted.
+import "time"
+
+// ServiceConfigStub implements the ServiceConfiguration, ServiceConfigurationV2 and ServiceSwaggerAuthConfiguration
+// interfaces and can be used to simplify the creation of the ProviderOpenAPI provider by calling the
+// CreateSchemaProviderWithConfiguration function passing in the stub wit the swagger URL populated with the URL where the
+// openapi doc is hosted.
 type ServiceConfigStub struct {
 	SwaggerURL          string
 	PluginVersion       string
 	InsecureSkipVerify  bool
 	SchemaConfiguration []*ServiceSchemaPropertyConfigurationStub
+	PollConfiguration   []*ServiceResourcePollConfigurationV1
+	TLSConfiguration    *ServiceTLSConfigurationV2
+	ProxyURL            string
+	RequestTimeout      time.Duration
+	RetryPolicy         *ServiceRetryPolicyConfigurationV2
+	Headers             map[string]string
+	ResourceTimeouts    *ServiceResourceTimeoutsConfigurationV2
+	SwaggerAuth         *ServiceSwaggerAuthConfigurationV1
 	Err                 error
 }
 
@@ -50,6 +61,51 @@ func (s ServiceConfigStub) GetSchemaPropertyConfiguration(schemaPropertyName str
 	return nil
 }
 
+// GetResourcePollConfiguration returns the poll configuration set in the ServiceConfigStub.PollConfiguration field
+func (s ServiceConfigStub) GetResourcePollConfiguration(resourceName string) *ServiceResourcePollConfigurationV1 {
+	for _, p := range s.PollConfiguration {
+		if p.ResourceName == resourceName {
+			return p
+		}
+	}
+	return nil
+}
+
+// GetTLSConfiguration returns the TLS configuration set in the ServiceConfigStub.TLSConfiguration field
+func (s *ServiceConfigStub) GetTLSConfiguration() *ServiceTLSConfigurationV2 {
+	return s.TLSConfiguration
+}
+
+// GetProxyURL returns the proxy URL set in the ServiceConfigStub.ProxyURL field
+func (s *ServiceConfigStub) GetProxyURL() string {
+	return s.ProxyURL
+}
+
+// GetRequestTimeout returns the request timeout set in the ServiceConfigStub.RequestTimeout field
+func (s *ServiceConfigStub) GetRequestTimeout() time.Duration {
+	return s.RequestTimeout
+}
+
+// GetRetryPolicyConfiguration returns the retry policy set in the ServiceConfigStub.RetryPolicy field
+func (s *ServiceConfigStub) GetRetryPolicyConfiguration() *ServiceRetryPolicyConfigurationV2 {
+	return s.RetryPolicy
+}
+
+// GetHeaders returns the headers set in the ServiceConfigStub.Headers field
+func (s *ServiceConfigStub) GetHeaders() map[string]string {
+	return s.Headers
+}
+
+// GetResourceTimeoutsConfiguration returns the resource timeouts set in the ServiceConfigStub.ResourceTimeouts field
+func (s *ServiceConfigStub) GetResourceTimeoutsConfiguration() *ServiceResourceTimeoutsConfigurationV2 {
+	return s.ResourceTimeouts
+}
+
+// GetSwaggerAuthConfiguration returns the swagger auth configuration set in the ServiceConfigStub.SwaggerAuth field
+func (s *ServiceConfigStub) GetSwaggerAuthConfiguration() *ServiceSwaggerAuthConfigurationV1 {
+	return s.SwaggerAuth
+}
+
 // GetDefaultValue returns the dafult value configured in the ServiceSchemaPropertyConfigurationStub.defaultValue field
 func (s *ServiceSchemaPropertyConfigurationStub) GetDefaultValue() (string, error) {
 	return s.DefaultValue, nil
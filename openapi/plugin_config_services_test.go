@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestNewServiceConfigV1(t *testing.T) {
@@ -65,6 +66,36 @@ func TestServiceConfigV1IsSecureSkipVerifyEnabled(t *testing.T) {
 	})
 }
 
+func TestServiceConfigV1GetResourcePollConfiguration(t *testing.T) {
+	Convey("Given a ServiceConfigV1 containing a poll configuration for a resource", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			PollConfigurationV1: []ServiceResourcePollConfigurationV1{
+				{ResourceName: "cdn_v1", Delay: "1m", Interval: "30s", MaxInterval: "5m", BackoffFactor: 2},
+			},
+		}
+		Convey("When GetResourcePollConfiguration method is called with the configured resource name", func() {
+			pollConfiguration := serviceConfiguration.GetResourcePollConfiguration("cdn_v1")
+			Convey("Then the poll configuration returned should be the configured one", func() {
+				So(pollConfiguration, ShouldResemble, &ServiceResourcePollConfigurationV1{ResourceName: "cdn_v1", Delay: "1m", Interval: "30s", MaxInterval: "5m", BackoffFactor: 2})
+			})
+			Convey("And the spec poll configuration should contain the parsed values", func() {
+				specPollConfiguration, err := pollConfiguration.getPollConfiguration()
+				So(err, ShouldBeNil)
+				So(specPollConfiguration.delay, ShouldEqual, time.Minute)
+				So(specPollConfiguration.interval, ShouldEqual, 30*time.Second)
+				So(specPollConfiguration.maxInterval, ShouldEqual, 5*time.Minute)
+				So(specPollConfiguration.backoffFactor, ShouldEqual, 2)
+			})
+		})
+		Convey("When GetResourcePollConfiguration method is called with a resource name that is not configured", func() {
+			pollConfiguration := serviceConfiguration.GetResourcePollConfiguration("non_configured")
+			Convey("Then the poll configuration returned should be nil", func() {
+				So(pollConfiguration, ShouldBeNil)
+			})
+		})
+	})
+}
+
 func TestServiceConfigV1Validate(t *testing.T) {
 	Convey("Given a ServiceConfigV1 containing a valid swagger URL and a specific plugin version", t, func() {
 		var serviceConfiguration ServiceConfiguration
@@ -143,4 +174,328 @@ func TestServiceConfigV1Validate(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a ServiceConfigV1 containing a poll configuration with an invalid interval", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SwaggerURL: "http://sevice-api.com/swagger.yaml",
+			PollConfigurationV1: []ServiceResourcePollConfigurationV1{
+				{ResourceName: "cdn_v1", Interval: "often"},
+			},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldStartWith, "resource 'cdn_v1' poll configuration 'interval' is not valid: invalid duration value 'often'")
+			})
+		})
+	})
+	Convey("Given a ServiceConfigV1 containing a poll configuration with a backoff factor lower than 1", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SwaggerURL: "http://sevice-api.com/swagger.yaml",
+			PollConfigurationV1: []ServiceResourcePollConfigurationV1{
+				{ResourceName: "cdn_v1", BackoffFactor: 0.5},
+			},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "resource 'cdn_v1' poll configuration 'backoff_factor' is not valid: invalid backoff factor value '0.5': value must be greater or equal to 1")
+			})
+		})
+	})
+}
+
+func TestServiceConfigV1ServiceConnectionConfiguration(t *testing.T) {
+	Convey("Given a ServiceConfigV1", t, func() {
+		var serviceConfiguration ServiceConfiguration = NewServiceConfigV1("http://sevice-api.com/swagger.yaml", false)
+		Convey("When the ServiceConfigV1 is asserted to be a ServiceConfigurationV2", func() {
+			_, ok := serviceConfiguration.(ServiceConfigurationV2)
+			Convey("Then the assertion should fail as version 1 does not support the service connection configuration", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+		Convey("When the ServiceConfigV1 is asserted to be a ServiceSwaggerAuthConfiguration", func() {
+			_, ok := serviceConfiguration.(ServiceSwaggerAuthConfiguration)
+			Convey("Then the assertion should succeed as version 1 supports the swagger auth configuration", func() {
+				So(ok, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestServiceConfigV2(t *testing.T) {
+	Convey("Given a ServiceConfigV2 containing the version 1 configuration as well as the service connection configuration", t, func() {
+		maxRetries := 5
+		serviceConfiguration := &ServiceConfigV2{
+			ServiceConfigV1: ServiceConfigV1{
+				SwaggerURL:         "http://sevice-api.com/swagger.yaml",
+				InsecureSkipVerify: true,
+				PollConfigurationV1: []ServiceResourcePollConfigurationV1{
+					{ResourceName: "cdn_v1", Delay: "1m"},
+				},
+			},
+			TLSConfiguration:              &ServiceTLSConfigurationV2{CABundle: "/path/to/ca.pem"},
+			Proxy:                         "http://proxy.company.com:3128",
+			RequestTimeout:                "30s",
+			RetryPolicyConfiguration:      &ServiceRetryPolicyConfigurationV2{MaxRetries: &maxRetries, MaxWait: "1m"},
+			Headers:                       map[string]string{"X-Team": "infra"},
+			ResourceTimeoutsConfiguration: &ServiceResourceTimeoutsConfigurationV2{Create: "30m"},
+		}
+		Convey("When the ServiceConfigV2 is used as a ServiceConfiguration", func() {
+			var _ ServiceConfiguration = serviceConfiguration
+			var _ ServiceConfigurationV2 = serviceConfiguration
+			var _ ServiceSwaggerAuthConfiguration = serviceConfiguration
+			Convey("Then the version 1 configuration should be returned", func() {
+				So(serviceConfiguration.GetSwaggerURL(), ShouldEqual, "http://sevice-api.com/swagger.yaml")
+				So(serviceConfiguration.IsInsecureSkipVerifyEnabled(), ShouldBeTrue)
+				So(serviceConfiguration.GetResourcePollConfiguration("cdn_v1"), ShouldResemble, &ServiceResourcePollConfigurationV1{ResourceName: "cdn_v1", Delay: "1m"})
+			})
+			Convey("And the service connection configuration should be returned", func() {
+				So(serviceConfiguration.GetTLSConfiguration(), ShouldResemble, &ServiceTLSConfigurationV2{CABundle: "/path/to/ca.pem"})
+				So(serviceConfiguration.GetProxyURL(), ShouldEqual, "http://proxy.company.com:3128")
+				So(serviceConfiguration.GetRequestTimeout(), ShouldEqual, 30*time.Second)
+				So(serviceConfiguration.GetRetryPolicyConfiguration(), ShouldResemble, &ServiceRetryPolicyConfigurationV2{MaxRetries: &maxRetries, MaxWait: "1m"})
+				So(serviceConfiguration.GetHeaders(), ShouldResemble, map[string]string{"X-Team": "infra"})
+				So(serviceConfiguration.GetResourceTimeoutsConfiguration(), ShouldResemble, &ServiceResourceTimeoutsConfigurationV2{Create: "30m"})
+			})
+		})
+	})
+}
+
+func TestServiceConfigV2Validate(t *testing.T) {
+	caBundle, err := ioutil.TempFile("", "ca.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(caBundle.Name())
+	maxRetries := 0
+	negativeMaxRetries := -1
+
+	Convey("Given a ServiceConfigV2 containing a valid service connection configuration", t, func() {
+		serviceConfiguration := &ServiceConfigV2{
+			ServiceConfigV1:               ServiceConfigV1{SwaggerURL: "http://sevice-api.com/swagger.yaml"},
+			TLSConfiguration:              &ServiceTLSConfigurationV2{CABundle: caBundle.Name(), ClientCert: caBundle.Name(), ClientKey: caBundle.Name(), MinVersion: "1.2", PinnedPublicKeys: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}},
+			Proxy:                         "http://proxy.company.com:3128",
+			RequestTimeout:                "30s",
+			RetryPolicyConfiguration:      &ServiceRetryPolicyConfigurationV2{MaxRetries: &maxRetries, MaxWait: "1m"},
+			Headers:                       map[string]string{"X-Team": "infra"},
+			ResourceTimeoutsConfiguration: &ServiceResourceTimeoutsConfigurationV2{Create: "30m", Read: "1m", Update: "30m", Delete: "10m", Default: "20m"},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+
+	Convey("Given a ServiceConfigV2 containing an invalid version 1 configuration", t, func() {
+		serviceConfiguration := &ServiceConfigV2{
+			ServiceConfigV1: ServiceConfigV1{SwaggerURL: "http://sevice-api.com/swagger.yaml", PluginVersion: "0.13.0"},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be the version 1 validation error", func() {
+				So(err.Error(), ShouldEqual, "plugin version '0.13.0' in the plugin configuration file does not match the version of the OpenAPI plugin that is running '0.14.0'")
+			})
+		})
+	})
+
+	invalidConfigurations := []struct {
+		description          string
+		serviceConfiguration *ServiceConfigV2
+		expectedError        string
+	}{
+		{
+			description:          "a client certificate without key",
+			serviceConfiguration: &ServiceConfigV2{TLSConfiguration: &ServiceTLSConfigurationV2{ClientCert: caBundle.Name()}},
+			expectedError:        "service tls configuration not valid, 'client_cert' and 'client_key' must be configured together",
+		},
+		{
+			description:          "a CA bundle that does not exist",
+			serviceConfiguration: &ServiceConfigV2{TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: "/non/existing/ca.pem"}},
+			expectedError:        "service tls configuration not valid, file '/non/existing/ca.pem' can not be read",
+		},
+		{
+			description:          "a TLS min version that is not supported",
+			serviceConfiguration: &ServiceConfigV2{TLSConfiguration: &ServiceTLSConfigurationV2{MinVersion: "1.4"}},
+			expectedError:        "service tls configuration not valid: 'min_version' value '1.4' not supported, supported values are [1.0, 1.1, 1.2, 1.3]",
+		},
+		{
+			description:          "a pinned public key that is not a base64 encoded SHA-256 hash",
+			serviceConfiguration: &ServiceConfigV2{TLSConfiguration: &ServiceTLSConfigurationV2{PinnedPublicKeys: []string{"c29tZSBoYXNo"}}},
+			expectedError:        "service tls configuration not valid: 'pinned_public_keys' value 'c29tZSBoYXNo' is not a base64 encoded SHA-256 hash",
+		},
+		{
+			description:          "a proxy that is not a URL",
+			serviceConfiguration: &ServiceConfigV2{Proxy: "proxy.company.com"},
+			expectedError:        "service proxy configuration not valid ('proxy.company.com'), proxy must be a valid formed URL (e,g: http://proxy.company.com:3128)",
+		},
+		{
+			description:          "a request timeout that is not a duration",
+			serviceConfiguration: &ServiceConfigV2{RequestTimeout: "30"},
+			expectedError:        "service request_timeout configuration not valid: invalid duration value '30'",
+		},
+		{
+			description:          "a retry policy with negative max retries",
+			serviceConfiguration: &ServiceConfigV2{RetryPolicyConfiguration: &ServiceRetryPolicyConfigurationV2{MaxRetries: &negativeMaxRetries}},
+			expectedError:        "service retry_policy configuration not valid, 'max_retries' must be greater or equal to 0",
+		},
+		{
+			description:          "a retry policy with a zero max wait",
+			serviceConfiguration: &ServiceConfigV2{RetryPolicyConfiguration: &ServiceRetryPolicyConfigurationV2{MaxWait: "0s"}},
+			expectedError:        "service retry_policy configuration 'max_wait' not valid: invalid duration value '0s': duration must be greater than zero",
+		},
+		{
+			description:          "a header without name",
+			serviceConfiguration: &ServiceConfigV2{Headers: map[string]string{"": "infra"}},
+			expectedError:        "service headers configuration not valid, header names can not be empty",
+		},
+		{
+			description:          "a resource timeouts configuration with an invalid timeout",
+			serviceConfiguration: &ServiceConfigV2{ResourceTimeoutsConfiguration: &ServiceResourceTimeoutsConfigurationV2{Delete: "-1m"}},
+			expectedError:        "service resource_timeouts configuration 'delete' not valid: invalid duration value '-1m': duration must be greater than zero",
+		},
+	}
+	for _, invalidConfiguration := range invalidConfigurations {
+		Convey("Given a ServiceConfigV2 containing "+invalidConfiguration.description, t, func() {
+			invalidConfiguration.serviceConfiguration.SwaggerURL = "http://sevice-api.com/swagger.yaml"
+			Convey("When Validate method is called", func() {
+				err := invalidConfiguration.serviceConfiguration.Validate("0.14.0")
+				Convey("Then the error returned should be the expected one", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldStartWith, invalidConfiguration.expectedError)
+				})
+			})
+		})
+	}
+}
+
+func TestServiceResourceTimeoutsConfigurationV2GetTimeouts(t *testing.T) {
+	Convey("Given a ServiceResourceTimeoutsConfigurationV2 containing some of the timeouts", t, func() {
+		resourceTimeoutsConfiguration := ServiceResourceTimeoutsConfigurationV2{Create: "30m", Delete: "10m", Default: "20m"}
+		Convey("When getTimeouts method is called", func() {
+			timeouts, defaultTimeout, err := resourceTimeoutsConfiguration.getTimeouts()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the timeouts configured should be parsed and the rest should be nil", func() {
+				So(*timeouts.Post, ShouldEqual, 30*time.Minute)
+				So(timeouts.Get, ShouldBeNil)
+				So(timeouts.Put, ShouldBeNil)
+				So(*timeouts.Delete, ShouldEqual, 10*time.Minute)
+			})
+			Convey("And the default timeout should be parsed", func() {
+				So(*defaultTimeout, ShouldEqual, 20*time.Minute)
+			})
+		})
+	})
+}
+
+func TestServiceSwaggerAuthConfigurationV1Validate(t *testing.T) {
+	Convey("Given a ServiceConfigV1 containing a swagger auth configuration with both a bearer token and a token command", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SwaggerURL: "http://sevice-api.com/swagger.yaml",
+			SwaggerAuthConfigurationV1: &ServiceSwaggerAuthConfigurationV1{
+				BearerToken:  "some token",
+				TokenCommand: []string{"echo", "some token"},
+			},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "swagger auth configuration not valid: 'bearer_token' and 'token_cmd' are mutually exclusive")
+			})
+		})
+	})
+	Convey("Given a ServiceConfigV1 containing a swagger auth configuration with an empty header name", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SwaggerURL:                 "http://sevice-api.com/swagger.yaml",
+			SwaggerAuthConfigurationV1: &ServiceSwaggerAuthConfigurationV1{Headers: map[string]string{" ": "value"}},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "swagger auth configuration not valid: header names must not be empty")
+			})
+		})
+	})
+	Convey("Given a ServiceConfigV1 containing a swagger auth configuration with a negative token command timeout", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SwaggerURL: "http://sevice-api.com/swagger.yaml",
+			SwaggerAuthConfigurationV1: &ServiceSwaggerAuthConfigurationV1{
+				TokenCommand:        []string{"echo", "some token"},
+				TokenCommandTimeout: -1,
+			},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "swagger auth configuration not valid: 'token_cmd_timeout' must not be negative")
+			})
+		})
+	})
+	Convey("Given a ServiceConfigV1 containing a swagger auth configuration with a zero token command timeout", t, func() {
+		serviceConfiguration := &ServiceConfigV1{
+			SwaggerURL: "http://sevice-api.com/swagger.yaml",
+			SwaggerAuthConfigurationV1: &ServiceSwaggerAuthConfigurationV1{
+				TokenCommand:        []string{"echo", "some token"},
+				TokenCommandTimeout: 0,
+			},
+		}
+		Convey("When Validate method is called", func() {
+			err := serviceConfiguration.Validate("0.14.0")
+			Convey("Then the error returned should be nil as the default timeout is used", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestServiceSwaggerAuthConfigurationV1GetHeaders(t *testing.T) {
+	Convey("Given a swagger auth configuration containing headers and a bearer token", t, func() {
+		swaggerAuthConfiguration := ServiceSwaggerAuthConfigurationV1{
+			Headers:     map[string]string{"X-Team": "infra"},
+			BearerToken: "some token",
+		}
+		Convey("When getHeaders method is called", func() {
+			headers, err := swaggerAuthConfiguration.getHeaders()
+			Convey("Then the headers returned should contain the configured headers and the bearer token", func() {
+				So(err, ShouldBeNil)
+				So(headers, ShouldResemble, map[string]string{"X-Team": "infra", "Authorization": "Bearer some token"})
+			})
+		})
+	})
+	Convey("Given a swagger auth configuration containing a token command", t, func() {
+		swaggerAuthConfiguration := ServiceSwaggerAuthConfigurationV1{TokenCommand: []string{"echo", " some token "}}
+		Convey("When getHeaders method is called", func() {
+			headers, err := swaggerAuthConfiguration.getHeaders()
+			Convey("Then the headers returned should contain the command output as bearer token", func() {
+				So(err, ShouldBeNil)
+				So(headers, ShouldResemble, map[string]string{"Authorization": "Bearer some token"})
+			})
+		})
+	})
+	Convey("Given a swagger auth configuration containing a token command that does not output anything", t, func() {
+		swaggerAuthConfiguration := ServiceSwaggerAuthConfigurationV1{TokenCommand: []string{"true"}}
+		Convey("When getHeaders method is called", func() {
+			_, err := swaggerAuthConfiguration.getHeaders()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "swagger auth 'token_cmd' command '[true]' did not output any token")
+			})
+		})
+	})
+	Convey("Given a swagger auth configuration containing a token command that fails", t, func() {
+		swaggerAuthConfiguration := ServiceSwaggerAuthConfigurationV1{TokenCommand: []string{"false"}}
+		Convey("When getHeaders method is called", func() {
+			_, err := swaggerAuthConfiguration.getHeaders()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to execute 'swagger_auth' command '[false]'")
+			})
+		})
+	})
 }
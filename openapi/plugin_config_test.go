@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"strings"
+	"time"
 
 	"github.com/smartystreets/assertions/should"
 	. "github.com/smartystreets/goconvey/convey"
@@ -162,6 +163,34 @@ services:
 
 	})
 
+	Convey("Given a PluginConfiguration for 'test' provider and a version 2 plugin configuration file containing a service called 'test'", t, func() {
+		pluginConfig := fmt.Sprintf(`version: '2'
+services:
+    %s:
+        swagger-url: %s
+        request_timeout: 30s
+        headers:
+            X-Team: infra`, providerName, otfVarSwaggerURLValue)
+		configReader := strings.NewReader(pluginConfig)
+		pluginConfiguration := PluginConfiguration{
+			ProviderName:  providerName,
+			Configuration: configReader,
+		}
+		Convey("When getServiceConfiguration is called", func() {
+			serviceConfiguration, err := pluginConfiguration.getServiceConfiguration()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the serviceConfiguration returned should contain the version 1 and version 2 configuration", func() {
+				So(serviceConfiguration.GetSwaggerURL(), ShouldEqual, otfVarSwaggerURLValue)
+				So(serviceConfiguration, ShouldImplement, (*ServiceConfigurationV2)(nil))
+				serviceConfigurationV2 := serviceConfiguration.(ServiceConfigurationV2)
+				So(serviceConfigurationV2.GetRequestTimeout(), ShouldEqual, 30*time.Second)
+				So(serviceConfigurationV2.GetHeaders(), ShouldResemble, map[string]string{"X-Team": "infra"})
+			})
+		})
+	})
+
 	Convey("Given a PluginConfiguration for 'test' provider and a plugin configuration that DOES NOT contain a service called 'test'", t, func() {
 		pluginConfig := fmt.Sprintf(`version: '1'
 services:
@@ -217,7 +246,7 @@ services:
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And the error should containing the following message", func() {
-				So(err.Error(), should.ContainSubstring, "error occurred while validating 'terraform-provider-openapi.yaml' - error = provider configuration version not matching current implementation, please use version '1' of provider configuration specification")
+				So(err.Error(), should.ContainSubstring, "error occurred while validating 'terraform-provider-openapi.yaml' - error = provider configuration version not matching current implementation, please use version '2' of provider configuration specification")
 			})
 		})
 	})
@@ -245,3 +274,97 @@ services:
 	})
 
 }
+
+func TestGetServiceProviderConfigurationSwaggerAuth(t *testing.T) {
+	otfVarSwaggerHeadersName := fmt.Sprintf(otfVarSwaggerHeaders, providerName)
+	otfVarSwaggerBearerTokenName := strings.ToUpper(fmt.Sprintf(otfVarSwaggerBearerToken, providerName))
+	otfVarSwaggerTokenCommandName := fmt.Sprintf(otfVarSwaggerTokenCommand, providerName)
+
+	Convey("Given a PluginConfiguration for 'test' provider, a OTF_VAR_test_SWAGGER_URL and the swagger auth env variables set", t, func() {
+		pluginConfiguration := PluginConfiguration{ProviderName: providerName}
+		os.Setenv(otfVarNameLc, otfVarSwaggerURLValue)
+		os.Setenv(otfVarSwaggerHeadersName, "X-API-Key=secret, X-Team=infra")
+		os.Setenv(otfVarSwaggerBearerTokenName, "some token")
+		Convey("When getServiceConfiguration is called", func() {
+			serviceConfiguration, err := pluginConfiguration.getServiceConfiguration()
+			Convey("Then the serviceConfiguration returned should contain the swagger auth configuration", func() {
+				So(err, ShouldBeNil)
+				So(serviceConfiguration.(ServiceSwaggerAuthConfiguration).GetSwaggerAuthConfiguration(), ShouldResemble, &ServiceSwaggerAuthConfigurationV1{
+					Headers:     map[string]string{"X-API-Key": "secret", "X-Team": "infra"},
+					BearerToken: "some token",
+				})
+			})
+		})
+		os.Unsetenv(otfVarNameLc)
+		os.Unsetenv(otfVarSwaggerHeadersName)
+		os.Unsetenv(otfVarSwaggerBearerTokenName)
+	})
+
+	Convey("Given a PluginConfiguration for 'test' provider with a plugin configuration file containing a swagger auth configuration and the OTF_VAR_test_SWAGGER_TOKEN_COMMAND env variable set", t, func() {
+		pluginConfig := fmt.Sprintf(`version: '1'
+services:
+    %s:
+        swagger-url: %s
+        swagger_auth:
+            bearer_token: some token`, providerName, otfVarSwaggerURLValue)
+		pluginConfiguration := PluginConfiguration{
+			ProviderName:  providerName,
+			Configuration: strings.NewReader(pluginConfig),
+		}
+		os.Setenv(otfVarSwaggerTokenCommandName, `["echo", "some token"]`)
+		Convey("When getServiceConfiguration is called", func() {
+			serviceConfiguration, err := pluginConfiguration.getServiceConfiguration()
+			Convey("Then the swagger auth configuration returned should be the one provided in the env variables as it takes preference", func() {
+				So(err, ShouldBeNil)
+				So(serviceConfiguration.(ServiceSwaggerAuthConfiguration).GetSwaggerAuthConfiguration(), ShouldResemble, &ServiceSwaggerAuthConfigurationV1{
+					TokenCommand: []string{"echo", "some token"},
+				})
+			})
+		})
+		os.Unsetenv(otfVarSwaggerTokenCommandName)
+	})
+
+	Convey("Given a PluginConfiguration for 'test' provider and a OTF_VAR_test_SWAGGER_HEADERS env variable that is not valid", t, func() {
+		pluginConfiguration := PluginConfiguration{ProviderName: providerName}
+		os.Setenv(otfVarNameLc, otfVarSwaggerURLValue)
+		os.Setenv(otfVarSwaggerHeadersName, "X-API-Key")
+		Convey("When getServiceConfiguration is called", func() {
+			_, err := pluginConfiguration.getServiceConfiguration()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, "OTF_VAR_test_SWAGGER_HEADERS env variable not valid, headers must be provided as comma separated name=value pairs (e,g: X-API-Key=secret,X-Team=infra)")
+			})
+		})
+		os.Unsetenv(otfVarNameLc)
+		os.Unsetenv(otfVarSwaggerHeadersName)
+	})
+}
+
+func TestParseSwaggerTokenCommand(t *testing.T) {
+	testCases := []struct {
+		name            string
+		tokenCommand    string
+		expectedCommand []string
+		expectedError   string
+	}{
+		{name: "a command without arguments", tokenCommand: "/usr/local/bin/get-swagger-token", expectedCommand: []string{"/usr/local/bin/get-swagger-token"}},
+		{name: "a command with arguments provided as a JSON array", tokenCommand: ` ["vault", "read", "-field=token", "secret/swagger token"] `, expectedCommand: []string{"vault", "read", "-field=token", "secret/swagger token"}},
+		{name: "a JSON array that is not valid", tokenCommand: `["vault", "read"`, expectedError: `the token command must be a JSON array of strings containing the command and its arguments (e,g: ["vault", "read", "-field=token", "secret/swagger"])`},
+		{name: "an empty JSON array", tokenCommand: `[]`, expectedError: `the token command must be a JSON array of strings containing the command and its arguments (e,g: ["vault", "read", "-field=token", "secret/swagger"])`},
+	}
+	for _, tc := range testCases {
+		Convey("Given "+tc.name, t, func() {
+			Convey("When parseSwaggerTokenCommand is called", func() {
+				command, err := parseSwaggerTokenCommand(tc.tokenCommand)
+				Convey("Then the command and error returned should be the expected ones", func() {
+					if tc.expectedError == "" {
+						So(err, ShouldBeNil)
+					} else {
+						So(err.Error(), ShouldEqual, tc.expectedError)
+					}
+					So(command, ShouldResemble, tc.expectedCommand)
+				})
+			})
+		})
+	}
+}
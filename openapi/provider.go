@@ -1,10 +1,6 @@
 package openapi
 
 import (
-	"net/http"
-
-	"crypto/tls"
-
 	"fmt"
 	"log"
 
@@ -38,7 +34,14 @@ func (p *ProviderOpenAPI) CreateSchemaProviderFromServiceConfiguration(serviceCo
 
 	log.Printf("[DEBUG] service configuration = %+v", serviceConfiguration)
 
-	openAPISpecAnalyser, err := CreateSpecAnalyser(specAnalyserV2, serviceConfiguration.GetSwaggerURL())
+	// the OpenAPI document is retrieved using the same transport (TLS, proxy) configured for the service API, sending the
+	// swagger auth credentials (if any)
+	swaggerHTTPClient, err := newSwaggerHTTPClient(serviceConfiguration)
+	if err != nil {
+		return nil, fmt.Errorf("plugin service http client error: %s", err)
+	}
+
+	openAPISpecAnalyser, err := newSpecAnalyser(serviceConfiguration.GetSwaggerURL(), swaggerHTTPClient)
 	if err != nil {
 		return nil, fmt.Errorf("plugin OpenAPI spec analyser error: %s", err)
 	}
@@ -70,10 +73,6 @@ func getServiceConfiguration(providerName string) (ServiceConfiguration, error)
 	}
 
 	if serviceConfiguration.IsInsecureSkipVerifyEnabled() {
-		tr := http.DefaultTransport.(*http.Transport)
-		tr.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
-		}
 		log.Printf("[WARN] Provider '%s' is using insecure skip verify. Please make sure you trust the aforementioned server hosting the swagger file. Otherwise, it's highly recommended avoiding the use of OTF_INSECURE_SKIP_VERIFY env variable when executing this provider", providerName)
 	}
 
@@ -2,11 +2,17 @@ package openapi
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 const providerPropertyRegion = "region"
 const providerPropertyEndPoints = "endpoints"
+const providerPropertyMaxRetries = "max_retries"
+const providerPropertyRetryMaxWait = "retry_max_wait"
 
 // providerConfiguration contains all the configuration related to the OpenAPI provider. The configuration at the moment
 // supports:
@@ -16,11 +22,18 @@ const providerPropertyEndPoints = "endpoints"
 // file. These headers may be sent as part of the HTTP calls if the resource requires them (as specified in the swagger doc)
 // - Endpoints contains the endpoints configured by the user, which effectively will override the default host set in the swagger file
 // - Region contains the region if user provided value for it (only supported for multi-region providers)
+// - HostVariables contains the values provided by the user for the host variables (e,g: ${environment}) defined in the swagger doc
+// - MaxRetries and RetryMaxWait define how requests that failed due to transient errors are retried (see retryPolicy)
+// - CookieJar contains the session cookies obtained by the session login security definitions (nil if none is configured)
 type providerConfiguration struct {
 	Headers                   map[string]string
 	SecuritySchemaDefinitions map[string]specAPIKeyAuthenticator
 	Endpoints                 map[string]string
 	Region                    string
+	HostVariables             map[string]string
+	MaxRetries                int
+	RetryMaxWait              time.Duration
+	CookieJar                 http.CookieJar
 }
 
 // createProviderConfig returns a providerConfiguration populated with the values provided by the user in the provider's terraform
@@ -30,6 +43,7 @@ func newProviderConfiguration(specAnalyser SpecAnalyser, data *schema.ResourceDa
 	providerConfiguration.Headers = map[string]string{}
 	providerConfiguration.Endpoints = map[string]string{}
 	providerConfiguration.SecuritySchemaDefinitions = map[string]specAPIKeyAuthenticator{}
+	providerConfiguration.HostVariables = map[string]string{}
 
 	securitySchemaDefinitions, err := specAnalyser.GetSecurity().GetAPIKeySecurityDefinitions()
 	if err != nil {
@@ -38,7 +52,38 @@ func newProviderConfiguration(specAnalyser SpecAnalyser, data *schema.ResourceDa
 	if securitySchemaDefinitions != nil {
 		for _, secDef := range *securitySchemaDefinitions {
 			secDefTerraformCompliantName := secDef.getTerraformConfigurationName()
+			if multiPropertySecDef, ok := secDef.(specMultiPropertySecurityDefinition); ok {
+				values := map[string]string{}
+				configured := true
+				for _, property := range multiPropertySecDef.getTerraformConfigurationProperties() {
+					value, _ := data.GetOkExists(property.name)
+					values[property.name], _ = value.(string)
+					if property.required && values[property.name] == "" {
+						configured = false
+					}
+				}
+				// security definitions not fully configured are not registered so other security requirements can be selected instead
+				if !configured {
+					continue
+				}
+				authenticator, err := multiPropertySecDef.createAuthenticator(values)
+				if err != nil {
+					return nil, err
+				}
+				providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = authenticator
+				continue
+			}
 			if value, exists := data.GetOkExists(secDefTerraformCompliantName); exists {
+				// security definitions without value are not registered so other security requirements can be selected instead
+				if value.(string) == "" {
+					continue
+				}
+				if secDef.getType() == securityDefinitionAPIKeySessionLogin {
+					// the session cookie is stored in the provider cookie jar so the ProviderClient http client sends it along with the API requests
+					loginURL, _ := secDef.getAPIKey().Metadata[sessionLoginURLKey].(string)
+					providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = newAPISessionLoginAuthenticator(secDef.getAPIKey().Name, secDef.buildValue(value.(string)), loginURL, providerConfiguration.getCookieJar())
+					continue
+				}
 				providerConfiguration.SecuritySchemaDefinitions[secDefTerraformCompliantName] = createAPIKeyAuthenticator(secDef, value.(string))
 			} else {
 				return nil, fmt.Errorf("security schema definition '%s' is missing the value, please make sure this value is provided in the terraform configuration", secDefTerraformCompliantName)
@@ -66,6 +111,32 @@ func newProviderConfiguration(specAnalyser SpecAnalyser, data *schema.ResourceDa
 		providerConfiguration.Region = region.(string)
 	}
 
+	if maxRetries, ok := data.Get(providerPropertyMaxRetries).(int); ok {
+		providerConfiguration.MaxRetries = maxRetries
+	}
+	if retryMaxWait, ok := data.Get(providerPropertyRetryMaxWait).(string); ok && retryMaxWait != "" {
+		providerConfiguration.RetryMaxWait, err = time.ParseDuration(retryMaxWait)
+		if err != nil {
+			return nil, fmt.Errorf("provider property '%s' value '%s' is not a valid duration: %s", providerPropertyRetryMaxWait, retryMaxWait, err)
+		}
+	}
+
+	backendConfiguration, err := specAnalyser.GetAPIBackendConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	if backendConfiguration != nil {
+		hostVariables, err := backendConfiguration.getHostVariables()
+		if err != nil {
+			return nil, err
+		}
+		for _, hostVariable := range hostVariables {
+			if value, exists := data.GetOk(hostVariable.name); exists {
+				providerConfiguration.HostVariables[hostVariable.name] = value.(string)
+			}
+		}
+	}
+
 	providerConfigurationEndPoints, err := newProviderConfigurationEndPoints(specAnalyser)
 	if err != nil {
 		return nil, err
@@ -97,6 +168,31 @@ func (p *providerConfiguration) getRegion() string {
 	return p.Region
 }
 
+// getHostVariables returns the host variable values provided by the user in the configuration for the provider
+func (p *providerConfiguration) getHostVariables() map[string]string {
+	return p.HostVariables
+}
+
+// getCookieJar returns the cookie jar shared by the session login authenticators and the ProviderClient http client. The
+// cookie jar is created the first time it is requested
+func (p *providerConfiguration) getCookieJar() http.CookieJar {
+	if p.CookieJar == nil {
+		// cookiejar.New never returns an error when no options are provided
+		p.CookieJar, _ = cookiejar.New(nil)
+	}
+	return p.CookieJar
+}
+
+// setAuthenticatorsTransport configures the authenticators that send requests themselves (e,g: session login or token
+// requests) to use the given transport
+func (p *providerConfiguration) setAuthenticatorsTransport(transport http.RoundTripper) {
+	for _, authenticator := range p.SecuritySchemaDefinitions {
+		if configurableAuthenticator, ok := authenticator.(transportConfigurableAuthenticator); ok {
+			configurableAuthenticator.setTransport(transport)
+		}
+	}
+}
+
 // getEndPoint resolves the endpoint value for a given resource name
 func (p *providerConfiguration) getEndPoint(resourceName string) string {
 	if endpoint, ok := p.Endpoints[resourceName]; ok {
@@ -2,6 +2,7 @@ package openapi
 
 import (
 	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
 	"testing"
 )
 
@@ -45,6 +46,81 @@ func TestNewProviderConfiguration(t *testing.T) {
 		})
 	})
 
+	Convey("Given a session login security definition and a schema ResourceData containing the credentials", t, func() {
+		sessionProperty := newStringSchemaDefinitionPropertyWithDefaults("session_auth", "", true, false, "Basic credentials")
+		specAnalyser := &specAnalyserStub{
+			headers: SpecHeaderParameters{},
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newAPIKeyCookieSessionLoginSecurityDefinition("session_auth", "session_id", "https://api.server.com/login"),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+			},
+		}
+		data := newTestSchema(sessionProperty).getResourceData(t)
+		Convey("When newProviderConfiguration method is called", func() {
+			providerConfiguration, err := newProviderConfiguration(specAnalyser, data)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the session login authenticator should store the session cookie in the provider configuration cookie jar", func() {
+				So(providerConfiguration.CookieJar, ShouldNotBeNil)
+				authenticator := providerConfiguration.SecuritySchemaDefinitions["session_auth"].(apiSessionLoginAuthenticator)
+				So(authenticator.jar, ShouldEqual, providerConfiguration.CookieJar)
+				So(authenticator.loginURL, ShouldEqual, "https://api.server.com/login")
+				So(authenticator.value, ShouldEqual, "Basic credentials")
+			})
+		})
+	})
+
+	Convey("Given an OAuth2 client credentials security definition and a schema ResourceData containing the client credentials", t, func() {
+		clientIDProperty := newStringSchemaDefinitionPropertyWithDefaults("oauth2_auth_client_id", "", true, false, "clientID")
+		clientSecretProperty := newStringSchemaDefinitionPropertyWithDefaults("oauth2_auth_client_secret", "", true, false, "clientSecret")
+		specAnalyser := &specAnalyserStub{
+			headers: SpecHeaderParameters{},
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newOAuth2ClientCredentialsSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token"),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+			},
+		}
+		data := newTestSchema(clientIDProperty, clientSecretProperty).getResourceData(t)
+		Convey("When newProviderConfiguration method is called", func() {
+			providerConfiguration, err := newProviderConfiguration(specAnalyser, data)
+			Convey("Then the providerConfiguration securitySchemaDefinitions should contain the OAuth2 authenticator configured with the client credentials", func() {
+				So(err, ShouldBeNil)
+				So(providerConfiguration.SecuritySchemaDefinitions, ShouldContainKey, "oauth2_auth")
+				authenticator := providerConfiguration.SecuritySchemaDefinitions["oauth2_auth"].(*oauth2Authenticator)
+				So(authenticator.clientID, ShouldEqual, "clientID")
+				So(authenticator.clientSecret, ShouldEqual, "clientSecret")
+				So(authenticator.scopes, ShouldBeEmpty)
+			})
+		})
+	})
+
+	Convey("Given an OAuth2 client credentials security definition and a schema ResourceData containing an empty client secret", t, func() {
+		clientIDProperty := newStringSchemaDefinitionPropertyWithDefaults("oauth2_auth_client_id", "", false, false, "clientID")
+		clientSecretProperty := newStringSchemaDefinitionPropertyWithDefaults("oauth2_auth_client_secret", "", false, false, "")
+		specAnalyser := &specAnalyserStub{
+			headers: SpecHeaderParameters{},
+			security: &specSecurityStub{
+				securityDefinitions: &SpecSecurityDefinitions{
+					newOAuth2ClientCredentialsSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token"),
+				},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+			},
+		}
+		data := newTestSchema(clientIDProperty, clientSecretProperty).getResourceData(t)
+		Convey("When newProviderConfiguration method is called", func() {
+			providerConfiguration, err := newProviderConfiguration(specAnalyser, data)
+			Convey("Then the security definition should not be registered as it is not fully configured, so other security requirements can be selected", func() {
+				So(err, ShouldBeNil)
+				So(providerConfiguration.SecuritySchemaDefinitions, ShouldBeEmpty)
+			})
+		})
+	})
+
 	Convey("Given securitySchemaDefinitions and a schema ResourceData not containing values for the security definitions", t, func() {
 		data := newTestSchema().getResourceData(t)
 		specAnalyser := &specAnalyserStub{
@@ -91,6 +167,33 @@ func TestNewProviderConfiguration(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Given a backend configuration with host variables and a schema ResourceData containing a value for one of them", t, func() {
+		environmentProperty := newStringSchemaDefinitionPropertyWithDefaults("environment", "", true, false, "dev")
+		specAnalyser := &specAnalyserStub{
+			headers: SpecHeaderParameters{},
+			security: &specSecurityStub{
+				securityDefinitions:   &SpecSecurityDefinitions{},
+				globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+			},
+			backendConfiguration: &specStubBackendConfiguration{
+				hostVariables: specHostVariables{
+					{name: "environment", defaultValue: "prod"},
+					{name: "cell", defaultValue: "cell1"},
+				},
+			},
+		}
+		data := newTestSchema(environmentProperty).getResourceData(t)
+		Convey("When newProviderConfiguration method is called", func() {
+			providerConfiguration, err := newProviderConfiguration(specAnalyser, data)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the providerConfiguration host variables should only contain the host variable value provided", func() {
+				So(providerConfiguration.getHostVariables(), ShouldResemble, map[string]string{"environment": "dev"})
+			})
+		})
+	})
 }
 
 func TestGetAuthenticatorFor(t *testing.T) {
@@ -102,7 +205,7 @@ func TestGetAuthenticatorFor(t *testing.T) {
 			},
 		}
 		Convey("When getAuthenticatorFor method with an existing sec def", func() {
-			apiKeyAuth := providerConfiguration.getAuthenticatorFor(SpecSecurityScheme{"registered_sec_def_name"})
+			apiKeyAuth := providerConfiguration.getAuthenticatorFor(SpecSecurityScheme{Name: "registered_sec_def_name"})
 			Convey("Then the apikey name should be headerName", func() {
 				So(apiKeyAuth.getContext().(apiKey).name, ShouldEqual, "headerName")
 			})
@@ -111,7 +214,7 @@ func TestGetAuthenticatorFor(t *testing.T) {
 			})
 		})
 		Convey("When getAuthenticatorFor method with a NON existing sec def", func() {
-			apiKeyAuth := providerConfiguration.getAuthenticatorFor(SpecSecurityScheme{"nonExistingSecDef"})
+			apiKeyAuth := providerConfiguration.getAuthenticatorFor(SpecSecurityScheme{Name: "nonExistingSecDef"})
 			Convey("Then the apiKeyAuth returned should be nil", func() {
 				So(apiKeyAuth, ShouldBeNil)
 			})
@@ -119,6 +222,25 @@ func TestGetAuthenticatorFor(t *testing.T) {
 	})
 }
 
+func TestSetAuthenticatorsTransport(t *testing.T) {
+	Convey("Given a providerConfiguration with an OAuth2 authenticator and an api key authenticator", t, func() {
+		oauth2Authenticator := newOAuth2ClientCredentialsAuthenticator("oauth2_sec_def_name", "https://auth.server.com/token", "clientID", "clientSecret", nil)
+		providerConfiguration := providerConfiguration{
+			SecuritySchemaDefinitions: map[string]specAPIKeyAuthenticator{
+				"oauth2_sec_def_name":     oauth2Authenticator,
+				"registered_sec_def_name": createAPIKeyAuthenticator(newAPIKeyHeaderSecurityDefinition("registeredSecDefName", "headerName"), "value"),
+			},
+		}
+		Convey("When setAuthenticatorsTransport method is called with a transport", func() {
+			transport := &http.Transport{}
+			providerConfiguration.setAuthenticatorsTransport(transport)
+			Convey("Then the OAuth2 authenticator token requests should be sent using the transport", func() {
+				So(oauth2Authenticator.httpClient.Transport, ShouldEqual, transport)
+			})
+		})
+	})
+}
+
 func TestGetHeaderValueFor(t *testing.T) {
 	Convey("Given a providerConfiguration with some headers", t, func() {
 		providerConfiguration := providerConfiguration{
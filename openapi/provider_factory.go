@@ -9,7 +9,6 @@ import (
 
 	"log"
 
-	"github.com/dikhan/http_goclient"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
@@ -92,7 +91,7 @@ func (p providerFactory) createTerraformProviderSchema(openAPIBackendConfigurati
 		}
 	}
 
-	// Override security definitions to required if they are global security schemes
+	// Override security definitions to required if they are part of all the global security requirements
 	globalSecuritySchemes, err := p.specAnalyser.GetSecurity().GetGlobalSecuritySchemes()
 	if err != nil {
 		return nil, err
@@ -106,9 +105,19 @@ func (p providerFactory) createTerraformProviderSchema(openAPIBackendConfigurati
 	for _, securityDefinition := range *securityDefinitions {
 		secDefName := securityDefinition.getTerraformConfigurationName()
 		required := false
-		if globalSecuritySchemes.securitySchemeExists(securityDefinition) {
+		if globalSecuritySchemes.securitySchemeRequired(securityDefinition) {
 			required = true
 		}
+		// security definitions whose credentials are made up of multiple values register one property per value
+		if multiPropertySecurityDefinition, ok := securityDefinition.(specMultiPropertySecurityDefinition); ok {
+			for _, property := range multiPropertySecurityDefinition.getTerraformConfigurationProperties() {
+				if err := p.configureProviderPropertyFromPluginConfig(s, property.name, required && property.required); err != nil {
+					return nil, err
+				}
+				s[property.name].Sensitive = property.sensitive
+			}
+			continue
+		}
 		if err := p.configureProviderPropertyFromPluginConfig(s, secDefName, required); err != nil {
 			return nil, err
 		}
@@ -137,9 +146,89 @@ func (p providerFactory) createTerraformProviderSchema(openAPIBackendConfigurati
 	if endpoints != nil {
 		s[providerPropertyEndPoints] = endpoints
 	}
+
+	// the host variables and retry properties are registered once all the other properties derived from the OpenAPI
+	// document are registered so name collisions can be detected regardless of the order
+	hostVariables, err := openAPIBackendConfiguration.getHostVariables()
+	if err != nil {
+		return nil, err
+	}
+	for _, hostVariable := range hostVariables {
+		if _, exists := s[hostVariable.name]; exists {
+			return nil, fmt.Errorf("host variable '%s' name collides with an already registered provider property", hostVariable.name)
+		}
+		log.Printf("[DEBUG] service provider host is configured with host variable '%s' (default value: '%s', allowed values: %+v)", hostVariable.name, hostVariable.defaultValue, hostVariable.allowedValues)
+		if err := p.configureProviderProperty(s, hostVariable.name, hostVariable.defaultValue, true, hostVariable.allowedValues); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.configureRetryProviderProperties(s); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
 
+// configureRetryProviderProperties adds the optional properties that allow users to configure how requests that failed
+// due to transient errors are retried. The retries are disabled by default (max_retries = 0) unless the service retry
+// policy configuration says otherwise. An error is returned if any of the properties is already registered (e,g: a header
+// or security definition defined in the OpenAPI document with the same name)
+func (p providerFactory) configureRetryProviderProperties(providerSchema map[string]*schema.Schema) error {
+	for _, propertyName := range []string{providerPropertyMaxRetries, providerPropertyRetryMaxWait} {
+		if _, exists := providerSchema[propertyName]; exists {
+			return fmt.Errorf("retry property '%s' name collides with an already registered provider property", propertyName)
+		}
+	}
+	maxRetries, retryMaxWait := p.getRetryPolicyDefaults()
+	providerSchema[providerPropertyMaxRetries] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Optional:    true,
+		Default:     maxRetries,
+		Description: "Maximum number of times a request that failed due to a transient error (e,g: 429, 503 or connection reset) is retried. Retries are disabled (0) by default unless the service retry policy defines a different default",
+		ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+			if val.(int) < 0 {
+				errs = append(errs, fmt.Errorf("property %s must be greater or equal to 0", key))
+			}
+			return
+		},
+	}
+	providerSchema[providerPropertyRetryMaxWait] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     retryMaxWait.String(),
+		Description: "Maximum time to wait between retries (e,g: 30s)",
+		ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+			if duration, err := time.ParseDuration(val.(string)); err != nil || duration <= 0 {
+				errs = append(errs, fmt.Errorf("property %s must be a positive duration (e,g: 30s), got: %s", key, val))
+			}
+			return
+		},
+	}
+	return nil
+}
+
+// getRetryPolicyDefaults returns the default values of the retry provider properties. The values configured in the
+// service retry policy configuration (if any) take precedence over the built-in defaults
+func (p providerFactory) getRetryPolicyDefaults() (int, time.Duration) {
+	maxRetries, retryMaxWait := defaultMaxRetries, defaultRetryMaxWait
+	serviceConfigurationV2, ok := p.serviceConfiguration.(ServiceConfigurationV2)
+	if !ok {
+		return maxRetries, retryMaxWait
+	}
+	retryPolicy := serviceConfigurationV2.GetRetryPolicyConfiguration()
+	if retryPolicy == nil {
+		return maxRetries, retryMaxWait
+	}
+	if retryPolicy.MaxRetries != nil {
+		maxRetries = *retryPolicy.MaxRetries
+	}
+	if retryPolicy.MaxWait != "" {
+		if maxWait, err := parsePositiveDuration(retryPolicy.MaxWait); err == nil {
+			retryMaxWait = maxWait
+		}
+	}
+	return maxRetries, retryMaxWait
+}
+
 func (p providerFactory) configureProviderPropertyFromPluginConfig(providerSchema map[string]*schema.Schema, schemaPropertyName string, required bool) error {
 	var defaultValue = ""
 	var err error
@@ -201,6 +290,33 @@ func (p providerFactory) createTerraformProviderDataSourceMap() (map[string]*sch
 	return dataSourceMap, nil
 }
 
+// getResourcePollConfiguration returns the poll configuration defined for the given resource in the service
+// configuration (if any)
+func (p providerFactory) getResourcePollConfiguration(resourceName string) (specPollConfiguration, error) {
+	if p.serviceConfiguration == nil {
+		return specPollConfiguration{}, nil
+	}
+	pollConfiguration := p.serviceConfiguration.GetResourcePollConfiguration(resourceName)
+	if pollConfiguration == nil {
+		return specPollConfiguration{}, nil
+	}
+	return pollConfiguration.getPollConfiguration()
+}
+
+// getResourceTimeoutsConfiguration returns the default timeouts of the resources defined in the service configuration
+// (if any) along with the default timeout (nil if not configured)
+func (p providerFactory) getResourceTimeoutsConfiguration() (*specTimeouts, *time.Duration, error) {
+	serviceConfigurationV2, ok := p.serviceConfiguration.(ServiceConfigurationV2)
+	if !ok {
+		return nil, nil, nil
+	}
+	resourceTimeoutsConfiguration := serviceConfigurationV2.GetResourceTimeoutsConfiguration()
+	if resourceTimeoutsConfiguration == nil {
+		return nil, nil, nil
+	}
+	return resourceTimeoutsConfiguration.getTimeouts()
+}
+
 // createTerraformProviderResourceMapAndDataSourceInstanceMap is responsible for building the following:
 // - a map containing the resources that are terraform compatible
 // - a map containing the data sources from the resources that are terraform compatible. This data sources enable data
@@ -212,6 +328,7 @@ func (p providerFactory) createTerraformProviderResourceMapAndDataSourceInstance
 	if err != nil {
 		return nil, nil, err
 	}
+	var defaultTimeout *time.Duration
 	for _, openAPIResource := range openAPIResources {
 		start := time.Now()
 
@@ -226,6 +343,15 @@ func (p providerFactory) createTerraformProviderResourceMapAndDataSourceInstance
 		}
 
 		r := newResourceFactory(openAPIResource)
+		if r.pollConfiguration, err = p.getResourcePollConfiguration(openAPIResource.getResourceName()); err != nil {
+			return nil, nil, err
+		}
+		if r.timeoutsConfiguration, defaultTimeout, err = p.getResourceTimeoutsConfiguration(); err != nil {
+			return nil, nil, err
+		}
+		if defaultTimeout != nil {
+			r.defaultTimeout = *defaultTimeout
+		}
 		d := newDataSourceInstanceFactory(openAPIResource)
 		fullDataSourceInstanceName, _ := p.getProviderResourceName(d.getDataSourceInstanceName())
 
@@ -263,16 +389,37 @@ func (p providerFactory) configureProvider(openAPIBackendConfiguration SpecBacke
 		if err != nil {
 			return nil, err
 		}
+		serviceHTTPClient, err := newServiceHTTPClient(p.serviceConfiguration, config.CookieJar)
+		if err != nil {
+			return nil, err
+		}
 		openAPIClient := &ProviderClient{
 			openAPIBackendConfiguration: openAPIBackendConfiguration,
 			apiAuthenticator:            authenticator,
-			httpClient:                  &http_goclient.HttpClient{HttpClient: &http.Client{}},
+			httpClient:                  newHTTPClient(serviceHTTPClient),
 			providerConfiguration:       *config,
+			headers:                     p.getServiceHeaders(),
+		}
+		if serviceTransport, ok := serviceHTTPClient.Transport.(*http.Transport); ok {
+			serviceHosts, err := openAPIClient.getServiceHosts()
+			if err != nil {
+				return nil, err
+			}
+			config.setAuthenticatorsTransport(newAuthenticatorsTransport(serviceTransport, serviceHosts))
 		}
 		return openAPIClient, nil
 	}
 }
 
+// getServiceHeaders returns the static headers configured in the service configuration (if any)
+func (p providerFactory) getServiceHeaders() map[string]string {
+	serviceConfigurationV2, ok := p.serviceConfiguration.(ServiceConfigurationV2)
+	if !ok {
+		return nil
+	}
+	return serviceConfigurationV2.GetHeaders()
+}
+
 // createProviderConfig returns a providerConfiguration populated with:
 // - Header values that might be required by API operations
 // - Security definition values that might be required by API operations (or globally)
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/stretchr/testify/assert"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -399,6 +401,64 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 		})
 	})
 
+	Convey("Given a provider factory containing a global OAuth2 client credentials security definition", t, func() {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				security: &specSecurityStub{
+					securityDefinitions: &SpecSecurityDefinitions{
+						newOAuth2ClientCredentialsSecurityDefinition("oauth2_auth", "https://api.iam.com/oauth2/token"),
+					},
+					globalSecuritySchemes: createSecuritySchemes([]map[string][]string{
+						{
+							"oauth2_auth": []string{},
+						},
+					}),
+				},
+			},
+			serviceConfiguration: &ServiceConfigStub{},
+		}
+		Convey("When createTerraformProviderSchema is called", func() {
+			providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{})
+			Convey("Then the provider schema should contain the client credentials properties instead of the security definition property", func() {
+				So(err, ShouldBeNil)
+				So(providerSchema, ShouldNotContainKey, "oauth2_auth")
+				So(providerSchema["oauth2_auth_client_id"].Required, ShouldBeTrue)
+				So(providerSchema["oauth2_auth_client_secret"].Required, ShouldBeTrue)
+				So(providerSchema["oauth2_auth_scopes"].Optional, ShouldBeTrue)
+			})
+			Convey("And the client secret property should be sensitive so it is not displayed", func() {
+				So(providerSchema["oauth2_auth_client_id"].Sensitive, ShouldBeFalse)
+				So(providerSchema["oauth2_auth_client_secret"].Sensitive, ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given a provider factory containing a basic security definition that is not attached to the global security schemes", t, func() {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				security: &specSecurityStub{
+					securityDefinitions: &SpecSecurityDefinitions{
+						newBasicSecurityDefinition("basic_auth"),
+					},
+					globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+				},
+			},
+			serviceConfiguration: &ServiceConfigStub{},
+		}
+		Convey("When createTerraformProviderSchema is called", func() {
+			providerSchema, err := p.createTerraformProviderSchema(&specStubBackendConfiguration{})
+			Convey("Then the provider schema should contain the optional username and password properties, the latter being sensitive", func() {
+				So(err, ShouldBeNil)
+				So(providerSchema["basic_auth_username"].Optional, ShouldBeTrue)
+				So(providerSchema["basic_auth_username"].Sensitive, ShouldBeFalse)
+				So(providerSchema["basic_auth_password"].Optional, ShouldBeTrue)
+				So(providerSchema["basic_auth_password"].Sensitive, ShouldBeTrue)
+			})
+		})
+	})
+
 	Convey("Given a provider factory containing a property with command (that exit with error) set up", t, func() {
 		apiKeyAuthProperty := newStringSchemaDefinitionPropertyWithDefaults("apikey_auth", "", true, false, "someAuthValue")
 		expectedError := "some error executing the command"
@@ -550,6 +610,72 @@ func TestCreateTerraformProviderSchema(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a provider factory with an spec analyser with no resources and a backend configuration with host variables", t, func() {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				headers: SpecHeaderParameters{},
+				security: &specSecurityStub{
+					securityDefinitions:   &SpecSecurityDefinitions{},
+					globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+				},
+			},
+			serviceConfiguration: &ServiceConfigStub{},
+		}
+		Convey("When createTerraformProviderSchema is called", func() {
+			backendConfig := &specStubBackendConfiguration{
+				hostVariables: specHostVariables{{name: "environment", defaultValue: "prod", allowedValues: []string{"prod", "dev"}}},
+			}
+			providerSchema, err := p.createTerraformProviderSchema(backendConfig)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the provider schema should contain the host variable property with the validate function configured", func() {
+				So(providerSchema, ShouldContainKey, "environment")
+				So(providerSchema["environment"].ValidateFunc, ShouldNotBeNil)
+				_, errs := providerSchema["environment"].ValidateFunc("staging", "environment")
+				So(errs, ShouldNotBeEmpty)
+			})
+		})
+		Convey("When createTerraformProviderSchema is called with a host variable colliding with the region property", func() {
+			backendConfig := &specStubBackendConfiguration{
+				host:          "api.${region}.server.com",
+				regions:       []string{"rst1"},
+				hostVariables: specHostVariables{{name: providerPropertyRegion, defaultValue: "rst1"}},
+			}
+			_, err := p.createTerraformProviderSchema(backendConfig)
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "host variable 'region' name collides with an already registered provider property")
+			})
+		})
+		Convey("When createTerraformProviderSchema is called with a host variable colliding with a security definition registered afterwards", func() {
+			p.specAnalyser = &specAnalyserStub{
+				headers: SpecHeaderParameters{},
+				security: &specSecurityStub{
+					securityDefinitions: &SpecSecurityDefinitions{
+						newAPIKeyHeaderSecurityDefinition("environment", "X-Environment"),
+					},
+					globalSecuritySchemes: createSecuritySchemes([]map[string][]string{}),
+				},
+			}
+			backendConfig := &specStubBackendConfiguration{
+				hostVariables: specHostVariables{{name: "environment", defaultValue: "prod"}},
+			}
+			_, err := p.createTerraformProviderSchema(backendConfig)
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "host variable 'environment' name collides with an already registered provider property")
+			})
+		})
+		Convey("When createTerraformProviderSchema is called with a host variable colliding with the max_retries property", func() {
+			backendConfig := &specStubBackendConfiguration{
+				hostVariables: specHostVariables{{name: providerPropertyMaxRetries, defaultValue: "5"}},
+			}
+			_, err := p.createTerraformProviderSchema(backendConfig)
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "retry property 'max_retries' name collides with an already registered provider property")
+			})
+		})
+	})
 	Convey("Given a provider factory with an spec analyser with no resources (testing endpoints)", t, func() {
 		p := providerFactory{
 			name: "provider",
@@ -616,6 +742,102 @@ func TestConfigureProvider(t *testing.T) {
 	})
 }
 
+func TestConfigureProviderWithServiceConfiguration(t *testing.T) {
+	Convey("Given a provider factory with a service configuration containing static headers and a request timeout", t, func() {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				security: &specSecurityStub{
+					securityDefinitions: &SpecSecurityDefinitions{},
+				},
+			},
+			serviceConfiguration: &ServiceConfigStub{
+				Headers:        map[string]string{"X-Team": "infra"},
+				RequestTimeout: 30 * time.Second,
+			},
+		}
+		testProviderSchema := newTestSchema()
+		Convey("When configureProvider is called and the returned configureFunc is invoked", func() {
+			configureFunc := p.configureProvider(&specStubBackendConfiguration{})
+			client, err := configureFunc(testProviderSchema.getResourceData(t))
+			Convey("Then error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the client should send the service static headers and use the request timeout", func() {
+				providerClient := client.(*ProviderClient)
+				So(providerClient.headers, ShouldResemble, map[string]string{"X-Team": "infra"})
+				So(providerClient.httpClient.(*httpClient).HttpClient.HttpClient.Timeout, ShouldEqual, 30*time.Second)
+			})
+		})
+	})
+	Convey("Given a provider factory with a service configuration containing a CA bundle that does not exist", t, func() {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				security: &specSecurityStub{
+					securityDefinitions: &SpecSecurityDefinitions{},
+				},
+			},
+			serviceConfiguration: &ServiceConfigStub{
+				TLSConfiguration: &ServiceTLSConfigurationV2{CABundle: "/non/existing/ca.pem"},
+			},
+		}
+		testProviderSchema := newTestSchema()
+		Convey("When configureProvider is called and the returned configureFunc is invoked", func() {
+			configureFunc := p.configureProvider(&specStubBackendConfiguration{})
+			_, err := configureFunc(testProviderSchema.getResourceData(t))
+			Convey("Then error returned should be the expected one", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "failed to read the service tls ca_bundle '/non/existing/ca.pem'")
+			})
+		})
+	})
+}
+
+func TestGetRetryPolicyDefaults(t *testing.T) {
+	Convey("Given a provider factory without service configuration", t, func() {
+		p := providerFactory{}
+		Convey("When getRetryPolicyDefaults is called", func() {
+			maxRetries, retryMaxWait := p.getRetryPolicyDefaults()
+			Convey("Then the built-in defaults should be returned", func() {
+				So(maxRetries, ShouldEqual, defaultMaxRetries)
+				So(retryMaxWait, ShouldEqual, defaultRetryMaxWait)
+			})
+		})
+	})
+	Convey("Given a provider factory with a service configuration containing a retry policy", t, func() {
+		maxRetries := 0
+		p := providerFactory{
+			serviceConfiguration: &ServiceConfigStub{
+				RetryPolicy: &ServiceRetryPolicyConfigurationV2{MaxRetries: &maxRetries, MaxWait: "1m"},
+			},
+		}
+		Convey("When configureRetryProviderProperties is called", func() {
+			providerSchema := map[string]*schema.Schema{}
+			err := p.configureRetryProviderProperties(providerSchema)
+			Convey("Then the retry properties defaults should be the ones configured in the retry policy", func() {
+				So(err, ShouldBeNil)
+				So(providerSchema[providerPropertyMaxRetries].Default, ShouldEqual, 0)
+				So(providerSchema[providerPropertyRetryMaxWait].Default, ShouldEqual, "1m0s")
+			})
+		})
+	})
+	Convey("Given a provider factory with a service configuration containing a retry policy with only the max wait", t, func() {
+		p := providerFactory{
+			serviceConfiguration: &ServiceConfigStub{
+				RetryPolicy: &ServiceRetryPolicyConfigurationV2{MaxWait: "1m"},
+			},
+		}
+		Convey("When getRetryPolicyDefaults is called", func() {
+			maxRetries, retryMaxWait := p.getRetryPolicyDefaults()
+			Convey("Then the max retries should be the built-in default and the max wait the configured one", func() {
+				So(maxRetries, ShouldEqual, defaultMaxRetries)
+				So(retryMaxWait, ShouldEqual, time.Minute)
+			})
+		})
+	})
+}
+
 func TestCreateProviderConfig(t *testing.T) {
 	Convey("Given a provider factory configured with a global header and security scheme", t, func() {
 		apiKeyAuthProperty := newStringSchemaDefinitionPropertyWithDefaults("apikey_auth", "", true, false, "someAuthValue")
@@ -825,6 +1047,39 @@ func TestCreateTerraformProviderResourceMapAndDataSourceInstanceMap(t *testing.T
 	}
 }
 
+func TestCreateTerraformProviderResourceMap_resource_timeouts_configuration(t *testing.T) {
+	t.Run("happy path -- the resources use the timeouts configured in the service configuration", func(t *testing.T) {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				resources: []SpecResource{newSpecStubResource("resource", "/v1/resource", false, &specSchemaDefinition{})},
+			},
+			serviceConfiguration: &ServiceConfigStub{
+				ResourceTimeouts: &ServiceResourceTimeoutsConfigurationV2{Create: "30m", Default: "20m"},
+			},
+		}
+		resourceMap, _, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+		assert.Nil(t, err)
+		assert.Equal(t, 30*time.Minute, *resourceMap["provider_resource"].Timeouts.Create)
+		assert.Equal(t, 20*time.Minute, *resourceMap["provider_resource"].Timeouts.Default)
+	})
+
+	t.Run("crappy path -- the timeouts configured in the service configuration are not valid", func(t *testing.T) {
+		p := providerFactory{
+			name: "provider",
+			specAnalyser: &specAnalyserStub{
+				resources: []SpecResource{newSpecStubResource("resource", "/v1/resource", false, &specSchemaDefinition{})},
+			},
+			serviceConfiguration: &ServiceConfigStub{
+				ResourceTimeouts: &ServiceResourceTimeoutsConfigurationV2{Create: "often"},
+			},
+		}
+		_, _, err := p.createTerraformProviderResourceMapAndDataSourceInstanceMap()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "service resource_timeouts configuration 'create' not valid: invalid duration value 'often'")
+	})
+}
+
 func TestCreateTerraformProviderDataSourceInstanceMap_ignore_resource(t *testing.T) {
 	p := providerFactory{
 		name: "provider",
@@ -753,4 +753,21 @@ func TestGetServiceConfiguration(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a swagger url configured with environment variable and skip verify being true", t, func() {
+		providerName := "providerName"
+		os.Setenv(fmt.Sprintf(otfVarSwaggerURL, providerName), "https://www.domain.com/swagger.yaml")
+		os.Setenv(otfVarInsecureSkipVerify, "true")
+		defer os.Setenv(otfVarInsecureSkipVerify, "false")
+		defaultTLSClientConfig := http.DefaultTransport.(*http.Transport).TLSClientConfig
+		Convey("When getServiceConfiguration method is called", func() {
+			serviceConfiguration, err := getServiceConfiguration(providerName)
+			Convey("Then the service configuration should have insecure skip verify enabled", func() {
+				So(err, ShouldBeNil)
+				So(serviceConfiguration.IsInsecureSkipVerifyEnabled(), ShouldBeTrue)
+			})
+			Convey("And the default http transport should not be modified as the service transport is used instead", func() {
+				So(http.DefaultTransport.(*http.Transport).TLSClientConfig, ShouldEqual, defaultTLSClientConfig)
+			})
+		})
+	})
 }
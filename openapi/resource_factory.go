@@ -1,11 +1,15 @@
 package openapi
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,14 +25,42 @@ type resourceFactory struct {
 	defaultPollInterval   time.Duration
 	defaultPollMinTimeout time.Duration
 	defaultPollDelay      time.Duration
+	// pollConfiguration contains the poll timings configured for the resource in the plugin configuration, which take
+	// precedence over the ones defined in the OpenAPI document
+	pollConfiguration specPollConfiguration
+	// timeoutsConfiguration contains the default timeouts configured for the resources in the plugin configuration (if
+	// any), which are used when the OpenAPI document does not define the timeouts
+	timeoutsConfiguration *specTimeouts
 }
 
 // only applicable when remote resource no longer exists and GET operations return 404 NotFound
 const defaultDestroyStatus = "destroyed"
 
+// internal statuses used when polling operation resources (see handleOperationPollingIfConfigured)
+const pollOperationRunningStatus = "running"
+const pollOperationCompletedStatus = "completed"
+
+// long-running operation fields as defined by the Google API design guidelines (AIP-151)
+const (
+	longRunningOperationNameField         = "name"
+	longRunningOperationDoneField         = "done"
+	longRunningOperationErrorField        = "error"
+	longRunningOperationErrorMessageField = "message"
+	longRunningOperationResponseField     = "response"
+)
+
+// eTagAttributeName is the name of the computed attribute where the resource ETag is stored when the resource supports
+// optimistic concurrency (see specResourceOperation.IfMatch). The terraform plugin SDK (v1) does not give providers
+// access to the resource private state, hence the computed attribute is the only place the ETag can be kept between runs
+const eTagAttributeName = "etag"
+
 var defaultPollInterval = time.Duration(5 * time.Second)
 var defaultPollMinTimeout = time.Duration(10 * time.Second)
 var defaultPollDelay = time.Duration(1 * time.Second)
+
+// maxPollInterval is the longest poll interval honoured by the state change configuration; longer intervals are ignored
+// by the SDK which then falls back to its own backoff (up to 10 seconds between polls)
+var maxPollInterval = time.Duration(3*time.Minute - time.Second)
 var defaultTimeout = time.Duration(10 * time.Minute)
 
 func newResourceFactory(openAPIResource SpecResource) resourceFactory {
@@ -69,6 +101,14 @@ func (r resourceFactory) createSchemaResourceTimeout() (*schema.ResourceTimeout,
 	if timeouts, err = r.openAPIResource.getTimeouts(); err != nil {
 		return nil, err
 	}
+	if r.timeoutsConfiguration != nil {
+		timeouts = &specTimeouts{
+			Post:   getTimeoutOrDefault(timeouts.Post, r.timeoutsConfiguration.Post),
+			Get:    getTimeoutOrDefault(timeouts.Get, r.timeoutsConfiguration.Get),
+			Put:    getTimeoutOrDefault(timeouts.Put, r.timeoutsConfiguration.Put),
+			Delete: getTimeoutOrDefault(timeouts.Delete, r.timeoutsConfiguration.Delete),
+		}
+	}
 	return &schema.ResourceTimeout{
 		Create:  timeouts.Post,
 		Read:    timeouts.Get,
@@ -78,13 +118,132 @@ func (r resourceFactory) createSchemaResourceTimeout() (*schema.ResourceTimeout,
 	}, nil
 }
 
+// getTimeoutOrDefault returns the given timeout if set; otherwise the default timeout is returned
+func getTimeoutOrDefault(timeout, defaultTimeout *time.Duration) *time.Duration {
+	if timeout != nil {
+		return timeout
+	}
+	return defaultTimeout
+}
+
 func (r resourceFactory) createTerraformResourceSchema() (map[string]*schema.Schema, error) {
 	schemaDefinition, err := r.openAPIResource.getResourceSchema()
 	if err != nil {
 		return nil, err
 	}
 	log.Printf("[DEBUG] resource '%s' schemaDefinition: %s", r.openAPIResource.getResourceName(), sPrettyPrint(schemaDefinition))
-	return schemaDefinition.createResourceSchema()
+	resourceSchema, err := schemaDefinition.createResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	// resources created via PUT are identified by the value provided by the user in the identifier property, hence changing
+	// the value requires a new resource to be created
+	if method, _ := r.openAPIResource.getResourceOperations().getCreateOperation(); method == httpPut && !r.openAPIResource.isSingleton() {
+		identifier, err := schemaDefinition.getResourceIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		identifierProperty, err := schemaDefinition.getProperty(identifier)
+		if err != nil {
+			return nil, err
+		}
+		if identifierSchema, exists := resourceSchema[identifierProperty.getTerraformCompliantPropertyName()]; exists && !identifierProperty.isComputed() {
+			identifierSchema.ForceNew = true
+		}
+	}
+	// the trigger attributes are only known by terraform, hence they are never sent to the API
+	for _, action := range r.openAPIResource.getResourceOperations().Actions {
+		if _, exists := resourceSchema[action.TriggerAttribute]; exists {
+			return nil, fmt.Errorf("resource '%s' action '%s' trigger attribute '%s' conflicts with an existing property of the resource schema", r.openAPIResource.getResourceName(), action.Name, action.TriggerAttribute)
+		}
+		resourceSchema[action.TriggerAttribute] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: fmt.Sprintf("Changing the value of this attribute triggers the '%s' action on the resource", action.Name),
+		}
+	}
+	// the resource ETag is only known by terraform, hence it is stored in a computed attribute unless the resource
+	// schema already exposes it as a read only property
+	if r.isETagEnabled() {
+		if eTagSchema, exists := resourceSchema[eTagAttributeName]; !exists {
+			resourceSchema[eTagAttributeName] = &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version of the resource as returned by the API in the ETag header, which is sent in the If-Match header when updating or deleting the resource",
+			}
+		} else if !eTagSchema.Computed || eTagSchema.Optional || eTagSchema.Required {
+			return nil, fmt.Errorf("resource '%s' ETag attribute '%s' conflicts with an existing property of the resource schema that is not read only", r.openAPIResource.getResourceName(), eTagAttributeName)
+		}
+	}
+	return resourceSchema, nil
+}
+
+// isETagEnabled returns true if any of the operations that modify the resource (PUT, PATCH or DELETE) send the If-Match
+// header with the resource ETag
+func (r resourceFactory) isETagEnabled() bool {
+	operations := r.openAPIResource.getResourceOperations()
+	for _, operation := range []*specResourceOperation{operations.Put, operations.Patch, operations.Delete} {
+		if operation != nil && operation.IfMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// setETag stores the ETag returned in the given response in the resource state so it can be sent in the If-Match header
+// of subsequent updates and deletes. If the response does not contain the ETag header, the ETag stored is cleared as it
+// no longer matches the version of the resource; the ETag is then obtained again the next time the resource is read
+func (r resourceFactory) setETag(data *schema.ResourceData, res *http.Response) error {
+	if res == nil || !r.isETagEnabled() {
+		return nil
+	}
+	eTag := res.Header.Get(eTagHeader)
+	if eTag == "" {
+		log.Printf("[DEBUG] [resource='%s'] response is missing the '%s' header, clearing the resource ETag", r.openAPIResource.getResourceName(), eTagHeader)
+	}
+	return data.Set(eTagAttributeName, eTag)
+}
+
+// setETagAfterRequest stores the resource ETag once the given create or update request completed. If the resource was
+// read while waiting for the request to complete (readResponses, in the order the reads happened), the ETag is taken
+// from the last GET response since the request response (e,g: 202 Accepted or the operation resource) does not describe
+// the resource version. If the request was accepted but the resource was not read, the resource is read to obtain its
+// ETag. Otherwise, the request completed synchronously and the ETag is taken from the request response
+func (r resourceFactory) setETagAfterRequest(data *schema.ResourceData, providerClient ClientOpenAPI, res *http.Response, parentIDs []string, readResponses ...*http.Response) error {
+	if res == nil || !r.isETagEnabled() {
+		return nil
+	}
+	var readRes *http.Response
+	for _, readResponse := range readResponses {
+		if readResponse != nil {
+			readRes = readResponse
+		}
+	}
+	if readRes == nil && res.StatusCode == http.StatusAccepted {
+		var err error
+		_, readRes, err = r.readRemoteWithResponse(data.Id(), providerClient, parentIDs...)
+		if err != nil {
+			return fmt.Errorf("[resource='%s'] failed to read the resource ETag after the request was accepted: %s", r.openAPIResource.getResourceName(), err)
+		}
+	}
+	if readRes != nil {
+		return r.setETag(data, readRes)
+	}
+	return r.setETag(data, res)
+}
+
+// getIfMatchClient returns a client that sends the resource ETag stored in the state in the If-Match header if the given
+// operation supports it; otherwise, the given client is returned as is
+func (r resourceFactory) getIfMatchClient(data *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation) ClientOpenAPI {
+	if operation == nil || !operation.IfMatch {
+		return providerClient
+	}
+	eTag, ok := data.Get(eTagAttributeName).(string)
+	if !ok || eTag == "" {
+		log.Printf("[WARN] [resource='%s'] resource '%s' ETag is unknown, the If-Match header will not be sent", r.openAPIResource.getResourceName(), data.Id())
+		return providerClient
+	}
+	return providerClient.WithIfMatch(eTag)
 }
 
 func (r resourceFactory) create(data *schema.ResourceData, i interface{}) error {
@@ -95,10 +254,29 @@ func (r resourceFactory) create(data *schema.ResourceData, i interface{}) error
 		return err
 	}
 
+	if r.openAPIResource.isSingleton() {
+		return r.createSingleton(data, providerClient, parentIDs, resourcePath)
+	}
+
+	if method, operation := r.openAPIResource.getResourceOperations().getCreateOperation(); method == httpPut {
+		return r.createWithClientGeneratedID(data, providerClient, parentIDs, resourcePath, operation)
+	}
+
 	operation := r.openAPIResource.getResourceOperations().Post
 	requestPayload := r.createPayloadFromLocalStateData(data)
 	responsePayload := map[string]interface{}{}
 
+	// the idempotency key is derived from the resource instance being created, so the same key is sent across the retries
+	// of the POST request as well as by subsequent terraform executions creating the same instance (e,g: apply re-run
+	// after the plugin crashed mid-create)
+	if operation != nil && operation.IdempotencyKeyHeader != "" {
+		idempotencyKey, err := buildIdempotencyKey(r.openAPIResource.getResourceName(), parentIDs, requestPayload)
+		if err != nil {
+			return err
+		}
+		providerClient = providerClient.WithIdempotencyKey(idempotencyKey)
+	}
+
 	res, err := providerClient.Post(r.openAPIResource, requestPayload, &responsePayload, parentIDs...)
 	if err != nil {
 		return err
@@ -107,18 +285,159 @@ func (r resourceFactory) create(data *schema.ResourceData, i interface{}) error
 		return fmt.Errorf("[resource='%s'] POST %s failed: %s", r.openAPIResource.getResourceName(), resourcePath, err)
 	}
 
+	operationRes, err := r.handleOperationPollingIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutCreate, parentIDs...)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after POST %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
+	longRunningOperationRes, err := r.handleLongRunningOperationIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutCreate, parentIDs...)
+	if err != nil {
+		return fmt.Errorf("long-running operation failed after POST %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
 	err = setStateID(r.openAPIResource, data, responsePayload)
 	if err != nil {
 		return err
 	}
 	log.Printf("[INFO] Resource '%s' ID: %s", resourcePath, data.Id())
 
-	err = r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutCreate)
+	pollRes, err := r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutCreate)
 	if err != nil {
 		return fmt.Errorf("polling mechanism failed after POST %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
 	}
 
-	return updateStateWithPayloadData(r.openAPIResource, responsePayload, data)
+	if err := updateStateWithPayloadData(r.openAPIResource, responsePayload, data); err != nil {
+		return err
+	}
+	return r.setETagAfterRequest(data, providerClient, res, parentIDs, operationRes, longRunningOperationRes, pollRes)
+}
+
+// buildIdempotencyKey returns the idempotency key sent when creating a resource instance. The key is the SHA-256 hash of
+// the resource name, the parent IDs and the create payload
+func buildIdempotencyKey(resourceName string, parentIDs []string, requestPayload map[string]interface{}) (string, error) {
+	payload, err := json.Marshal(requestPayload)
+	if err != nil {
+		return "", fmt.Errorf("failed to build the idempotency key: %s", err)
+	}
+	hash := sha256.New()
+	hash.Write([]byte(resourceName))
+	for _, parentID := range parentIDs {
+		hash.Write([]byte("/" + parentID))
+	}
+	hash.Write([]byte{0})
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// createWithClientGeneratedID creates resources that do not expose a POST operation on the root path via PUT on the
+// instance path (e,g: PUT /v1/buckets/{name}). The ID of the resource is the value provided by the user in the property
+// marked with the 'x-terraform-id' extension
+func (r resourceFactory) createWithClientGeneratedID(data *schema.ResourceData, providerClient ClientOpenAPI, parentIDs []string, resourcePath string, operation *specResourceOperation) error {
+	id, err := r.getClientGeneratedID(data)
+	if err != nil {
+		return err
+	}
+	requestPayload := r.createPayloadFromLocalStateData(data)
+	responsePayload := map[string]interface{}{}
+
+	res, err := providerClient.Put(r.openAPIResource, id, requestPayload, &responsePayload, parentIDs...)
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}); err != nil {
+		return fmt.Errorf("[resource='%s'] PUT %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, id, err)
+	}
+
+	data.SetId(id)
+	log.Printf("[INFO] Resource '%s' ID: %s", resourcePath, data.Id())
+
+	operationRes, err := r.handleOperationPollingIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutCreate, parentIDs...)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after PUT %s/%s call with response status code (%d): %s", resourcePath, id, res.StatusCode, err)
+	}
+
+	longRunningOperationRes, err := r.handleLongRunningOperationIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutCreate, parentIDs...)
+	if err != nil {
+		return fmt.Errorf("long-running operation failed after PUT %s/%s call with response status code (%d): %s", resourcePath, id, res.StatusCode, err)
+	}
+
+	pollRes, err := r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutCreate)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after PUT %s/%s call with response status code (%d): %s", resourcePath, id, res.StatusCode, err)
+	}
+
+	if err := updateStateWithPayloadData(r.openAPIResource, responsePayload, data); err != nil {
+		return err
+	}
+	return r.setETagAfterRequest(data, providerClient, res, parentIDs, operationRes, longRunningOperationRes, pollRes)
+}
+
+// getClientGeneratedID returns the value configured by the user for the resource identifier property
+func (r resourceFactory) getClientGeneratedID(data *schema.ResourceData) (string, error) {
+	identifierProperty, err := r.getResourceIdentifierProperty()
+	if err != nil {
+		return "", err
+	}
+	value, exists := data.GetOk(identifierProperty.getTerraformCompliantPropertyName())
+	if !exists {
+		return "", fmt.Errorf("[resource='%s'] missing value for identifier property '%s', which is required to create the resource via PUT", r.openAPIResource.getResourceName(), identifierProperty.Name)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// getResourceIdentifierProperty returns the resource schema property that identifies the resource
+func (r resourceFactory) getResourceIdentifierProperty() (*specSchemaDefinitionProperty, error) {
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	identifier, err := resourceSchema.getResourceIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	return resourceSchema.getProperty(identifier)
+}
+
+// createSingleton creates singleton resources via PUT. Since singleton resources are not identified by an ID returned by
+// the API, the terraform ID is synthesized from the resource path with the parent IDs resolved (e,g: /v1/projects/1234/quota)
+func (r resourceFactory) createSingleton(data *schema.ResourceData, providerClient ClientOpenAPI, parentIDs []string, resourcePath string) error {
+	operation := r.openAPIResource.getResourceOperations().Put
+	if operation == nil {
+		return fmt.Errorf("[resource='%s'] singleton resource does not support PUT operation, check the swagger file exposed on '%s'", r.openAPIResource.getResourceName(), resourcePath)
+	}
+	requestPayload := r.createPayloadFromLocalStateData(data)
+	responsePayload := map[string]interface{}{}
+
+	res, err := providerClient.Put(r.openAPIResource, "", requestPayload, &responsePayload, parentIDs...)
+	if err != nil {
+		return err
+	}
+	if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}); err != nil {
+		return fmt.Errorf("[resource='%s'] PUT %s failed: %s", r.openAPIResource.getResourceName(), resourcePath, err)
+	}
+
+	data.SetId(resourcePath)
+	log.Printf("[INFO] Singleton resource '%s' ID: %s", resourcePath, data.Id())
+
+	operationRes, err := r.handleOperationPollingIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutCreate, parentIDs...)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after PUT %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
+	longRunningOperationRes, err := r.handleLongRunningOperationIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutCreate, parentIDs...)
+	if err != nil {
+		return fmt.Errorf("long-running operation failed after PUT %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
+	pollRes, err := r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutCreate)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after PUT %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
+	if err := updateStateWithPayloadData(r.openAPIResource, responsePayload, data); err != nil {
+		return err
+	}
+	return r.setETagAfterRequest(data, providerClient, res, parentIDs, operationRes, longRunningOperationRes, pollRes)
 }
 
 func (r resourceFactory) read(data *schema.ResourceData, i interface{}) error {
@@ -129,7 +448,7 @@ func (r resourceFactory) read(data *schema.ResourceData, i interface{}) error {
 		return err
 	}
 
-	remoteData, err := r.readRemote(data.Id(), openAPIClient, parentsIDs...)
+	remoteData, res, err := r.readRemoteWithResponse(data.Id(), openAPIClient, parentsIDs...)
 
 	if err != nil {
 		if openapiErr, ok := err.(openapierr.Error); ok {
@@ -140,23 +459,33 @@ func (r resourceFactory) read(data *schema.ResourceData, i interface{}) error {
 		return fmt.Errorf("[resource='%s'] GET %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
 	}
 
-	return updateStateWithPayloadData(r.openAPIResource, remoteData, data)
+	if err := updateStateWithPayloadData(r.openAPIResource, remoteData, data); err != nil {
+		return err
+	}
+	return r.setETag(data, res)
 }
 
 func (r resourceFactory) readRemote(id string, providerClient ClientOpenAPI, parentIDs ...string) (map[string]interface{}, error) {
+	remoteData, _, err := r.readRemoteWithResponse(id, providerClient, parentIDs...)
+	return remoteData, err
+}
+
+// readRemoteWithResponse returns the resource remote data along with the GET response, which contains the response
+// headers (e,g: ETag)
+func (r resourceFactory) readRemoteWithResponse(id string, providerClient ClientOpenAPI, parentIDs ...string) (map[string]interface{}, *http.Response, error) {
 	var err error
 	responsePayload := map[string]interface{}{}
 	resp, err := providerClient.Get(r.openAPIResource, id, &responsePayload, parentIDs...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := checkHTTPStatusCode(r.openAPIResource, resp, []int{http.StatusOK}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	log.Printf("[DEBUG] GET '%s' response payload: %#v", r.openAPIResource.getResourceName(), responsePayload)
-	return responsePayload, nil
+	return responsePayload, resp, nil
 }
 
 func (r resourceFactory) getParentIDs(data *schema.ResourceData) ([]string, error) {
@@ -190,29 +519,125 @@ func (r resourceFactory) update(data *schema.ResourceData, i interface{}) error
 		return err
 	}
 
-	operation := r.openAPIResource.getResourceOperations().Put
+	triggeredActions := r.getTriggeredActions(data)
+	if len(triggeredActions) > 0 && !r.hasResourceChanges(data) {
+		// only trigger attributes changed, hence there is no need to update the resource itself
+		return r.performActions(data, providerClient, triggeredActions, parentsIDs, resourcePath)
+	}
+
+	method, operation := r.openAPIResource.getResourceOperations().getUpdateOperation()
 	if operation == nil {
-		return fmt.Errorf("[resource='%s'] resource does not support PUT operation, check the swagger file exposed on '%s'", r.openAPIResource.getResourceName(), resourcePath)
+		return fmt.Errorf("[resource='%s'] resource does not support PUT nor PATCH operations, check the swagger file exposed on '%s'", r.openAPIResource.getResourceName(), resourcePath)
 	}
-	requestPayload := r.createPayloadFromLocalStateData(data)
 	responsePayload := map[string]interface{}{}
 	if err := r.checkImmutableFields(data, providerClient, parentsIDs...); err != nil {
 		return err
 	}
-	res, err := providerClient.Put(r.openAPIResource, data.Id(), requestPayload, &responsePayload, parentsIDs...)
+	var res *http.Response
+	expectedStatusCodes := []int{http.StatusOK, http.StatusAccepted}
+	if method == httpPatch {
+		requestPayload, err := r.createPatchPayloadFromLocalStateData(data, operation)
+		if err != nil {
+			return fmt.Errorf("[resource='%s'] failed to create the PATCH payload: %s", r.openAPIResource.getResourceName(), err)
+		}
+		res, err = r.getIfMatchClient(data, providerClient, operation).Patch(r.openAPIResource, data.Id(), requestPayload, &responsePayload, parentsIDs...)
+		if err != nil {
+			return err
+		}
+		expectedStatusCodes = append(expectedStatusCodes, http.StatusNoContent)
+	} else {
+		requestPayload := r.createPayloadFromLocalStateData(data)
+		res, err = r.getIfMatchClient(data, providerClient, operation).Put(r.openAPIResource, data.Id(), requestPayload, &responsePayload, parentsIDs...)
+		if err != nil {
+			return err
+		}
+	}
+	if err := checkHTTPStatusCode(r.openAPIResource, res, expectedStatusCodes); err != nil {
+		return fmt.Errorf("[resource='%s'] UPDATE %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
+	}
+
+	operationRes, err := r.handleOperationPollingIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutUpdate, parentsIDs...)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after %s %s call with response status code (%d): %s", method, resourcePath, res.StatusCode, err)
+	}
+
+	longRunningOperationRes, err := r.handleLongRunningOperationIfConfigured(&responsePayload, data, providerClient, operation, res, schema.TimeoutUpdate, parentsIDs...)
+	if err != nil {
+		return fmt.Errorf("long-running operation failed after %s %s call with response status code (%d): %s", method, resourcePath, res.StatusCode, err)
+	}
+
+	pollRes, err := r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutUpdate)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after %s %s call with response status code (%d): %s", method, resourcePath, res.StatusCode, err)
+	}
+
+	err = updateStateWithPayloadData(r.openAPIResource, responsePayload, data)
 	if err != nil {
 		return err
 	}
-	if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusAccepted}); err != nil {
-		return fmt.Errorf("[resource='%s'] UPDATE %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
+	if err := r.setETagAfterRequest(data, providerClient, res, parentsIDs, operationRes, longRunningOperationRes, pollRes); err != nil {
+		return err
 	}
+	return r.performActions(data, providerClient, triggeredActions, parentsIDs, resourcePath)
+}
 
-	err = r.handlePollingIfConfigured(&responsePayload, data, providerClient, operation, res.StatusCode, schema.TimeoutUpdate)
+// getTriggeredActions returns the resource actions whose trigger attribute value changed
+func (r resourceFactory) getTriggeredActions(data *schema.ResourceData) []*specResourceAction {
+	var triggeredActions []*specResourceAction
+	for _, action := range r.openAPIResource.getResourceOperations().Actions {
+		if data.HasChange(action.TriggerAttribute) {
+			triggeredActions = append(triggeredActions, action)
+		}
+	}
+	return triggeredActions
+}
+
+// hasResourceChanges returns true if any of the resource schema properties changed
+func (r resourceFactory) hasResourceChanges(data *schema.ResourceData) bool {
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
 	if err != nil {
-		return fmt.Errorf("polling mechanism failed after PUT %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+		// assuming there are changes so the update operation surfaces the error
+		return true
 	}
+	for _, property := range resourceSchema.Properties {
+		if data.HasChange(property.getTerraformCompliantPropertyName()) {
+			return true
+		}
+	}
+	return false
+}
 
-	return updateStateWithPayloadData(r.openAPIResource, responsePayload, data)
+// performActions invokes the given resource actions (custom methods) in order. The action responses go through the
+// same polling mechanisms as the rest of the operations. Once all the actions are performed, the state is refreshed with
+// the resource remote data as the actions might have changed the resource
+func (r resourceFactory) performActions(data *schema.ResourceData, providerClient ClientOpenAPI, actions []*specResourceAction, parentIDs []string, resourcePath string) error {
+	if len(actions) == 0 {
+		return nil
+	}
+	for _, action := range actions {
+		responsePayload := map[string]interface{}{}
+		res, err := providerClient.PerformAction(r.openAPIResource, action, data.Id(), map[string]interface{}{}, &responsePayload, parentIDs...)
+		if err != nil {
+			return err
+		}
+		if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent}); err != nil {
+			return fmt.Errorf("[resource='%s'] POST %s/%s:%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), action.Name, err)
+		}
+		_, err = r.handleOperationPollingIfConfigured(&responsePayload, data, providerClient, action.Operation, res, schema.TimeoutUpdate, parentIDs...)
+		if err != nil {
+			return fmt.Errorf("polling mechanism failed after POST %s/%s:%s call with response status code (%d): %s", resourcePath, data.Id(), action.Name, res.StatusCode, err)
+		}
+		_, err = r.handleLongRunningOperationIfConfigured(&responsePayload, data, providerClient, action.Operation, res, schema.TimeoutUpdate, parentIDs...)
+		if err != nil {
+			return fmt.Errorf("long-running operation failed after POST %s/%s:%s call with response status code (%d): %s", resourcePath, data.Id(), action.Name, res.StatusCode, err)
+		}
+		_, err = r.handlePollingIfConfigured(&responsePayload, data, providerClient, action.Operation, res.StatusCode, schema.TimeoutUpdate)
+		if err != nil {
+			return fmt.Errorf("polling mechanism failed after POST %s/%s:%s call with response status code (%d): %s", resourcePath, data.Id(), action.Name, res.StatusCode, err)
+		}
+		log.Printf("[INFO] [resource='%s'] action '%s' performed on resource '%s'", r.openAPIResource.getResourceName(), action.Name, data.Id())
+	}
+	return r.read(data, providerClient)
 }
 
 func (r resourceFactory) delete(data *schema.ResourceData, i interface{}) error {
@@ -224,10 +649,15 @@ func (r resourceFactory) delete(data *schema.ResourceData, i interface{}) error
 	}
 
 	operation := r.openAPIResource.getResourceOperations().Delete
+	if operation == nil && r.openAPIResource.isSingleton() {
+		// singleton resources can not be deleted, hence the resource is just removed from the state
+		log.Printf("[INFO] [resource='%s'] singleton resource does not support DELETE operation, the resource will only be removed from the state", r.openAPIResource.getResourceName())
+		return nil
+	}
 	if operation == nil {
 		return fmt.Errorf("[resource='%s'] resource does not support DELETE operation, check the swagger file exposed on '%s'", r.openAPIResource.getResourceName(), resourcePath)
 	}
-	res, err := providerClient.Delete(r.openAPIResource, data.Id(), parentsIDs...)
+	res, err := r.getIfMatchClient(data, providerClient, operation).Delete(r.openAPIResource, data.Id(), parentsIDs...)
 	if err != nil {
 		return err
 	}
@@ -240,7 +670,17 @@ func (r resourceFactory) delete(data *schema.ResourceData, i interface{}) error
 		return fmt.Errorf("[resource='%s'] DELETE %s/%s failed: %s", r.openAPIResource.getResourceName(), resourcePath, data.Id(), err)
 	}
 
-	err = r.handlePollingIfConfigured(nil, data, providerClient, operation, res.StatusCode, schema.TimeoutDelete)
+	_, err = r.handleOperationPollingIfConfigured(nil, data, providerClient, operation, res, schema.TimeoutDelete, parentsIDs...)
+	if err != nil {
+		return fmt.Errorf("polling mechanism failed after DELETE %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
+	_, err = r.handleLongRunningOperationIfConfigured(nil, data, providerClient, operation, res, schema.TimeoutDelete, parentsIDs...)
+	if err != nil {
+		return fmt.Errorf("long-running operation failed after DELETE %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
+	}
+
+	_, err = r.handlePollingIfConfigured(nil, data, providerClient, operation, res.StatusCode, schema.TimeoutDelete)
 	if err != nil {
 		return fmt.Errorf("polling mechanism failed after DELETE %s call with response status code (%d): %s", resourcePath, res.StatusCode, err)
 	}
@@ -253,6 +693,9 @@ func (r resourceFactory) importer() *schema.ResourceImporter {
 		State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
 			results := make([]*schema.ResourceData, 1, 1)
 			results[0] = data
+			if r.openAPIResource.isSingleton() {
+				return results, r.importSingleton(data, i)
+			}
 			parentResourceInfo := r.openAPIResource.getParentResourceInfo()
 			if parentResourceInfo != nil {
 				parentPropertyNames := parentResourceInfo.getParentPropertiesNames()
@@ -274,6 +717,19 @@ func (r resourceFactory) importer() *schema.ResourceImporter {
 				}
 				data.SetId(ids[len(ids)-1])
 			}
+			// Resources created via PUT are identified by a value provided by the user, hence the identifier property is
+			// populated with the imported ID in case the API does not return it
+			if method, _ := r.openAPIResource.getResourceOperations().getCreateOperation(); method == httpPut {
+				identifierProperty, err := r.getResourceIdentifierProperty()
+				if err != nil {
+					return results, err
+				}
+				if identifierProperty.Type == typeString {
+					if err := data.Set(identifierProperty.getTerraformCompliantPropertyName(), data.Id()); err != nil {
+						return results, err
+					}
+				}
+			}
 			// If the resources is NOT a sub-resource and just a top level resource then the array passed in will just contain
 			// 	the data object we get from terraform core without any updates.
 			err := r.read(data, i)
@@ -282,11 +738,37 @@ func (r resourceFactory) importer() *schema.ResourceImporter {
 	}
 }
 
-func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, responseStatusCode int, timeoutFor string) error {
+// importSingleton imports singleton resources. The expected format for the ID provided when importing a singleton
+// sub-resource is 1234/567 where 1234 and 567 would be the parent IDs. The ID provided when importing a top level
+// singleton resource is ignored. In both cases, the ID is then synthesized from the resource path (same as in create)
+func (r resourceFactory) importSingleton(data *schema.ResourceData, i interface{}) error {
+	parentResourceInfo := r.openAPIResource.getParentResourceInfo()
+	if parentResourceInfo != nil {
+		parentPropertyNames := parentResourceInfo.getParentPropertiesNames()
+		ids := strings.Split(strings.Trim(data.Id(), "/"), "/")
+		if len(parentPropertyNames) != len(ids) {
+			return fmt.Errorf("can not import a singleton subresource without providing all the parent IDs, expected %d and got %d parent IDs", len(parentPropertyNames), len(ids))
+		}
+		for idx, parentPropertyName := range parentPropertyNames {
+			data.Set(parentPropertyName, ids[idx])
+		}
+	}
+	_, resourcePath, err := getParentIDsAndResourcePath(r.openAPIResource, data)
+	if err != nil {
+		return err
+	}
+	data.SetId(resourcePath)
+	return r.read(data, i)
+}
+
+// handlePollingIfConfigured polls the resource until it reaches one of the target statuses if the response matching the
+// given status code has polling enabled. The response of the last GET request performed (if any) is returned so the
+// resource ETag can be read from it
+func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, responseStatusCode int, timeoutFor string) (*http.Response, error) {
 	response := operation.responses.getResponse(responseStatusCode)
 
 	if response == nil || !response.isPollingEnabled {
-		return nil
+		return nil, nil
 	}
 
 	targetStatuses := response.pollTargetStatuses
@@ -308,36 +790,400 @@ func (r resourceFactory) handlePollingIfConfigured(responsePayload *map[string]i
 	log.Printf("[DEBUG] target statuses (%s); pending statuses (%s)", targetStatuses, pendingStatuses)
 	log.Printf("[INFO] Waiting for resource '%s' to reach a completion status (%s)", r.openAPIResource.getResourceName(), targetStatuses)
 
+	var readRes *http.Response
 	stateConf := &resource.StateChangeConf{
 		Pending:      pendingStatuses,
 		Target:       targetStatuses,
-		Refresh:      r.resourceStateRefreshFunc(resourceLocalData, providerClient),
+		Refresh:      r.resourceStateRefreshFunc(resourceLocalData, providerClient, response, &readRes),
 		Timeout:      resourceLocalData.Timeout(timeoutFor),
 		PollInterval: r.defaultPollInterval,
 		MinTimeout:   r.defaultPollMinTimeout,
 		Delay:        r.defaultPollDelay,
 	}
+	r.applyPollConfiguration(stateConf, response)
 
 	// Wait, catching any errors
 	remoteData, err := stateConf.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for resource to reach a completion status (%s) [valid pending statuses (%s)]: %s", targetStatuses, pendingStatuses, err)
+		return nil, fmt.Errorf("error waiting for resource to reach a completion status (%s) [valid pending statuses (%s)]: %s", targetStatuses, pendingStatuses, err)
 	}
 	if responsePayload != nil {
 		remoteDataCasted, ok := remoteData.(map[string]interface{})
 		if ok {
 			*responsePayload = remoteDataCasted
 		} else {
-			return fmt.Errorf("failed to convert remote data (%s) to map[string]interface{}", reflect.TypeOf(remoteData))
+			return nil, fmt.Errorf("failed to convert remote data (%s) to map[string]interface{}", reflect.TypeOf(remoteData))
 		}
 	}
-	return nil
+	return readRes, nil
+}
+
+// applyPollConfiguration overrides the timings of the given state change configuration with the poll configuration
+// defined in the response (spec extensions) and in the plugin configuration of the resource, the latter taking
+// precedence. If the backoff factor is configured, the poll interval is increased after each refresh (see pollBackoffRefreshFunc)
+func (r resourceFactory) applyPollConfiguration(stateConf *resource.StateChangeConf, response *specResponse) {
+	pollConfiguration := r.pollConfiguration
+	if response != nil {
+		pollConfiguration = response.pollConfiguration.merge(r.pollConfiguration)
+	}
+	if pollConfiguration.delay > 0 {
+		stateConf.Delay = pollConfiguration.delay
+	}
+	if !pollConfiguration.isIntervalConfigured() {
+		return
+	}
+	if pollConfiguration.interval == 0 {
+		pollConfiguration.interval = r.defaultPollInterval
+	}
+	if pollConfiguration.maxInterval == 0 || pollConfiguration.maxInterval > maxPollInterval {
+		pollConfiguration.maxInterval = maxPollInterval
+	}
+	log.Printf("[DEBUG] poll configuration for resource '%s': delay (%s); interval (%s); max interval (%s); backoff factor (%v)", r.openAPIResource.getResourceName(), stateConf.Delay, pollConfiguration.interval, pollConfiguration.maxInterval, pollConfiguration.backoffFactor)
+	stateConf.PollInterval = pollConfiguration.interval
+	if stateConf.PollInterval > pollConfiguration.maxInterval {
+		stateConf.PollInterval = pollConfiguration.maxInterval
+	}
+	stateConf.MinTimeout = stateConf.PollInterval
+	if pollConfiguration.backoffFactor > 1 {
+		stateConf.Refresh = pollBackoffRefreshFunc(stateConf, pollConfiguration)
+	}
+}
+
+// pollBackoffRefreshFunc wraps the refresh function of the given state change configuration so the poll interval is
+// increased after each refresh by the backoff factor up to the max interval. The state change configuration reads the
+// poll interval after each refresh to decide how long to wait before the next one
+func pollBackoffRefreshFunc(stateConf *resource.StateChangeConf, pollConfiguration specPollConfiguration) resource.StateRefreshFunc {
+	refresh := stateConf.Refresh
+	return func() (interface{}, string, error) {
+		res, state, err := refresh()
+		stateConf.PollInterval = pollConfiguration.nextInterval(stateConf.PollInterval)
+		stateConf.MinTimeout = stateConf.PollInterval
+		return res, state, err
+	}
+}
+
+// handleOperationPollingIfConfigured handles asynchronous requests where the API responds with the Operation-Location (or
+// Location) header pointing at an operation resource that reports the progress of the request. If the response is
+// configured with the 'x-terraform-resource-poll-operation-location' extension, the operation resource is polled until it
+// completes. Once completed, the resource is read and the response payload is replaced with the resource remote data. The
+// response payload is expected to be nil for DELETE operations, in which case the resource is not read. The response of
+// the GET request reading the resource (if any) is returned so the resource ETag can be read from it
+func (r resourceFactory) handleOperationPollingIfConfigured(responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, res *http.Response, timeoutFor string, parentIDs ...string) (*http.Response, error) {
+	response := operation.responses.getResponse(res.StatusCode)
+	if response == nil || response.pollOperation == nil {
+		return nil, nil
+	}
+	operationURL := res.Header.Get(operationLocationHeader)
+	if operationURL == "" {
+		operationURL = res.Header.Get(locationHeader)
+	}
+	if operationURL == "" {
+		return nil, fmt.Errorf("response is missing the '%s' or '%s' header containing the operation URL", operationLocationHeader, locationHeader)
+	}
+
+	log.Printf("[INFO] Waiting for operation '%s' of resource '%s' to complete", operationURL, r.openAPIResource.getResourceName())
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{pollOperationRunningStatus},
+		Target:  []string{pollOperationCompletedStatus},
+		Refresh: r.operationStateRefreshFunc(operationURL, response.pollOperation, providerClient, parentIDs...),
+		Timeout: resourceLocalData.Timeout(timeoutFor),
+		Delay:   r.defaultPollDelay,
+		// PollInterval and MinTimeout are not set on purpose so the operation is polled with exponential backoff
+	}
+	r.applyPollConfiguration(stateConf, response)
+	operationPayload, err := stateConf.WaitForState()
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for operation '%s' to complete: %s", operationURL, err)
+	}
+	if responsePayload == nil {
+		return nil, nil
+	}
+
+	id := resourceLocalData.Id()
+	if id == "" {
+		id, err = r.getOperationResourceID(operationPayload.(map[string]interface{}), *responsePayload, response.pollOperation)
+		if err != nil {
+			return nil, err
+		}
+	}
+	readRes, err := r.readRemoteAfterOperation(responsePayload, resourceLocalData, providerClient, id, parentIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource '%s' (%s) after operation '%s' completed: %s", r.openAPIResource.getResourceName(), id, operationURL, err)
+	}
+	return readRes, nil
+}
+
+// readRemoteAfterOperation reads the resource with the given id once the asynchronous operation completed and replaces
+// the response payload with the resource remote data. If the resource local data does not have an ID yet (create), the
+// given id is added to the payload in case the API does not return the identifier property since it is required to set
+// the state ID afterwards. The GET response is returned so the resource ETag can be read from it
+func (r resourceFactory) readRemoteAfterOperation(responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, id string, parentIDs ...string) (*http.Response, error) {
+	remoteData, readRes, err := r.readRemoteWithResponse(id, providerClient, parentIDs...)
+	if err != nil {
+		return nil, err
+	}
+	if resourceLocalData.Id() == "" {
+		identifierProperty, err := r.getResourceIdentifierProperty()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := remoteData[identifierProperty.Name]; !exists {
+			remoteData[identifierProperty.Name] = id
+		}
+	}
+	*responsePayload = remoteData
+	return readRes, nil
+}
+
+// handleLongRunningOperationIfConfigured handles asynchronous requests where the API responds with a long-running
+// operation as defined by the Google API design guidelines (AIP-151), containing the 'name', 'done', 'error' and
+// 'response' fields. If the response is configured with the 'x-terraform-resource-long-running-operation' extension,
+// the operation is polled via GET /operations/{name} until it is done. If the operation finished with an error, the
+// error message is returned; otherwise the resource named in the operation response is read and the response payload
+// is replaced with the resource remote data. The response payload is expected to be nil for DELETE operations, in which
+// case the operation is read from the response body and the resource is not read once the operation is done. The
+// response of the GET request reading the resource (if any) is returned so the resource ETag can be read from it
+func (r resourceFactory) handleLongRunningOperationIfConfigured(responsePayload *map[string]interface{}, resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, operation *specResourceOperation, res *http.Response, timeoutFor string, parentIDs ...string) (*http.Response, error) {
+	response := operation.responses.getResponse(res.StatusCode)
+	if response == nil || !response.isLongRunningOperation {
+		return nil, nil
+	}
+	longRunningOperation := map[string]interface{}{}
+	if responsePayload != nil {
+		longRunningOperation = *responsePayload
+	} else if res.Body != nil {
+		if err := json.NewDecoder(res.Body).Decode(&longRunningOperation); err != nil {
+			return nil, fmt.Errorf("failed to read the long-running operation from the response body: %s", err)
+		}
+	}
+	name, _ := longRunningOperation[longRunningOperationNameField].(string)
+	if name == "" {
+		return nil, fmt.Errorf("response payload is not a long-running operation, missing the '%s' property", longRunningOperationNameField)
+	}
+
+	result, state, err := longRunningOperationState(name, longRunningOperation)
+	if err != nil {
+		return nil, err
+	}
+	if state != pollOperationCompletedStatus {
+		log.Printf("[INFO] Waiting for long-running operation '%s' of resource '%s' to be done", name, r.openAPIResource.getResourceName())
+		stateConf := &resource.StateChangeConf{
+			Pending: []string{pollOperationRunningStatus},
+			Target:  []string{pollOperationCompletedStatus},
+			Refresh: r.longRunningOperationStateRefreshFunc(name, providerClient, parentIDs...),
+			Timeout: resourceLocalData.Timeout(timeoutFor),
+			Delay:   r.defaultPollDelay,
+			// PollInterval and MinTimeout are not set on purpose so the operation is polled with exponential backoff
+		}
+		r.applyPollConfiguration(stateConf, response)
+		result, err = stateConf.WaitForState()
+		if err != nil {
+			return nil, fmt.Errorf("error waiting for long-running operation '%s' to be done: %s", name, err)
+		}
+	}
+	if responsePayload == nil {
+		return nil, nil
+	}
+
+	id := resourceLocalData.Id()
+	if id == "" {
+		id, err = r.getLongRunningOperationResourceID(result.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	readRes, err := r.readRemoteAfterOperation(responsePayload, resourceLocalData, providerClient, id, parentIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource '%s' (%s) after long-running operation '%s' was done: %s", r.openAPIResource.getResourceName(), id, name, err)
+	}
+	return readRes, nil
+}
+
+// longRunningOperationStateRefreshFunc returns a resource.StateRefreshFunc that reads the long-running operation and maps
+// the operation 'done' field to either pollOperationRunningStatus or pollOperationCompletedStatus
+func (r resourceFactory) longRunningOperationStateRefreshFunc(name string, providerClient ClientOpenAPI, parentIDs ...string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		longRunningOperation := map[string]interface{}{}
+		res, err := providerClient.GetLongRunningOperation(r.openAPIResource, name, &longRunningOperation, parentIDs...)
+		if err != nil {
+			return nil, "", fmt.Errorf("error on retrieving long-running operation '%s': %s", name, err)
+		}
+		if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK}); err != nil {
+			return nil, "", fmt.Errorf("error on retrieving long-running operation '%s': %s", name, err)
+		}
+		return longRunningOperationState(name, longRunningOperation)
+	}
+}
+
+// longRunningOperationState returns the operation result (the 'response' field) and pollOperationCompletedStatus if the
+// operation is done; pollOperationRunningStatus otherwise. If the operation is done with an error, the error message is
+// returned as an error
+func longRunningOperationState(name string, longRunningOperation map[string]interface{}) (interface{}, string, error) {
+	if done, _ := longRunningOperation[longRunningOperationDoneField].(bool); !done {
+		log.Printf("[DEBUG] long-running operation '%s' is not done yet", name)
+		return longRunningOperation, pollOperationRunningStatus, nil
+	}
+	if operationError, exists := longRunningOperation[longRunningOperationErrorField]; exists && operationError != nil {
+		message := "no error message provided"
+		if errorDetails, ok := operationError.(map[string]interface{}); ok {
+			if errorMessage, ok := errorDetails[longRunningOperationErrorMessageField].(string); ok && errorMessage != "" {
+				message = errorMessage
+			}
+		}
+		return nil, "", fmt.Errorf("long-running operation '%s' failed: %s", name, message)
+	}
+	operationResponse, _ := longRunningOperation[longRunningOperationResponseField].(map[string]interface{})
+	if operationResponse == nil {
+		operationResponse = map[string]interface{}{}
+	}
+	return operationResponse, pollOperationCompletedStatus, nil
+}
+
+// getLongRunningOperationResourceID returns the ID of the resource contained in the long-running operation response. The
+// ID is read from the resource identifier property and, if not present, from the resource name (e,g: cdns/1234) in
+// which case the last segment is used
+func (r resourceFactory) getLongRunningOperationResourceID(operationResponse map[string]interface{}) (string, error) {
+	if identifierProperty, err := r.getResourceIdentifierProperty(); err == nil {
+		if value, exists := operationResponse[identifierProperty.Name]; exists && value != nil {
+			return formatResourceID(value), nil
+		}
+	}
+	if name, ok := operationResponse[longRunningOperationNameField].(string); ok && name != "" {
+		return formatResourceID(name), nil
+	}
+	return "", fmt.Errorf("could not find the resource ID in the long-running operation response")
+}
+
+// operationStateRefreshFunc returns a resource.StateRefreshFunc that reads the operation resource and maps the operation
+// status to either pollOperationRunningStatus or pollOperationCompletedStatus. An error is returned if the operation status
+// is one of the failed statuses, including the error details reported by the operation (if any)
+func (r resourceFactory) operationStateRefreshFunc(operationURL string, pollOperation *specPollOperation, providerClient ClientOpenAPI, parentIDs ...string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		operationPayload := map[string]interface{}{}
+		res, err := providerClient.GetOperation(r.openAPIResource, operationURL, &operationPayload, parentIDs...)
+		if err != nil {
+			return nil, "", fmt.Errorf("error on retrieving operation '%s': %s", operationURL, err)
+		}
+		if err := checkHTTPStatusCode(r.openAPIResource, res, []int{http.StatusOK}); err != nil {
+			return nil, "", fmt.Errorf("error on retrieving operation '%s': %s", operationURL, err)
+		}
+		status := getPayloadPropertyValue(operationPayload, pollOperation.statusField)
+		if status == nil {
+			return nil, "", fmt.Errorf("operation '%s' payload is missing the status property '%s'", operationURL, pollOperation.statusField)
+		}
+		statusValue := fmt.Sprintf("%v", status)
+		log.Printf("[DEBUG] operation '%s' status: %s", operationURL, statusValue)
+		if pollOperation.isFailed(statusValue) {
+			return nil, "", fmt.Errorf("operation '%s' failed with status '%s': %s", operationURL, statusValue, getErrorDetailsFromPayload(operationPayload, pollOperation.errorField))
+		}
+		if pollOperation.isCompleted(statusValue) {
+			return operationPayload, pollOperationCompletedStatus, nil
+		}
+		return operationPayload, pollOperationRunningStatus, nil
+	}
+}
+
+// getOperationResourceID returns the ID of the resource created by the operation. The ID is read from the operation
+// resource ID property and, if not present, from the identifier property of the initial response payload (if any). If
+// the operation returns the resource path or URL instead of the ID, the last segment is used (see formatResourceID)
+func (r resourceFactory) getOperationResourceID(operationPayload, responsePayload map[string]interface{}, pollOperation *specPollOperation) (string, error) {
+	value := getPayloadPropertyValue(operationPayload, pollOperation.resourceIDField)
+	if value == nil {
+		if identifierProperty, err := r.getResourceIdentifierProperty(); err == nil {
+			value = responsePayload[identifierProperty.Name]
+		}
+	}
+	if value == nil {
+		return "", fmt.Errorf("could not find the resource ID in the operation property '%s' nor in the response payload", pollOperation.resourceIDField)
+	}
+	return formatResourceID(value), nil
+}
+
+// formatResourceID returns the string representation of the given resource ID value. If the value is the resource path
+// or URL (e,g: /v1/cdns/1234) instead of the ID, the last segment is returned
+func formatResourceID(value interface{}) string {
+	var id string
+	switch v := value.(type) {
+	case float64:
+		id = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		id = fmt.Sprintf("%v", v)
+	}
+	id = strings.TrimRight(id, "/")
+	if idx := strings.LastIndex(id, "/"); idx >= 0 {
+		id = id[idx+1:]
+	}
+	return id
+}
+
+// getErrorDetailsFromPayload returns the error details found in the given payload error field (if any). Error values
+// that are not strings (e,g: objects) are returned JSON encoded
+func getErrorDetailsFromPayload(payload map[string]interface{}, errorField string) string {
+	if errorField == "" {
+		return "no error details provided"
+	}
+	errorValue := getPayloadPropertyValue(payload, errorField)
+	if errorValue == nil {
+		return "no error details provided"
+	}
+	if errorMessage, ok := errorValue.(string); ok {
+		return errorMessage
+	}
+	errorDetails, err := json.Marshal(errorValue)
+	if err != nil {
+		return fmt.Sprintf("%v", errorValue)
+	}
+	return string(errorDetails)
 }
 
-func (r resourceFactory) resourceStateRefreshFunc(resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI) resource.StateRefreshFunc {
+// payloadPathIndexRegex is used to find the array indexes in a payload path segment (e,g: details[0])
+var payloadPathIndexRegex = regexp.MustCompile(`\[(\d+)\]`)
+
+// getPayloadPropertyValue returns the value of the property found in the given JSON path. The path supports dot notation
+// to refer to nested properties and array indexes, optionally prefixed with '$' to refer to the root of the payload
+// (e,g: error.message, $.status.conditions[0].reason). Nil is returned if the property does not exist
+func getPayloadPropertyValue(payload map[string]interface{}, propertyPath string) interface{} {
+	propertyPath = strings.TrimPrefix(strings.TrimPrefix(propertyPath, "$"), ".")
+	var value interface{} = payload
+	if propertyPath == "" {
+		return value
+	}
+	for _, segment := range strings.Split(propertyPath, ".") {
+		name := segment
+		if idx := strings.Index(segment, "["); idx >= 0 {
+			name = segment[:idx]
+		}
+		if name != "" {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = object[name]
+		}
+		for _, match := range payloadPathIndexRegex.FindAllStringSubmatch(segment[len(name):], -1) {
+			array, ok := value.([]interface{})
+			if !ok {
+				return nil
+			}
+			index, _ := strconv.Atoi(match[1])
+			if index >= len(array) {
+				return nil
+			}
+			value = array[index]
+		}
+	}
+	return value
+}
+
+// resourceStateRefreshFunc returns a resource.StateRefreshFunc that reads the resource status. The status is read from
+// the response poll status field if configured; otherwise the resource schema status field is used. If the status is
+// one of the response poll failed statuses, an error containing the error details found in the response poll error
+// field (if configured) is returned so the polling fails fast. If readRes is not nil, it is set to the response of the
+// last successful GET request so the resource ETag can be read from it once the polling completes
+func (r resourceFactory) resourceStateRefreshFunc(resourceLocalData *schema.ResourceData, providerClient ClientOpenAPI, response *specResponse, readRes **http.Response) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		remoteData, err := r.readRemote(resourceLocalData.Id(), providerClient)
+		remoteData, res, err := r.readRemoteWithResponse(resourceLocalData.Id(), providerClient)
 		if err != nil {
 			if openapiErr, ok := err.(openapierr.Error); ok {
 				if openapierr.NotFound == openapiErr.Code() {
@@ -346,13 +1192,19 @@ func (r resourceFactory) resourceStateRefreshFunc(resourceLocalData *schema.Reso
 			}
 			return nil, "", fmt.Errorf("error on retrieving resource '%s' (%s) when waiting: %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
 		}
+		if readRes != nil {
+			*readRes = res
+		}
 
-		newStatus, err := r.getStatusValueFromPayload(remoteData)
+		newStatus, err := r.getResourceStatus(remoteData, response)
 		if err != nil {
 			return nil, "", fmt.Errorf("error occurred while retrieving status identifier value from payload for resource '%s' (%s): %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), err)
 		}
 
 		log.Printf("[DEBUG] resource status '%s' (%s): %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus)
+		if response.isPollFailedStatus(newStatus) {
+			return nil, "", fmt.Errorf("resource '%s' (%s) reached a failed status '%s': %s", r.openAPIResource.getResourceName(), resourceLocalData.Id(), newStatus, getErrorDetailsFromPayload(remoteData, response.pollErrorField))
+		}
 		return remoteData, newStatus, nil
 	}
 }
@@ -476,6 +1328,54 @@ func (r resourceFactory) createPayloadFromLocalStateData(resourceLocalData *sche
 	return input
 }
 
+// createPatchPayloadFromLocalStateData returns the PATCH request payload encoded as per the operation patch content type.
+// Only the properties that have changed (based on ResourceData.HasChange) are included in the payload:
+// - JSON Merge Patch (RFC 7396): a document containing the changed properties; properties that have been removed are set to null
+// - JSON Patch (RFC 6902): a list of add/replace/remove operations, one per changed property
+func (r resourceFactory) createPatchPayloadFromLocalStateData(resourceLocalData *schema.ResourceData, operation *specResourceOperation) (interface{}, error) {
+	contentType, err := operation.getPatchContentType()
+	if err != nil {
+		return nil, err
+	}
+	resourceSchema, err := r.openAPIResource.getResourceSchema()
+	if err != nil {
+		return nil, err
+	}
+	input := r.createPayloadFromLocalStateData(resourceLocalData)
+	mergePatch := map[string]interface{}{}
+	jsonPatch := []map[string]interface{}{}
+	for _, property := range resourceSchema.Properties {
+		if property.isReadOnly() || property.IsParentProperty {
+			continue
+		}
+		terraformName := property.getTerraformCompliantPropertyName()
+		if !resourceLocalData.HasChange(terraformName) {
+			continue
+		}
+		value, exists := input[property.Name]
+		mergePatch[property.Name] = value
+		oldValue, _ := resourceLocalData.GetChange(terraformName)
+		jsonPatchOperation := map[string]interface{}{"path": buildJSONPointer(property.Name)}
+		switch {
+		case !exists:
+			jsonPatchOperation["op"] = "remove"
+		case isEmptyValue(oldValue):
+			jsonPatchOperation["op"] = "add"
+			jsonPatchOperation["value"] = value
+		default:
+			jsonPatchOperation["op"] = "replace"
+			jsonPatchOperation["value"] = value
+		}
+		jsonPatch = append(jsonPatch, jsonPatchOperation)
+	}
+	if contentType == jsonPatchContentType {
+		log.Printf("[DEBUG] [resource='%s'] createPatchPayloadFromLocalStateData (%s): %s", r.openAPIResource.getResourceName(), contentType, sPrettyPrint(jsonPatch))
+		return jsonPatch, nil
+	}
+	log.Printf("[DEBUG] [resource='%s'] createPatchPayloadFromLocalStateData (%s): %s", r.openAPIResource.getResourceName(), contentType, sPrettyPrint(mergePatch))
+	return mergePatch, nil
+}
+
 func (r resourceFactory) populatePayload(input map[string]interface{}, property *specSchemaDefinitionProperty, dataValue interface{}) error {
 	if property.isReadOnly() {
 		return nil
@@ -568,6 +1468,22 @@ func (r resourceFactory) populatePayload(input map[string]interface{}, property
 	return nil
 }
 
+// getResourceStatus returns the resource status found in the given payload. If the response is configured with a poll
+// status field, the status is read from the given JSON path; otherwise the resource schema status field is used
+func (r resourceFactory) getResourceStatus(payload map[string]interface{}, response *specResponse) (string, error) {
+	if response == nil || response.pollStatusField == "" {
+		return r.getStatusValueFromPayload(payload)
+	}
+	status := getPayloadPropertyValue(payload, response.pollStatusField)
+	switch status.(type) {
+	case nil:
+		return "", fmt.Errorf("could not find the status field '%s' in the payload", response.pollStatusField)
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("status field '%s' value does not have a supported type [string/number/bool]", response.pollStatusField)
+	}
+	return fmt.Sprintf("%v", status), nil
+}
+
 func (r resourceFactory) getStatusValueFromPayload(payload map[string]interface{}) (string, error) {
 	resourceSchema, err := r.openAPIResource.getResourceSchema()
 	if err != nil {
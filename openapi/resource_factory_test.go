@@ -14,7 +14,9 @@ import (
 	"github.com/go-openapi/spec"
 
 	"encoding/json"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -45,6 +47,31 @@ func TestCreateSchemaResourceTimeout(t *testing.T) {
 	})
 }
 
+func TestCreateSchemaResourceTimeoutWithTimeoutsConfiguration(t *testing.T) {
+	Convey("Given a resource factory initialised with a spec resource that has the create timeout and a timeouts configuration", t, func() {
+		specTimeout := 30 * time.Minute
+		configuredTimeout := 20 * time.Minute
+		r := newResourceFactory(&specStubResource{
+			timeouts: &specTimeouts{Post: &specTimeout},
+		})
+		r.timeoutsConfiguration = &specTimeouts{Post: &configuredTimeout, Delete: &configuredTimeout}
+		Convey("When createSchemaResourceTimeout is called", func() {
+			timeouts, err := r.createSchemaResourceTimeout()
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the timeout defined in the spec should take precedence over the configured one", func() {
+				So(*timeouts.Create, ShouldEqual, specTimeout)
+			})
+			Convey("And the configured timeouts should be used when the spec does not define them", func() {
+				So(*timeouts.Delete, ShouldEqual, configuredTimeout)
+				So(timeouts.Read, ShouldBeNil)
+				So(timeouts.Update, ShouldBeNil)
+			})
+		})
+	})
+}
+
 func TestCreateTerraformResource(t *testing.T) {
 	Convey("Given a resource factory initialised with a spec resource that has an id and string property and supports all CRUD operations", t, func() {
 		r, resourceData := testCreateResourceFactory(t, idProperty, stringProperty)
@@ -98,6 +125,50 @@ func TestCreateTerraformResourceSchema(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a resource factory configured with a resource that has actions", t, func() {
+		r, _ := testCreateResourceFactory(t, idProperty, stringProperty)
+		specResource := r.openAPIResource.(*specStubResource)
+		specResource.resourceActions = []*specResourceAction{{Name: "rotate", TriggerAttribute: "rotation_trigger", Operation: &specResourceOperation{}}}
+		Convey("When createResourceSchema is called", func() {
+			schema, err := r.createTerraformResourceSchema()
+			Convey("Then the schema returned should contain the action trigger attribute as an optional string", func() {
+				So(err, ShouldBeNil)
+				So(schema, ShouldContainKey, "rotation_trigger")
+				So(schema["rotation_trigger"].Optional, ShouldBeTrue)
+			})
+		})
+		Convey("When createResourceSchema is called and the trigger attribute conflicts with a resource property", func() {
+			specResource.resourceActions[0].TriggerAttribute = stringProperty.Name
+			_, err := r.createTerraformResourceSchema()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "resource 'resourceName' action 'rotate' trigger attribute 'string_property' conflicts with an existing property of the resource schema")
+			})
+		})
+	})
+	Convey("Given a resource factory configured with a resource which update operation sends the If-Match header", t, func() {
+		r, _ := testCreateResourceFactory(t, idProperty, stringProperty)
+		r.openAPIResource.(*specStubResource).resourcePutOperation.IfMatch = true
+		Convey("When createResourceSchema is called", func() {
+			schema, err := r.createTerraformResourceSchema()
+			Convey("Then the schema returned should contain the ETag attribute as a computed string", func() {
+				So(err, ShouldBeNil)
+				So(schema, ShouldContainKey, eTagAttributeName)
+				So(schema[eTagAttributeName].Computed, ShouldBeTrue)
+				So(schema[eTagAttributeName].Optional, ShouldBeFalse)
+			})
+		})
+	})
+	Convey("Given a resource factory configured with a resource which update operation sends the If-Match header and that has an 'etag' property that is not read only", t, func() {
+		eTagProperty := newStringSchemaDefinitionPropertyWithDefaults(eTagAttributeName, "", false, false, nil)
+		r, _ := testCreateResourceFactory(t, idProperty, stringProperty, eTagProperty)
+		r.openAPIResource.(*specStubResource).resourcePutOperation.IfMatch = true
+		Convey("When createResourceSchema is called", func() {
+			_, err := r.createTerraformResourceSchema()
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "resource 'resourceName' ETag attribute 'etag' conflicts with an existing property of the resource schema that is not read only")
+			})
+		})
+	})
 }
 
 func TestCreate(t *testing.T) {
@@ -119,6 +190,36 @@ func TestCreate(t *testing.T) {
 				So(resourceData.Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
 			})
 		})
+		Convey("When create is called with a POST operation that does not define the idempotency key header", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: "someID",
+				},
+			}
+			err := r.create(resourceData, client)
+			Convey("Then the error returned should be nil and no idempotency key should be generated", func() {
+				So(err, ShouldBeNil)
+				So(client.idempotencyKeyReceived, ShouldBeEmpty)
+			})
+		})
+		Convey("When create is called twice for the same resource instance with a POST operation that defines the idempotency key header", func() {
+			r.openAPIResource.(*specStubResource).resourcePostOperation = &specResourceOperation{IdempotencyKeyHeader: "Idempotency-Key"}
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: "someID",
+				},
+			}
+			err := r.create(resourceData, client)
+			firstIdempotencyKey := client.idempotencyKeyReceived
+			_, sameResourceData := testCreateResourceFactory(t, idProperty, stringProperty)
+			err2 := r.create(sameResourceData, client)
+			Convey("Then the same idempotency key derived from the resource instance should be sent in both creations", func() {
+				So(err, ShouldBeNil)
+				So(err2, ShouldBeNil)
+				So(firstIdempotencyKey, ShouldHaveLength, 64)
+				So(client.idempotencyKeyReceived, ShouldEqual, firstIdempotencyKey)
+			})
+		})
 		Convey("When create is called with resource data and a client configured to return an error when POST is called", func() {
 			createError := fmt.Errorf("some error when deleting")
 			client := &clientOpenAPIStub{
@@ -200,6 +301,128 @@ func TestCreate(t *testing.T) {
 	})
 }
 
+func TestCreateSingleton(t *testing.T) {
+	Convey("Given a resource factory configured with a singleton resource", t, func() {
+		testSchema := newTestSchema(stringProperty)
+		resourceData := testSchema.getResourceData(t)
+		specResource := newSpecStubResourceWithOperations("settings", "/v1/account/settings", false, testSchema.getSchemaDefinition(), nil, &specResourceOperation{}, &specResourceOperation{}, nil)
+		specResource.singleton = true
+		r := newResourceFactory(specResource)
+		Convey("When create is called with resource data and a client", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					stringProperty.Name: "someExtraValueThatProvesResponseDataIsPersisted",
+				},
+			}
+			err := r.create(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the resource should have been created with a PUT request that does not contain an id", func() {
+				So(client.idReceived, ShouldBeEmpty)
+			})
+			Convey("And resourceData should be populated with the values returned by the API and the ID should be the resource path", func() {
+				So(resourceData.Id(), ShouldEqual, "/v1/account/settings")
+				So(resourceData.Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
+			})
+		})
+		Convey("When create is called with resource data and a client that returns a non expected http code", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{},
+				returnHTTPCode:  http.StatusInternalServerError,
+			}
+			err := r.create(resourceData, client)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "[resource='settings'] PUT /v1/account/settings failed: [resource='settings'] HTTP Response Status Code 500 not matching expected one [200 201 202] ()")
+			})
+		})
+		Convey("When delete is called with resource data and a client", func() {
+			client := &clientOpenAPIStub{}
+			err := r.delete(resourceData, client)
+			Convey("Then the error returned should be nil since singleton resources without DELETE operation are just removed from the state", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+	Convey("Given a resource factory configured with a singleton resource that does not have a PUT operation", t, func() {
+		testSchema := newTestSchema(stringProperty)
+		resourceData := testSchema.getResourceData(t)
+		specResource := newSpecStubResourceWithOperations("settings", "/v1/account/settings", false, testSchema.getSchemaDefinition(), nil, nil, &specResourceOperation{}, nil)
+		specResource.singleton = true
+		r := newResourceFactory(specResource)
+		Convey("When create is called with resource data and a client", func() {
+			err := r.create(resourceData, &clientOpenAPIStub{})
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "[resource='settings'] singleton resource does not support PUT operation, check the swagger file exposed on '/v1/account/settings'")
+			})
+		})
+	})
+}
+
+func TestCreateWithClientGeneratedID(t *testing.T) {
+	Convey("Given a resource factory configured with a resource that is created via PUT on the instance path", t, func() {
+		nameProperty := newStringSchemaDefinitionProperty("name", "", true, false, false, true, false, false, true, false, "bucketName")
+		testSchema := newTestSchema(nameProperty, stringProperty)
+		resourceData := testSchema.getResourceData(t)
+		specResource := newSpecStubResourceWithOperations("buckets", "/v1/buckets", false, testSchema.getSchemaDefinition(), nil, &specResourceOperation{}, &specResourceOperation{}, &specResourceOperation{})
+		r := newResourceFactory(specResource)
+		Convey("When createTerraformResourceSchema is called", func() {
+			resourceSchema, err := r.createTerraformResourceSchema()
+			Convey("Then the identifier property should force a new resource as the resource can not be renamed via PUT", func() {
+				So(err, ShouldBeNil)
+				So(resourceSchema[nameProperty.Name].ForceNew, ShouldBeTrue)
+				So(resourceSchema[stringProperty.Name].ForceNew, ShouldBeFalse)
+			})
+		})
+		Convey("When create is called with resource data and a client", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					nameProperty.Name:   "bucketName",
+					stringProperty.Name: "someExtraValueThatProvesResponseDataIsPersisted",
+				},
+			}
+			err := r.create(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the resource should have been created with a PUT request using the ID provided by the user", func() {
+				So(client.idReceived, ShouldEqual, "bucketName")
+			})
+			Convey("And resourceData should be populated with the values returned by the API and the ID should be the user provided one", func() {
+				So(resourceData.Id(), ShouldEqual, "bucketName")
+				So(resourceData.Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
+			})
+		})
+		Convey("When create is called with resource data and a client that returns a non expected http code", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{},
+				returnHTTPCode:  http.StatusInternalServerError,
+			}
+			err := r.create(resourceData, client)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "[resource='buckets'] PUT /v1/buckets/bucketName failed: [resource='buckets'] HTTP Response Status Code 500 not matching expected one [200 201 202] ()")
+			})
+		})
+		Convey("When the resourceImporter State method is invoked with the user provided ID and the API response does not contain the identifier", func() {
+			resourceData.SetId("importedBucket")
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					stringProperty.Name: "someOtherStringValue",
+				},
+			}
+			data, err := r.importer().State(resourceData, client)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the identifier property should be populated with the imported ID", func() {
+				So(data[0].Id(), ShouldEqual, "importedBucket")
+				So(data[0].Get(nameProperty.Name), ShouldEqual, "importedBucket")
+				So(data[0].Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
+			})
+		})
+	})
+}
+
 func TestRead(t *testing.T) {
 	Convey("Given a resource factory", t, func() {
 		r, resourceData := testCreateResourceFactory(t, idProperty, stringProperty)
@@ -435,6 +658,54 @@ func TestUpdate(t *testing.T) {
 		})
 	})
 
+	Convey("Given a resource factory for a resource that only supports updates via PATCH", t, func() {
+		r, resourceData := testCreateResourceFactoryWithID(t, idProperty, stringProperty)
+		specResource := r.openAPIResource.(*specStubResource)
+		specResource.resourcePutOperation = nil
+		specResource.resourcePatchOperation = &specResourceOperation{}
+		client := &clientOpenAPIStub{
+			responsePayload: map[string]interface{}{
+				idProperty.Name:     "id",
+				stringProperty.Name: "someExtraValueThatProvesResponseDataIsPersisted",
+			},
+		}
+		Convey("When update is called with resource data and a client", func() {
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the request payload should be a JSON Merge Patch document containing the changed properties", func() {
+				So(client.requestPayloadReceived, ShouldResemble, map[string]interface{}{
+					idProperty.Name:     idProperty.Default,
+					stringProperty.Name: stringProperty.Default,
+				})
+			})
+			Convey("And resourceData should be populated with the values returned by the API", func() {
+				So(resourceData.Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
+			})
+		})
+		Convey("When update is called with resource data and the PATCH operation is configured with the JSON Patch content type", func() {
+			specResource.resourcePatchOperation.PatchContentType = jsonPatchContentType
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the request payload should contain the JSON Patch operations for the changed properties", func() {
+				So(client.requestPayloadReceived, ShouldResemble, []map[string]interface{}{
+					{"op": "add", "path": "/string_property", "value": stringProperty.Default},
+					{"op": "add", "path": "/id", "value": idProperty.Default},
+				})
+			})
+		})
+		Convey("When update is called with resource data and the PATCH operation is configured with a non supported content type", func() {
+			specResource.resourcePatchOperation.PatchContentType = "application/json"
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should match the expected one", func() {
+				So(err.Error(), ShouldEqual, "[resource='resourceName'] failed to create the PATCH payload: patch content type 'application/json' not supported - must use application/merge-patch+json or application/json-patch+json")
+			})
+		})
+	})
+
 	Convey("Given a resource factory with no update operation configured", t, func() {
 		specResource := newSpecStubResource("resourceName", "/v1/resource", false, nil)
 		r := newResourceFactory(specResource)
@@ -445,7 +716,7 @@ func TestUpdate(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 			Convey("And resourceData should be populated with the values returned by the API including the ID", func() {
-				So(err.Error(), ShouldEqual, "[resource='resourceName'] resource does not support PUT operation, check the swagger file exposed on '/v1/resource'")
+				So(err.Error(), ShouldEqual, "[resource='resourceName'] resource does not support PUT nor PATCH operations, check the swagger file exposed on '/v1/resource'")
 			})
 		})
 	})
@@ -491,6 +762,228 @@ func TestUpdate(t *testing.T) {
 	})
 }
 
+func TestUpdateWithActions(t *testing.T) {
+	readOnlyIDProperty := newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, "id")
+	Convey("Given a resource factory configured with a resource that has an action triggered by the 'rotation_trigger' attribute", t, func() {
+		r, _ := testCreateResourceFactory(t, readOnlyIDProperty, stringProperty)
+		r.openAPIResource.(*specStubResource).resourceActions = []*specResourceAction{
+			{Name: "rotate", TriggerAttribute: "rotation_trigger", Operation: &specResourceOperation{}},
+		}
+		resourceSchema, err := r.createTerraformResourceSchema()
+		So(err, ShouldBeNil)
+		state := &terraform.InstanceState{
+			ID: "id",
+			Attributes: map[string]string{
+				"id":                "id",
+				stringProperty.Name: "someValue",
+				"rotation_trigger":  "v1",
+			},
+		}
+		// newResourceData returns the resource data resulting from applying the given configuration to the state above
+		newResourceData := func(config map[string]interface{}) *schema.ResourceData {
+			diff, err := schema.InternalMap(resourceSchema).Diff(state, terraform.NewResourceConfigRaw(config), nil, nil, true)
+			So(err, ShouldBeNil)
+			resourceData, err := schema.InternalMap(resourceSchema).Data(state, diff)
+			So(err, ShouldBeNil)
+			return resourceData
+		}
+		Convey("When update is called with resource data where only the trigger attribute changed", func() {
+			resourceData := newResourceData(map[string]interface{}{stringProperty.Name: "someValue", "rotation_trigger": "v2"})
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someValueAfterRotation",
+				},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the action should have been performed without updating the resource", func() {
+				So(client.actionsReceived, ShouldResemble, []string{"rotate"})
+				So(client.requestPayloadReceived, ShouldResemble, map[string]interface{}{})
+			})
+			Convey("And the state should be refreshed with the resource remote data", func() {
+				So(resourceData.Get(stringProperty.Name), ShouldEqual, "someValueAfterRotation")
+			})
+		})
+		Convey("When update is called with resource data where the trigger attribute did not change", func() {
+			resourceData := newResourceData(map[string]interface{}{stringProperty.Name: "someNewValue", "rotation_trigger": "v1"})
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someNewValue",
+				},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil and the resource should have been updated without performing the action", func() {
+				So(err, ShouldBeNil)
+				So(client.actionsReceived, ShouldBeEmpty)
+				So(resourceData.Get(stringProperty.Name), ShouldEqual, "someNewValue")
+			})
+		})
+		Convey("When update is called with resource data where the trigger attribute changed and the action fails", func() {
+			resourceData := newResourceData(map[string]interface{}{stringProperty.Name: "someValue", "rotation_trigger": "v2"})
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{},
+				returnHTTPCode:  http.StatusInternalServerError,
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "[resource='resourceName'] POST /v1/resource/id:rotate failed: [resource='resourceName'] HTTP Response Status Code 500 not matching expected one [200 201 202 204] ()")
+			})
+		})
+	})
+}
+
+func TestUpdateAndDeleteWithETag(t *testing.T) {
+	readOnlyIDProperty := newStringSchemaDefinitionPropertyWithDefaults("id", "", false, true, "id")
+	Convey("Given a resource factory configured with a resource which update and delete operations send the If-Match header", t, func() {
+		r, _ := testCreateResourceFactory(t, readOnlyIDProperty, stringProperty)
+		specResource := r.openAPIResource.(*specStubResource)
+		specResource.resourcePutOperation.IfMatch = true
+		specResource.resourceDeleteOperation.IfMatch = true
+		resourceSchema, err := r.createTerraformResourceSchema()
+		So(err, ShouldBeNil)
+		state := &terraform.InstanceState{
+			ID: "id",
+			Attributes: map[string]string{
+				"id":                "id",
+				stringProperty.Name: "someValue",
+				eTagAttributeName:   `"v1"`,
+			},
+		}
+		diff, err := schema.InternalMap(resourceSchema).Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{stringProperty.Name: "someNewValue"}), nil, nil, true)
+		So(err, ShouldBeNil)
+		resourceData, err := schema.InternalMap(resourceSchema).Data(state, diff)
+		So(err, ShouldBeNil)
+		Convey("When update is called with a client that returns a new ETag", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someNewValue",
+				},
+				responseHeaders: http.Header{http.CanonicalHeaderKey(eTagHeader): []string{`"v2"`}},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the update request should have been sent with the ETag stored in the state", func() {
+				So(client.ifMatchReceived, ShouldEqual, `"v1"`)
+			})
+			Convey("And the state should contain the new ETag", func() {
+				So(resourceData.Get(eTagAttributeName), ShouldEqual, `"v2"`)
+			})
+		})
+		Convey("When update is called with a client that does not return the ETag header", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someNewValue",
+				},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the ETag stored in the state should be cleared as it no longer matches the resource version", func() {
+				So(resourceData.Get(eTagAttributeName), ShouldBeEmpty)
+			})
+		})
+		Convey("When update is called with a client that accepts the request (202) and the resource is polled until the update completes", func() {
+			r.defaultPollDelay = 0
+			specResource.resourcePutOperation.responses = specResponses{
+				http.StatusAccepted: {
+					isPollingEnabled:    true,
+					pollStatusField:     stringProperty.Name,
+					pollPendingStatuses: []string{"someValue"},
+					pollTargetStatuses:  []string{"someNewValue"},
+				},
+			}
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someNewValue",
+				},
+				responseHeaders: http.Header{http.CanonicalHeaderKey(eTagHeader): []string{`"v2"`}},
+				funcPut: func() (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusAccepted,
+						Header:     http.Header{http.CanonicalHeaderKey(eTagHeader): []string{`"accepted"`}},
+						Body:       ioutil.NopCloser(strings.NewReader("")),
+					}, nil
+				},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the state should contain the ETag returned by the last GET performed while polling instead of the one in the 202 response", func() {
+				So(resourceData.Get(eTagAttributeName), ShouldEqual, `"v2"`)
+			})
+		})
+		Convey("When update is called with a client that accepts the request (202) and the response is not configured to poll the resource", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someNewValue",
+				},
+				responseHeaders: http.Header{http.CanonicalHeaderKey(eTagHeader): []string{`"v2"`}},
+				funcPut: func() (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusAccepted, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+				},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the resource should have been read to obtain the ETag", func() {
+				So(resourceData.Get(eTagAttributeName), ShouldEqual, `"v2"`)
+			})
+		})
+		Convey("When update is called with a client that returns 412 Precondition Failed as the resource was modified by someone else", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someValue",
+				},
+				funcPut: func() (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusPreconditionFailed, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+				},
+			}
+			err := r.update(resourceData, client)
+			Convey("Then the error returned should tell the user to refresh the resource", func() {
+				So(err.Error(), ShouldEqual, "[resource='resourceName'] UPDATE /v1/resource/id failed: [resource='resourceName'] HTTP Response Status Code 412 - Precondition Failed: the resource has been modified outside terraform since it was last read (e,g: by another team or tool). Run 'terraform refresh' to get the latest changes, review them and try again ()")
+			})
+		})
+		Convey("When delete is called", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{},
+			}
+			err := r.delete(resourceData, client)
+			Convey("Then the delete request should have been sent with the ETag stored in the state", func() {
+				So(err, ShouldBeNil)
+				So(client.ifMatchReceived, ShouldEqual, `"v1"`)
+			})
+		})
+		Convey("When read is called with a client that returns the resource ETag", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     "id",
+					stringProperty.Name: "someRemoteValue",
+				},
+				responseHeaders: http.Header{http.CanonicalHeaderKey(eTagHeader): []string{`"v3"`}},
+			}
+			err := r.read(resourceData, client)
+			Convey("Then the state should contain the ETag returned by the API", func() {
+				So(err, ShouldBeNil)
+				So(resourceData.Get(eTagAttributeName), ShouldEqual, `"v3"`)
+			})
+		})
+	})
+}
+
 func TestDelete(t *testing.T) {
 	Convey("Given a resource factory", t, func() {
 		r, resourceData := testCreateResourceFactoryWithID(t, idProperty)
@@ -779,6 +1272,49 @@ func TestImporter(t *testing.T) {
 			})
 		})
 	})
+	Convey("Given a resource factory configured with a singleton resource", t, func() {
+		testSchema := newTestSchema(stringProperty)
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("settings")
+		specResource := newSpecStubResourceWithOperations("settings", "/v1/account/settings", false, testSchema.getSchemaDefinition(), nil, &specResourceOperation{}, &specResourceOperation{}, nil)
+		specResource.singleton = true
+		r := newResourceFactory(specResource)
+		Convey("When the resourceImporter State method is invoked with data resource and the provider client", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					stringProperty.Name: "someOtherStringValue",
+				},
+			}
+			data, err := r.importer().State(resourceData, client)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the data returned should contain the resource path as ID and the values returned from the API", func() {
+				So(len(data), ShouldEqual, 1)
+				So(data[0].Id(), ShouldEqual, "/v1/account/settings")
+				So(data[0].Get(stringProperty.Name), ShouldEqual, client.responsePayload[stringProperty.Name])
+			})
+		})
+	})
+	Convey("Given a resource factory configured with a singleton subresource and an imported ID missing parent IDs", t, func() {
+		expectedParentPropertyName := "projects_v1_id"
+		parentProperty := newStringSchemaDefinitionProperty(expectedParentPropertyName, "", true, true, false, false, false, true, false, false, "")
+		testSchema := newTestSchema(stringProperty, parentProperty)
+		resourceData := testSchema.getResourceData(t)
+		resourceData.SetId("projectID/extra")
+		specResource := newSpecStubResourceWithOperations("projects_v1_quota", "/v1/projects/{id}/quota", false, testSchema.getSchemaDefinition(), nil, &specResourceOperation{}, &specResourceOperation{}, nil)
+		specResource.singleton = true
+		specResource.parentResourceNames = []string{"projects_v1"}
+		specResource.parentPropertyNames = []string{expectedParentPropertyName}
+		specResource.fullParentResourceName = "projects_v1"
+		r := newResourceFactory(specResource)
+		Convey("When the resourceImporter State method is invoked with data resource and the provider client", func() {
+			_, err := r.importer().State(resourceData, &clientOpenAPIStub{})
+			Convey("Then the err returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "can not import a singleton subresource without providing all the parent IDs, expected 1 and got 2 parent IDs")
+			})
+		})
+	})
 }
 
 func TestHandlePollingIfConfigured(t *testing.T) {
@@ -807,7 +1343,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 					},
 				},
 			}
-			err := r.handlePollingIfConfigured(&responsePayload, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			_, err := r.handlePollingIfConfigured(&responsePayload, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -835,7 +1371,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 					},
 				},
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			_, err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -852,7 +1388,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 			operation := &specResourceOperation{
 				responses: map[int]*specResponse{},
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			_, err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
 			Convey("Then the err  should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -870,7 +1406,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 					},
 				},
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
+			_, err := r.handlePollingIfConfigured(nil, resourceData, client, operation, responseStatusCode, schema.TimeoutCreate)
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
 			})
@@ -898,7 +1434,7 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 				},
 				error: fmt.Errorf("some error"),
 			}
-			err := r.handlePollingIfConfigured(nil, resourceData, client, operation, expectedReturnCode, schema.TimeoutCreate)
+			_, err := r.handlePollingIfConfigured(nil, resourceData, client, operation, expectedReturnCode, schema.TimeoutCreate)
 			Convey("Then the error returned should be the expected one", func() {
 				So(err.Error(), ShouldEqual, "error waiting for resource to reach a completion status ([destroyed]) [valid pending statuses ([pending])]: error on retrieving resource 'resourceName' (id) when waiting: some error")
 			})
@@ -907,6 +1443,263 @@ func TestHandlePollingIfConfigured(t *testing.T) {
 
 }
 
+func TestHandleOperationPollingIfConfigured(t *testing.T) {
+	Convey("Given a resource factory configured with a resource that has an operation with a response configured to poll the operation resource", t, func() {
+		r, resourceData := testCreateResourceFactory(t, idProperty, stringProperty)
+		r.defaultPollDelay = 0
+		responseStatusCode := http.StatusAccepted
+		operation := &specResourceOperation{
+			responses: specResponses{
+				responseStatusCode: {pollOperation: newSpecPollOperation("", "", "", nil, nil)},
+			},
+		}
+		res := &http.Response{StatusCode: responseStatusCode, Header: http.Header{}}
+		Convey("When handleOperationPollingIfConfigured is called with a response containing the Operation-Location header and the operation eventually succeeds", func() {
+			res.Header.Set(operationLocationHeader, "/v1/operations/op1")
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					stringProperty.Name: "someValueReturnedOnceCreated",
+				},
+				operationPayloads: []map[string]interface{}{
+					{"status": "running"},
+					{"status": "Succeeded", "resourceId": "/v1/resource/1234"},
+				},
+			}
+			responsePayload := map[string]interface{}{}
+			_, err := r.handleOperationPollingIfConfigured(&responsePayload, resourceData, client, operation, res, schema.TimeoutCreate)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the operation URL received by the client should be the one in the Operation-Location header", func() {
+				So(client.operationURLReceived, ShouldEqual, "/v1/operations/op1")
+			})
+			Convey("And the response payload should be the resource payload including the ID returned in the operation", func() {
+				So(client.idReceived, ShouldEqual, "1234")
+				So(responsePayload[idProperty.Name], ShouldEqual, "1234")
+				So(responsePayload[stringProperty.Name], ShouldEqual, "someValueReturnedOnceCreated")
+			})
+		})
+		Convey("When handleOperationPollingIfConfigured is called with a response containing the Location header and the operation fails", func() {
+			res.Header.Set(locationHeader, "https://www.host.com/v1/operations/op1")
+			client := &clientOpenAPIStub{
+				operationPayload: map[string]interface{}{"status": "failed", "error": map[string]interface{}{"code": "QuotaExceeded"}},
+			}
+			responsePayload := map[string]interface{}{}
+			_, err := r.handleOperationPollingIfConfigured(&responsePayload, resourceData, client, operation, res, schema.TimeoutCreate)
+			Convey("Then the err returned should contain the operation error details", func() {
+				So(err.Error(), ShouldEqual, `error waiting for operation 'https://www.host.com/v1/operations/op1' to complete: operation 'https://www.host.com/v1/operations/op1' failed with status 'failed': {"code":"QuotaExceeded"}`)
+			})
+		})
+		Convey("When handleOperationPollingIfConfigured is called with a response that is missing the Location and Operation-Location headers", func() {
+			client := &clientOpenAPIStub{}
+			_, err := r.handleOperationPollingIfConfigured(nil, resourceData, client, operation, res, schema.TimeoutDelete)
+			Convey("Then the err returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "response is missing the 'Operation-Location' or 'Location' header containing the operation URL")
+			})
+		})
+		Convey("When handleOperationPollingIfConfigured is called with a response status code that does not have the operation polling configured", func() {
+			client := &clientOpenAPIStub{}
+			_, err := r.handleOperationPollingIfConfigured(nil, resourceData, client, operation, &http.Response{StatusCode: http.StatusOK}, schema.TimeoutDelete)
+			Convey("Then the err returned should be nil and the operation should not be polled", func() {
+				So(err, ShouldBeNil)
+				So(client.operationURLReceived, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestHandleLongRunningOperationIfConfigured(t *testing.T) {
+	Convey("Given a resource factory configured with a resource that has an operation with a response configured as a long-running operation", t, func() {
+		r, resourceData := testCreateResourceFactory(t, idProperty, stringProperty)
+		r.defaultPollDelay = 0
+		responseStatusCode := http.StatusOK
+		operation := &specResourceOperation{
+			responses: specResponses{
+				responseStatusCode: {isLongRunningOperation: true},
+			},
+		}
+		res := &http.Response{StatusCode: responseStatusCode}
+		Convey("When handleLongRunningOperationIfConfigured is called with a long-running operation that is eventually done with a response", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					stringProperty.Name: "someValueReturnedOnceCreated",
+				},
+				operationPayloads: []map[string]interface{}{
+					{"name": "operations/op1", "done": false},
+					{"name": "operations/op1", "done": true, "response": map[string]interface{}{"name": "cdns/1234"}},
+				},
+			}
+			responsePayload := map[string]interface{}{"name": "operations/op1", "done": false}
+			_, err := r.handleLongRunningOperationIfConfigured(&responsePayload, resourceData, client, operation, res, schema.TimeoutCreate)
+			Convey("Then the err returned should be nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("And the operation name received by the client should be the one in the response payload", func() {
+				So(client.operationNameReceived, ShouldEqual, "operations/op1")
+			})
+			Convey("And the response payload should be the resource payload including the ID of the resource named in the operation response", func() {
+				So(client.idReceived, ShouldEqual, "1234")
+				So(responsePayload[idProperty.Name], ShouldEqual, "1234")
+				So(responsePayload[stringProperty.Name], ShouldEqual, "someValueReturnedOnceCreated")
+			})
+		})
+		Convey("When handleLongRunningOperationIfConfigured is called with a long-running operation that is already done with a response", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: "5678",
+				},
+			}
+			responsePayload := map[string]interface{}{"name": "operations/op1", "done": true, "response": map[string]interface{}{idProperty.Name: "5678"}}
+			_, err := r.handleLongRunningOperationIfConfigured(&responsePayload, resourceData, client, operation, res, schema.TimeoutCreate)
+			Convey("Then the err returned should be nil and the operation should not be polled", func() {
+				So(err, ShouldBeNil)
+				So(client.operationNameReceived, ShouldBeEmpty)
+			})
+			Convey("And the resource should have been read using the ID in the operation response", func() {
+				So(client.idReceived, ShouldEqual, "5678")
+				So(responsePayload, ShouldResemble, map[string]interface{}{idProperty.Name: "5678"})
+			})
+		})
+		Convey("When handleLongRunningOperationIfConfigured is called with a DELETE response body containing a long-running operation that is done with an error", func() {
+			client := &clientOpenAPIStub{}
+			deleteRes := &http.Response{
+				StatusCode: responseStatusCode,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"name":"operations/op1","done":true,"error":{"code":9,"message":"resource is still in use"}}`)),
+			}
+			_, err := r.handleLongRunningOperationIfConfigured(nil, resourceData, client, operation, deleteRes, schema.TimeoutDelete)
+			Convey("Then the err returned should contain the operation error message", func() {
+				So(err.Error(), ShouldEqual, "long-running operation 'operations/op1' failed: resource is still in use")
+			})
+		})
+		Convey("When handleLongRunningOperationIfConfigured is called with a response payload that is not a long-running operation", func() {
+			client := &clientOpenAPIStub{}
+			responsePayload := map[string]interface{}{"done": false}
+			_, err := r.handleLongRunningOperationIfConfigured(&responsePayload, resourceData, client, operation, res, schema.TimeoutCreate)
+			Convey("Then the err returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "response payload is not a long-running operation, missing the 'name' property")
+			})
+		})
+		Convey("When handleLongRunningOperationIfConfigured is called with a response status code that is not configured as a long-running operation", func() {
+			client := &clientOpenAPIStub{}
+			responsePayload := map[string]interface{}{"name": "operations/op1", "done": false}
+			_, err := r.handleLongRunningOperationIfConfigured(&responsePayload, resourceData, client, operation, &http.Response{StatusCode: http.StatusAccepted}, schema.TimeoutCreate)
+			Convey("Then the err returned should be nil and the operation should not be polled", func() {
+				So(err, ShouldBeNil)
+				So(client.operationNameReceived, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestGetPayloadPropertyValue(t *testing.T) {
+	payload := map[string]interface{}{
+		"status": "active",
+		"error": map[string]interface{}{
+			"message": "some error",
+			"details": []interface{}{
+				map[string]interface{}{"reason": "QuotaExceeded"},
+			},
+		},
+		"matrix": []interface{}{[]interface{}{"a", "b"}},
+	}
+	testCases := []struct {
+		name          string
+		propertyPath  string
+		expectedValue interface{}
+	}{
+		{name: "top level property", propertyPath: "status", expectedValue: "active"},
+		{name: "top level property using the root prefix", propertyPath: "$.status", expectedValue: "active"},
+		{name: "nested property", propertyPath: "error.message", expectedValue: "some error"},
+		{name: "property inside an array item", propertyPath: "$.error.details[0].reason", expectedValue: "QuotaExceeded"},
+		{name: "nested arrays", propertyPath: "matrix[0][1]", expectedValue: "b"},
+		{name: "array index out of range", propertyPath: "error.details[1].reason", expectedValue: nil},
+		{name: "index on a non array property", propertyPath: "status[0]", expectedValue: nil},
+		{name: "non existing property", propertyPath: "error.code", expectedValue: nil},
+	}
+	for _, tc := range testCases {
+		value := getPayloadPropertyValue(payload, tc.propertyPath)
+		assert.Equal(t, tc.expectedValue, value, tc.name)
+	}
+}
+
+func TestApplyPollConfiguration(t *testing.T) {
+	Convey("Given a resource factory", t, func() {
+		r := newResourceFactory(&specStubResource{name: "cdn_v1"})
+		Convey("When applyPollConfiguration is called with a response that does not have any poll configuration", func() {
+			stateConf := &resource.StateChangeConf{Delay: r.defaultPollDelay, PollInterval: r.defaultPollInterval, MinTimeout: r.defaultPollMinTimeout}
+			r.applyPollConfiguration(stateConf, &specResponse{})
+			Convey("Then the state change configuration timings should not be modified", func() {
+				So(stateConf.Delay, ShouldEqual, defaultPollDelay)
+				So(stateConf.PollInterval, ShouldEqual, defaultPollInterval)
+				So(stateConf.MinTimeout, ShouldEqual, defaultPollMinTimeout)
+				So(stateConf.Refresh, ShouldBeNil)
+			})
+		})
+		Convey("When applyPollConfiguration is called with a response that has a poll delay and interval configured", func() {
+			stateConf := &resource.StateChangeConf{
+				Delay:        r.defaultPollDelay,
+				PollInterval: r.defaultPollInterval,
+				MinTimeout:   r.defaultPollMinTimeout,
+				Refresh: func() (interface{}, string, error) {
+					return nil, "", nil
+				},
+			}
+			r.applyPollConfiguration(stateConf, &specResponse{pollConfiguration: specPollConfiguration{delay: 30 * time.Second, interval: time.Minute}})
+			Convey("Then the delay and the poll interval should be the configured ones", func() {
+				So(stateConf.Delay, ShouldEqual, 30*time.Second)
+				So(stateConf.PollInterval, ShouldEqual, time.Minute)
+				So(stateConf.MinTimeout, ShouldEqual, time.Minute)
+			})
+		})
+		Convey("When applyPollConfiguration is called with a response that has a poll interval longer than the one supported by the state change configuration", func() {
+			stateConf := &resource.StateChangeConf{PollInterval: r.defaultPollInterval, MinTimeout: r.defaultPollMinTimeout}
+			r.applyPollConfiguration(stateConf, &specResponse{pollConfiguration: specPollConfiguration{interval: 10 * time.Minute}})
+			Convey("Then the poll interval should be capped to the max poll interval", func() {
+				So(stateConf.PollInterval, ShouldEqual, maxPollInterval)
+				So(stateConf.MinTimeout, ShouldEqual, maxPollInterval)
+			})
+		})
+		Convey("When applyPollConfiguration is called with a response that has a poll delay configured and the resource factory has a poll delay configured too", func() {
+			r.pollConfiguration = specPollConfiguration{delay: 2 * time.Minute}
+			stateConf := &resource.StateChangeConf{Delay: r.defaultPollDelay}
+			r.applyPollConfiguration(stateConf, &specResponse{pollConfiguration: specPollConfiguration{delay: 30 * time.Second}})
+			Convey("Then the delay should be the one configured in the resource factory (plugin configuration)", func() {
+				So(stateConf.Delay, ShouldEqual, 2*time.Minute)
+			})
+		})
+	})
+}
+
+func TestPollBackoffRefreshFunc(t *testing.T) {
+	Convey("Given a state change configuration and a poll configuration with an interval, a backoff factor and a max interval", t, func() {
+		refreshCalls := 0
+		stateConf := &resource.StateChangeConf{
+			Refresh: func() (interface{}, string, error) {
+				refreshCalls++
+				return nil, "pending", nil
+			},
+		}
+		r := newResourceFactory(&specStubResource{name: "cdn_v1"})
+		r.applyPollConfiguration(stateConf, &specResponse{pollConfiguration: specPollConfiguration{interval: 10 * time.Second, backoffFactor: 2, maxInterval: 30 * time.Second}})
+		Convey("When the refresh function of the state change configuration is called several times", func() {
+			var pollIntervals []time.Duration
+			for i := 0; i < 3; i++ {
+				_, state, err := stateConf.Refresh()
+				So(err, ShouldBeNil)
+				So(state, ShouldEqual, "pending")
+				pollIntervals = append(pollIntervals, stateConf.PollInterval)
+			}
+			Convey("Then the original refresh function should be called each time", func() {
+				So(refreshCalls, ShouldEqual, 3)
+			})
+			Convey("And the poll interval should be increased by the backoff factor after each refresh up to the max interval", func() {
+				So(pollIntervals, ShouldResemble, []time.Duration{20 * time.Second, 30 * time.Second, 30 * time.Second})
+				So(stateConf.MinTimeout, ShouldEqual, 30*time.Second)
+			})
+		})
+	})
+}
+
 func TestResourceStateRefreshFunc(t *testing.T) {
 	Convey("Given a resource factory configured with a resource which has a schema definition containing a status property", t, func() {
 		r, resourceData := testCreateResourceFactoryWithID(t, idProperty, stringProperty, statusProperty)
@@ -918,7 +1711,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 					statusProperty.Name: statusProperty.Default,
 				},
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, &specResponse{}, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
@@ -933,11 +1726,54 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 			})
 
 		})
+		Convey("When resourceStateRefreshFunc is called with a response configured with failed statuses and an error field and the API returns one of the failed statuses", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name:     idProperty.Default,
+					statusProperty.Name: "FAILED",
+					"error":             map[string]interface{}{"details": []interface{}{map[string]interface{}{"message": "quota exceeded"}}},
+				},
+			}
+			response := &specResponse{pollFailedStatuses: []string{"FAILED"}, pollErrorField: "$.error.details[0].message"}
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, response, nil)
+			_, _, err := stateRefreshFunc()
+			Convey("Then the err returned should contain the error message found in the error field", func() {
+				So(err.Error(), ShouldEqual, "resource 'resourceName' (id) reached a failed status 'FAILED': quota exceeded")
+			})
+		})
+		Convey("When resourceStateRefreshFunc is called with a response configured with a status field pointing at a nested property", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: idProperty.Default,
+					"properties":    map[string]interface{}{"provisioningState": "Succeeded"},
+				},
+			}
+			response := &specResponse{pollStatusField: "properties.provisioningState"}
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, response, nil)
+			_, newStatus, err := stateRefreshFunc()
+			Convey("Then the err returned should be nil and the new status should be the nested property value", func() {
+				So(err, ShouldBeNil)
+				So(newStatus, ShouldEqual, "Succeeded")
+			})
+		})
+		Convey("When resourceStateRefreshFunc is called with a response configured with a status field that does not exist in the payload", func() {
+			client := &clientOpenAPIStub{
+				responsePayload: map[string]interface{}{
+					idProperty.Name: idProperty.Default,
+				},
+			}
+			response := &specResponse{pollStatusField: "properties.provisioningState"}
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, response, nil)
+			_, _, err := stateRefreshFunc()
+			Convey("Then the err returned should be the expected one", func() {
+				So(err.Error(), ShouldEqual, "error occurred while retrieving status identifier value from payload for resource 'resourceName' (id): could not find the status field 'properties.provisioningState' in the payload")
+			})
+		})
 		Convey("When resourceStateRefreshFunc is called with an update resource data and an open api client that returns 404 not found", func() {
 			client := &clientOpenAPIStub{
 				returnHTTPCode: http.StatusNotFound,
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, &specResponse{}, nil)
 			_, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should be nil", func() {
 				So(err, ShouldBeNil)
@@ -952,7 +1788,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 			client := &clientOpenAPIStub{
 				error: errors.New(expectedError),
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, &specResponse{}, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
@@ -978,7 +1814,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 					stringProperty.Name: stringProperty.Default,
 				},
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, &specResponse{}, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
@@ -1004,7 +1840,7 @@ func TestResourceStateRefreshFunc(t *testing.T) {
 					stringProperty.Name: stringProperty.Default,
 				},
 			}
-			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client)
+			stateRefreshFunc := r.resourceStateRefreshFunc(resourceData, client, &specResponse{}, nil)
 			remoteData, newStatus, err := stateRefreshFunc()
 			Convey("Then the err returned should not be nil", func() {
 				So(err, ShouldNotBeNil)
@@ -2231,3 +3067,28 @@ func testCreateSubResourceFactory(t *testing.T, path string, parentResourceNames
 	specResource.fullParentResourceName = fullParentResourceName
 	return newResourceFactory(specResource), resourceData
 }
+
+func TestBuildIdempotencyKey(t *testing.T) {
+	Convey("Given a resource name, the parent IDs and the create payload", t, func() {
+		payload := map[string]interface{}{"label": "some label", "size": 10}
+		Convey("When buildIdempotencyKey is called", func() {
+			idempotencyKey, err := buildIdempotencyKey("cdn_v1", []string{"parentID"}, payload)
+			Convey("Then the key returned should be the hex encoded SHA-256 hash", func() {
+				So(err, ShouldBeNil)
+				So(idempotencyKey, ShouldHaveLength, 64)
+			})
+			Convey("And the same key should be returned for the same resource instance", func() {
+				sameIdempotencyKey, _ := buildIdempotencyKey("cdn_v1", []string{"parentID"}, map[string]interface{}{"size": 10, "label": "some label"})
+				So(sameIdempotencyKey, ShouldEqual, idempotencyKey)
+			})
+			Convey("And a different key should be returned if the resource name, parent IDs or payload differ", func() {
+				otherResourceKey, _ := buildIdempotencyKey("lb_v1", []string{"parentID"}, payload)
+				otherParentKey, _ := buildIdempotencyKey("cdn_v1", []string{"otherParentID"}, payload)
+				otherPayloadKey, _ := buildIdempotencyKey("cdn_v1", []string{"parentID"}, map[string]interface{}{"label": "other label", "size": 10})
+				So(otherResourceKey, ShouldNotEqual, idempotencyKey)
+				So(otherParentKey, ShouldNotEqual, idempotencyKey)
+				So(otherPayloadKey, ShouldNotEqual, idempotencyKey)
+			})
+		})
+	})
+}
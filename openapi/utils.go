@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 	"log"
 	"net/url"
+	"reflect"
+	"strings"
 )
 
 func prettyPrint(v interface{}) {
@@ -46,3 +48,27 @@ func isURL(str string) bool {
 	u, err := url.Parse(str)
 	return err == nil && u.Scheme != "" && u.Host != ""
 }
+
+// buildJSONPointer returns the JSON Pointer (RFC 6901) referencing the given top level property name. The characters
+// '~' and '/' are escaped as per the RFC
+func buildJSONPointer(propertyName string) string {
+	escaped := strings.Replace(propertyName, "~", "~0", -1)
+	escaped = strings.Replace(escaped, "/", "~1", -1)
+	return "/" + escaped
+}
+
+// isEmptyValue returns true if the value is nil or the zero value of its type. Slices, maps and strings are considered
+// empty if their length is zero
+func isEmptyValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return reflect.DeepEqual(v, reflect.Zero(value.Type()).Interface())
+}
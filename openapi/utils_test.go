@@ -85,3 +85,39 @@ func TestIsUrl(t *testing.T) {
 		assert.Equal(t, tc.expectedResult, isURL, tc.name)
 	}
 }
+
+func TestBuildJSONPointer(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedResult string
+	}{
+		{name: "simple property name", input: "name", expectedResult: "/name"},
+		{name: "property name containing a forward slash", input: "a/b", expectedResult: "/a~1b"},
+		{name: "property name containing a tilde", input: "m~n", expectedResult: "/m~0n"},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedResult, buildJSONPointer(tc.input), tc.name)
+	}
+}
+
+func TestIsEmptyValue(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          interface{}
+		expectedResult bool
+	}{
+		{name: "nil value", input: nil, expectedResult: true},
+		{name: "empty string", input: "", expectedResult: true},
+		{name: "non empty string", input: "value", expectedResult: false},
+		{name: "zero int", input: 0, expectedResult: true},
+		{name: "non zero int", input: 12, expectedResult: false},
+		{name: "false bool", input: false, expectedResult: true},
+		{name: "empty list", input: []interface{}{}, expectedResult: true},
+		{name: "non empty list", input: []interface{}{"value"}, expectedResult: false},
+		{name: "empty map", input: map[string]interface{}{}, expectedResult: true},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedResult, isEmptyValue(tc.input), tc.name)
+	}
+}